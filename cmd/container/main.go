@@ -3,15 +3,23 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"encoding/xml"
 	"flag"
 	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/zsoftly/logguardian/internal/container"
+	"github.com/zsoftly/logguardian/internal/logging"
+	"github.com/zsoftly/logguardian/internal/service"
+	"github.com/zsoftly/logguardian/internal/types"
 )
 
 const (
@@ -20,16 +28,52 @@ const (
 	ExitUsage   = 2
 )
 
+// Request types accepted by --type
+const (
+	TypeConfigRuleEvaluation = "config-rule-evaluation"
+	TypePreflight            = "preflight"
+)
+
 type CommandInput struct {
-	Type           string
-	ConfigRuleName string
-	Region         string
-	BatchSize      int
-	DryRun         bool
-	Profile        string
-	AssumeRole     string
-	Verbose        bool
-	OutputFormat   string
+	Type              string
+	ConfigRuleName    string
+	Region            string
+	BatchSize         int
+	DryRun            bool
+	Profile           string
+	AssumeRole        string
+	Verbose           bool
+	OutputFormat      string
+	VerifyCredentials bool
+	// AllowRetentionReduction permits shortening retention on data-protected log groups
+	AllowRetentionReduction bool
+	// LogGroup targets a single log group by name instead of querying Config
+	LogGroup string
+	// LogGroupLookupRetries overrides LOG_GROUP_LOOKUP_RETRIES when zero or more
+	LogGroupLookupRetries int
+	// Regions runs the evaluation concurrently in each of these regions when more than one is given
+	Regions []string
+	// RunConfigURI is an s3://bucket/key run plan listing the rules, regions and batch sizes to evaluate
+	RunConfigURI string
+	// ExitCodeOnEmpty is the exit code of a successful run that found no non-compliant resources
+	ExitCodeOnEmpty int
+	// IncludeCompliant also lists log groups Config reports compliant with the rule, with status compliant
+	IncludeCompliant bool
+	// AnnotationKeywords keeps only resources whose Config annotation contains one of these keywords
+	AnnotationKeywords []string
+	// InsightsResultsPath is a saved Logs Insights query result listing the log groups to remediate
+	// instead of querying Config
+	InsightsResultsPath string
+	// InsightsField is the Logs Insights result field holding each log group name
+	InsightsField string
+	// KMSPreflight also fails preflight when a region's KMS key fails comprehensive validation
+	KMSPreflight bool
+	// Rules evaluates several Config rules in one run, remediating each log group once for every
+	// requirement the rules flagged it for
+	Rules []string
+	// Mode is the resolved execution mode and whether --dry-run or DRY_RUN decided it; DryRun
+	// follows it
+	Mode service.ExecutionModeResolution
 }
 
 func main() {
@@ -40,20 +84,40 @@ func main() {
 		logLevel = slog.LevelDebug
 	}
 
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+	// Repeated audit logs are collapsed when LOG_DEDUP_AUDIT is set
+	dedup := logging.NewDedupHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
 		Level: logLevel,
-	}))
-	slog.SetDefault(logger)
+	}), logging.DedupEnabledFromEnv())
+	slog.SetDefault(logging.WithEnvLabels(slog.New(dedup)))
 
 	executionID := fmt.Sprintf("exec-%d", time.Now().Unix())
 	startTime := time.Now()
 	slog.Info("Starting LogGuardian container execution",
 		"execution_id", executionID,
 		"version", getVersion(),
-		"mode", getExecutionMode(input.DryRun))
+		"mode", input.Mode.Mode,
+		"mode_source", input.Mode.Source)
+
+	// The health server is only started for long-lived deployments that set HEALTH_PORT, and
+	// only serves /metrics when METRICS_ENABLED is also set. Readiness stays nil without it, so
+	// one-shot runs skip the region validation it needs.
+	var readiness *container.Readiness
+	var metrics *container.ServiceMetrics
+	if port := os.Getenv("HEALTH_PORT"); port != "" {
+		if strings.ToLower(os.Getenv("METRICS_ENABLED")) == "true" {
+			metrics = &container.ServiceMetrics{}
+		}
+		served := &container.Readiness{}
+		if _, err := container.StartHealthServer(port, served, metrics); err != nil {
+			slog.Warn("Health server not started", "error", err, "execution_id", executionID)
+		} else {
+			readiness = served
+		}
+	}
 
 	ctx := context.Background()
-	exitCode := execute(ctx, input, executionID)
+	exitCode := execute(ctx, input, executionID, readiness, metrics)
+	dedup.Flush(ctx)
 
 	slog.Info("Execution completed",
 		"execution_id", executionID,
@@ -66,15 +130,31 @@ func main() {
 func parseCommandLineArgs() CommandInput {
 	input := CommandInput{}
 
-	flag.StringVar(&input.Type, "type", "config-rule-evaluation", "Request type: config-rule-evaluation")
+	flag.StringVar(&input.Type, "type", TypeConfigRuleEvaluation, "Request type: config-rule-evaluation or preflight")
 	flag.StringVar(&input.ConfigRuleName, "config-rule", "", "AWS Config rule name to evaluate")
+	var rules string
+	flag.StringVar(&rules, "rules", "", "Comma-separated Config rules to evaluate in one run, e.g. cw-lg-kms-encryption,cw-lg-retention-min")
+	flag.StringVar(&input.LogGroup, "log-group", "", "Evaluate and remediate only this log group, without querying Config")
 	flag.StringVar(&input.Region, "region", os.Getenv("AWS_REGION"), "AWS region")
 	flag.IntVar(&input.BatchSize, "batch-size", 10, "Batch size for processing resources")
 	flag.BoolVar(&input.DryRun, "dry-run", false, "Preview changes without applying them")
 	flag.StringVar(&input.Profile, "profile", os.Getenv("AWS_PROFILE"), "AWS profile to use")
 	flag.StringVar(&input.AssumeRole, "assume-role", os.Getenv("AWS_ASSUME_ROLE_ARN"), "IAM role ARN to assume")
 	flag.BoolVar(&input.Verbose, "verbose", false, "Enable verbose logging")
-	flag.StringVar(&input.OutputFormat, "output", "json", "Output format: json or text")
+	flag.StringVar(&input.OutputFormat, "output", "json", "Output format: json, text, config-remediation or junit")
+	flag.BoolVar(&input.VerifyCredentials, "verify-credentials", true, "Verify credentials with STS GetCallerIdentity before processing")
+	flag.BoolVar(&input.AllowRetentionReduction, "allow-retention-reduction", false, "Allow shortening retention on log groups with active data protection")
+	flag.IntVar(&input.LogGroupLookupRetries, "log-group-lookup-retries", -1, "Extra lookups of a just-created log group not yet visible (default from LOG_GROUP_LOOKUP_RETRIES, or 3)")
+	var regions string
+	flag.StringVar(&regions, "regions", "", "Comma-separated regions to evaluate concurrently or preflight, e.g. ca-central-1,ca-west-1")
+	flag.StringVar(&input.RunConfigURI, "run-config", os.Getenv("RUN_CONFIG_S3_URI"), "S3 URI of a JSON or YAML run plan of rules, regions and batch sizes")
+	flag.BoolVar(&input.IncludeCompliant, "include-compliant", false, "Also list log groups Config reports compliant with the rule, with status compliant")
+	var annotationKeywords string
+	flag.StringVar(&annotationKeywords, "annotation-keywords", "", "Comma-separated keywords; only resources whose Config annotation contains one are processed, e.g. retention")
+	flag.IntVar(&input.ExitCodeOnEmpty, "exit-code-on-empty", ExitSuccess, "Exit code when a successful run finds no non-compliant resources")
+	flag.StringVar(&input.InsightsResultsPath, "insights-results", "", "Remediate the log groups in this saved Logs Insights query result JSON instead of querying Config")
+	flag.StringVar(&input.InsightsField, "insights-field", container.DefaultInsightsField, "Logs Insights result field holding the log group name")
+	flag.BoolVar(&input.KMSPreflight, "kms-preflight", false, "With --type preflight, also fail when a region's KMS key fails comprehensive validation")
 
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "LogGuardian Container - AWS Config Compliance Automation\n")
@@ -88,13 +168,26 @@ func parseCommandLineArgs() CommandInput {
 		fmt.Fprintf(os.Stderr, "  AWS_ASSUME_ROLE_ARN     IAM role ARN to assume\n")
 		fmt.Fprintf(os.Stderr, "  CONFIG_RULE_NAME        Config rule name (alternative to --config-rule)\n")
 		fmt.Fprintf(os.Stderr, "  BATCH_SIZE              Batch size for processing\n")
-		fmt.Fprintf(os.Stderr, "  DRY_RUN                 Set to 'true' for dry-run mode\n")
+		fmt.Fprintf(os.Stderr, "  DRY_RUN                 Set to 'true' for dry-run mode (an explicit --dry-run wins)\n")
+		fmt.Fprintf(os.Stderr, "  LOG_GROUP_LOOKUP_RETRIES Lookups of a just-created log group before not-found\n")
+		fmt.Fprintf(os.Stderr, "  RUN_CONFIG_S3_URI       S3 URI of a run plan (alternative to --run-config)\n")
+		fmt.Fprintf(os.Stderr, "  HEALTH_PORT             Serve /healthz and /readyz on this port (off when unset)\n")
+		fmt.Fprintf(os.Stderr, "  METRICS_ENABLED         Set to 'true' to also serve Prometheus /metrics on HEALTH_PORT\n")
 	}
 
 	flag.Parse()
 
+	// A single region is an ordinary single-region run
+	input.Regions = splitList(regions)
+	input.AnnotationKeywords = splitList(annotationKeywords)
+	input.Rules = splitList(rules)
+	if len(input.Regions) == 1 {
+		input.Region = input.Regions[0]
+		input.Regions = nil
+	}
+
 	// Check environment variables as fallback
-	if input.ConfigRuleName == "" {
+	if input.ConfigRuleName == "" && len(input.Rules) == 0 {
 		input.ConfigRuleName = os.Getenv("CONFIG_RULE_NAME")
 	}
 	if input.Region == "" {
@@ -106,14 +199,23 @@ func parseCommandLineArgs() CommandInput {
 			input.BatchSize = batchSize
 		}
 	}
-	if strings.ToLower(os.Getenv("DRY_RUN")) == "true" {
-		input.DryRun = true
+	// An explicit --dry-run, including --dry-run=false, overrides DRY_RUN
+	var dryRunFlag *bool
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "dry-run" {
+			dryRunFlag = &input.DryRun
+		}
+	})
+	input.Mode = service.ResolveExecutionMode(dryRunFlag, os.Getenv("DRY_RUN"))
+	input.DryRun = input.Mode.DryRun()
+	if strings.ToLower(os.Getenv("ALLOW_RETENTION_REDUCTION")) == "true" {
+		input.AllowRetentionReduction = true
 	}
 
 	return input
 }
 
-func execute(ctx context.Context, input CommandInput, executionID string) int {
+func execute(ctx context.Context, input CommandInput, executionID string, readiness *container.Readiness, metrics *container.ServiceMetrics) int {
 	if err := validateInput(input); err != nil {
 		slog.Error("Invalid input", "error", err, "execution_id", executionID)
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -128,20 +230,72 @@ func execute(ctx context.Context, input CommandInput, executionID string) int {
 		outputError(input.OutputFormat, executionID, "Authentication failed", err)
 		return ExitError
 	}
+	// Every client built from the config records its API calls on /metrics when it is served
+	awsCfg = container.InstrumentAWSConfig(awsCfg, metrics)
+
+	callerAccountId := ""
+	if input.VerifyCredentials {
+		identity, err := container.VerifyCredentials(ctx, sts.NewFromConfig(service.ApplyUserAgent(awsCfg)))
+		if err != nil {
+			slog.Error("Failed to verify AWS credentials", "error", err, "execution_id", executionID)
+			outputError(input.OutputFormat, executionID, "Authentication failed", err)
+			return ExitError
+		}
+		callerAccountId = identity.Account
+	}
+	if readiness != nil {
+		readiness.MarkAWSConfigLoaded()
+	}
+
+	if input.Type == TypePreflight {
+		return executePreflight(ctx, input, awsCfg, executionID, readiness)
+	}
+	if input.RunConfigURI != "" {
+		return executeRunPlan(ctx, input, awsCfg, executionID, readiness, metrics)
+	}
+	if len(input.Regions) > 1 {
+		return executeAcrossRegions(ctx, input, awsCfg, executionID, readiness, metrics)
+	}
+	validateRegionsForReadiness(ctx, awsCfg, []string{input.Region}, executionID, readiness)
+
+	var resources []types.NonCompliantResource
+	var skipped []service.SkippedResource
+	if input.InsightsResultsPath != "" {
+		resources, skipped, err = loadInsightsResources(input, callerAccountId)
+		if err != nil {
+			slog.Error("Failed to load Logs Insights results", "error", err, "insights_results", input.InsightsResultsPath, "execution_id", executionID)
+			outputError(input.OutputFormat, executionID, "Execution failed", err)
+			return ExitError
+		}
+	}
 
 	// Create the command processor
-	processor := container.NewCommandProcessor(awsCfg, container.ProcessorOptions{
-		DryRun:       input.DryRun,
-		ExecutionID:  executionID,
-		OutputFormat: input.OutputFormat,
+	processor, err := container.NewCommandProcessor(awsCfg, container.ProcessorOptions{
+		DryRun:                  input.DryRun,
+		ExecutionID:             executionID,
+		OutputFormat:            input.OutputFormat,
+		AllowRetentionReduction: input.AllowRetentionReduction,
+		LogGroupLookupRetries:   logGroupLookupRetries(input),
+		IncludeCompliant:        input.IncludeCompliant,
+		AnnotationKeywords:      input.AnnotationKeywords,
+		Role:                    input.AssumeRole,
 	})
+	if err != nil {
+		slog.Error("Failed to create command processor", "error", err, "execution_id", executionID)
+		outputError(input.OutputFormat, executionID, "Execution failed", err)
+		return ExitError
+	}
 
 	// Execute the command
 	result, err := processor.Execute(ctx, container.CommandRequest{
-		Type:           input.Type,
-		ConfigRuleName: input.ConfigRuleName,
-		Region:         input.Region,
-		BatchSize:      input.BatchSize,
+		Type:             input.Type,
+		ConfigRuleName:   input.ConfigRuleName,
+		ConfigRuleNames:  input.Rules,
+		Region:           input.Region,
+		BatchSize:        input.BatchSize,
+		LogGroupName:     input.LogGroup,
+		Resources:        resources,
+		SkippedResources: skipped,
 	})
 
 	if err != nil {
@@ -149,6 +303,7 @@ func execute(ctx context.Context, input CommandInput, executionID string) int {
 		outputError(input.OutputFormat, executionID, "Execution failed", err)
 		return ExitError
 	}
+	metrics.RecordExecution(result)
 
 	// Output the result
 	if err := outputResult(input.OutputFormat, result); err != nil {
@@ -156,19 +311,260 @@ func execute(ctx context.Context, input CommandInput, executionID string) int {
 		return ExitError
 	}
 
+	return successExitCode(input, result.Status, result.TotalProcessed)
+}
+
+// executeAcrossRegions evaluates the config rule in every --regions region concurrently and
+// outputs a single aggregated result
+func executeAcrossRegions(ctx context.Context, input CommandInput, awsCfg aws.Config, executionID string, readiness *container.Readiness, metrics *container.ServiceMetrics) int {
+	options := container.ProcessorOptions{
+		DryRun:                  input.DryRun,
+		ExecutionID:             executionID,
+		OutputFormat:            input.OutputFormat,
+		AllowRetentionReduction: input.AllowRetentionReduction,
+		LogGroupLookupRetries:   logGroupLookupRetries(input),
+		IncludeCompliant:        input.IncludeCompliant,
+		AnnotationKeywords:      input.AnnotationKeywords,
+		Role:                    input.AssumeRole,
+	}
+
+	mrs, err := container.NewMultiRegionService(ctx, awsCfg, input.Regions, options)
+	if err != nil {
+		slog.Error("Failed to configure regions", "error", err, "regions", input.Regions, "execution_id", executionID)
+		outputError(input.OutputFormat, executionID, "Execution failed", err)
+		return ExitError
+	}
+	validateRegionsForReadiness(ctx, awsCfg, input.Regions, executionID, readiness)
+
+	result, err := container.ExecuteAcrossRegions(ctx, mrs, container.CommandRequest{
+		Type:            input.Type,
+		ConfigRuleName:  input.ConfigRuleName,
+		ConfigRuleNames: input.Rules,
+		BatchSize:       input.BatchSize,
+	}, options)
+	if err != nil {
+		slog.Error("Multi-region execution failed", "error", err, "execution_id", executionID)
+	}
+	metrics.RecordExecution(result)
+
+	if outErr := outputResult(input.OutputFormat, result); outErr != nil {
+		slog.Error("Failed to output result", "error", outErr, "execution_id", executionID)
+		return ExitError
+	}
+
+	if err != nil {
+		return ExitError
+	}
+	return successExitCode(input, result.Status, result.TotalProcessed)
+}
+
+// executePreflight checks CloudWatch Logs and KMS access in the --regions regions, or the single
+// --region, and outputs one row per region; any region failing a critical check fails the run
+func executePreflight(ctx context.Context, input CommandInput, awsCfg aws.Config, executionID string, readiness *container.Readiness) int {
+	regions := input.Regions
+	if len(regions) == 0 {
+		regions = []string{input.Region}
+	}
+
+	mrs, err := container.NewMultiRegionService(ctx, awsCfg, regions, container.ProcessorOptions{
+		DryRun:      true,
+		ExecutionID: executionID,
+	})
+	if err != nil {
+		slog.Error("Failed to configure regions", "error", err, "regions", regions, "execution_id", executionID)
+		outputError(input.OutputFormat, executionID, "Preflight failed", err)
+		return ExitError
+	}
+
+	result, err := container.RunPreflight(ctx, mrs, executionID)
+	if err == nil && input.KMSPreflight {
+		err = container.RunKMSPreflight(ctx, mrs, result)
+	}
+	if err != nil {
+		slog.Error("Preflight failed", "error", err, "execution_id", executionID)
+	} else if readiness != nil {
+		readiness.MarkRegionsValidated()
+	}
+
+	if outErr := outputPreflightResult(input.OutputFormat, result); outErr != nil {
+		slog.Error("Failed to output result", "error", outErr, "execution_id", executionID)
+		return ExitError
+	}
+
+	if err != nil {
+		return ExitError
+	}
+	return ExitSuccess
+}
+
+// loadInsightsResources reads the --insights-results file and maps its --insights-field values to
+// resources in the run's region. Rows of an account other than REMEDIATION_ACCOUNT_ID, or the
+// caller's account when that is unset, are returned as skipped.
+func loadInsightsResources(input CommandInput, callerAccountId string) ([]types.NonCompliantResource, []service.SkippedResource, error) {
+	data, err := os.ReadFile(filepath.Clean(input.InsightsResultsPath))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read logs insights results: %w", err)
+	}
+	accountId := os.Getenv("REMEDIATION_ACCOUNT_ID")
+	if accountId == "" {
+		accountId = callerAccountId
+	}
+	return container.ParseInsightsResults(data, input.InsightsField, input.Region, accountId)
+}
+
+// splitList parses a comma-separated flag value such as --regions, dropping blanks and duplicates
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" && !slices.Contains(items, item) {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// executeRunPlan loads the run plan from S3 and evaluates each entry in order, with a processor
+// configured for the entry's region
+func executeRunPlan(ctx context.Context, input CommandInput, awsCfg aws.Config, executionID string, readiness *container.Readiness, metrics *container.ServiceMetrics) int {
+	entries, err := container.LoadRunPlan(ctx, s3.NewFromConfig(service.ApplyUserAgent(awsCfg)), input.RunConfigURI, input.Region)
+	if err != nil {
+		slog.Error("Failed to load run plan", "error", err, "run_config", input.RunConfigURI, "execution_id", executionID)
+		outputError(input.OutputFormat, executionID, "Run plan failed", err)
+		return ExitError
+	}
+
+	slog.Info("Loaded run plan", "run_config", input.RunConfigURI, "entries", len(entries), "execution_id", executionID)
+
+	var regions []string
+	for _, entry := range entries {
+		if !slices.Contains(regions, entry.Region) {
+			regions = append(regions, entry.Region)
+		}
+	}
+	validateRegionsForReadiness(ctx, awsCfg, regions, executionID, readiness)
+
+	result, err := container.ExecuteRunPlan(ctx, entries, executionID, func(entry container.RunPlanEntry) (*container.CommandProcessor, error) {
+		entryCfg := awsCfg.Copy()
+		entryCfg.Region = entry.Region
+		return container.NewCommandProcessor(entryCfg, container.ProcessorOptions{
+			DryRun:                  input.DryRun,
+			ExecutionID:             executionID,
+			OutputFormat:            input.OutputFormat,
+			AllowRetentionReduction: input.AllowRetentionReduction,
+			LogGroupLookupRetries:   logGroupLookupRetries(input),
+			IncludeCompliant:        input.IncludeCompliant,
+			AnnotationKeywords:      input.AnnotationKeywords,
+			Role:                    input.AssumeRole,
+		})
+	})
+	if err != nil {
+		slog.Error("Run plan execution failed", "error", err, "execution_id", executionID)
+	}
+	for _, entry := range result.Entries {
+		metrics.RecordExecution(entry)
+	}
+
+	if outErr := outputRunPlanResult(input.OutputFormat, result); outErr != nil {
+		slog.Error("Failed to output result", "error", outErr, "execution_id", executionID)
+		return ExitError
+	}
+
+	if err != nil {
+		return ExitError
+	}
+	return successExitCode(input, result.Status, result.TotalProcessed)
+}
+
+// validateRegionsForReadiness runs the region access preflight and marks the regions validated
+// on readiness only when every region passes. A failure is logged and leaves /readyz failing
+// without stopping the run. It does nothing when readiness is nil, as in one-shot runs.
+func validateRegionsForReadiness(ctx context.Context, awsCfg aws.Config, regions []string, executionID string, readiness *container.Readiness) {
+	if readiness == nil {
+		return
+	}
+
+	mrs, err := container.NewMultiRegionService(ctx, awsCfg, regions, container.ProcessorOptions{
+		DryRun:      true,
+		ExecutionID: executionID,
+	})
+	if err == nil {
+		_, err = container.RunPreflight(ctx, mrs, executionID)
+	}
+	if err != nil {
+		slog.Warn("Regions failed validation, readiness not reported", "error", err, "regions", regions, "execution_id", executionID)
+		return
+	}
+	readiness.MarkRegionsValidated()
+}
+
+// successExitCode returns the exit code of a run that returned without error: ExitError when its
+// status is failed, such as every resource failing or a cancelled run, otherwise success, using
+// --exit-code-on-empty when there were no non-compliant resources to process
+func successExitCode(input CommandInput, status string, totalProcessed int) int {
+	if status == container.ExecutionStatusFailed {
+		return ExitError
+	}
+	if totalProcessed == 0 {
+		return input.ExitCodeOnEmpty
+	}
 	return ExitSuccess
 }
 
 func validateInput(input CommandInput) error {
-	if input.Type != "config-rule-evaluation" {
+	if input.OutputFormat != "" && !slices.Contains([]string{"json", "text", "config-remediation", "junit"}, input.OutputFormat) {
+		return fmt.Errorf("unsupported output format: %s", input.OutputFormat)
+	}
+	if (input.OutputFormat == "config-remediation" || input.OutputFormat == "junit") && (input.Type == TypePreflight || input.RunConfigURI != "") {
+		return fmt.Errorf("--output %s cannot be combined with --type preflight or --run-config", input.OutputFormat)
+	}
+
+	if input.Type == TypePreflight {
+		if len(input.Regions) == 0 && input.Region == "" {
+			return fmt.Errorf("region is required (use --region, --regions, AWS_REGION, or AWS_DEFAULT_REGION env var)")
+		}
+		return nil
+	}
+
+	if input.Type != TypeConfigRuleEvaluation {
 		return fmt.Errorf("unsupported request type: %s", input.Type)
 	}
 
-	if input.ConfigRuleName == "" {
-		return fmt.Errorf("config rule name is required (use --config-rule or CONFIG_RULE_NAME env var)")
+	if input.KMSPreflight {
+		return fmt.Errorf("--kms-preflight requires --type preflight")
 	}
 
-	if input.Region == "" {
+	if input.ConfigRuleName == "" && input.RunConfigURI == "" && len(input.Rules) == 0 {
+		return fmt.Errorf("config rule name is required (use --config-rule, --rules or CONFIG_RULE_NAME env var)")
+	}
+
+	if len(input.Rules) > 0 && (input.ConfigRuleName != "" || input.LogGroup != "" || input.InsightsResultsPath != "" || input.RunConfigURI != "") {
+		return fmt.Errorf("--rules cannot be combined with --config-rule, --log-group, --insights-results or --run-config")
+	}
+
+	if input.LogGroup != "" && input.RunConfigURI == "" {
+		if input.ConfigRuleName == "" {
+			return fmt.Errorf("--log-group requires --config-rule")
+		}
+		if types.NewRuleClassifier().ClassifyRule(input.ConfigRuleName) == types.RuleTypeUnknown {
+			return fmt.Errorf("--log-group requires a config rule that checks encryption or retention, got %s", input.ConfigRuleName)
+		}
+	}
+
+	if input.InsightsResultsPath != "" {
+		if strings.TrimSpace(input.InsightsField) == "" {
+			return fmt.Errorf("--insights-field is required with --insights-results")
+		}
+		if len(input.Regions) > 1 || input.LogGroup != "" || input.RunConfigURI != "" {
+			return fmt.Errorf("--insights-results cannot be combined with --regions, --log-group or --run-config")
+		}
+	}
+
+	if len(input.Regions) > 1 {
+		if input.LogGroup != "" || input.RunConfigURI != "" {
+			return fmt.Errorf("--regions cannot be combined with --log-group or --run-config")
+		}
+	} else if input.Region == "" {
 		return fmt.Errorf("region is required (use --region, AWS_REGION, or AWS_DEFAULT_REGION env var)")
 	}
 
@@ -176,16 +572,40 @@ func validateInput(input CommandInput) error {
 		return fmt.Errorf("batch size must be between 1 and 100")
 	}
 
+	if input.LogGroupLookupRetries > 10 {
+		return fmt.Errorf("log group lookup retries must be at most 10")
+	}
+
+	if input.ExitCodeOnEmpty < 0 || input.ExitCodeOnEmpty > 125 {
+		return fmt.Errorf("exit code on empty must be between 0 and 125")
+	}
+
 	return nil
 }
 
+// logGroupLookupRetries returns the --log-group-lookup-retries override, or nil to keep the
+// service's LOG_GROUP_LOOKUP_RETRIES setting
+func logGroupLookupRetries(input CommandInput) *int32 {
+	if input.LogGroupLookupRetries < 0 {
+		return nil
+	}
+	retries := int32(input.LogGroupLookupRetries)
+	return &retries
+}
+
 func createAWSConfig(ctx context.Context, input CommandInput) (aws.Config, error) {
 	authStrategy := container.NewAuthenticationStrategy()
 
+	// Multi-region runs authenticate in the first region; each region's clients override it
+	region := input.Region
+	if region == "" && len(input.Regions) > 0 {
+		region = input.Regions[0]
+	}
+
 	options := container.AuthOptions{
 		Profile:    input.Profile,
 		AssumeRole: input.AssumeRole,
-		Region:     input.Region,
+		Region:     region,
 	}
 
 	return authStrategy.GetAWSConfig(ctx, options)
@@ -197,6 +617,21 @@ func outputResult(format string, result *container.ExecutionResult) error {
 		encoder := json.NewEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
 		return encoder.Encode(result)
+	case "config-remediation":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(container.NewConfigRemediationResult(result))
+	case "junit":
+		if _, err := fmt.Fprint(os.Stdout, xml.Header); err != nil {
+			return err
+		}
+		encoder := xml.NewEncoder(os.Stdout)
+		encoder.Indent("", "  ")
+		if err := encoder.Encode(container.NewJUnitReport(result)); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintln(os.Stdout)
+		return err
 	case "text":
 		fmt.Printf("Execution ID: %s\n", result.ExecutionID)
 		fmt.Printf("Status: %s\n", result.Status)
@@ -206,6 +641,11 @@ func outputResult(format string, result *container.ExecutionResult) error {
 		fmt.Printf("Total Processed: %d\n", result.TotalProcessed)
 		fmt.Printf("Success Count: %d\n", result.SuccessCount)
 		fmt.Printf("Failure Count: %d\n", result.FailureCount)
+		fmt.Printf("No Action Needed: %d\n", result.NoActionCount)
+		fmt.Printf("Skipped Count: %d\n", result.SkippedCount)
+		if len(result.Deferred) > 0 {
+			fmt.Printf("Deferred: %d\n", len(result.Deferred))
+		}
 		fmt.Printf("Duration: %s\n", result.Duration)
 		if result.DryRunSummary != nil {
 			fmt.Printf("\nDry Run Summary:\n")
@@ -213,12 +653,106 @@ func outputResult(format string, result *container.ExecutionResult) error {
 			fmt.Printf("  Would Apply Retention: %d\n", result.DryRunSummary.WouldApplyRetention)
 			fmt.Printf("  Already Compliant: %d\n", result.DryRunSummary.AlreadyCompliant)
 		}
+		if r := result.Reconciliation; r != nil {
+			fmt.Printf("\nReconciliation:\n")
+			fmt.Printf("  Reported: %d\n", r.Reported)
+			printReconciliationStage("After Existence Validation", r.AfterExistenceValidation)
+			printReconciliationStage("After Exclusions", r.AfterExclusions)
+			printReconciliationStage("After Allowlist", r.AfterAllowlist)
+			printReconciliationStage("After Dedup", r.AfterDedup)
+			printReconciliationStage("Processed", r.Processed)
+		}
+		if len(result.RegionResults) > 0 {
+			fmt.Printf("\nRegions:\n")
+			for _, region := range strings.Split(result.Region, ",") {
+				if r := result.RegionResults[region]; r != nil {
+					fmt.Printf("  %s: %s, processed %d, succeeded %d, failed %d, skipped %d\n", region, r.Status,
+						r.TotalProcessed, r.SuccessCount, r.FailureCount, r.SkippedCount)
+				}
+			}
+		}
+		if len(result.Resources) > 0 {
+			fmt.Printf("\nResources:\n")
+			for _, r := range result.Resources {
+				fmt.Printf("  %s: %s\n", r.ResourceName, r.Status)
+				if r.Error != "" {
+					fmt.Printf("    Error: %s\n", r.Error)
+				}
+			}
+		}
+		if len(result.Deferred) > 0 {
+			fmt.Printf("\nDeferred:\n")
+			for _, name := range result.Deferred {
+				fmt.Printf("  %s\n", name)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+func outputRunPlanResult(format string, result *container.RunPlanResult) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	case "text":
+		fmt.Printf("Execution ID: %s\n", result.ExecutionID)
+		fmt.Printf("Status: %s\n", result.Status)
+		fmt.Printf("Mode: %s\n", result.Mode)
+		fmt.Printf("Total Processed: %d\n", result.TotalProcessed)
+		fmt.Printf("Success Count: %d\n", result.SuccessCount)
+		fmt.Printf("Failure Count: %d\n", result.FailureCount)
+		fmt.Printf("No Action Needed: %d\n", result.NoActionCount)
+		fmt.Printf("Failed Entries: %d\n", result.FailedEntries)
+		fmt.Printf("Duration: %s\n", result.Duration)
+		for _, entry := range result.Entries {
+			fmt.Printf("\n%s (%s): %s, processed %d, succeeded %d, failed %d\n", entry.ConfigRuleName, entry.Region,
+				entry.Status, entry.TotalProcessed, entry.SuccessCount, entry.FailureCount)
+			if entry.Error != "" {
+				fmt.Printf("  Error: %s\n", entry.Error)
+			}
+		}
 		return nil
 	default:
 		return fmt.Errorf("unsupported output format: %s", format)
 	}
 }
 
+func outputPreflightResult(format string, result *container.PreflightResult) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	case "text":
+		fmt.Printf("Execution ID: %s\n", result.ExecutionID)
+		fmt.Printf("Status: %s\n", result.Status)
+		fmt.Printf("Failed Regions: %d\n", result.FailedRegions)
+		fmt.Printf("Duration: %s\n", result.Duration)
+		fmt.Printf("\nRegions:\n")
+		for _, region := range result.Regions {
+			if !region.Passed {
+				fmt.Printf("  %s: failed\n    Error: %s\n", region.Region, region.Error)
+				continue
+			}
+			fmt.Printf("  %s: passed, key %s %s\n", region.Region, region.Key.KeyAlias, region.Key.Status)
+			if region.Key.Error != "" {
+				fmt.Printf("    Key Error: %s\n", region.Key.Error)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+func printReconciliationStage(name string, stage container.ReconciliationStage) {
+	fmt.Printf("  %s: %d (dropped %d)\n", name, stage.Count, stage.Dropped)
+}
+
 func outputError(format, executionID, message string, err error) {
 	result := &container.ExecutionResult{
 		ExecutionID: executionID,
@@ -240,15 +774,5 @@ func outputError(format, executionID, message string, err error) {
 }
 
 func getVersion() string {
-	if version := os.Getenv("APP_VERSION"); version != "" {
-		return version
-	}
-	return "unknown"
-}
-
-func getExecutionMode(dryRun bool) string {
-	if dryRun {
-		return "dry-run"
-	}
-	return "apply"
+	return service.Version()
 }
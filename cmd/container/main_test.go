@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/zsoftly/logguardian/internal/container"
+	"github.com/zsoftly/logguardian/internal/service"
 )
 
 func TestParseCommandLineArgs(t *testing.T) {
@@ -18,22 +20,114 @@ func TestParseCommandLineArgs(t *testing.T) {
 		{
 			name: "default values",
 			args: []string{"cmd"},
+			expected: CommandInput{
+				Type:              "config-rule-evaluation",
+				BatchSize:         10,
+				OutputFormat:      "json",
+				VerifyCredentials: true,
+			},
+		},
+		{
+			name: "credential verification disabled",
+			args: []string{"cmd", "--verify-credentials=false"},
 			expected: CommandInput{
 				Type:         "config-rule-evaluation",
 				BatchSize:    10,
 				OutputFormat: "json",
 			},
 		},
+		{
+			name: "retention reduction allowed",
+			args: []string{"cmd", "--allow-retention-reduction"},
+			expected: CommandInput{
+				Type:                    "config-rule-evaluation",
+				BatchSize:               10,
+				OutputFormat:            "json",
+				VerifyCredentials:       true,
+				AllowRetentionReduction: true,
+			},
+		},
+		{
+			name: "compliant resources included",
+			args: []string{"cmd", "--include-compliant"},
+			expected: CommandInput{
+				Type:              "config-rule-evaluation",
+				BatchSize:         10,
+				OutputFormat:      "json",
+				VerifyCredentials: true,
+				IncludeCompliant:  true,
+			},
+		},
+		{
+			name: "annotation keywords",
+			args: []string{"cmd", "--annotation-keywords", "retention, kms,retention"},
+			expected: CommandInput{
+				Type:               "config-rule-evaluation",
+				BatchSize:          10,
+				OutputFormat:       "json",
+				VerifyCredentials:  true,
+				AnnotationKeywords: []string{"retention", "kms"},
+			},
+		},
+		{
+			name: "single log group",
+			args: []string{"cmd", "--config-rule", "test-rule", "--log-group", "/aws/lambda/foo"},
+			expected: CommandInput{
+				Type:              "config-rule-evaluation",
+				ConfigRuleName:    "test-rule",
+				BatchSize:         10,
+				OutputFormat:      "json",
+				VerifyCredentials: true,
+				LogGroup:          "/aws/lambda/foo",
+			},
+		},
+		{
+			name: "multiple regions",
+			args: []string{"cmd", "--config-rule", "test-rule", "--regions", "ca-central-1, ca-west-1,ca-central-1"},
+			expected: CommandInput{
+				Type:              "config-rule-evaluation",
+				ConfigRuleName:    "test-rule",
+				BatchSize:         10,
+				OutputFormat:      "json",
+				VerifyCredentials: true,
+				Regions:           []string{"ca-central-1", "ca-west-1"},
+			},
+		},
+		{
+			name: "single region in regions falls back to region",
+			args: []string{"cmd", "--config-rule", "test-rule", "--regions", "ca-west-1"},
+			expected: CommandInput{
+				Type:              "config-rule-evaluation",
+				ConfigRuleName:    "test-rule",
+				Region:            "ca-west-1",
+				BatchSize:         10,
+				OutputFormat:      "json",
+				VerifyCredentials: true,
+			},
+		},
+		{
+			name:    "run plan from environment",
+			args:    []string{"cmd"},
+			envVars: map[string]string{"RUN_CONFIG_S3_URI": "s3://plans/nightly.yaml"},
+			expected: CommandInput{
+				Type:              "config-rule-evaluation",
+				BatchSize:         10,
+				OutputFormat:      "json",
+				VerifyCredentials: true,
+				RunConfigURI:      "s3://plans/nightly.yaml",
+			},
+		},
 		{
 			name: "with command line args",
 			args: []string{"cmd", "--config-rule", "test-rule", "--region", "us-west-2", "--batch-size", "20", "--dry-run"},
 			expected: CommandInput{
-				Type:           "config-rule-evaluation",
-				ConfigRuleName: "test-rule",
-				Region:         "us-west-2",
-				BatchSize:      20,
-				DryRun:         true,
-				OutputFormat:   "json",
+				Type:              "config-rule-evaluation",
+				ConfigRuleName:    "test-rule",
+				Region:            "us-west-2",
+				BatchSize:         20,
+				DryRun:            true,
+				OutputFormat:      "json",
+				VerifyCredentials: true,
 			},
 		},
 		{
@@ -46,12 +140,13 @@ func TestParseCommandLineArgs(t *testing.T) {
 				"DRY_RUN":          "true",
 			},
 			expected: CommandInput{
-				Type:           "config-rule-evaluation",
-				ConfigRuleName: "env-rule",
-				Region:         "eu-west-1",
-				BatchSize:      30,
-				DryRun:         true,
-				OutputFormat:   "json",
+				Type:              "config-rule-evaluation",
+				ConfigRuleName:    "env-rule",
+				Region:            "eu-west-1",
+				BatchSize:         30,
+				DryRun:            true,
+				OutputFormat:      "json",
+				VerifyCredentials: true,
 			},
 		},
 		{
@@ -62,11 +157,12 @@ func TestParseCommandLineArgs(t *testing.T) {
 				"AWS_REGION":       "eu-west-1",
 			},
 			expected: CommandInput{
-				Type:           "config-rule-evaluation",
-				ConfigRuleName: "cli-rule",
-				Region:         "ap-south-1",
-				BatchSize:      10,
-				OutputFormat:   "json",
+				Type:              "config-rule-evaluation",
+				ConfigRuleName:    "cli-rule",
+				Region:            "ap-south-1",
+				BatchSize:         10,
+				OutputFormat:      "json",
+				VerifyCredentials: true,
 			},
 		},
 	}
@@ -105,6 +201,11 @@ func TestParseCommandLineArgs(t *testing.T) {
 			assert.Equal(t, tt.expected.BatchSize, result.BatchSize)
 			assert.Equal(t, tt.expected.DryRun, result.DryRun)
 			assert.Equal(t, tt.expected.OutputFormat, result.OutputFormat)
+			assert.Equal(t, tt.expected.VerifyCredentials, result.VerifyCredentials)
+			assert.Equal(t, tt.expected.AllowRetentionReduction, result.AllowRetentionReduction)
+			assert.Equal(t, tt.expected.LogGroup, result.LogGroup)
+			assert.Equal(t, tt.expected.RunConfigURI, result.RunConfigURI)
+			assert.Equal(t, tt.expected.Regions, result.Regions)
 
 			// Restore original values
 			os.Args = originalArgs
@@ -174,6 +275,16 @@ func TestValidateInput(t *testing.T) {
 			wantErr: true,
 			errMsg:  "config rule name is required",
 		},
+		{
+			name: "run plan without config rule name",
+			input: CommandInput{
+				Type:         "config-rule-evaluation",
+				Region:       "us-east-1",
+				BatchSize:    10,
+				RunConfigURI: "s3://plans/nightly.yaml",
+			},
+			wantErr: false,
+		},
 		{
 			name: "missing region",
 			input: CommandInput{
@@ -206,6 +317,216 @@ func TestValidateInput(t *testing.T) {
 			wantErr: true,
 			errMsg:  "batch size must be between 1 and 100",
 		},
+		{
+			name: "multiple regions without region",
+			input: CommandInput{
+				Type:           "config-rule-evaluation",
+				ConfigRuleName: "test-rule",
+				BatchSize:      10,
+				Regions:        []string{"ca-central-1", "ca-west-1"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "multiple regions with single log group",
+			input: CommandInput{
+				Type:           "config-rule-evaluation",
+				ConfigRuleName: "cloudwatch-log-group-retention",
+				BatchSize:      10,
+				Regions:        []string{"ca-central-1", "ca-west-1"},
+				LogGroup:       "/aws/lambda/foo",
+			},
+			wantErr: true,
+			errMsg:  "--regions cannot be combined",
+		},
+		{
+			name: "single log group with unclassified rule",
+			input: CommandInput{
+				Type:           "config-rule-evaluation",
+				ConfigRuleName: "test-rule",
+				Region:         "ca-central-1",
+				BatchSize:      10,
+				LogGroup:       "/aws/lambda/foo",
+			},
+			wantErr: true,
+			errMsg:  "checks encryption or retention",
+		},
+		{
+			name: "preflight without config rule name",
+			input: CommandInput{
+				Type:    "preflight",
+				Regions: []string{"ca-central-1", "ca-west-1"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "preflight without region",
+			input: CommandInput{
+				Type: "preflight",
+			},
+			wantErr: true,
+			errMsg:  "region is required",
+		},
+		{
+			name: "config remediation output",
+			input: CommandInput{
+				Type:           "config-rule-evaluation",
+				ConfigRuleName: "test-rule",
+				Region:         "us-east-1",
+				BatchSize:      10,
+				OutputFormat:   "config-remediation",
+			},
+			wantErr: false,
+		},
+		{
+			name: "unsupported output format",
+			input: CommandInput{
+				Type:           "config-rule-evaluation",
+				ConfigRuleName: "test-rule",
+				Region:         "us-east-1",
+				BatchSize:      10,
+				OutputFormat:   "xml",
+			},
+			wantErr: true,
+			errMsg:  "unsupported output format",
+		},
+		{
+			name: "config remediation output with run plan",
+			input: CommandInput{
+				Type:         "config-rule-evaluation",
+				Region:       "us-east-1",
+				BatchSize:    10,
+				RunConfigURI: "s3://plans/nightly.yaml",
+				OutputFormat: "config-remediation",
+			},
+			wantErr: true,
+			errMsg:  "--output config-remediation cannot be combined",
+		},
+		{
+			name: "junit output",
+			input: CommandInput{
+				Type:           "config-rule-evaluation",
+				ConfigRuleName: "test-rule",
+				Region:         "us-east-1",
+				BatchSize:      10,
+				OutputFormat:   "junit",
+			},
+			wantErr: false,
+		},
+		{
+			name: "junit output with preflight",
+			input: CommandInput{
+				Type:         "preflight",
+				Region:       "us-east-1",
+				OutputFormat: "junit",
+			},
+			wantErr: true,
+			errMsg:  "--output junit cannot be combined",
+		},
+		{
+			name: "multiple rules",
+			input: CommandInput{
+				Type:      "config-rule-evaluation",
+				Rules:     []string{"cw-lg-kms-encryption", "cw-lg-retention-min"},
+				Region:    "us-east-1",
+				BatchSize: 10,
+			},
+			wantErr: false,
+		},
+		{
+			name: "multiple rules with config rule",
+			input: CommandInput{
+				Type:           "config-rule-evaluation",
+				ConfigRuleName: "test-rule",
+				Rules:          []string{"cw-lg-kms-encryption", "cw-lg-retention-min"},
+				Region:         "us-east-1",
+				BatchSize:      10,
+			},
+			wantErr: true,
+			errMsg:  "--rules cannot be combined",
+		},
+		{
+			name: "kms preflight",
+			input: CommandInput{
+				Type:         "preflight",
+				Region:       "us-east-1",
+				KMSPreflight: true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "kms preflight without preflight type",
+			input: CommandInput{
+				Type:           "config-rule-evaluation",
+				ConfigRuleName: "test-rule",
+				Region:         "us-east-1",
+				BatchSize:      10,
+				KMSPreflight:   true,
+			},
+			wantErr: true,
+			errMsg:  "--kms-preflight requires --type preflight",
+		},
+		{
+			name: "insights results",
+			input: CommandInput{
+				Type:                "config-rule-evaluation",
+				ConfigRuleName:      "test-rule",
+				Region:              "us-east-1",
+				BatchSize:           10,
+				InsightsResultsPath: "results.json",
+				InsightsField:       "@log",
+			},
+			wantErr: false,
+		},
+		{
+			name: "insights results without field",
+			input: CommandInput{
+				Type:                "config-rule-evaluation",
+				ConfigRuleName:      "test-rule",
+				Region:              "us-east-1",
+				BatchSize:           10,
+				InsightsResultsPath: "results.json",
+			},
+			wantErr: true,
+			errMsg:  "--insights-field is required",
+		},
+		{
+			name: "insights results with multiple regions",
+			input: CommandInput{
+				Type:                "config-rule-evaluation",
+				ConfigRuleName:      "test-rule",
+				BatchSize:           10,
+				Regions:             []string{"ca-central-1", "ca-west-1"},
+				InsightsResultsPath: "results.json",
+				InsightsField:       "@log",
+			},
+			wantErr: true,
+			errMsg:  "--insights-results cannot be combined",
+		},
+		{
+			name: "log group lookup retries too large",
+			input: CommandInput{
+				Type:                  "config-rule-evaluation",
+				ConfigRuleName:        "test-rule",
+				Region:                "us-east-1",
+				BatchSize:             10,
+				LogGroupLookupRetries: 11,
+			},
+			wantErr: true,
+			errMsg:  "log group lookup retries must be at most 10",
+		},
+		{
+			name: "exit code on empty out of range",
+			input: CommandInput{
+				Type:            "config-rule-evaluation",
+				ConfigRuleName:  "test-rule",
+				Region:          "us-east-1",
+				BatchSize:       10,
+				ExitCodeOnEmpty: 126,
+			},
+			wantErr: true,
+			errMsg:  "exit code on empty must be between 0 and 125",
+		},
 	}
 
 	for _, tt := range tests {
@@ -223,6 +544,72 @@ func TestValidateInput(t *testing.T) {
 	}
 }
 
+func TestSuccessExitCode(t *testing.T) {
+	tests := []struct {
+		name            string
+		exitCodeOnEmpty int
+		status          string
+		totalProcessed  int
+		expected        int
+	}{
+		{name: "empty run defaults to success", status: container.ExecutionStatusCompleted, totalProcessed: 0, expected: ExitSuccess},
+		{name: "empty run uses configured code", exitCodeOnEmpty: 3, status: container.ExecutionStatusCompleted, totalProcessed: 0, expected: 3},
+		{name: "run with resources ignores configured code", exitCodeOnEmpty: 3, status: container.ExecutionStatusCompleted, totalProcessed: 5, expected: ExitSuccess},
+		{name: "partial run succeeds", status: container.ExecutionStatusPartial, totalProcessed: 5, expected: ExitSuccess},
+		{name: "all-failed run fails", status: container.ExecutionStatusFailed, totalProcessed: 5, expected: ExitError},
+		{name: "failed empty run ignores configured code", exitCodeOnEmpty: 3, status: container.ExecutionStatusFailed, totalProcessed: 0, expected: ExitError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := CommandInput{ExitCodeOnEmpty: tt.exitCodeOnEmpty}
+			assert.Equal(t, tt.expected, successExitCode(input, tt.status, tt.totalProcessed))
+		})
+	}
+}
+
+func TestParseCommandLineArgs_ExecutionMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		dryRun   string
+		expected service.ExecutionModeResolution
+	}{
+		{
+			name:     "flag wins over environment",
+			args:     []string{"cmd", "--dry-run=false"},
+			dryRun:   "true",
+			expected: service.ExecutionModeResolution{Mode: service.ExecutionModeApply, Source: service.ExecutionModeSourceFlag},
+		},
+		{
+			name:     "environment without flag",
+			args:     []string{"cmd"},
+			dryRun:   "true",
+			expected: service.ExecutionModeResolution{Mode: service.ExecutionModeDryRun, Source: service.ExecutionModeSourceEnv},
+		},
+		{
+			name:     "default",
+			args:     []string{"cmd"},
+			expected: service.ExecutionModeResolution{Mode: service.ExecutionModeApply, Source: service.ExecutionModeSourceDefault},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DRY_RUN", tt.dryRun)
+			originalArgs := os.Args
+			t.Cleanup(func() { os.Args = originalArgs })
+			os.Args = tt.args
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+
+			input := parseCommandLineArgs()
+
+			assert.Equal(t, tt.expected, input.Mode)
+			assert.Equal(t, tt.expected.DryRun(), input.DryRun)
+		})
+	}
+}
+
 func TestGetVersion(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -262,28 +649,3 @@ func TestGetVersion(t *testing.T) {
 		})
 	}
 }
-
-func TestGetExecutionMode(t *testing.T) {
-	tests := []struct {
-		name     string
-		dryRun   bool
-		expected string
-	}{
-		{
-			name:     "dry-run mode",
-			dryRun:   true,
-			expected: "dry-run",
-		},
-		{
-			name:     "apply mode",
-			dryRun:   false,
-			expected: "apply",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			assert.Equal(t, tt.expected, getExecutionMode(tt.dryRun))
-		})
-	}
-}
@@ -2,23 +2,29 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"os"
+	"runtime/debug"
 
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-lambda-go/lambdacontext"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/zsoftly/logguardian/internal/handler"
+	"github.com/zsoftly/logguardian/internal/logging"
 	"github.com/zsoftly/logguardian/internal/service"
 	"github.com/zsoftly/logguardian/internal/types"
 )
 
 func main() {
-	// Set up structured logging with JSON output for Lambda
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+	// Set up structured logging with JSON output for Lambda, stamped with any LOG_LABELS and with
+	// repeated audit logs collapsed when LOG_DEDUP_AUDIT is set
+	dedup := logging.NewDedupHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
 		Level: slog.LevelInfo,
-	}))
-	slog.SetDefault(logger)
+	}), logging.DedupEnabledFromEnv())
+	slog.SetDefault(logging.WithEnvLabels(slog.New(dedup)))
 
 	// Load AWS configuration
 	cfg, err := config.LoadDefaultConfig(context.TODO())
@@ -27,37 +33,108 @@ func main() {
 		panic(err)
 	}
 
+	// The Lambda takes no flags, so DRY_RUN alone decides the execution mode
+	mode := service.ResolveExecutionMode(nil, os.Getenv("DRY_RUN"))
+	slog.Info("Starting LogGuardian Lambda",
+		"version", service.Version(),
+		"mode", mode.Mode,
+		"mode_source", mode.Source)
+
 	// Create services
-	complianceService := service.NewComplianceService(cfg)
+	complianceService, err := service.NewCheckedComplianceService(cfg, service.WithDryRun(mode.DryRun()))
+	if err != nil {
+		slog.Error("Failed to create compliance service", "error", err)
+		panic(err)
+	}
 
-	// Create handler
-	h := handler.NewComplianceHandler(complianceService)
+	// Create handler. Redelivered Config events are skipped using an in-memory store, or a
+	// DynamoDB table shared by all Lambda environments when IDEMPOTENCY_TABLE_NAME is set.
+	var handlerOpts []handler.ComplianceHandlerOption
+	if tableName := os.Getenv("IDEMPOTENCY_TABLE_NAME"); tableName != "" {
+		store := handler.NewDynamoDBIdempotencyStore(dynamodb.NewFromConfig(service.ApplyUserAgent(cfg)), tableName, handler.DefaultIdempotencyTTL)
+		handlerOpts = append(handlerOpts, handler.WithIdempotencyStore(store))
+	}
+	h := handler.NewComplianceHandler(complianceService, handlerOpts...)
+
+	// AGGREGATION_WINDOW_MS buffers Config events across the invocations of a warm environment and
+	// remediates them together. Events still buffered at shutdown are flushed on SIGTERM, which
+	// Lambda only sends when an extension is registered.
+	aggregator := handler.NewEventAggregatorFromEnvironment(h)
+	var startOpts []lambda.Option
+	if aggregator != nil {
+		startOpts = append(startOpts, lambda.WithEnableSIGTERM(func() {
+			if err := aggregator.Close(context.Background()); err != nil {
+				slog.Error("Failed to flush aggregated Config events at shutdown", "error", err)
+			}
+		}))
+	}
+
+	// Start Lambda with unified handler. By default the run report is returned as the response
+	// payload so synchronous callers such as Step Functions can branch on it; RUN_REPORT_RESPONSE=false
+	// keeps the original void-return behaviour.
+	if os.Getenv("RUN_REPORT_RESPONSE") == "false" {
+		lambda.StartWithOptions(func(ctx context.Context, request types.LambdaRequest) error {
+			defer dedup.Flush(ctx)
+			return handleUnifiedRequest(ctx, h, aggregator, request)
+		}, startOpts...)
+		return
+	}
+	lambda.StartWithOptions(func(ctx context.Context, request types.LambdaRequest) (types.RunReport, error) {
+		defer dedup.Flush(ctx)
+		return handleUnifiedRequestWithReport(ctx, h, aggregator, request)
+	}, startOpts...)
+}
 
-	// Start Lambda with unified handler
-	lambda.Start(func(ctx context.Context, request types.LambdaRequest) error {
-		return handleUnifiedRequest(ctx, h, request)
-	})
+// handleUnifiedRequest routes requests to the appropriate handler for callers that only need an error
+func handleUnifiedRequest(ctx context.Context, h *handler.ComplianceHandler, aggregator *handler.EventAggregator, request types.LambdaRequest) error {
+	_, err := handleUnifiedRequestWithReport(ctx, h, aggregator, request)
+	return err
 }
 
-// handleUnifiedRequest routes requests to the appropriate handler based on request type
-func handleUnifiedRequest(ctx context.Context, h *handler.ComplianceHandler, request types.LambdaRequest) error {
+// handleUnifiedRequestWithReport routes requests to the appropriate handler based on request type
+// and returns a report of the outcome. Config events go to aggregator instead when it is not nil.
+// A panic is logged with its stack trace and returned as an error so the runtime records a normal
+// failed invocation instead of crashing.
+func handleUnifiedRequestWithReport(ctx context.Context, h *handler.ComplianceHandler, aggregator *handler.EventAggregator, request types.LambdaRequest) (report types.RunReport, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Recovered from panic while handling Lambda request",
+				"type", request.Type,
+				"config_rule", request.ConfigRuleName,
+				"region", request.Region,
+				"panic", fmt.Sprint(r),
+				"stack", string(debug.Stack()),
+				"audit_action", "handler_panic")
+			report = types.RunReport{}
+			err = fmt.Errorf("panic while handling %q request: %v", request.Type, r)
+		}
+	}()
+
 	slog.Info("Received Lambda request", "type", request.Type)
 
+	// Audit records and evaluation annotations attribute the changes to the invocation
+	if lc, ok := lambdacontext.FromContext(ctx); ok {
+		ctx = service.ContextWithExecutionID(ctx, lc.AwsRequestID)
+	}
+
 	switch request.Type {
 	case "config-event":
 		// Handle individual Config rule evaluation events
 		if request.ConfigEvent == nil {
-			return fmt.Errorf("configEvent is required for type 'config-event'")
+			return types.RunReport{}, fmt.Errorf("configEvent is required for type 'config-event'")
 		}
-		return h.HandleConfigEvent(ctx, request.ConfigEvent)
+		if aggregator != nil {
+			return bufferConfigEvent(ctx, aggregator, request.ConfigEvent)
+		}
+		return h.HandleConfigEventWithReport(ctx, request.ConfigEvent)
 
 	case "config-rule-evaluation":
 		// Handle batch Config rule evaluation requests
 		if request.ConfigRuleName == "" {
-			return fmt.Errorf("configRuleName is required for type 'config-rule-evaluation'")
+			return types.RunReport{}, fmt.Errorf("configRuleName is required for type 'config-rule-evaluation'")
 		}
 		if request.Region == "" {
-			return fmt.Errorf("region is required for type 'config-rule-evaluation'")
+			return types.RunReport{}, fmt.Errorf("region is required for type 'config-rule-evaluation'")
 		}
 
 		batchSize := request.BatchSize
@@ -65,9 +142,20 @@ func handleUnifiedRequest(ctx context.Context, h *handler.ComplianceHandler, req
 			batchSize = 10 // Default batch size
 		}
 
-		return h.HandleConfigRuleEvaluationRequest(ctx, request.ConfigRuleName, request.Region, batchSize)
+		return h.HandleConfigRuleEvaluationRequestWithReport(ctx, request.ConfigRuleName, request.Region, batchSize)
 
 	default:
-		return fmt.Errorf("unsupported request type: %s (supported types: 'config-event', 'config-rule-evaluation')", request.Type)
+		return types.RunReport{}, fmt.Errorf("unsupported request type: %s (supported types: 'config-event', 'config-rule-evaluation')", request.Type)
+	}
+}
+
+// bufferConfigEvent adds a Config event to the aggregator. The event is remediated by a later
+// flush, so the report only records whether it was accepted.
+func bufferConfigEvent(ctx context.Context, aggregator *handler.EventAggregator, event json.RawMessage) (types.RunReport, error) {
+	report := types.RunReport{RequestType: "config-event", Total: 1, Status: "buffered"}
+	if err := aggregator.Add(ctx, event); err != nil {
+		report.Status = "failed"
+		return report, err
 	}
+	return report, nil
 }
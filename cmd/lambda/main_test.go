@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zsoftly/logguardian/internal/handler"
+	"github.com/zsoftly/logguardian/internal/service"
+	"github.com/zsoftly/logguardian/internal/testutil"
+	"github.com/zsoftly/logguardian/internal/types"
+)
+
+// batchComplianceService returns a fixed batch result for the batch path
+type batchComplianceService struct {
+	service.ComplianceServiceInterface
+	resources   []types.NonCompliantResource
+	result      *types.BatchRemediationResult
+	executionID string // Execution ID the batch's context carried
+}
+
+func (b *batchComplianceService) GetNonCompliantResources(ctx context.Context, configRuleName, region string) ([]types.NonCompliantResource, error) {
+	return b.resources, nil
+}
+
+func (b *batchComplianceService) ValidateResourceExistence(ctx context.Context, resources []types.NonCompliantResource) ([]types.NonCompliantResource, error) {
+	return resources, nil
+}
+
+func (b *batchComplianceService) ProcessNonCompliantResourcesOptimized(ctx context.Context, request types.BatchComplianceRequest) (*types.BatchRemediationResult, error) {
+	b.executionID = service.ExecutionIDFromContext(ctx)
+	return b.result, nil
+}
+
+// panickingComplianceService panics on the first call the batch path makes
+type panickingComplianceService struct {
+	service.ComplianceServiceInterface
+}
+
+func (p *panickingComplianceService) GetNonCompliantResources(ctx context.Context, configRuleName, region string) ([]types.NonCompliantResource, error) {
+	panic("simulated handler bug")
+}
+
+func TestHandleUnifiedRequest_RecoversFromPanic(t *testing.T) {
+	logger, logs := testutil.CaptureLogs(t)
+	previous := slog.Default()
+	slog.SetDefault(logger)
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	h := handler.NewComplianceHandler(&panickingComplianceService{})
+
+	err := handleUnifiedRequest(context.Background(), h, nil, types.LambdaRequest{
+		Type:           "config-rule-evaluation",
+		ConfigRuleName: "logguardian-retention",
+		Region:         "ca-central-1",
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "simulated handler bug")
+
+	records := logs.WithAttr("audit_action", "handler_panic")
+	require.Len(t, records, 1)
+	assert.Equal(t, "simulated handler bug", records[0].Attrs["panic"])
+	stack, _ := records[0].Attrs["stack"].(string)
+	assert.True(t, strings.Contains(stack, "GetNonCompliantResources"), "expected stack trace to include the panicking call")
+}
+
+func TestHandleUnifiedRequestWithReport_ReturnsResourceOutcomes(t *testing.T) {
+	h := handler.NewComplianceHandler(&batchComplianceService{
+		resources: testutil.NewNonCompliantResources(2),
+		result: &types.BatchRemediationResult{
+			TotalProcessed: 2,
+			SuccessCount:   1,
+			FailureCount:   1,
+			Results: []types.RemediationResult{
+				{LogGroupName: "/aws/lambda/test-0", Region: "ca-central-1", RetentionApplied: true, Success: true},
+				{LogGroupName: "/aws/lambda/test-1", Region: "ca-central-1", Error: errors.New("AccessDeniedException")},
+			},
+		},
+	})
+
+	report, err := handleUnifiedRequestWithReport(context.Background(), h, nil, types.LambdaRequest{
+		Type:           "config-rule-evaluation",
+		ConfigRuleName: "logguardian-retention",
+		Region:         "ca-central-1",
+	})
+	require.NoError(t, err)
+
+	payload, err := json.Marshal(report)
+	require.NoError(t, err)
+
+	var decoded struct {
+		Resources []struct {
+			LogGroupName     string `json:"logGroupName"`
+			Status           string `json:"status"`
+			RetentionApplied bool   `json:"retentionApplied"`
+			Error            string `json:"error"`
+		} `json:"resources"`
+	}
+	require.NoError(t, json.Unmarshal(payload, &decoded))
+	require.Len(t, decoded.Resources, 2)
+	assert.Equal(t, "/aws/lambda/test-0", decoded.Resources[0].LogGroupName)
+	assert.Equal(t, "success", decoded.Resources[0].Status)
+	assert.True(t, decoded.Resources[0].RetentionApplied)
+	assert.Equal(t, "/aws/lambda/test-1", decoded.Resources[1].LogGroupName)
+	assert.Equal(t, "failed", decoded.Resources[1].Status)
+	assert.Equal(t, "AccessDeniedException", decoded.Resources[1].Error)
+}
+
+// recordingBatchService counts the batch remediations an aggregator flushes
+type recordingBatchService struct {
+	service.ComplianceServiceInterface
+	batches atomic.Int32
+}
+
+func (r *recordingBatchService) ProcessNonCompliantResourcesOptimized(ctx context.Context, request types.BatchComplianceRequest) (*types.BatchRemediationResult, error) {
+	r.batches.Add(1)
+	result := &types.BatchRemediationResult{TotalProcessed: len(request.NonCompliantResults)}
+	for _, resource := range request.NonCompliantResults {
+		result.SuccessCount++
+		result.Results = append(result.Results, types.RemediationResult{LogGroupName: resource.ResourceName, Success: true})
+	}
+	return result, nil
+}
+
+func TestHandleUnifiedRequestWithReport_AggregationWindowBuffersConfigEvents(t *testing.T) {
+	event, err := json.Marshal(types.ConfigEvent{
+		ConfigRuleName: "cloudwatch-log-group-encrypted",
+		AccountId:      "123456789012",
+		ConfigRuleInvokingEvent: types.ConfigRuleInvokingEvent{
+			ConfigurationItem: types.ConfigurationItem{
+				ResourceType:            "AWS::Logs::LogGroup",
+				ResourceId:              "/aws/lambda/buffered",
+				ResourceName:            "/aws/lambda/buffered",
+				AwsRegion:               "ca-central-1",
+				AwsAccountId:            "123456789012",
+				ConfigurationItemStatus: "OK",
+				Configuration:           types.LogGroupConfiguration{LogGroupName: "/aws/lambda/buffered"},
+			},
+		},
+	})
+	require.NoError(t, err)
+	request := types.LambdaRequest{Type: "config-event", ConfigEvent: event}
+
+	t.Run("unset window handles events immediately", func(t *testing.T) {
+		t.Setenv("AGGREGATION_WINDOW_MS", "")
+		assert.Nil(t, handler.NewEventAggregatorFromEnvironment(handler.NewComplianceHandler(&recordingBatchService{})))
+	})
+
+	t.Run("window buffers events until the aggregator flushes", func(t *testing.T) {
+		t.Setenv("AGGREGATION_WINDOW_MS", "60000")
+		complianceService := &recordingBatchService{}
+		aggregator := handler.NewEventAggregatorFromEnvironment(handler.NewComplianceHandler(complianceService))
+		require.NotNil(t, aggregator)
+
+		report, err := handleUnifiedRequestWithReport(context.Background(), nil, aggregator, request)
+		require.NoError(t, err)
+		assert.Equal(t, "buffered", report.Status)
+		assert.Equal(t, 1, report.Total)
+		assert.Zero(t, complianceService.batches.Load(), "Expected the event to wait for the window")
+
+		require.NoError(t, aggregator.Close(context.Background()))
+		assert.Equal(t, int32(1), complianceService.batches.Load())
+	})
+}
+
+func TestHandleUnifiedRequestWithReport_PassesRequestIDAsExecutionID(t *testing.T) {
+	complianceService := &batchComplianceService{
+		resources: testutil.NewNonCompliantResources(1),
+		result:    &types.BatchRemediationResult{TotalProcessed: 1, SuccessCount: 1},
+	}
+	h := handler.NewComplianceHandler(complianceService)
+
+	ctx := lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "request-1"})
+	_, err := handleUnifiedRequestWithReport(ctx, h, nil, types.LambdaRequest{
+		Type:           "config-rule-evaluation",
+		ConfigRuleName: "logguardian-retention",
+		Region:         "ca-central-1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "request-1", complianceService.executionID)
+}
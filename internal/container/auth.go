@@ -244,3 +244,36 @@ func isEC2Instance() bool {
 	// Check IMDSv2 endpoint availability would be done by SDK
 	return false
 }
+
+// CallerIdentityClient is the subset of STS used to verify credentials
+type CallerIdentityClient interface {
+	GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error)
+}
+
+// CallerIdentity describes the principal behind the resolved credentials
+type CallerIdentity struct {
+	Account string
+	Arn     string
+	UserId  string
+}
+
+// VerifyCredentials confirms the resolved credentials work before any processing starts,
+// so a misconfigured environment fails fast with an authentication error
+func VerifyCredentials(ctx context.Context, client CallerIdentityClient) (*CallerIdentity, error) {
+	output, err := client.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, fmt.Errorf("credential verification failed: %w", err)
+	}
+
+	identity := &CallerIdentity{
+		Account: aws.ToString(output.Account),
+		Arn:     aws.ToString(output.Arn),
+		UserId:  aws.ToString(output.UserId),
+	}
+
+	slog.Info("Verified AWS credentials",
+		"account", identity.Account,
+		"arn", identity.Arn)
+
+	return identity, nil
+}
@@ -2,9 +2,12 @@ package container
 
 import (
 	"context"
+	"errors"
 	"os"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -306,3 +309,47 @@ func restoreEnvironment(saved map[string]string) {
 		}
 	}
 }
+
+// mockCallerIdentityClient returns a fixed STS identity or error
+type mockCallerIdentityClient struct {
+	output *sts.GetCallerIdentityOutput
+	err    error
+	calls  int
+}
+
+func (m *mockCallerIdentityClient) GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error) {
+	m.calls++
+	return m.output, m.err
+}
+
+func TestVerifyCredentials(t *testing.T) {
+	t.Run("valid credentials resolve identity", func(t *testing.T) {
+		client := &mockCallerIdentityClient{
+			output: &sts.GetCallerIdentityOutput{
+				Account: aws.String("123456789012"),
+				Arn:     aws.String("arn:aws:sts::123456789012:assumed-role/logguardian/task"),
+				UserId:  aws.String("AROAEXAMPLE:task"),
+			},
+		}
+
+		identity, err := VerifyCredentials(context.Background(), client)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "123456789012", identity.Account)
+		assert.Equal(t, "arn:aws:sts::123456789012:assumed-role/logguardian/task", identity.Arn)
+		assert.Equal(t, 1, client.calls)
+	})
+
+	t.Run("invalid credentials fail fast", func(t *testing.T) {
+		client := &mockCallerIdentityClient{
+			err: errors.New("InvalidClientTokenId: The security token included in the request is invalid"),
+		}
+
+		identity, err := VerifyCredentials(context.Background(), client)
+
+		assert.Error(t, err)
+		assert.Nil(t, identity)
+		assert.Contains(t, err.Error(), "credential verification failed")
+		assert.Contains(t, err.Error(), "InvalidClientTokenId")
+	})
+}
@@ -0,0 +1,70 @@
+package container
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// callAttemptsKey holds the attempt counter of the API call in progress
+type callAttemptsKey struct{}
+
+// InstrumentAWSConfig returns a copy of cfg whose clients record every API call in metrics: each
+// operation counts once as a successful or failed call, each attempt after the first as a retry,
+// and each throttled attempt as a throttle. A nil metrics returns cfg unchanged.
+func InstrumentAWSConfig(cfg aws.Config, metrics *ServiceMetrics) aws.Config {
+	if metrics == nil {
+		return cfg
+	}
+
+	cfg = cfg.Copy()
+	apiOptions := make([]func(*middleware.Stack) error, 0, len(cfg.APIOptions)+1)
+	apiOptions = append(apiOptions, cfg.APIOptions...)
+	cfg.APIOptions = append(apiOptions, func(stack *middleware.Stack) error {
+		return addCallMetricsMiddleware(stack, metrics)
+	})
+	return cfg
+}
+
+// addCallMetricsMiddleware counts the operation in the initialize step, which runs once per call,
+// and its attempts in the finalize step after the SDK's retry middleware, which runs per attempt
+func addCallMetricsMiddleware(stack *middleware.Stack, metrics *ServiceMetrics) error {
+	operation := middleware.InitializeMiddlewareFunc("LogGuardianCallMetrics", func(
+		ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler,
+	) (middleware.InitializeOutput, middleware.Metadata, error) {
+		var attempts int64
+		out, metadata, err := next.HandleInitialize(context.WithValue(ctx, callAttemptsKey{}, &attempts), in)
+		if err != nil {
+			metrics.RecordFailure()
+		} else {
+			metrics.RecordSuccess()
+		}
+		for range atomic.LoadInt64(&attempts) - 1 {
+			metrics.RecordRetry()
+		}
+		return out, metadata, err
+	})
+	if err := stack.Initialize.Add(operation, middleware.Before); err != nil {
+		return err
+	}
+
+	attempt := middleware.FinalizeMiddlewareFunc("LogGuardianCallAttemptMetrics", func(
+		ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler,
+	) (middleware.FinalizeOutput, middleware.Metadata, error) {
+		if attempts, ok := ctx.Value(callAttemptsKey{}).(*int64); ok {
+			atomic.AddInt64(attempts, 1)
+		}
+		out, metadata, err := next.HandleFinalize(ctx, in)
+		if isThrottlingError(err) {
+			metrics.RecordThrottle()
+		}
+		return out, metadata, err
+	})
+	// Clients without the SDK retry middleware still count attempts at the end of the step
+	if err := stack.Finalize.Insert(attempt, "Retry", middleware.After); err != nil {
+		return stack.Finalize.Add(attempt, middleware.After)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package container
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubHTTPClient answers each request with the next status and JSON body in responses
+type stubHTTPClient struct {
+	responses []stubResponse
+	requests  int
+}
+
+type stubResponse struct {
+	status int
+	body   string
+}
+
+func (c *stubHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	response := c.responses[min(c.requests, len(c.responses)-1)]
+	c.requests++
+	return &http.Response{
+		StatusCode: response.status,
+		Header:     http.Header{"Content-Type": []string{"application/x-amz-json-1.1"}},
+		Body:       io.NopCloser(strings.NewReader(response.body)),
+		Request:    req,
+	}, nil
+}
+
+func TestInstrumentAWSConfig(t *testing.T) {
+	throttled := stubResponse{status: http.StatusBadRequest, body: `{"__type":"ThrottlingException","message":"Rate exceeded"}`}
+	denied := stubResponse{status: http.StatusBadRequest, body: `{"__type":"AccessDeniedException","message":"not authorized"}`}
+	ok := stubResponse{status: http.StatusOK, body: `{"logGroups":[]}`}
+	httpClient := &stubHTTPClient{responses: []stubResponse{throttled, ok, denied}}
+
+	metrics := &ServiceMetrics{}
+	cfg := InstrumentAWSConfig(aws.Config{
+		Region:      "ca-central-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+		HTTPClient:  httpClient,
+		Retryer: func() aws.Retryer {
+			return retry.NewStandard(func(o *retry.StandardOptions) {
+				o.MaxAttempts = 2
+				o.Backoff = retry.BackoffDelayerFunc(func(int, error) (time.Duration, error) { return 0, nil })
+			})
+		},
+	}, metrics)
+	client := cloudwatchlogs.NewFromConfig(cfg)
+
+	// The first call succeeds after a throttled attempt; the second fails outright
+	_, err := client.DescribeLogGroups(context.Background(), &cloudwatchlogs.DescribeLogGroupsInput{})
+	require.NoError(t, err)
+	_, err = client.DescribeLogGroups(context.Background(), &cloudwatchlogs.DescribeLogGroupsInput{})
+	require.Error(t, err)
+
+	assert.Equal(t, 3, httpClient.requests)
+	assert.Equal(t, int64(2), metrics.TotalCalls)
+	assert.Equal(t, int64(1), metrics.SuccessfulCalls)
+	assert.Equal(t, int64(1), metrics.FailedCalls)
+	assert.Equal(t, int64(1), metrics.RetryCount)
+	assert.Equal(t, int64(1), metrics.ThrottleCount)
+}
+
+func TestInstrumentAWSConfig_NilMetrics(t *testing.T) {
+	cfg := aws.Config{Region: "ca-central-1"}
+	assert.Empty(t, InstrumentAWSConfig(cfg, nil).APIOptions)
+}
@@ -0,0 +1,134 @@
+package container
+
+import (
+	"slices"
+	"time"
+)
+
+// Remediation execution states as reported by AWS Config
+const (
+	ConfigRemediationStateQueued    = "QUEUED"
+	ConfigRemediationStateSucceeded = "SUCCEEDED"
+	ConfigRemediationStateFailed    = "FAILED"
+)
+
+// ConfigRemediationStepStatePending is the step state of a queued execution; Config step states
+// are SUCCEEDED, PENDING and FAILED
+const ConfigRemediationStepStatePending = "PENDING"
+
+// configRemediationResourceType is the Config resource type of every remediated resource
+const configRemediationResourceType = "AWS::Logs::LogGroup"
+
+// configRemediationStepName names the single step reported for each resource
+const configRemediationStepName = "LogGuardianRemediation"
+
+// ConfigRemediationResult mirrors the DescribeRemediationExecutionStatus response of AWS Config,
+// so tooling built on Config's remediation framework can read LogGuardian results
+type ConfigRemediationResult struct {
+	ConfigRuleName               string                             `json:"ConfigRuleName"`
+	RemediationExecutionStatuses []ConfigRemediationExecutionStatus `json:"RemediationExecutionStatuses"`
+}
+
+// ConfigRemediationExecutionStatus is the remediation outcome of a single resource
+type ConfigRemediationExecutionStatus struct {
+	ResourceKey     ConfigRemediationResourceKey `json:"ResourceKey"`
+	State           string                       `json:"State"`
+	StepDetails     []ConfigRemediationStep      `json:"StepDetails"`
+	InvocationTime  time.Time                    `json:"InvocationTime"`
+	LastUpdatedTime time.Time                    `json:"LastUpdatedTime"`
+}
+
+// ConfigRemediationResourceKey identifies a remediated resource
+type ConfigRemediationResourceKey struct {
+	ResourceType string `json:"resourceType"`
+	ResourceID   string `json:"resourceId"`
+}
+
+// ConfigRemediationStep is a step of a remediation execution
+type ConfigRemediationStep struct {
+	Name         string    `json:"Name"`
+	State        string    `json:"State"`
+	ErrorMessage string    `json:"ErrorMessage,omitempty"`
+	StartTime    time.Time `json:"StartTime"`
+	StopTime     time.Time `json:"StopTime"`
+}
+
+// NewConfigRemediationResult maps every resource of result, including those of each region of a
+// multi-region run, into Config's remediation execution status format
+func NewConfigRemediationResult(result *ExecutionResult) *ConfigRemediationResult {
+	output := &ConfigRemediationResult{
+		ConfigRuleName:               result.ConfigRuleName,
+		RemediationExecutionStatuses: []ConfigRemediationExecutionStatus{},
+	}
+
+	results := []*ExecutionResult{result}
+	regions := make([]string, 0, len(result.RegionResults))
+	for region := range result.RegionResults {
+		regions = append(regions, region)
+	}
+	slices.Sort(regions)
+	for _, region := range regions {
+		results = append(results, result.RegionResults[region])
+	}
+
+	for _, r := range results {
+		for _, resource := range r.Resources {
+			output.RemediationExecutionStatuses = append(output.RemediationExecutionStatuses,
+				newConfigRemediationExecutionStatus(resource, r.Timestamp))
+		}
+		// Resources deferred by the soft time budget were never started and are left for the next run
+		for _, logGroupName := range r.Deferred {
+			output.RemediationExecutionStatuses = append(output.RemediationExecutionStatuses,
+				newConfigRemediationExecutionStatus(ResourceResult{
+					ResourceID: logGroupName,
+					Status:     "deferred",
+					Timestamp:  r.Timestamp,
+				}, r.Timestamp))
+		}
+	}
+	return output
+}
+
+// newConfigRemediationExecutionStatus maps a resource result invoked at invocationTime
+func newConfigRemediationExecutionStatus(resource ResourceResult, invocationTime time.Time) ConfigRemediationExecutionStatus {
+	state := configRemediationState(resource.Status)
+	stepState := state
+	if state == ConfigRemediationStateQueued {
+		stepState = ConfigRemediationStepStatePending
+	}
+	errorMessage := resource.Error
+	if errorMessage == "" && resource.Status == "skipped" {
+		errorMessage = "skipped: " + resource.SkipReason
+	}
+	return ConfigRemediationExecutionStatus{
+		ResourceKey: ConfigRemediationResourceKey{
+			ResourceType: configRemediationResourceType,
+			ResourceID:   resource.ResourceID,
+		},
+		State: state,
+		StepDetails: []ConfigRemediationStep{{
+			Name:         configRemediationStepName,
+			State:        stepState,
+			ErrorMessage: errorMessage,
+			StartTime:    invocationTime,
+			StopTime:     resource.Timestamp,
+		}},
+		InvocationTime:  invocationTime,
+		LastUpdatedTime: resource.Timestamp,
+	}
+}
+
+// configRemediationState maps a ResourceResult status to a Config remediation state. Dry-run and
+// deferred resources were not changed yet, so they remain queued; a skipped resource had a
+// required change withheld and is still non-compliant, so it failed. An unknown status is never
+// reported as a success.
+func configRemediationState(status string) string {
+	switch status {
+	case "success", "no-action", "compliant":
+		return ConfigRemediationStateSucceeded
+	case "dry-run", "deferred":
+		return ConfigRemediationStateQueued
+	default:
+		return ConfigRemediationStateFailed
+	}
+}
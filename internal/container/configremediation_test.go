@@ -0,0 +1,91 @@
+package container
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConfigRemediationResult(t *testing.T) {
+	invoked := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	finished := invoked.Add(2 * time.Second)
+
+	result := &ExecutionResult{
+		ConfigRuleName: "cw-lg-kms-encryption",
+		Timestamp:      invoked,
+		Resources: []ResourceResult{
+			{ResourceID: "/aws/lambda/ok", ResourceName: "/aws/lambda/ok", Status: "success", Timestamp: finished},
+			{ResourceID: "/aws/lambda/bad", ResourceName: "/aws/lambda/bad", Status: "failed", Error: "AccessDeniedException", Timestamp: finished},
+		},
+	}
+
+	data, err := json.Marshal(NewConfigRemediationResult(result))
+	require.NoError(t, err)
+
+	var output map[string]any
+	require.NoError(t, json.Unmarshal(data, &output))
+	assert.Equal(t, "cw-lg-kms-encryption", output["ConfigRuleName"])
+
+	statuses := output["RemediationExecutionStatuses"].([]any)
+	require.Len(t, statuses, 2)
+
+	succeeded := statuses[0].(map[string]any)
+	assert.Equal(t, map[string]any{"resourceType": "AWS::Logs::LogGroup", "resourceId": "/aws/lambda/ok"}, succeeded["ResourceKey"])
+	assert.Equal(t, "SUCCEEDED", succeeded["State"])
+	assert.Equal(t, "2025-06-01T12:00:00Z", succeeded["InvocationTime"])
+	assert.Equal(t, "2025-06-01T12:00:02Z", succeeded["LastUpdatedTime"])
+	succeededStep := succeeded["StepDetails"].([]any)[0].(map[string]any)
+	assert.Equal(t, "SUCCEEDED", succeededStep["State"])
+	assert.NotContains(t, succeededStep, "ErrorMessage")
+
+	failed := statuses[1].(map[string]any)
+	assert.Equal(t, map[string]any{"resourceType": "AWS::Logs::LogGroup", "resourceId": "/aws/lambda/bad"}, failed["ResourceKey"])
+	assert.Equal(t, "FAILED", failed["State"])
+	failedStep := failed["StepDetails"].([]any)[0].(map[string]any)
+	assert.Equal(t, "FAILED", failedStep["State"])
+	assert.Equal(t, "AccessDeniedException", failedStep["ErrorMessage"])
+}
+
+func TestNewConfigRemediationResult_RegionResults(t *testing.T) {
+	result := &ExecutionResult{
+		ConfigRuleName: "cw-lg-kms-encryption",
+		RegionResults: map[string]*ExecutionResult{
+			"ca-west-1":    {Resources: []ResourceResult{{ResourceID: "/aws/lambda/west", Status: "dry-run"}}},
+			"ca-central-1": {Resources: []ResourceResult{{ResourceID: "/aws/lambda/central", Status: "compliant"}}},
+		},
+	}
+
+	statuses := NewConfigRemediationResult(result).RemediationExecutionStatuses
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "/aws/lambda/central", statuses[0].ResourceKey.ResourceID)
+	assert.Equal(t, ConfigRemediationStateSucceeded, statuses[0].State)
+	assert.Equal(t, "/aws/lambda/west", statuses[1].ResourceKey.ResourceID)
+	assert.Equal(t, ConfigRemediationStateQueued, statuses[1].State)
+}
+
+func TestNewConfigRemediationResult_SkippedAndDeferred(t *testing.T) {
+	result := &ExecutionResult{
+		ConfigRuleName: "cw-lg-kms-encryption",
+		Resources: []ResourceResult{
+			{ResourceID: "/aws/lambda/noop", Status: "no-action"},
+			{ResourceID: "/aws/lambda/guarded", Status: "skipped", SkipReason: "retention would shorten"},
+		},
+		Deferred: []string{"/aws/lambda/later"},
+	}
+
+	statuses := NewConfigRemediationResult(result).RemediationExecutionStatuses
+	require.Len(t, statuses, 3)
+
+	assert.Equal(t, ConfigRemediationStateSucceeded, statuses[0].State)
+
+	assert.Equal(t, "/aws/lambda/guarded", statuses[1].ResourceKey.ResourceID)
+	assert.Equal(t, ConfigRemediationStateFailed, statuses[1].State)
+	assert.Equal(t, "skipped: retention would shorten", statuses[1].StepDetails[0].ErrorMessage)
+
+	assert.Equal(t, "/aws/lambda/later", statuses[2].ResourceKey.ResourceID)
+	assert.Equal(t, ConfigRemediationStateQueued, statuses[2].State)
+	assert.Equal(t, ConfigRemediationStepStatePending, statuses[2].StepDetails[0].State)
+}
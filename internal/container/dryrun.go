@@ -2,14 +2,14 @@ package container
 
 import (
 	"context"
-	"fmt"
 	"log/slog"
 
 	"github.com/zsoftly/logguardian/internal/service"
 	"github.com/zsoftly/logguardian/internal/types"
 )
 
-// DryRunComplianceService wraps the real compliance service for dry-run mode
+// DryRunComplianceService wraps the real compliance service for dry-run mode. The real service must
+// be built WithDryRun(true): remediation is delegated to it so that it skips only the changes.
 type DryRunComplianceService struct {
 	realService service.ComplianceServiceInterface
 }
@@ -29,6 +29,11 @@ func (s *DryRunComplianceService) GetNonCompliantResources(ctx context.Context,
 	return s.realService.GetNonCompliantResources(ctx, configRuleName, region)
 }
 
+// GetCompliantResources delegates to the real service (read-only operation)
+func (s *DryRunComplianceService) GetCompliantResources(ctx context.Context, configRuleName, region string) ([]types.NonCompliantResource, error) {
+	return s.realService.GetCompliantResources(ctx, configRuleName, region)
+}
+
 // ValidateResourceExistence delegates to the real service (read-only operation)
 func (s *DryRunComplianceService) ValidateResourceExistence(ctx context.Context, resources []types.NonCompliantResource) ([]types.NonCompliantResource, error) {
 	slog.Info("[DRY-RUN] Validating resource existence",
@@ -36,105 +41,51 @@ func (s *DryRunComplianceService) ValidateResourceExistence(ctx context.Context,
 	return s.realService.ValidateResourceExistence(ctx, resources)
 }
 
-// RemediateLogGroup simulates remediation without making changes
+// RemediateLogGroup delegates to the real service, which was built WithDryRun(true), so the same
+// decisions are made as in apply mode and only the changes are skipped
 func (s *DryRunComplianceService) RemediateLogGroup(ctx context.Context, compliance types.ComplianceResult) (*types.RemediationResult, error) {
 	slog.Info("[DRY-RUN] Would remediate log group",
 		"log_group", compliance.LogGroupName,
 		"missing_encryption", compliance.MissingEncryption,
 		"missing_retention", compliance.MissingRetention)
+	return s.realService.RemediateLogGroup(ctx, compliance)
+}
 
-	result := types.RemediationResult{
-		LogGroupName:      compliance.LogGroupName,
-		Region:            compliance.Region,
-		EncryptionApplied: compliance.MissingEncryption,
-		RetentionApplied:  compliance.MissingRetention,
-		Success:           true,
-		Error:             nil,
-	}
-
-	if compliance.MissingEncryption {
-		slog.Info("[DRY-RUN] Would apply encryption",
-			"log_group", compliance.LogGroupName,
-			"region", compliance.Region)
-	}
-
-	if compliance.MissingRetention {
-		slog.Info("[DRY-RUN] Would apply retention",
-			"log_group", compliance.LogGroupName,
-			"region", compliance.Region,
-			"retention_days", 7)
-	}
-
-	if !compliance.MissingEncryption && !compliance.MissingRetention {
-		slog.Info("[DRY-RUN] Log group already compliant",
-			"log_group", compliance.LogGroupName)
+// RemediateSingleLogGroup evaluates the live log group through the real service (read-only) and
+// simulates remediating the requirement ruleType checks
+func (s *DryRunComplianceService) RemediateSingleLogGroup(ctx context.Context, region, logGroupName string, ruleType types.RuleType) (*types.RemediationResult, error) {
+	compliance, err := s.EvaluateCompliance(ctx, logGroupName, region)
+	if err != nil {
+		return nil, err
 	}
-
-	return &result, nil
+	return s.RemediateLogGroup(ctx, service.ScopeComplianceToRule(compliance, ruleType))
 }
 
-// ProcessNonCompliantResourcesOptimized simulates batch processing without making changes
+// ProcessNonCompliantResourcesOptimized delegates to the real service, which was built
+// WithDryRun(true), so the batch makes the same decisions as in apply mode and only skips the changes
 func (s *DryRunComplianceService) ProcessNonCompliantResourcesOptimized(ctx context.Context, request types.BatchComplianceRequest) (*types.BatchRemediationResult, error) {
 	slog.Info("[DRY-RUN] Would process non-compliant resources",
 		"config_rule", request.ConfigRuleName,
 		"region", request.Region,
 		"resource_count", len(request.NonCompliantResults),
 		"batch_size", request.BatchSize)
-
-	result := types.BatchRemediationResult{
-		TotalProcessed: len(request.NonCompliantResults),
-		Results:        []types.RemediationResult{},
-	}
-
-	// Simulate processing each resource
-	for _, resource := range request.NonCompliantResults {
-		slog.Info("[DRY-RUN] Would process resource",
-			"resource_id", resource.ResourceId,
-			"resource_name", resource.ResourceName,
-			"resource_type", resource.ResourceType)
-
-		// Create a simulated remediation result
-		remediationResult := types.RemediationResult{
-			LogGroupName:      resource.ResourceName,
-			Region:            resource.Region,
-			EncryptionApplied: false, // Would be determined by actual analysis
-			RetentionApplied:  false, // Would be determined by actual analysis
-			Success:           true,
-			Error:             nil,
-		}
-
-		// Simulate determining what would be applied based on the rule
-		if resource.Annotation != "" {
-			// Parse annotation to determine what's missing
-			// This is simplified - real implementation would analyze the actual state
-			remediationResult.EncryptionApplied = true
-			remediationResult.RetentionApplied = true
-		}
-
-		result.Results = append(result.Results, remediationResult)
-		result.SuccessCount++
-	}
-
-	slog.Info("[DRY-RUN] Batch processing simulation complete",
-		"total_processed", result.TotalProcessed,
-		"success_count", result.SuccessCount,
-		"failure_count", result.FailureCount)
-
-	return &result, nil
+	return s.realService.ProcessNonCompliantResourcesOptimized(ctx, request)
 }
 
-// EvaluateCompliance is not implemented for dry-run service
-// This method is not used in the container implementation as compliance evaluation
-// is handled differently through GetNonCompliantResources and ValidateResourceExistence
+// EvaluateCompliance delegates to the real service (read-only operation)
 func (s *DryRunComplianceService) EvaluateCompliance(ctx context.Context, logGroupName, region string) (types.ComplianceResult, error) {
-	slog.Warn("[DRY-RUN] EvaluateCompliance called but not implemented",
+	slog.Info("[DRY-RUN] Evaluating log group compliance",
 		"log_group", logGroupName,
-		"region", region,
-		"note", "This method is not used in container implementation")
+		"region", region)
+	return s.realService.EvaluateCompliance(ctx, logGroupName, region)
+}
 
-	// Return empty result - this method should not be called in container mode
-	// Container mode uses GetNonCompliantResources for compliance evaluation
-	return types.ComplianceResult{}, fmt.Errorf("EvaluateCompliance is not implemented for container dry-run mode - use GetNonCompliantResources instead")
+// ReportEvaluation simulates reporting to Config; nothing was remediated, so nothing is reported
+func (s *DryRunComplianceService) ReportEvaluation(ctx context.Context, resultToken string, configItem types.ConfigurationItem, result *types.RemediationResult) error {
+	slog.Info("[DRY-RUN] Would report evaluation to Config",
+		"resource_id", configItem.ResourceId,
+		"success", result.Success)
+	return nil
 }
 
 // GetLogGroupConfiguration is a helper method for dry-run analysis
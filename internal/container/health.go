@@ -0,0 +1,99 @@
+package container
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// healthReadHeaderTimeout bounds how long the health server waits for request headers
+const healthReadHeaderTimeout = 5 * time.Second
+
+// Readiness tracks whether the process can do useful work. It is safe for concurrent use.
+type Readiness struct {
+	awsConfigLoaded  atomic.Bool
+	regionsValidated atomic.Bool
+}
+
+// MarkAWSConfigLoaded records that the AWS configuration and credentials were loaded
+func (r *Readiness) MarkAWSConfigLoaded() {
+	r.awsConfigLoaded.Store(true)
+}
+
+// MarkRegionsValidated records that every region to process passed preflight
+func (r *Readiness) MarkRegionsValidated() {
+	r.regionsValidated.Store(true)
+}
+
+// notReadyReason returns why the process is not ready, or an empty string when it is
+func (r *Readiness) notReadyReason() string {
+	switch {
+	case !r.awsConfigLoaded.Load():
+		return "aws config not loaded"
+	case !r.regionsValidated.Load():
+		return "regions not validated"
+	default:
+		return ""
+	}
+}
+
+// NewHealthHandler serves /healthz, which succeeds while the process is up, and /readyz, which
+// succeeds once readiness reports the AWS config loaded and the regions validated. With metrics,
+// /metrics also serves them in the Prometheus text format; nil metrics leaves it unserved.
+func NewHealthHandler(readiness *Readiness, metrics *ServiceMetrics) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeHealthStatus(w, http.StatusOK, "ok")
+	})
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		if reason := readiness.notReadyReason(); reason != "" {
+			writeHealthStatus(w, http.StatusServiceUnavailable, reason)
+			return
+		}
+		writeHealthStatus(w, http.StatusOK, "ready")
+	})
+	if metrics != nil {
+		mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", prometheusContentType)
+			if err := metrics.WritePrometheus(w); err != nil {
+				slog.Debug("Failed to write metrics response", "error", err)
+			}
+		})
+	}
+	return mux
+}
+
+func writeHealthStatus(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(status)
+	if _, err := fmt.Fprintln(w, message); err != nil {
+		slog.Debug("Failed to write health response", "error", err)
+	}
+}
+
+// StartHealthServer serves the health endpoints, and /metrics when metrics is non-nil, on port in
+// the background and returns the server so the caller may shut it down. Serve errors other than a
+// shutdown are logged.
+func StartHealthServer(port string, readiness *Readiness, metrics *ServiceMetrics) (*http.Server, error) {
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return nil, fmt.Errorf("invalid health port %q: must be between 1 and 65535", port)
+	}
+
+	server := &http.Server{
+		Addr:              ":" + port,
+		Handler:           NewHealthHandler(readiness, metrics),
+		ReadHeaderTimeout: healthReadHeaderTimeout,
+	}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("Health server stopped", "error", err, "port", port)
+		}
+	}()
+
+	slog.Info("Started health server", "port", port)
+	return server, nil
+}
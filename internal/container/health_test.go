@@ -0,0 +1,93 @@
+package container
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHealthHandler(t *testing.T) {
+	tests := []struct {
+		name             string
+		awsConfigLoaded  bool
+		regionsValidated bool
+		expectedReady    int
+		expectedBody     string
+	}{
+		{name: "starting", expectedReady: http.StatusServiceUnavailable, expectedBody: "aws config not loaded"},
+		{name: "config loaded", awsConfigLoaded: true, expectedReady: http.StatusServiceUnavailable, expectedBody: "regions not validated"},
+		{name: "ready", awsConfigLoaded: true, regionsValidated: true, expectedReady: http.StatusOK, expectedBody: "ready"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			readiness := &Readiness{}
+			if tt.awsConfigLoaded {
+				readiness.MarkAWSConfigLoaded()
+			}
+			if tt.regionsValidated {
+				readiness.MarkRegionsValidated()
+			}
+			handler := NewHealthHandler(readiness, nil)
+
+			healthz := httptest.NewRecorder()
+			handler.ServeHTTP(healthz, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+			assert.Equal(t, http.StatusOK, healthz.Code, "healthz succeeds while the process is up")
+
+			readyz := httptest.NewRecorder()
+			handler.ServeHTTP(readyz, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			assert.Equal(t, tt.expectedReady, readyz.Code)
+			assert.Equal(t, tt.expectedBody, strings.TrimSpace(readyz.Body.String()))
+		})
+	}
+}
+
+func TestNewHealthHandler_UnknownPath(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	NewHealthHandler(&Readiness{}, nil).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestStartHealthServer_InvalidPort(t *testing.T) {
+	for _, port := range []string{"", "http", "0", "70000"} {
+		server, err := StartHealthServer(port, &Readiness{}, nil)
+		require.Error(t, err, port)
+		assert.Nil(t, server)
+	}
+}
+
+func TestNewHealthHandler_Metrics(t *testing.T) {
+	metrics := &ServiceMetrics{}
+	metrics.RecordSuccess()
+	metrics.RecordFailure()
+	metrics.RecordRetry()
+	metrics.RecordThrottle()
+	metrics.RecordExecution(&ExecutionResult{TotalProcessed: 5, SuccessCount: 3, FailureCount: 2, SkippedCount: 1})
+
+	recorder := httptest.NewRecorder()
+	NewHealthHandler(&Readiness{}, metrics).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, prometheusContentType, recorder.Header().Get("Content-Type"))
+
+	body := recorder.Body.String()
+	for _, line := range []string{
+		"# TYPE logguardian_service_calls_total counter",
+		"logguardian_service_calls_total 2",
+		"logguardian_service_calls_successful_total 1",
+		"logguardian_service_calls_failed_total 1",
+		"logguardian_service_call_retries_total 1",
+		"logguardian_service_call_throttles_total 1",
+		"# TYPE logguardian_remediations_processed_total counter",
+		"logguardian_remediations_processed_total 5",
+		"logguardian_remediations_succeeded_total 3",
+		"logguardian_remediations_failed_total 2",
+		"logguardian_remediations_skipped_total 1",
+	} {
+		assert.Contains(t, strings.Split(body, "\n"), line)
+	}
+}
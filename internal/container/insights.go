@@ -0,0 +1,90 @@
+package container
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/zsoftly/logguardian/internal/service"
+	"github.com/zsoftly/logguardian/internal/types"
+)
+
+// DefaultInsightsField is the Logs Insights field holding the log group, in the
+// accountId:logGroupName form Logs Insights uses for @log
+const DefaultInsightsField = "@log"
+
+// insightsAccountPrefix matches the account ID Logs Insights prepends to @log values
+var insightsAccountPrefix = regexp.MustCompile(`^(\d{12}):(.+)$`)
+
+// insightsQueryResults is the GetQueryResults response saved by `aws logs get-query-results`
+type insightsQueryResults struct {
+	Results [][]insightsResultField `json:"results"`
+}
+
+// insightsResultField is one field of a Logs Insights result row
+type insightsResultField struct {
+	Field string `json:"field"`
+	Value string `json:"value"`
+}
+
+// ParseInsightsResults builds the resources to remediate from saved Logs Insights query results,
+// reading each row's log group name from field. A value prefixed with an account ID, as @log
+// values are, sets the resource's account; when accountId is set, rows of any other account are
+// not remediated but returned as skipped. Rows without the field are skipped and repeated log
+// groups are listed once, but results whose rows never contain the field are rejected as a wrong
+// mapping. Empty results return an empty, non-nil list.
+func ParseInsightsResults(data []byte, field, region, accountId string) ([]types.NonCompliantResource, []service.SkippedResource, error) {
+	field = strings.TrimSpace(field)
+	if field == "" {
+		return nil, nil, fmt.Errorf("logs insights field is required")
+	}
+
+	var results insightsQueryResults
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse logs insights results: %w", err)
+	}
+
+	resources := []types.NonCompliantResource{}
+	var skipped []service.SkippedResource
+	seen := make(map[string]bool)
+	matched := false
+	for _, row := range results.Results {
+		for _, column := range row {
+			if column.Field != field {
+				continue
+			}
+			matched = true
+
+			logGroupName, rowAccountId := strings.TrimSpace(column.Value), ""
+			if m := insightsAccountPrefix.FindStringSubmatch(logGroupName); m != nil {
+				rowAccountId, logGroupName = m[1], m[2]
+			}
+			if logGroupName == "" || seen[logGroupName] {
+				break
+			}
+			seen[logGroupName] = true
+
+			resource := types.NonCompliantResource{
+				ResourceId:     logGroupName,
+				ResourceType:   service.LogGroupResourceType,
+				ResourceName:   logGroupName,
+				Region:         region,
+				AccountId:      rowAccountId,
+				ComplianceType: "NON_COMPLIANT",
+				Annotation:     "Identified by CloudWatch Logs Insights query",
+			}
+			if accountId != "" && rowAccountId != "" && rowAccountId != accountId {
+				skipped = append(skipped, service.SkippedResource{Resource: resource, Reason: service.SkipReasonOtherAccount})
+			} else {
+				resources = append(resources, resource)
+			}
+			break
+		}
+	}
+
+	if len(results.Results) > 0 && !matched {
+		return nil, nil, fmt.Errorf("logs insights field %q not found in any of the %d result rows", field, len(results.Results))
+	}
+	return resources, skipped, nil
+}
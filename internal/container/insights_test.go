@@ -0,0 +1,192 @@
+package container
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/zsoftly/logguardian/internal/service"
+	"github.com/zsoftly/logguardian/internal/types"
+)
+
+// sampleInsightsResults is `aws logs get-query-results` output for a query such as
+// `stats count(*) by @log`, with one log group repeated and one row lacking @log
+const sampleInsightsResults = `{
+  "results": [
+    [{"field": "@log", "value": "123456789012:/aws/lambda/orders"}, {"field": "count(*)", "value": "42"}],
+    [{"field": "@log", "value": "210987654321:/aws/lambda/payments"}, {"field": "count(*)", "value": "7"}],
+    [{"field": "count(*)", "value": "3"}],
+    [{"field": "@log", "value": "123456789012:/aws/lambda/orders"}, {"field": "count(*)", "value": "1"}]
+  ],
+  "statistics": {"recordsMatched": 53.0, "recordsScanned": 1200.0, "bytesScanned": 51200.0},
+  "status": "Complete"
+}`
+
+func TestParseInsightsResults(t *testing.T) {
+	resources, skipped, err := ParseInsightsResults([]byte(sampleInsightsResults), DefaultInsightsField, "ca-central-1", "")
+	require.NoError(t, err)
+
+	assert.Empty(t, skipped)
+	require.Len(t, resources, 2)
+	assert.Equal(t, types.NonCompliantResource{
+		ResourceId:     "/aws/lambda/orders",
+		ResourceType:   "AWS::Logs::LogGroup",
+		ResourceName:   "/aws/lambda/orders",
+		Region:         "ca-central-1",
+		AccountId:      "123456789012",
+		ComplianceType: "NON_COMPLIANT",
+		Annotation:     "Identified by CloudWatch Logs Insights query",
+	}, resources[0])
+	assert.Equal(t, "/aws/lambda/payments", resources[1].ResourceName)
+	assert.Equal(t, "210987654321", resources[1].AccountId)
+}
+
+func TestParseInsightsResults_FieldMapping(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          string
+		field         string
+		expectedNames []string
+		expectedErr   string
+	}{
+		{
+			name:          "custom field with plain log group names",
+			data:          `{"results": [[{"field": "logGroup", "value": "/aws/ecs/api"}], [{"field": "logGroup", "value": "/aws/ecs/worker"}]]}`,
+			field:         "logGroup",
+			expectedNames: []string{"/aws/ecs/api", "/aws/ecs/worker"},
+		},
+		{
+			name:          "empty results",
+			data:          `{"results": [], "status": "Complete"}`,
+			field:         DefaultInsightsField,
+			expectedNames: []string{},
+		},
+		{
+			name:        "field missing from every row",
+			data:        sampleInsightsResults,
+			field:       "logGroup",
+			expectedErr: `logs insights field "logGroup" not found in any of the 4 result rows`,
+		},
+		{
+			name:        "blank field",
+			data:        sampleInsightsResults,
+			field:       " ",
+			expectedErr: "logs insights field is required",
+		},
+		{
+			name:        "invalid JSON",
+			data:        `[{"field": "@log"`,
+			field:       DefaultInsightsField,
+			expectedErr: "failed to parse logs insights results",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resources, _, err := ParseInsightsResults([]byte(tt.data), tt.field, "ca-central-1", "")
+			if tt.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			names := []string{}
+			for _, resource := range resources {
+				names = append(names, resource.ResourceName)
+			}
+			assert.Equal(t, tt.expectedNames, names)
+		})
+	}
+}
+
+func TestParseInsightsResults_OtherAccount(t *testing.T) {
+	resources, skipped, err := ParseInsightsResults([]byte(sampleInsightsResults), DefaultInsightsField, "ca-central-1", "123456789012")
+	require.NoError(t, err)
+
+	require.Len(t, resources, 1)
+	assert.Equal(t, "/aws/lambda/orders", resources[0].ResourceName)
+	require.Len(t, skipped, 1)
+	assert.Equal(t, "/aws/lambda/payments", skipped[0].Resource.ResourceName)
+	assert.Equal(t, "210987654321", skipped[0].Resource.AccountId)
+	assert.Equal(t, service.SkipReasonOtherAccount, skipped[0].Reason)
+
+	// Plain log group names carry no account and are kept
+	resources, skipped, err = ParseInsightsResults([]byte(`{"results": [[{"field": "logGroup", "value": "/aws/ecs/api"}]]}`), "logGroup", "ca-central-1", "123456789012")
+	require.NoError(t, err)
+	assert.Len(t, resources, 1)
+	assert.Empty(t, skipped)
+}
+
+func TestCommandProcessor_Execute_SuppliedResources(t *testing.T) {
+	ctx := context.Background()
+	resources, _, err := ParseInsightsResults([]byte(sampleInsightsResults), DefaultInsightsField, "ca-central-1", "")
+	require.NoError(t, err)
+
+	// Config is not queried when resources are supplied
+	mockService := new(MockComplianceService)
+	mockService.On("ValidateResourceExistence", ctx, resources).Return(resources, nil)
+	mockService.On("ProcessNonCompliantResourcesOptimized", ctx, mock.MatchedBy(func(request types.BatchComplianceRequest) bool {
+		return assert.ObjectsAreEqual(resources, request.NonCompliantResults)
+	})).Return(&types.BatchRemediationResult{TotalProcessed: 2, SuccessCount: 2}, nil)
+
+	processor := &CommandProcessor{
+		service:      mockService,
+		executionLog: []ExecutionLogEntry{},
+	}
+
+	result, err := processor.Execute(ctx, CommandRequest{
+		Type:           "config-rule-evaluation",
+		ConfigRuleName: "cloudwatch-log-group-encrypted",
+		Region:         "ca-central-1",
+		BatchSize:      10,
+		Resources:      resources,
+	})
+
+	require.NoError(t, err)
+	mockService.AssertExpectations(t)
+	mockService.AssertNotCalled(t, "GetNonCompliantResources", mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, 2, result.Reconciliation.Reported)
+	assert.Equal(t, 2, result.SuccessCount)
+}
+
+func TestCommandProcessor_Execute_OtherAccountRowsReportedSkipped(t *testing.T) {
+	ctx := context.Background()
+	resources, skipped, err := ParseInsightsResults([]byte(sampleInsightsResults), DefaultInsightsField, "ca-central-1", "123456789012")
+	require.NoError(t, err)
+
+	mockService := new(MockComplianceService)
+	mockService.On("ValidateResourceExistence", ctx, resources).Return(resources, nil)
+	mockService.On("ProcessNonCompliantResourcesOptimized", ctx, mock.MatchedBy(func(request types.BatchComplianceRequest) bool {
+		return assert.ObjectsAreEqual(resources, request.NonCompliantResults)
+	})).Return(&types.BatchRemediationResult{
+		TotalProcessed: 1,
+		SuccessCount:   1,
+		Results:        []types.RemediationResult{{LogGroupName: "/aws/lambda/orders", EncryptionApplied: true, Success: true}},
+	}, nil)
+
+	processor := &CommandProcessor{
+		service:      mockService,
+		executionLog: []ExecutionLogEntry{},
+	}
+
+	result, err := processor.Execute(ctx, CommandRequest{
+		Type:             "config-rule-evaluation",
+		ConfigRuleName:   "cloudwatch-log-group-encrypted",
+		Region:           "ca-central-1",
+		BatchSize:        10,
+		Resources:        resources,
+		SkippedResources: skipped,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.SuccessCount)
+	assert.Equal(t, 1, result.SkippedCount)
+	assert.Equal(t, ExecutionStatusCompletedWithSkips, result.Status)
+	require.Len(t, result.Resources, 2)
+	assert.Equal(t, "/aws/lambda/payments", result.Resources[0].ResourceName)
+	assert.Equal(t, "skipped", result.Resources[0].Status)
+	assert.Equal(t, string(service.SkipReasonOtherAccount), result.Resources[0].SkipReason)
+}
@@ -0,0 +1,148 @@
+package container
+
+import (
+	"encoding/xml"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// JUnitTestSuites is a JUnit XML report of an execution, so CI systems can display LogGuardian
+// results natively. Each region is a test suite and each processed resource a test case.
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite holds the resources of a single region
+type JUnitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	TestCases []JUnitTestCase `xml:"testcase"`
+}
+
+// JUnitTestCase is the remediation of one log group; a remediation failure is a test failure
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+	Skipped   *JUnitSkipped `xml:"skipped,omitempty"`
+}
+
+// JUnitFailure describes why a remediation failed
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// JUnitSkipped marks a resource that was not remediated
+type JUnitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// NewJUnitReport maps result into a JUnit report. A multi-region result has one suite per
+// region, sorted by region; any other result is a single suite. Dry-run, skipped and deferred
+// resources are reported as skipped test cases.
+func NewJUnitReport(result *ExecutionResult) *JUnitTestSuites {
+	report := &JUnitTestSuites{
+		Name:   "logguardian",
+		Time:   junitSeconds(parseExecutionDuration(result.Duration)),
+		Suites: []JUnitTestSuite{},
+	}
+
+	results := []*ExecutionResult{result}
+	if len(result.RegionResults) > 0 {
+		regions := make([]string, 0, len(result.RegionResults))
+		for region := range result.RegionResults {
+			regions = append(regions, region)
+		}
+		slices.Sort(regions)
+
+		results = results[:0]
+		for _, region := range regions {
+			results = append(results, result.RegionResults[region])
+		}
+	}
+
+	for _, r := range results {
+		suite := newJUnitTestSuite(r)
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Skipped += suite.Skipped
+		report.Suites = append(report.Suites, suite)
+	}
+	return report
+}
+
+// newJUnitTestSuite maps the resources of a single-region result
+func newJUnitTestSuite(result *ExecutionResult) JUnitTestSuite {
+	name := fmt.Sprintf("%s.%s", result.ConfigRuleName, result.Region)
+	suite := JUnitTestSuite{
+		Name:      name,
+		Time:      junitSeconds(parseExecutionDuration(result.Duration)),
+		Timestamp: result.Timestamp.UTC().Format(time.RFC3339),
+		TestCases: []JUnitTestCase{},
+	}
+
+	for _, resource := range result.Resources {
+		testCase := JUnitTestCase{
+			Name:      resource.ResourceName,
+			ClassName: name,
+			Time:      junitSeconds(time.Duration(resource.DurationMs) * time.Millisecond),
+		}
+		switch resource.Status {
+		case "failed":
+			testCase.Failure = &JUnitFailure{
+				Message: "remediation failed",
+				Type:    "RemediationFailure",
+				Text:    resource.Error,
+			}
+			suite.Failures++
+		case "dry-run":
+			testCase.Skipped = &JUnitSkipped{Message: "dry run, no changes applied"}
+			suite.Skipped++
+		case "skipped":
+			testCase.Skipped = &JUnitSkipped{Message: "required change deliberately skipped"}
+			suite.Skipped++
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	for _, name := range result.Deferred {
+		suite.TestCases = append(suite.TestCases, JUnitTestCase{
+			Name:      name,
+			ClassName: suite.Name,
+			Time:      junitSeconds(0),
+			Skipped:   &JUnitSkipped{Message: "deferred, soft time budget spent"},
+		})
+		suite.Skipped++
+	}
+
+	suite.Tests = len(suite.TestCases)
+	return suite
+}
+
+// parseExecutionDuration reads an ExecutionResult duration, treating an unset or unparsable one as zero
+func parseExecutionDuration(duration string) time.Duration {
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// junitSeconds formats d as the fractional seconds JUnit time attributes hold
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,141 @@
+package container
+
+import (
+	"encoding/xml"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zsoftly/logguardian/internal/types"
+)
+
+// junitDocument decodes a JUnit report independently of the types that produce it
+type junitDocument struct {
+	XMLName  xml.Name `xml:"testsuites"`
+	Tests    int      `xml:"tests,attr"`
+	Failures int      `xml:"failures,attr"`
+	Skipped  int      `xml:"skipped,attr"`
+	Time     string   `xml:"time,attr"`
+	Suites   []struct {
+		Name      string `xml:"name,attr"`
+		Tests     int    `xml:"tests,attr"`
+		Failures  int    `xml:"failures,attr"`
+		Skipped   int    `xml:"skipped,attr"`
+		Time      string `xml:"time,attr"`
+		Timestamp string `xml:"timestamp,attr"`
+		TestCases []struct {
+			Name      string `xml:"name,attr"`
+			ClassName string `xml:"classname,attr"`
+			Time      string `xml:"time,attr"`
+			Failure   *struct {
+				Message string `xml:"message,attr"`
+				Text    string `xml:",chardata"`
+			} `xml:"failure"`
+			Skipped *struct {
+				Message string `xml:"message,attr"`
+			} `xml:"skipped"`
+		} `xml:"testcase"`
+	} `xml:"testsuite"`
+}
+
+func decodeJUnitReport(t *testing.T, result *ExecutionResult) junitDocument {
+	t.Helper()
+	data, err := xml.Marshal(NewJUnitReport(result))
+	require.NoError(t, err)
+
+	var document junitDocument
+	require.NoError(t, xml.Unmarshal(data, &document))
+	return document
+}
+
+func TestNewJUnitReport(t *testing.T) {
+	batch := &types.BatchRemediationResult{
+		TotalProcessed:     3,
+		SuccessCount:       2,
+		NoActionCount:      1,
+		FailureCount:       1,
+		ProcessingDuration: 2500 * time.Millisecond,
+		Deferred:           []string{"/aws/lambda/later"},
+		Results: []types.RemediationResult{
+			{LogGroupName: "/aws/lambda/remediated", Success: true, RetentionApplied: true, Duration: 1200 * time.Millisecond},
+			{LogGroupName: "/aws/lambda/compliant", Success: true, NoActionNeeded: true, Duration: 40 * time.Millisecond},
+			{LogGroupName: "/aws/lambda/failed", Error: errors.New("AccessDeniedException"), Duration: 300 * time.Millisecond},
+		},
+	}
+	result := ExecutionResultFromBatch(batch, "exec-1", "apply", "cw-lg-retention-min", "ca-central-1")
+	result.Timestamp = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	document := decodeJUnitReport(t, result)
+
+	assert.Equal(t, 4, document.Tests)
+	assert.Equal(t, 1, document.Failures)
+	assert.Equal(t, 1, document.Skipped)
+	assert.Equal(t, "2.500", document.Time)
+
+	require.Len(t, document.Suites, 1)
+	suite := document.Suites[0]
+	assert.Equal(t, "cw-lg-retention-min.ca-central-1", suite.Name)
+	assert.Equal(t, 4, suite.Tests)
+	assert.Equal(t, 1, suite.Failures)
+	assert.Equal(t, 1, suite.Skipped)
+	assert.Equal(t, "2.500", suite.Time)
+	assert.Equal(t, "2025-06-01T12:00:00Z", suite.Timestamp)
+
+	require.Len(t, suite.TestCases, 4)
+	remediated, compliant, failed, deferred := suite.TestCases[0], suite.TestCases[1], suite.TestCases[2], suite.TestCases[3]
+
+	assert.Equal(t, "/aws/lambda/remediated", remediated.Name)
+	assert.Equal(t, "cw-lg-retention-min.ca-central-1", remediated.ClassName)
+	assert.Equal(t, "1.200", remediated.Time)
+	assert.Nil(t, remediated.Failure)
+	assert.Nil(t, remediated.Skipped)
+
+	assert.Equal(t, "0.040", compliant.Time)
+	assert.Nil(t, compliant.Failure)
+
+	assert.Equal(t, "/aws/lambda/failed", failed.Name)
+	assert.Equal(t, "0.300", failed.Time)
+	require.NotNil(t, failed.Failure)
+	assert.Equal(t, "remediation failed", failed.Failure.Message)
+	assert.Equal(t, "AccessDeniedException", failed.Failure.Text)
+
+	assert.Equal(t, "/aws/lambda/later", deferred.Name)
+	require.NotNil(t, deferred.Skipped)
+	assert.Contains(t, deferred.Skipped.Message, "deferred")
+}
+
+func TestNewJUnitReport_Regions(t *testing.T) {
+	result := &ExecutionResult{
+		ConfigRuleName: "cw-lg-kms-encryption",
+		Region:         "ca-central-1,ca-west-1",
+		Duration:       "3s",
+		RegionResults: map[string]*ExecutionResult{
+			"ca-west-1": {
+				ConfigRuleName: "cw-lg-kms-encryption",
+				Region:         "ca-west-1",
+				Resources:      []ResourceResult{{ResourceName: "/aws/lambda/west", Status: "dry-run"}},
+			},
+			"ca-central-1": {
+				ConfigRuleName: "cw-lg-kms-encryption",
+				Region:         "ca-central-1",
+				Resources:      []ResourceResult{{ResourceName: "/aws/lambda/central", Status: "failed", Error: "KMS key not found"}},
+			},
+		},
+	}
+
+	document := decodeJUnitReport(t, result)
+
+	assert.Equal(t, 2, document.Tests)
+	assert.Equal(t, 1, document.Failures)
+	assert.Equal(t, 1, document.Skipped)
+	assert.Equal(t, "3.000", document.Time)
+
+	require.Len(t, document.Suites, 2)
+	assert.Equal(t, "cw-lg-kms-encryption.ca-central-1", document.Suites[0].Name)
+	assert.Equal(t, 1, document.Suites[0].Failures)
+	assert.Equal(t, "cw-lg-kms-encryption.ca-west-1", document.Suites[1].Name)
+	assert.Equal(t, 1, document.Suites[1].Skipped)
+	require.NotNil(t, document.Suites[1].TestCases[0].Skipped)
+}
@@ -0,0 +1,110 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/zsoftly/logguardian/internal/service"
+)
+
+// RegionRunner runs a task against every configured region; it is satisfied by
+// service.MultiRegionComplianceService
+type RegionRunner interface {
+	RemediateAcrossRegions(ctx context.Context, task service.RegionTask, opts ...func(*service.RegionValidationOptions)) map[string]error
+}
+
+// NewMultiRegionService creates a multi-region compliance service for regions whose per-region
+// services share the processor options, and whose key aliases honour KMS_KEY_ALIAS_<region>
+func NewMultiRegionService(ctx context.Context, awsCfg aws.Config, regions []string, options ProcessorOptions) (*service.MultiRegionComplianceService, error) {
+	mrs := service.NewMultiRegionComplianceService(awsCfg, service.WithRegionServiceOptions(serviceOptions(options)...))
+	if err := mrs.LoadRegionsFromConfig(ctx, regions); err != nil {
+		return nil, err
+	}
+	return mrs, nil
+}
+
+// ExecuteAcrossRegions runs the request concurrently in every region of runner and aggregates the
+// per-region results, which are kept in RegionResults. The request's Region is ignored.
+func ExecuteAcrossRegions(ctx context.Context, runner RegionRunner, request CommandRequest, options ProcessorOptions) (*ExecutionResult, error) {
+	startTime := time.Now()
+	request.applyRuleNames()
+
+	var mu sync.Mutex
+	regionResults := make(map[string]*ExecutionResult)
+
+	errs := runner.RemediateAcrossRegions(ctx, func(ctx context.Context, region string, regionService service.ComplianceServiceInterface) error {
+		regionRequest := request
+		regionRequest.Region = region
+
+		result, err := newProcessorForService(regionService, options).Execute(ctx, regionRequest)
+		if result != nil {
+			mu.Lock()
+			regionResults[region] = result
+			mu.Unlock()
+		}
+		return err
+	})
+
+	regions := make([]string, 0, len(errs))
+	for region := range errs {
+		regions = append(regions, region)
+	}
+	slices.Sort(regions)
+
+	mode := "apply"
+	if options.DryRun {
+		mode = "dry-run"
+	}
+
+	result := &ExecutionResult{
+		ExecutionID:    options.ExecutionID,
+		Mode:           mode,
+		ConfigRuleName: request.ConfigRuleName,
+		Region:         strings.Join(regions, ","),
+		Timestamp:      startTime,
+		RegionResults:  regionResults,
+	}
+
+	var failures []string
+	for _, region := range regions {
+		if err := errs[region]; err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", region, err))
+		}
+
+		regionResult := regionResults[region]
+		if regionResult == nil {
+			continue
+		}
+		result.TotalProcessed += regionResult.TotalProcessed
+		result.SuccessCount += regionResult.SuccessCount
+		result.FailureCount += regionResult.FailureCount
+		result.NoActionCount += regionResult.NoActionCount
+		result.SkippedCount += regionResult.SkippedCount
+		result.Deferred = append(result.Deferred, regionResult.Deferred...)
+		if summary := regionResult.DryRunSummary; summary != nil {
+			if result.DryRunSummary == nil {
+				result.DryRunSummary = &DryRunSummary{}
+			}
+			result.DryRunSummary.WouldApplyEncryption += summary.WouldApplyEncryption
+			result.DryRunSummary.WouldApplyRetention += summary.WouldApplyRetention
+			result.DryRunSummary.AlreadyCompliant += summary.AlreadyCompliant
+			result.DryRunSummary.TotalResources += summary.TotalResources
+		}
+	}
+	result.Duration = time.Since(startTime).String()
+
+	if len(failures) > 0 {
+		err := fmt.Errorf("%d of %d regions failed: %s", len(failures), len(regions), strings.Join(failures, "; "))
+		result.Status = ExecutionStatusFailed
+		result.Error = err.Error()
+		return result, err
+	}
+
+	result.Status = executionStatus(result)
+	return result, nil
+}
@@ -0,0 +1,110 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/zsoftly/logguardian/internal/service"
+	"github.com/zsoftly/logguardian/internal/testutil"
+	"github.com/zsoftly/logguardian/internal/types"
+)
+
+// fakeRegionRunner runs the task once per region against that region's mock service
+type fakeRegionRunner struct {
+	services map[string]service.ComplianceServiceInterface
+}
+
+func (f *fakeRegionRunner) RemediateAcrossRegions(ctx context.Context, task service.RegionTask, opts ...func(*service.RegionValidationOptions)) map[string]error {
+	results := make(map[string]error, len(f.services))
+	for region, svc := range f.services {
+		results[region] = task(ctx, region, svc)
+	}
+	return results
+}
+
+func newRegionMockService(ctx context.Context, region string, resources []types.NonCompliantResource, batchResult *types.BatchRemediationResult) *MockComplianceService {
+	mockService := new(MockComplianceService)
+	mockService.On("GetNonCompliantResources", ctx, "test-rule", region).Return(resources, nil)
+	mockService.On("ValidateResourceExistence", ctx, resources).Return(resources, nil)
+	mockService.On("ProcessNonCompliantResourcesOptimized", ctx, mock.MatchedBy(func(request types.BatchComplianceRequest) bool {
+		return request.Region == region
+	})).Return(batchResult, nil)
+	return mockService
+}
+
+func TestExecuteAcrossRegions(t *testing.T) {
+	ctx := context.Background()
+
+	central := newRegionMockService(ctx, "ca-central-1",
+		[]types.NonCompliantResource{
+			testutil.NewTestNonCompliantResource("/aws/lambda/a"),
+			testutil.NewTestNonCompliantResource("/aws/lambda/b"),
+		},
+		&types.BatchRemediationResult{TotalProcessed: 2, SuccessCount: 2})
+	west := newRegionMockService(ctx, "ca-west-1",
+		[]types.NonCompliantResource{testutil.NewTestNonCompliantResource("/aws/lambda/c")},
+		&types.BatchRemediationResult{TotalProcessed: 1, FailureCount: 1})
+
+	runner := &fakeRegionRunner{services: map[string]service.ComplianceServiceInterface{
+		"ca-central-1": central,
+		"ca-west-1":    west,
+	}}
+
+	result, err := ExecuteAcrossRegions(ctx, runner, CommandRequest{
+		Type:           "config-rule-evaluation",
+		ConfigRuleName: "test-rule",
+		BatchSize:      10,
+	}, ProcessorOptions{ExecutionID: "test-multi"})
+
+	require.NoError(t, err)
+	central.AssertExpectations(t)
+	west.AssertExpectations(t)
+
+	assert.Equal(t, ExecutionStatusPartial, result.Status)
+	assert.Equal(t, "test-multi", result.ExecutionID)
+	assert.Equal(t, "ca-central-1,ca-west-1", result.Region)
+	assert.Equal(t, 3, result.TotalProcessed)
+	assert.Equal(t, 2, result.SuccessCount)
+	assert.Equal(t, 1, result.FailureCount)
+
+	require.Len(t, result.RegionResults, 2)
+	assert.Equal(t, "ca-central-1", result.RegionResults["ca-central-1"].Region)
+	assert.Equal(t, 2, result.RegionResults["ca-central-1"].SuccessCount)
+	assert.Equal(t, "ca-west-1", result.RegionResults["ca-west-1"].Region)
+	assert.Equal(t, 1, result.RegionResults["ca-west-1"].FailureCount)
+	assert.Equal(t, ExecutionStatusCompleted, result.RegionResults["ca-central-1"].Status)
+	assert.Equal(t, ExecutionStatusFailed, result.RegionResults["ca-west-1"].Status)
+}
+
+func TestExecuteAcrossRegions_RegionFailure(t *testing.T) {
+	ctx := context.Background()
+
+	central := newRegionMockService(ctx, "ca-central-1",
+		[]types.NonCompliantResource{testutil.NewTestNonCompliantResource("/aws/lambda/a")},
+		&types.BatchRemediationResult{TotalProcessed: 1, SuccessCount: 1})
+	west := new(MockComplianceService)
+	west.On("GetNonCompliantResources", ctx, "test-rule", "ca-west-1").
+		Return([]types.NonCompliantResource{}, errors.New("NoSuchConfigRuleException"))
+
+	runner := &fakeRegionRunner{services: map[string]service.ComplianceServiceInterface{
+		"ca-central-1": central,
+		"ca-west-1":    west,
+	}}
+
+	result, err := ExecuteAcrossRegions(ctx, runner, CommandRequest{
+		Type:           "config-rule-evaluation",
+		ConfigRuleName: "test-rule",
+		BatchSize:      10,
+	}, ProcessorOptions{DryRun: false})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 of 2 regions failed")
+	assert.Contains(t, err.Error(), "ca-west-1")
+	assert.Equal(t, "failed", result.Status)
+	assert.Equal(t, 1, result.SuccessCount)
+	assert.Equal(t, "failed", result.RegionResults["ca-west-1"].Status)
+}
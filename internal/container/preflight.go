@@ -0,0 +1,97 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zsoftly/logguardian/internal/service"
+	"github.com/zsoftly/logguardian/internal/types"
+)
+
+// PreflightRunner checks access in every configured region; it is satisfied by
+// service.MultiRegionComplianceService
+type PreflightRunner interface {
+	PreflightRegions(ctx context.Context) []types.RegionPreflightResult
+}
+
+// KMSPreflightRunner runs comprehensive KMS validation in every configured region; it is
+// satisfied by service.MultiRegionComplianceService
+type KMSPreflightRunner interface {
+	PreflightAllRegions(ctx context.Context) error
+}
+
+// PreflightResult is the consolidated preflight report with one row per region
+type PreflightResult struct {
+	ExecutionID   string                        `json:"executionId"`
+	Status        string                        `json:"status"`
+	Regions       []types.RegionPreflightResult `json:"regions"`
+	FailedRegions int                           `json:"failedRegions"`
+	Timestamp     time.Time                     `json:"timestamp"`
+	Duration      string                        `json:"duration"`
+	Error         string                        `json:"error,omitempty"`
+}
+
+// RunPreflight checks every region of runner and returns the consolidated report, along with an
+// error naming the regions that failed a critical check
+func RunPreflight(ctx context.Context, runner PreflightRunner, executionID string) (*PreflightResult, error) {
+	startTime := time.Now()
+
+	result := &PreflightResult{
+		ExecutionID: executionID,
+		Regions:     runner.PreflightRegions(ctx),
+		Timestamp:   startTime,
+	}
+
+	var failures []string
+	for _, region := range result.Regions {
+		if !region.Passed {
+			failures = append(failures, fmt.Sprintf("%s: %s", region.Region, region.Error))
+		}
+	}
+	result.FailedRegions = len(failures)
+	result.Duration = time.Since(startTime).String()
+
+	if len(failures) > 0 {
+		err := fmt.Errorf("%d of %d regions failed preflight: %s", len(failures), len(result.Regions), strings.Join(failures, "; "))
+		result.Status = "failed"
+		result.Error = err.Error()
+		return result, err
+	}
+
+	result.Status = "passed"
+	return result, nil
+}
+
+// RunKMSPreflight adds comprehensive KMS validation to a passed preflight report: each region of
+// result whose key fails validation is marked failed, and FailedRegions, Status and Error are
+// updated. The returned error is the runner's, naming the failing regions.
+func RunKMSPreflight(ctx context.Context, runner KMSPreflightRunner, result *PreflightResult) error {
+	err := runner.PreflightAllRegions(ctx)
+	if err == nil {
+		return nil
+	}
+	result.Status = "failed"
+	result.Error = err.Error()
+
+	var preflightErr *service.KMSPreflightError
+	if !errors.As(err, &preflightErr) {
+		return err
+	}
+	for i := range result.Regions {
+		region := &result.Regions[i]
+		if message, ok := preflightErr.Failures[region.Region]; ok {
+			region.Passed = false
+			region.Error = "KMS preflight failed: " + message
+		}
+	}
+	result.FailedRegions = 0
+	for _, region := range result.Regions {
+		if !region.Passed {
+			result.FailedRegions++
+		}
+	}
+	return err
+}
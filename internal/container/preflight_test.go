@@ -0,0 +1,105 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zsoftly/logguardian/internal/service"
+	"github.com/zsoftly/logguardian/internal/types"
+)
+
+// fakePreflightRunner returns fixed per-region preflight rows
+type fakePreflightRunner struct {
+	results []types.RegionPreflightResult
+}
+
+func (f *fakePreflightRunner) PreflightRegions(ctx context.Context) []types.RegionPreflightResult {
+	return f.results
+}
+
+func TestRunPreflight(t *testing.T) {
+	runner := &fakePreflightRunner{results: []types.RegionPreflightResult{
+		{Region: "ca-central-1", Passed: true, Key: &types.RegionKeySummary{Region: "ca-central-1", Status: types.RegionKeyStatusUsable}},
+		{Region: "ca-west-1", Passed: true, Key: &types.RegionKeySummary{Region: "ca-west-1", Status: types.RegionKeyStatusMissing}},
+	}}
+
+	result, err := RunPreflight(context.Background(), runner, "test-preflight")
+
+	require.NoError(t, err)
+	assert.Equal(t, "passed", result.Status)
+	assert.Equal(t, "test-preflight", result.ExecutionID)
+	assert.Equal(t, 0, result.FailedRegions)
+	assert.Len(t, result.Regions, 2)
+}
+
+func TestRunPreflight_RegionFailure(t *testing.T) {
+	runner := &fakePreflightRunner{results: []types.RegionPreflightResult{
+		{Region: "ca-central-1", Passed: true, Key: &types.RegionKeySummary{Region: "ca-central-1", Status: types.RegionKeyStatusUsable}},
+		{Region: "ca-west-1", Error: "failed to access CloudWatch Logs in region ca-west-1: AccessDeniedException"},
+	}}
+
+	result, err := RunPreflight(context.Background(), runner, "test-preflight")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 of 2 regions failed preflight")
+	assert.Contains(t, err.Error(), "ca-west-1")
+	assert.Equal(t, "failed", result.Status)
+	assert.Equal(t, 1, result.FailedRegions)
+	assert.Equal(t, err.Error(), result.Error)
+	require.Len(t, result.Regions, 2)
+	assert.True(t, result.Regions[0].Passed)
+}
+
+// fakeKMSPreflightRunner returns a fixed KMS preflight error
+type fakeKMSPreflightRunner struct {
+	err error
+}
+
+func (f *fakeKMSPreflightRunner) PreflightAllRegions(ctx context.Context) error {
+	return f.err
+}
+
+func TestRunKMSPreflight_MarksFailingRegions(t *testing.T) {
+	result := &PreflightResult{
+		Status: "passed",
+		Regions: []types.RegionPreflightResult{
+			{Region: "ca-central-1", Passed: true},
+			{Region: "ca-west-1", Passed: true},
+		},
+	}
+
+	err := RunKMSPreflight(context.Background(), &fakeKMSPreflightRunner{
+		err: &service.KMSPreflightError{Failures: map[string]string{"ca-west-1": "key is disabled"}, Regions: 2},
+	}, result)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 of 2 regions failed KMS preflight: ca-west-1: key is disabled")
+	assert.Equal(t, "failed", result.Status)
+	assert.Equal(t, err.Error(), result.Error)
+	assert.Equal(t, 1, result.FailedRegions)
+	assert.True(t, result.Regions[0].Passed)
+	assert.False(t, result.Regions[1].Passed)
+	assert.Equal(t, "KMS preflight failed: key is disabled", result.Regions[1].Error)
+}
+
+func TestRunKMSPreflight_AllPass(t *testing.T) {
+	result := &PreflightResult{Status: "passed", Regions: []types.RegionPreflightResult{{Region: "ca-central-1", Passed: true}}}
+
+	require.NoError(t, RunKMSPreflight(context.Background(), &fakeKMSPreflightRunner{}, result))
+	assert.Equal(t, "passed", result.Status)
+	assert.Equal(t, 0, result.FailedRegions)
+}
+
+func TestRunKMSPreflight_ValidationError(t *testing.T) {
+	result := &PreflightResult{Status: "passed", Regions: []types.RegionPreflightResult{{Region: "ca-central-1", Passed: true}}}
+
+	err := RunKMSPreflight(context.Background(), &fakeKMSPreflightRunner{err: errors.New("validation timed out")}, result)
+
+	require.Error(t, err)
+	assert.Equal(t, "failed", result.Status)
+	assert.Equal(t, "validation timed out", result.Error)
+	assert.True(t, result.Regions[0].Passed)
+}
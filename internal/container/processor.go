@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -23,6 +24,20 @@ type ProcessorOptions struct {
 	DryRun       bool
 	ExecutionID  string
 	OutputFormat string
+	// AllowRetentionReduction permits shortening retention on data-protected log groups
+	AllowRetentionReduction bool
+	// Logger receives execution and service logs; defaults to slog.Default()
+	Logger *slog.Logger
+	// LogGroupLookupRetries overrides LOG_GROUP_LOOKUP_RETRIES when set
+	LogGroupLookupRetries *int32
+	// IncludeCompliant also lists the log groups Config reports compliant with the rule, with
+	// status "compliant"; they are not counted as processed
+	IncludeCompliant bool
+	// AnnotationKeywords keeps only resources whose Config annotation contains one of these
+	// keywords, ignoring case; empty keeps every resource
+	AnnotationKeywords []string
+	// Role is the IAM role the run assumed, named with ExecutionID in remediation audit records
+	Role string
 }
 
 type CommandRequest struct {
@@ -30,8 +45,38 @@ type CommandRequest struct {
 	ConfigRuleName string
 	Region         string
 	BatchSize      int
+	// LogGroupName targets a single log group by name instead of querying Config
+	LogGroupName string
+	// Resources, when non-nil, are remediated instead of the non-compliant resources Config
+	// reports, e.g. those parsed by ParseInsightsResults
+	Resources []types.NonCompliantResource
+	// ConfigRuleNames evaluates several Config rules in one run, merging the resources they report
+	// and remediating every requirement a resource was flagged for; it replaces ConfigRuleName
+	ConfigRuleNames []string
+	// SkippedResources were dropped before the run, e.g. Logs Insights rows from another account;
+	// they are reported with status skipped and never remediated
+	SkippedResources []service.SkippedResource
 }
 
+// applyRuleNames names a multi-rule request after all of its rules, e.g. in ExecutionResult
+func (r *CommandRequest) applyRuleNames() {
+	if len(r.ConfigRuleNames) > 0 {
+		r.ConfigRuleName = strings.Join(r.ConfigRuleNames, ",")
+	}
+}
+
+// Execution statuses. A finished run is completed when every resource succeeded,
+// completed-with-skips when some required changes were deliberately skipped but nothing failed,
+// and partial when some resources failed. It is failed when it errored, was cut short, or every
+// remediated resource failed.
+const (
+	ExecutionStatusRunning            = "running"
+	ExecutionStatusCompleted          = "completed"
+	ExecutionStatusCompletedWithSkips = "completed-with-skips"
+	ExecutionStatusPartial            = "partial"
+	ExecutionStatusFailed             = "failed"
+)
+
 type ExecutionResult struct {
 	ExecutionID    string              `json:"execution_id"`
 	Status         string              `json:"status"`
@@ -41,12 +86,18 @@ type ExecutionResult struct {
 	TotalProcessed int                 `json:"total_processed"`
 	SuccessCount   int                 `json:"success_count"`
 	FailureCount   int                 `json:"failure_count"`
+	NoActionCount  int                 `json:"no_action_count"`
+	SkippedCount   int                 `json:"skipped_count"`
+	Deferred       []string            `json:"deferred,omitempty"` // Log groups left unstarted once the soft time budget was spent
 	Duration       string              `json:"duration"`
 	Timestamp      time.Time           `json:"timestamp"`
 	Resources      []ResourceResult    `json:"resources,omitempty"`
 	DryRunSummary  *DryRunSummary      `json:"dry_run_summary,omitempty"`
+	Reconciliation *Reconciliation     `json:"reconciliation,omitempty"`
 	Error          string              `json:"error,omitempty"`
 	ExecutionLog   []ExecutionLogEntry `json:"execution_log,omitempty"`
+	// RegionResults holds each region's result when the command ran across several regions
+	RegionResults map[string]*ExecutionResult `json:"region_results,omitempty"`
 }
 
 type ResourceResult struct {
@@ -55,7 +106,9 @@ type ResourceResult struct {
 	Status            string    `json:"status"`
 	EncryptionApplied bool      `json:"encryption_applied"`
 	RetentionApplied  bool      `json:"retention_applied"`
+	SkipReason        string    `json:"skip_reason,omitempty"`
 	Error             string    `json:"error,omitempty"`
+	DurationMs        int64     `json:"duration_ms,omitempty"`
 	Timestamp         time.Time `json:"timestamp"`
 }
 
@@ -66,28 +119,110 @@ type DryRunSummary struct {
 	TotalResources       int `json:"total_resources"`
 }
 
+// Reconciliation accounts for the difference between the resources Config reported and those processed
+type Reconciliation struct {
+	Reported                 int                 `json:"reported"`
+	AfterExistenceValidation ReconciliationStage `json:"after_existence_validation"`
+	AfterExclusions          ReconciliationStage `json:"after_exclusions"`
+	AfterAllowlist           ReconciliationStage `json:"after_allowlist"`
+	AfterDedup               ReconciliationStage `json:"after_dedup"`
+	Processed                ReconciliationStage `json:"processed"`
+}
+
+// ReconciliationStage records how many resources remain after a pipeline stage and how many it dropped
+type ReconciliationStage struct {
+	Count   int `json:"count"`
+	Dropped int `json:"dropped"`
+}
+
+func newReconciliationStage(previous, count int) ReconciliationStage {
+	return ReconciliationStage{Count: count, Dropped: previous - count}
+}
+
+// TotalDropped returns the number of reported resources dropped across all stages
+func (r *Reconciliation) TotalDropped() int {
+	return r.AfterExistenceValidation.Dropped + r.AfterExclusions.Dropped + r.AfterAllowlist.Dropped +
+		r.AfterDedup.Dropped + r.Processed.Dropped
+}
+
+// ExecutionLogEntry is one entry of the execution log embedded in ExecutionResult. Details always
+// serializes as an object; values shared across entries use the LogDetail keys.
 type ExecutionLogEntry struct {
-	Timestamp time.Time `json:"timestamp"`
-	Level     string    `json:"level"`
-	Message   string    `json:"message"`
-	Details   any       `json:"details,omitempty"`
+	Timestamp time.Time      `json:"timestamp"`
+	Level     string         `json:"level"`
+	Message   string         `json:"message"`
+	Details   map[string]any `json:"details,omitempty"`
+}
+
+// Common ExecutionLogEntry detail keys
+const (
+	LogDetailConfigRule = "config_rule" // Config rule being evaluated
+	LogDetailRegion     = "region"      // Region being evaluated
+	LogDetailLogGroup   = "log_group"   // Log group an entry is about
+	LogDetailError      = "error"       // Error message of a failed step
+)
+
+// executionLogLevels ranks the execution log levels from least to most severe
+var executionLogLevels = map[string]int{"DEBUG": 0, "INFO": 1, "WARN": 2, "ERROR": 3}
+
+// FilterExecutionLog returns the execution log entries at minLevel or more severe, in order; an
+// unknown minLevel returns every entry
+func (r *ExecutionResult) FilterExecutionLog(minLevel string) []ExecutionLogEntry {
+	minRank := executionLogLevels[strings.ToUpper(minLevel)]
+	var entries []ExecutionLogEntry
+	for _, entry := range r.ExecutionLog {
+		if executionLogLevels[entry.Level] >= minRank {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
 }
 
-func NewCommandProcessor(awsCfg aws.Config, options ProcessorOptions) *CommandProcessor {
-	var complianceService service.ComplianceServiceInterface
+// NewCommandProcessor builds a processor around a compliance service configured from awsCfg. It
+// returns the service's configuration error, such as a missing region with STRICT_REGION.
+func NewCommandProcessor(awsCfg aws.Config, options ProcessorOptions) (*CommandProcessor, error) {
+	if options.Logger == nil {
+		options.Logger = slog.Default()
+	}
+
+	complianceService, err := service.NewCheckedComplianceService(awsCfg, serviceOptions(options)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create compliance service: %w", err)
+	}
+	return newProcessorForService(complianceService, options), nil
+}
+
+// serviceOptions translates processor options into compliance service options
+func serviceOptions(options ProcessorOptions) []service.ComplianceServiceOption {
+	logger := options.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
 
+	serviceOpts := []service.ComplianceServiceOption{
+		service.WithLogger(logger),
+		service.WithDryRun(options.DryRun),
+		service.WithAuditActor(types.AuditActor{ExecutionID: options.ExecutionID, Role: options.Role}),
+	}
+	if options.AllowRetentionReduction {
+		serviceOpts = append(serviceOpts, service.WithAllowRetentionReduction(true))
+	}
+	if options.LogGroupLookupRetries != nil {
+		serviceOpts = append(serviceOpts, service.WithLogGroupLookupRetries(*options.LogGroupLookupRetries))
+	}
+	return serviceOpts
+}
+
+// newProcessorForService builds a processor around an existing compliance service, wrapping it
+// so that nothing is changed in dry-run mode
+func newProcessorForService(realService service.ComplianceServiceInterface, options ProcessorOptions) *CommandProcessor {
+	complianceService := realService
 	if options.DryRun {
-		// Create a dry-run wrapper for the compliance service
-		realService := service.NewComplianceService(awsCfg)
 		complianceService = NewDryRunComplianceService(realService)
-	} else {
-		complianceService = service.NewComplianceService(awsCfg)
 	}
 
-	h := handler.NewComplianceHandler(complianceService)
-
 	return &CommandProcessor{
-		handler:      h,
+		handler:      handler.NewComplianceHandler(complianceService),
 		service:      complianceService,
 		options:      options,
 		executionLog: []ExecutionLogEntry{},
@@ -96,18 +231,19 @@ func NewCommandProcessor(awsCfg aws.Config, options ProcessorOptions) *CommandPr
 
 func (p *CommandProcessor) Execute(ctx context.Context, request CommandRequest) (*ExecutionResult, error) {
 	startTime := time.Now()
+	request.applyRuleNames()
 
 	p.logEntry("INFO", "Starting command execution", map[string]any{
-		"type":        request.Type,
-		"config_rule": request.ConfigRuleName,
-		"region":      request.Region,
-		"batch_size":  request.BatchSize,
-		"dry_run":     p.options.DryRun,
+		"type":              request.Type,
+		LogDetailConfigRule: request.ConfigRuleName,
+		LogDetailRegion:     request.Region,
+		"batch_size":        request.BatchSize,
+		"dry_run":           p.options.DryRun,
 	})
 
 	result := &ExecutionResult{
 		ExecutionID:    p.options.ExecutionID,
-		Status:         "running",
+		Status:         ExecutionStatusRunning,
 		Mode:           p.getMode(),
 		ConfigRuleName: request.ConfigRuleName,
 		Region:         request.Region,
@@ -117,46 +253,97 @@ func (p *CommandProcessor) Execute(ctx context.Context, request CommandRequest)
 
 	switch request.Type {
 	case "config-rule-evaluation":
-		err := p.processConfigRuleEvaluation(ctx, request, result)
+		var err error
+		if request.LogGroupName != "" {
+			err = p.processSingleLogGroup(ctx, request, result)
+		} else {
+			err = p.processConfigRuleEvaluation(ctx, request, result)
+		}
 		if err != nil {
-			result.Status = "failed"
+			result.Status = ExecutionStatusFailed
 			result.Error = err.Error()
-			p.logEntry("ERROR", "Execution failed", map[string]any{"error": err.Error()})
+			p.logEntry("ERROR", "Execution failed", map[string]any{LogDetailError: err.Error()})
+			result.ExecutionLog = p.executionLog
 			return result, err
 		}
 	default:
 		err := fmt.Errorf("unsupported request type: %s", request.Type)
-		result.Status = "failed"
+		result.Status = ExecutionStatusFailed
 		result.Error = err.Error()
+		p.logEntry("ERROR", "Execution failed", map[string]any{LogDetailError: err.Error()})
+		result.ExecutionLog = p.executionLog
 		return result, err
 	}
 
-	result.Status = "completed"
+	if result.Status == ExecutionStatusRunning {
+		result.Status = executionStatus(result)
+	}
 	result.Duration = time.Since(startTime).String()
-	result.ExecutionLog = p.executionLog
 
 	p.logEntry("INFO", "Command execution completed", map[string]any{
+		"status":          result.Status,
 		"duration":        result.Duration,
 		"total_processed": result.TotalProcessed,
 		"success_count":   result.SuccessCount,
 		"failure_count":   result.FailureCount,
+		"no_action_count": result.NoActionCount,
+		"skipped_count":   result.SkippedCount,
 	})
+	result.ExecutionLog = p.executionLog
 
 	return result, nil
 }
 
 func (p *CommandProcessor) processConfigRuleEvaluation(ctx context.Context, request CommandRequest, result *ExecutionResult) error {
+	for _, skip := range request.SkippedResources {
+		p.logEntry("INFO", "Skipping resource", map[string]any{
+			LogDetailLogGroup: skip.Resource.ResourceName,
+			"account_id":      skip.Resource.AccountId,
+			"reason":          string(skip.Reason),
+		})
+		result.Resources = append(result.Resources, newResourceResult(types.RemediationResult{
+			LogGroupName: skip.Resource.ResourceName,
+			Region:       skip.Resource.Region,
+			Success:      true,
+			SkipReason:   string(skip.Reason),
+		}))
+		result.SkippedCount++
+	}
+
 	// Step 1: Get non-compliant resources
-	p.logEntry("INFO", "Retrieving non-compliant resources", map[string]any{
-		"config_rule": request.ConfigRuleName,
-		"region":      request.Region,
-	})
+	nonCompliantResources := request.Resources
+	if nonCompliantResources == nil {
+		p.logEntry("INFO", "Retrieving non-compliant resources", map[string]any{
+			LogDetailConfigRule: request.ConfigRuleName,
+			LogDetailRegion:     request.Region,
+		})
+
+		var err error
+		if len(request.ConfigRuleNames) > 0 {
+			nonCompliantResources, err = p.getMergedNonCompliantResources(ctx, request)
+		} else {
+			nonCompliantResources, err = p.service.GetNonCompliantResources(ctx, request.ConfigRuleName, request.Region)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to retrieve non-compliant resources: %w", err)
+		}
 
-	nonCompliantResources, err := p.service.GetNonCompliantResources(ctx, request.ConfigRuleName, request.Region)
-	if err != nil {
-		return fmt.Errorf("failed to retrieve non-compliant resources: %w", err)
+		if p.options.IncludeCompliant {
+			if err := p.addCompliantResources(ctx, request, result); err != nil {
+				return err
+			}
+		}
+	} else {
+		p.logEntry("INFO", "Using supplied resources instead of querying Config", map[string]any{
+			LogDetailConfigRule: request.ConfigRuleName,
+			LogDetailRegion:     request.Region,
+			"count":             len(nonCompliantResources),
+		})
 	}
 
+	reconciliation := &Reconciliation{Reported: len(nonCompliantResources)}
+	result.Reconciliation = reconciliation
+
 	if len(nonCompliantResources) == 0 {
 		p.logEntry("INFO", "No non-compliant resources found", nil)
 		result.TotalProcessed = 0
@@ -172,6 +359,7 @@ func (p *CommandProcessor) processConfigRuleEvaluation(ctx context.Context, requ
 	if err != nil {
 		return fmt.Errorf("failed to validate resource existence: %w", err)
 	}
+	reconciliation.AfterExistenceValidation = newReconciliationStage(len(nonCompliantResources), len(validResources))
 
 	if len(validResources) == 0 {
 		p.logEntry("INFO", "No valid resources found after validation", nil)
@@ -184,143 +372,296 @@ func (p *CommandProcessor) processConfigRuleEvaluation(ctx context.Context, requ
 		"filtered_count": len(nonCompliantResources) - len(validResources),
 	})
 
-	// Step 3: Process resources
-	if p.options.DryRun {
-		return p.processDryRun(ctx, request, validResources, result)
-	} else {
-		return p.processResources(ctx, request, validResources, result)
+	// The default chain with the annotation keyword allowlist applied before deduplication
+	filters := service.NewDefaultFilterChain(service.AnnotationKeywordFilter(p.options.AnnotationKeywords))
+	uniqueResources, skipped := filters.Apply(ctx, validResources)
+	duplicates, unmatched := 0, 0
+	for _, skip := range skipped {
+		switch skip.Reason {
+		case service.SkipReasonDuplicate:
+			duplicates++
+		case service.SkipReasonAnnotationKeyword:
+			unmatched++
+		}
+		p.logEntry("INFO", "Skipping filtered resource", map[string]any{
+			LogDetailLogGroup: skip.Resource.ResourceName,
+			"reason":          string(skip.Reason),
+		})
 	}
-}
 
-func (p *CommandProcessor) processResources(ctx context.Context, request CommandRequest, resources []types.NonCompliantResource, result *ExecutionResult) error {
-	batchRequest := types.BatchComplianceRequest{
+	afterExclusions := len(validResources) - (len(skipped) - duplicates - unmatched)
+	afterAllowlist := afterExclusions - unmatched
+	reconciliation.AfterExclusions = newReconciliationStage(len(validResources), afterExclusions)
+	reconciliation.AfterAllowlist = newReconciliationStage(afterExclusions, afterAllowlist)
+	reconciliation.AfterDedup = newReconciliationStage(afterAllowlist, len(uniqueResources))
+
+	// Step 3: Process resources. In dry-run mode the service was built WithDryRun(true), so the
+	// batch makes every decision it would in apply mode and only skips the changes
+	if p.options.DryRun {
+		p.logEntry("INFO", "Running in dry-run mode", map[string]any{
+			"total_resources": len(uniqueResources),
+		})
+	}
+	batchResult, err := p.service.ProcessNonCompliantResourcesOptimized(ctx, types.BatchComplianceRequest{
 		ConfigRuleName:      request.ConfigRuleName,
-		NonCompliantResults: resources,
+		NonCompliantResults: uniqueResources,
 		Region:              request.Region,
 		BatchSize:           request.BatchSize,
-	}
-
-	batchResult, err := p.service.ProcessNonCompliantResourcesOptimized(ctx, batchRequest)
+	})
 	if err != nil {
 		return fmt.Errorf("batch processing failed: %w", err)
 	}
-
-	result.TotalProcessed = batchResult.TotalProcessed
-	result.SuccessCount = batchResult.SuccessCount
-	result.FailureCount = batchResult.FailureCount
-
-	// Convert batch results to resource results
-	for _, r := range batchResult.Results {
-		resourceResult := ResourceResult{
-			ResourceID:        r.LogGroupName,
-			ResourceName:      r.LogGroupName,
-			Status:            getResourceStatus(r),
-			EncryptionApplied: r.EncryptionApplied,
-			RetentionApplied:  r.RetentionApplied,
-			Timestamp:         time.Now(),
-		}
-		if r.Error != nil {
-			resourceResult.Error = r.Error.Error()
-		}
-		result.Resources = append(result.Resources, resourceResult)
+	if p.options.DryRun {
+		result.DryRunSummary = newDryRunSummary(batchResult)
 	}
+	p.recordBatchResult(result, batchResult)
+
+	reconciliation.Processed = newReconciliationStage(len(uniqueResources), result.TotalProcessed)
+	p.logEntry("INFO", "Reconciled reported and processed resources", map[string]any{
+		"reconciliation": reconciliation,
+	})
 
 	return nil
 }
 
-func (p *CommandProcessor) processDryRun(ctx context.Context, request CommandRequest, resources []types.NonCompliantResource, result *ExecutionResult) error {
-	dryRunSummary := &DryRunSummary{
-		TotalResources: len(resources),
+// getMergedNonCompliantResources retrieves the non-compliant resources of every rule in
+// ConfigRuleNames and merges them, so each log group is remediated once for all its requirements
+func (p *CommandProcessor) getMergedNonCompliantResources(ctx context.Context, request CommandRequest) ([]types.NonCompliantResource, error) {
+	rules := make([]service.RuleResources, 0, len(request.ConfigRuleNames))
+	reported := 0
+	for _, configRuleName := range request.ConfigRuleNames {
+		resources, err := p.service.GetNonCompliantResources(ctx, configRuleName, request.Region)
+		if err != nil {
+			return nil, fmt.Errorf("config rule %s: %w", configRuleName, err)
+		}
+		reported += len(resources)
+		rules = append(rules, service.RuleResources{ConfigRuleName: configRuleName, Resources: resources})
 	}
 
-	p.logEntry("INFO", "Running in dry-run mode", map[string]any{
-		"total_resources": len(resources),
+	merged, err := service.MergeRuleResources(types.NewRuleClassifier(), rules)
+	if err != nil {
+		return nil, err
+	}
+
+	p.logEntry("INFO", "Merged non-compliant resources of several rules", map[string]any{
+		"config_rules": request.ConfigRuleNames,
+		"reported":     reported,
+		"merged":       len(merged),
 	})
+	return merged, nil
+}
 
-	// Analyze each resource to determine what would be done
-	ruleClassifier := types.NewRuleClassifier()
-	ruleType := ruleClassifier.ClassifyRule(request.ConfigRuleName)
+// processSingleLogGroup evaluates one named log group against its live state and remediates it
+// for the requested Config rule, without querying Config for non-compliant resources
+func (p *CommandProcessor) processSingleLogGroup(ctx context.Context, request CommandRequest, result *ExecutionResult) error {
+	p.logEntry("INFO", "Evaluating single log group", map[string]any{
+		LogDetailLogGroup:   request.LogGroupName,
+		LogDetailConfigRule: request.ConfigRuleName,
+		LogDetailRegion:     request.Region,
+	})
 
-	for _, resource := range resources {
-		// Get current state
-		compliance, err := p.analyzeResourceCompliance(ctx, resource, ruleType)
-		if err != nil {
-			p.logEntry("WARN", "Failed to analyze resource", map[string]any{
-				"resource": resource.ResourceName,
-				"error":    err.Error(),
-			})
-			result.FailureCount++
-			continue
-		}
+	// Scope the live evaluation to the requirement this Config rule checks; a rule that checks
+	// neither would otherwise report success without looking at the group
+	if request.ConfigRuleName == "" {
+		return fmt.Errorf("config rule name is required to remediate log group %s", request.LogGroupName)
+	}
+	ruleType := types.NewRuleClassifier().ClassifyRule(request.ConfigRuleName)
+	if ruleType == types.RuleTypeUnknown {
+		return fmt.Errorf("config rule %s checks neither encryption nor retention", request.ConfigRuleName)
+	}
 
-		resourceResult := ResourceResult{
-			ResourceID:   resource.ResourceId,
-			ResourceName: resource.ResourceName,
-			Status:       "dry-run",
-			Timestamp:    time.Now(),
+	compliance, err := p.service.EvaluateCompliance(ctx, request.LogGroupName, request.Region)
+	if err != nil {
+		return fmt.Errorf("failed to evaluate log group %s: %w", request.LogGroupName, err)
+	}
+
+	remediation, err := p.service.RemediateLogGroup(ctx, service.ScopeComplianceToRule(compliance, ruleType))
+	if err != nil {
+		remediation = &types.RemediationResult{
+			LogGroupName: request.LogGroupName,
+			Region:       request.Region,
+			Error:        err,
 		}
+	}
+
+	result.Resources = append(result.Resources, newResourceResult(*remediation))
 
+	result.TotalProcessed = 1
+	switch {
+	case !remediation.Success:
+		result.FailureCount = 1
+	case remediation.NoActionNeeded:
+		result.SuccessCount = 1
+		result.NoActionCount = 1
+	default:
+		result.SuccessCount = 1
+	}
+	if remediation.Success && remediation.SkipReason != "" {
+		result.SkippedCount = 1
+	}
+
+	if p.options.DryRun {
+		result.DryRunSummary = &DryRunSummary{TotalResources: 1}
 		if compliance.MissingEncryption {
-			dryRunSummary.WouldApplyEncryption++
-			resourceResult.EncryptionApplied = true
-			p.logEntry("INFO", "Would apply encryption", map[string]any{
-				"resource": resource.ResourceName,
-			})
+			result.DryRunSummary.WouldApplyEncryption = 1
 		}
-
 		if compliance.MissingRetention {
-			dryRunSummary.WouldApplyRetention++
-			resourceResult.RetentionApplied = true
-			p.logEntry("INFO", "Would apply retention", map[string]any{
-				"resource": resource.ResourceName,
-			})
+			result.DryRunSummary.WouldApplyRetention = 1
 		}
-
-		if !compliance.MissingEncryption && !compliance.MissingRetention {
-			dryRunSummary.AlreadyCompliant++
-			resourceResult.Status = "compliant"
-			p.logEntry("INFO", "Resource already compliant", map[string]any{
-				"resource": resource.ResourceName,
-			})
+		if remediation.NoActionNeeded {
+			result.DryRunSummary.AlreadyCompliant = 1
 		}
+	}
 
-		result.Resources = append(result.Resources, resourceResult)
-		result.SuccessCount++
+	return nil
+}
+
+// recordBatchResult adds a batch result, applied or simulated, to the execution result. A
+// simulated change is reported with status dry-run instead of success.
+func (p *CommandProcessor) recordBatchResult(result *ExecutionResult, batchResult *types.BatchRemediationResult) {
+	batchExecution := ExecutionResultFromBatch(batchResult, result.ExecutionID, result.Mode, result.ConfigRuleName, result.Region)
+	result.TotalProcessed = batchExecution.TotalProcessed
+	result.SuccessCount = batchExecution.SuccessCount
+	result.FailureCount = batchExecution.FailureCount
+	result.NoActionCount = batchExecution.NoActionCount
+	result.SkippedCount += batchExecution.SkippedCount
+	result.Deferred = batchExecution.Deferred
+	if batchExecution.Status == ExecutionStatusFailed {
+		result.Status = ExecutionStatusFailed
+	}
+	for _, resource := range batchExecution.Resources {
+		if p.options.DryRun && resource.Status == "success" {
+			resource.Status = "dry-run"
+		}
+		result.Resources = append(result.Resources, resource)
 	}
+}
 
-	result.TotalProcessed = len(resources)
-	result.DryRunSummary = dryRunSummary
+// ExecutionResultFromBatch maps a batch remediation result onto an execution result, so every
+// entrypoint reports batch runs the same way. Every remediated resource is listed, including
+// compliant ones; the status follows the resource counts unless the batch was cut short by
+// cancellation, a timeout or FAIL_FAST, in which case it is "failed".
+func ExecutionResultFromBatch(batch *types.BatchRemediationResult, executionID, mode, configRuleName, region string) *ExecutionResult {
+	result := &ExecutionResult{
+		ExecutionID:    executionID,
+		Mode:           mode,
+		ConfigRuleName: configRuleName,
+		Region:         region,
+		TotalProcessed: batch.TotalProcessed,
+		SuccessCount:   batch.SuccessCount,
+		FailureCount:   batch.FailureCount,
+		NoActionCount:  batch.NoActionCount,
+		Deferred:       batch.Deferred,
+		Duration:       batch.ProcessingDuration.String(),
+		Timestamp:      time.Now(),
+		Resources:      make([]ResourceResult, 0, len(batch.Results)),
+	}
+	for _, r := range batch.Results {
+		if r.Success && r.SkipReason != "" {
+			result.SkippedCount++
+		}
+		result.Resources = append(result.Resources, newResourceResult(r))
+	}
 
-	return nil
+	result.Status = executionStatus(result)
+	if batch.Cancelled || batch.TimedOut || batch.AbortedOnFailure {
+		result.Status = ExecutionStatusFailed
+	}
+	return result
 }
 
-func (p *CommandProcessor) analyzeResourceCompliance(ctx context.Context, resource types.NonCompliantResource, ruleType types.RuleType) (types.ComplianceResult, error) {
-	// Context will be used for AWS API calls when fetching actual log group configuration
-	_ = ctx // Currently unused but kept for future AWS API integration
-	// For now, we'll return based on the rule type
-	result := types.ComplianceResult{
-		LogGroupName: resource.ResourceName,
-		Region:       resource.Region,
-		AccountId:    resource.AccountId,
-	}
-
-	switch ruleType {
-	case types.RuleTypeEncryption:
-		result.MissingEncryption = true
-		result.MissingRetention = false
-	case types.RuleTypeRetention:
-		result.MissingRetention = true
-		result.MissingEncryption = false
+// executionStatus derives the status of a finished run from its success, failure and skip counts
+func executionStatus(result *ExecutionResult) string {
+	switch {
+	case result.FailureCount > 0 && result.SuccessCount == 0:
+		return ExecutionStatusFailed
+	case result.FailureCount > 0:
+		return ExecutionStatusPartial
+	case result.SkippedCount > 0:
+		return ExecutionStatusCompletedWithSkips
 	default:
-		// Unknown rule type
-		result.MissingEncryption = false
-		result.MissingRetention = false
+		return ExecutionStatusCompleted
 	}
+}
 
-	return result, nil
+// newResourceResult reports a single log group's remediation outcome
+func newResourceResult(remediation types.RemediationResult) ResourceResult {
+	resource := ResourceResult{
+		ResourceID:        remediation.LogGroupName,
+		ResourceName:      remediation.LogGroupName,
+		Status:            remediation.Status(),
+		EncryptionApplied: remediation.EncryptionApplied,
+		RetentionApplied:  remediation.RetentionApplied,
+		SkipReason:        remediation.SkipReason,
+		DurationMs:        remediation.Duration.Milliseconds(),
+		Timestamp:         time.Now(),
+	}
+	if remediation.Error != nil {
+		resource.Error = remediation.Error.Error()
+	}
+	return resource
 }
 
-func (p *CommandProcessor) logEntry(level, message string, details any) {
+// newDryRunSummary counts the changes a simulated batch would apply
+func newDryRunSummary(batchResult *types.BatchRemediationResult) *DryRunSummary {
+	summary := &DryRunSummary{TotalResources: batchResult.TotalProcessed}
+	for _, r := range batchResult.Results {
+		if r.EncryptionApplied {
+			summary.WouldApplyEncryption++
+		}
+		if r.RetentionApplied {
+			summary.WouldApplyRetention++
+		}
+		if r.NoActionNeeded {
+			summary.AlreadyCompliant++
+		}
+	}
+	return summary
+}
+
+// addCompliantResources lists the log groups Config reports compliant with every requested rule,
+// with status "compliant"
+func (p *CommandProcessor) addCompliantResources(ctx context.Context, request CommandRequest, result *ExecutionResult) error {
+	rules := request.ConfigRuleNames
+	if len(rules) == 0 {
+		rules = []string{request.ConfigRuleName}
+	}
+
+	var order []string
+	compliantRules := make(map[string]int)
+	for _, rule := range rules {
+		resources, err := p.service.GetCompliantResources(ctx, rule, request.Region)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve compliant resources for rule %s: %w", rule, err)
+		}
+		seen := make(map[string]bool, len(resources))
+		for _, resource := range resources {
+			if seen[resource.ResourceName] {
+				continue
+			}
+			seen[resource.ResourceName] = true
+			if compliantRules[resource.ResourceName] == 0 {
+				order = append(order, resource.ResourceName)
+			}
+			compliantRules[resource.ResourceName]++
+		}
+	}
+
+	for _, name := range order {
+		if compliantRules[name] < len(rules) {
+			continue
+		}
+		result.Resources = append(result.Resources, ResourceResult{
+			ResourceID:   name,
+			ResourceName: name,
+			Status:       "compliant",
+			Timestamp:    time.Now(),
+		})
+	}
+	return nil
+}
+
+func (p *CommandProcessor) logEntry(level, message string, details map[string]any) {
 	entry := ExecutionLogEntry{
 		Timestamp: time.Now(),
 		Level:     level,
@@ -330,28 +671,30 @@ func (p *CommandProcessor) logEntry(level, message string, details any) {
 	p.executionLog = append(p.executionLog, entry)
 
 	// Also log to slog
+	logger := p.getLogger()
 	switch level {
 	case "ERROR":
-		slog.Error(message, "details", details)
+		logger.Error(message, "details", details)
 	case "WARN":
-		slog.Warn(message, "details", details)
+		logger.Warn(message, "details", details)
 	case "INFO":
-		slog.Info(message, "details", details)
+		logger.Info(message, "details", details)
 	case "DEBUG":
-		slog.Debug(message, "details", details)
+		logger.Debug(message, "details", details)
 	}
 }
 
-func (p *CommandProcessor) getMode() string {
-	if p.options.DryRun {
-		return "dry-run"
+// getLogger returns the configured logger, falling back to the process default
+func (p *CommandProcessor) getLogger() *slog.Logger {
+	if p.options.Logger == nil {
+		return slog.Default()
 	}
-	return "apply"
+	return p.options.Logger
 }
 
-func getResourceStatus(result types.RemediationResult) string {
-	if result.Success {
-		return "success"
+func (p *CommandProcessor) getMode() string {
+	if p.options.DryRun {
+		return string(service.ExecutionModeDryRun)
 	}
-	return "failed"
+	return string(service.ExecutionModeApply)
 }
@@ -1,13 +1,27 @@
 package container
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/zsoftly/logguardian/internal/service"
+	"github.com/zsoftly/logguardian/internal/testutil"
 	"github.com/zsoftly/logguardian/internal/types"
 )
 
@@ -21,6 +35,11 @@ func (m *MockComplianceService) GetNonCompliantResources(ctx context.Context, co
 	return args.Get(0).([]types.NonCompliantResource), args.Error(1)
 }
 
+func (m *MockComplianceService) GetCompliantResources(ctx context.Context, configRuleName, region string) ([]types.NonCompliantResource, error) {
+	args := m.Called(ctx, configRuleName, region)
+	return args.Get(0).([]types.NonCompliantResource), args.Error(1)
+}
+
 func (m *MockComplianceService) ValidateResourceExistence(ctx context.Context, resources []types.NonCompliantResource) ([]types.NonCompliantResource, error) {
 	args := m.Called(ctx, resources)
 	return args.Get(0).([]types.NonCompliantResource), args.Error(1)
@@ -34,6 +53,14 @@ func (m *MockComplianceService) RemediateLogGroup(ctx context.Context, complianc
 	return args.Get(0).(*types.RemediationResult), args.Error(1)
 }
 
+func (m *MockComplianceService) RemediateSingleLogGroup(ctx context.Context, region, logGroupName string, ruleType types.RuleType) (*types.RemediationResult, error) {
+	args := m.Called(ctx, region, logGroupName, ruleType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*types.RemediationResult), args.Error(1)
+}
+
 func (m *MockComplianceService) ProcessNonCompliantResourcesOptimized(ctx context.Context, request types.BatchComplianceRequest) (*types.BatchRemediationResult, error) {
 	args := m.Called(ctx, request)
 	if args.Get(0) == nil {
@@ -47,6 +74,11 @@ func (m *MockComplianceService) EvaluateCompliance(ctx context.Context, logGroup
 	return args.Get(0).(types.ComplianceResult), args.Error(1)
 }
 
+func (m *MockComplianceService) ReportEvaluation(ctx context.Context, resultToken string, configItem types.ConfigurationItem, result *types.RemediationResult) error {
+	args := m.Called(ctx, resultToken, configItem, result)
+	return args.Error(0)
+}
+
 func TestNewCommandProcessor(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -73,8 +105,9 @@ func TestNewCommandProcessor(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cfg := aws.Config{}
-			processor := NewCommandProcessor(cfg, tt.options)
+			processor, err := NewCommandProcessor(cfg, tt.options)
 
+			require.NoError(t, err)
 			assert.NotNil(t, processor)
 			assert.NotNil(t, processor.handler)
 			assert.NotNil(t, processor.service)
@@ -84,6 +117,17 @@ func TestNewCommandProcessor(t *testing.T) {
 	}
 }
 
+func TestNewCommandProcessor_StrictRegion(t *testing.T) {
+	t.Setenv("STRICT_REGION", "true")
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+
+	processor, err := NewCommandProcessor(aws.Config{}, ProcessorOptions{ExecutionID: "test-strict"})
+
+	require.ErrorIs(t, err, service.ErrRegionNotConfigured)
+	assert.Nil(t, processor)
+}
+
 func TestCommandProcessor_Execute(t *testing.T) {
 	ctx := context.Background()
 
@@ -202,7 +246,8 @@ func TestCommandProcessor_Execute(t *testing.T) {
 
 			// Need to create handler manually since we're using mock
 			cfg := aws.Config{}
-			realProcessor := NewCommandProcessor(cfg, tt.options)
+			realProcessor, err := NewCommandProcessor(cfg, tt.options)
+			require.NoError(t, err)
 			processor.handler = realProcessor.handler
 
 			result, err := processor.Execute(ctx, tt.request)
@@ -223,6 +268,570 @@ func TestCommandProcessor_Execute(t *testing.T) {
 	}
 }
 
+func TestCommandProcessor_Execute_Reconciliation(t *testing.T) {
+	ctx := context.Background()
+
+	reported := []types.NonCompliantResource{
+		testutil.NewTestNonCompliantResource("/aws/lambda/a"),
+		testutil.NewTestNonCompliantResource("/aws/lambda/b"),
+		testutil.NewTestNonCompliantResource("/aws/lambda/deleted"),
+		testutil.NewTestNonCompliantResource("/aws/lambda/a"),
+		testutil.NewTestNonCompliantResource("/aws/lambda/c"),
+	}
+	existing := []types.NonCompliantResource{reported[0], reported[1], reported[3], reported[4]}
+	unique := []types.NonCompliantResource{reported[0], reported[1], reported[4]}
+
+	mockService := new(MockComplianceService)
+	mockService.On("GetNonCompliantResources", ctx, "test-rule", "ca-central-1").Return(reported, nil)
+	mockService.On("ValidateResourceExistence", ctx, reported).Return(existing, nil)
+	mockService.On("ProcessNonCompliantResourcesOptimized", ctx, mock.MatchedBy(func(request types.BatchComplianceRequest) bool {
+		return assert.ObjectsAreEqual(unique, request.NonCompliantResults)
+	})).Return(&types.BatchRemediationResult{
+		TotalProcessed: 2,
+		SuccessCount:   2,
+	}, nil)
+
+	processor := &CommandProcessor{
+		service:      mockService,
+		options:      ProcessorOptions{ExecutionID: "test-reconcile"},
+		executionLog: []ExecutionLogEntry{},
+	}
+
+	result, err := processor.Execute(ctx, CommandRequest{
+		Type:           "config-rule-evaluation",
+		ConfigRuleName: "test-rule",
+		Region:         "ca-central-1",
+		BatchSize:      10,
+	})
+
+	assert.NoError(t, err)
+	mockService.AssertExpectations(t)
+
+	r := result.Reconciliation
+	assert.NotNil(t, r)
+	assert.Equal(t, 5, r.Reported)
+	assert.Equal(t, ReconciliationStage{Count: 4, Dropped: 1}, r.AfterExistenceValidation)
+	assert.Equal(t, ReconciliationStage{Count: 4, Dropped: 0}, r.AfterExclusions)
+	assert.Equal(t, ReconciliationStage{Count: 4, Dropped: 0}, r.AfterAllowlist)
+	assert.Equal(t, ReconciliationStage{Count: 3, Dropped: 1}, r.AfterDedup)
+	assert.Equal(t, ReconciliationStage{Count: 2, Dropped: 1}, r.Processed)
+	assert.Equal(t, r.Reported, r.Processed.Count+r.TotalDropped())
+	assert.Equal(t, result.TotalProcessed, r.Processed.Count)
+}
+
+func TestCommandProcessor_Execute_AnnotationKeywords(t *testing.T) {
+	ctx := context.Background()
+
+	retention := testutil.NewTestNonCompliantResource("/aws/lambda/retention")
+	retention.Annotation = "Retention period not set"
+	encryption := testutil.NewTestNonCompliantResource("/aws/lambda/encryption")
+	encryption.Annotation = "Not encrypted with KMS"
+	reported := []types.NonCompliantResource{retention, encryption, retention}
+
+	mockService := new(MockComplianceService)
+	mockService.On("GetNonCompliantResources", ctx, "test-rule", "ca-central-1").Return(reported, nil)
+	mockService.On("ValidateResourceExistence", ctx, reported).Return(reported, nil)
+	mockService.On("ProcessNonCompliantResourcesOptimized", ctx, mock.MatchedBy(func(request types.BatchComplianceRequest) bool {
+		return assert.ObjectsAreEqual([]types.NonCompliantResource{retention}, request.NonCompliantResults)
+	})).Return(&types.BatchRemediationResult{TotalProcessed: 1, SuccessCount: 1}, nil)
+
+	processor := &CommandProcessor{
+		service:      mockService,
+		options:      ProcessorOptions{AnnotationKeywords: []string{"retention"}},
+		executionLog: []ExecutionLogEntry{},
+	}
+
+	result, err := processor.Execute(ctx, CommandRequest{
+		Type:           "config-rule-evaluation",
+		ConfigRuleName: "test-rule",
+		Region:         "ca-central-1",
+		BatchSize:      10,
+	})
+
+	require.NoError(t, err)
+	mockService.AssertExpectations(t)
+
+	r := result.Reconciliation
+	assert.Equal(t, ReconciliationStage{Count: 3, Dropped: 0}, r.AfterExclusions)
+	assert.Equal(t, ReconciliationStage{Count: 2, Dropped: 1}, r.AfterAllowlist)
+	assert.Equal(t, ReconciliationStage{Count: 1, Dropped: 1}, r.AfterDedup)
+	assert.Equal(t, r.Reported, r.Processed.Count+r.TotalDropped())
+}
+
+func TestCommandProcessor_Execute_MultipleRules(t *testing.T) {
+	ctx := context.Background()
+
+	unencrypted := testutil.NewTestNonCompliantResource("/aws/lambda/unencrypted")
+	both := testutil.NewTestNonCompliantResource("/aws/lambda/both")
+	noRetention := testutil.NewTestNonCompliantResource("/aws/lambda/no-retention")
+
+	withRequirements := func(resource types.NonCompliantResource, requirements ...types.RuleType) types.NonCompliantResource {
+		resource.Requirements = requirements
+		return resource
+	}
+	merged := []types.NonCompliantResource{
+		withRequirements(unencrypted, types.RuleTypeEncryption),
+		withRequirements(both, types.RuleTypeEncryption, types.RuleTypeRetention),
+		withRequirements(noRetention, types.RuleTypeRetention),
+	}
+
+	newMockService := func() *MockComplianceService {
+		mockService := new(MockComplianceService)
+		mockService.On("GetNonCompliantResources", ctx, "cw-lg-kms-encryption", "ca-central-1").
+			Return([]types.NonCompliantResource{unencrypted, both}, nil)
+		mockService.On("GetNonCompliantResources", ctx, "cw-lg-retention-min", "ca-central-1").
+			Return([]types.NonCompliantResource{both, noRetention}, nil)
+		mockService.On("ValidateResourceExistence", ctx, merged).Return(merged, nil)
+		return mockService
+	}
+	request := CommandRequest{
+		Type:            "config-rule-evaluation",
+		ConfigRuleNames: []string{"cw-lg-kms-encryption", "cw-lg-retention-min"},
+		Region:          "ca-central-1",
+		BatchSize:       10,
+	}
+
+	t.Run("one combined remediation", func(t *testing.T) {
+		mockService := newMockService()
+		mockService.On("ProcessNonCompliantResourcesOptimized", ctx, mock.MatchedBy(func(batch types.BatchComplianceRequest) bool {
+			return batch.ConfigRuleName == "cw-lg-kms-encryption,cw-lg-retention-min" &&
+				assert.ObjectsAreEqual(merged, batch.NonCompliantResults)
+		})).Return(&types.BatchRemediationResult{TotalProcessed: 3, SuccessCount: 3}, nil).Once()
+
+		processor := &CommandProcessor{service: mockService, executionLog: []ExecutionLogEntry{}}
+		result, err := processor.Execute(ctx, request)
+
+		require.NoError(t, err)
+		mockService.AssertExpectations(t)
+		assert.Equal(t, "cw-lg-kms-encryption,cw-lg-retention-min", result.ConfigRuleName)
+		assert.Equal(t, 3, result.Reconciliation.Reported)
+		assert.Equal(t, 3, result.TotalProcessed)
+	})
+
+	t.Run("dry run previews every flagged requirement", func(t *testing.T) {
+		mockService := newMockService()
+		mockService.On("ProcessNonCompliantResourcesOptimized", ctx, mock.MatchedBy(func(batch types.BatchComplianceRequest) bool {
+			return assert.ObjectsAreEqual(merged, batch.NonCompliantResults)
+		})).Return(&types.BatchRemediationResult{
+			TotalProcessed: 3,
+			SuccessCount:   3,
+			Results: []types.RemediationResult{
+				{LogGroupName: "/aws/lambda/unencrypted", EncryptionApplied: true, Success: true},
+				{LogGroupName: "/aws/lambda/both", EncryptionApplied: true, RetentionApplied: true, Success: true},
+				{LogGroupName: "/aws/lambda/no-retention", RetentionApplied: true, Success: true},
+			},
+		}, nil).Once()
+
+		processor := &CommandProcessor{service: mockService, options: ProcessorOptions{DryRun: true}, executionLog: []ExecutionLogEntry{}}
+		result, err := processor.Execute(ctx, request)
+
+		require.NoError(t, err)
+		require.NotNil(t, result.DryRunSummary)
+		assert.Equal(t, 2, result.DryRunSummary.WouldApplyEncryption)
+		assert.Equal(t, 2, result.DryRunSummary.WouldApplyRetention)
+		assert.Equal(t, 0, result.DryRunSummary.AlreadyCompliant)
+	})
+
+	t.Run("rule of unknown type", func(t *testing.T) {
+		mockService := new(MockComplianceService)
+		mockService.On("GetNonCompliantResources", ctx, "cw-lg-kms-encryption", "ca-central-1").Return([]types.NonCompliantResource{unencrypted}, nil)
+		mockService.On("GetNonCompliantResources", ctx, "unclassified-rule", "ca-central-1").Return([]types.NonCompliantResource{both}, nil)
+
+		processor := &CommandProcessor{service: mockService, executionLog: []ExecutionLogEntry{}}
+		_, err := processor.Execute(ctx, CommandRequest{
+			Type:            "config-rule-evaluation",
+			ConfigRuleNames: []string{"cw-lg-kms-encryption", "unclassified-rule"},
+			Region:          "ca-central-1",
+			BatchSize:       10,
+		})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unclassified-rule checks neither encryption nor retention")
+		mockService.AssertNotCalled(t, "ProcessNonCompliantResourcesOptimized", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCommandProcessor_Execute_SingleLogGroup(t *testing.T) {
+	ctx := context.Background()
+	request := CommandRequest{
+		Type:           "config-rule-evaluation",
+		ConfigRuleName: "cloudwatch-log-group-retention",
+		Region:         "ca-central-1",
+		BatchSize:      10,
+		LogGroupName:   "/aws/lambda/foo",
+	}
+
+	t.Run("remediates the named group for the rule", func(t *testing.T) {
+		mockService := new(MockComplianceService)
+		mockService.On("EvaluateCompliance", ctx, "/aws/lambda/foo", "ca-central-1").Return(types.ComplianceResult{
+			LogGroupName:      "/aws/lambda/foo",
+			Region:            "ca-central-1",
+			MissingEncryption: true,
+			MissingRetention:  true,
+		}, nil)
+		mockService.On("RemediateLogGroup", ctx, types.ComplianceResult{
+			LogGroupName:     "/aws/lambda/foo",
+			Region:           "ca-central-1",
+			MissingRetention: true,
+		}).Return(&types.RemediationResult{
+			LogGroupName:     "/aws/lambda/foo",
+			Region:           "ca-central-1",
+			RetentionApplied: true,
+			Success:          true,
+		}, nil)
+
+		processor := &CommandProcessor{
+			service:      mockService,
+			options:      ProcessorOptions{ExecutionID: "test-single"},
+			executionLog: []ExecutionLogEntry{},
+		}
+
+		result, err := processor.Execute(ctx, request)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "completed", result.Status)
+		assert.Equal(t, 1, result.TotalProcessed)
+		assert.Equal(t, 1, result.SuccessCount)
+		assert.Len(t, result.Resources, 1)
+		assert.True(t, result.Resources[0].RetentionApplied)
+		assert.False(t, result.Resources[0].EncryptionApplied)
+		mockService.AssertExpectations(t)
+		mockService.AssertNotCalled(t, "GetNonCompliantResources", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("missing group fails with a clear error", func(t *testing.T) {
+		mockService := new(MockComplianceService)
+		mockService.On("EvaluateCompliance", ctx, "/aws/lambda/foo", "ca-central-1").
+			Return(types.ComplianceResult{}, errors.New("log group /aws/lambda/foo not found in region ca-central-1"))
+
+		processor := &CommandProcessor{
+			service:      mockService,
+			options:      ProcessorOptions{ExecutionID: "test-single-missing"},
+			executionLog: []ExecutionLogEntry{},
+		}
+
+		result, err := processor.Execute(ctx, request)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+		assert.Equal(t, "failed", result.Status)
+		mockService.AssertNotCalled(t, "RemediateLogGroup", mock.Anything, mock.Anything)
+	})
+
+	for _, rule := range []string{"", "unclassified-rule"} {
+		t.Run(fmt.Sprintf("rule %q is rejected", rule), func(t *testing.T) {
+			mockService := new(MockComplianceService)
+
+			processor := &CommandProcessor{
+				service:      mockService,
+				options:      ProcessorOptions{ExecutionID: "test-single-rule"},
+				executionLog: []ExecutionLogEntry{},
+			}
+
+			unscoped := request
+			unscoped.ConfigRuleName = rule
+			result, err := processor.Execute(ctx, unscoped)
+
+			require.Error(t, err)
+			assert.Equal(t, "failed", result.Status)
+			assert.Zero(t, result.SuccessCount)
+			mockService.AssertNotCalled(t, "EvaluateCompliance", mock.Anything, mock.Anything, mock.Anything)
+			mockService.AssertNotCalled(t, "RemediateLogGroup", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCommandProcessor_Execute_IncludeCompliant(t *testing.T) {
+	ctx := context.Background()
+	resources := []types.NonCompliantResource{
+		testutil.NewTestNonCompliantResource("/aws/lambda/changed"),
+		testutil.NewTestNonCompliantResource("/aws/lambda/compliant"),
+	}
+
+	resourceStatuses := func(result *ExecutionResult) map[string]string {
+		statuses := make(map[string]string, len(result.Resources))
+		for _, r := range result.Resources {
+			statuses[r.ResourceName] = r.Status
+		}
+		return statuses
+	}
+
+	for _, includeCompliant := range []bool{false, true} {
+		t.Run(fmt.Sprintf("apply include_compliant=%t", includeCompliant), func(t *testing.T) {
+			mockService := new(MockComplianceService)
+			mockService.On("GetNonCompliantResources", ctx, "test-rule", "ca-central-1").Return(resources, nil)
+			mockService.On("ValidateResourceExistence", ctx, resources).Return(resources, nil)
+			mockService.On("ProcessNonCompliantResourcesOptimized", ctx, mock.Anything).Return(&types.BatchRemediationResult{
+				TotalProcessed: 2,
+				SuccessCount:   2,
+				NoActionCount:  1,
+				Results: []types.RemediationResult{
+					{LogGroupName: "/aws/lambda/changed", Success: true, RetentionApplied: true},
+					{LogGroupName: "/aws/lambda/compliant", Success: true, NoActionNeeded: true},
+				},
+			}, nil)
+			if includeCompliant {
+				mockService.On("GetCompliantResources", ctx, "test-rule", "ca-central-1").Return([]types.NonCompliantResource{
+					testutil.NewTestNonCompliantResource("/aws/lambda/already"),
+				}, nil)
+			}
+
+			processor := &CommandProcessor{
+				service:      mockService,
+				options:      ProcessorOptions{IncludeCompliant: includeCompliant},
+				executionLog: []ExecutionLogEntry{},
+			}
+
+			result, err := processor.Execute(ctx, CommandRequest{
+				Type:           "config-rule-evaluation",
+				ConfigRuleName: "test-rule",
+				Region:         "ca-central-1",
+				BatchSize:      10,
+			})
+
+			// Config's compliant resources are added to the remediated ones without being counted
+			require.NoError(t, err)
+			assert.Equal(t, 2, result.TotalProcessed)
+			assert.Equal(t, 1, result.NoActionCount)
+			statuses := resourceStatuses(result)
+			assert.Equal(t, "success", statuses["/aws/lambda/changed"])
+			assert.Equal(t, "no-action", statuses["/aws/lambda/compliant"])
+			if includeCompliant {
+				assert.Len(t, statuses, 3)
+				assert.Equal(t, "compliant", statuses["/aws/lambda/already"])
+			} else {
+				assert.Len(t, statuses, 2)
+				mockService.AssertNotCalled(t, "GetCompliantResources", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+
+		t.Run(fmt.Sprintf("dry-run inapplicable rule include_compliant=%t", includeCompliant), func(t *testing.T) {
+			stub := &awsAPIStub{
+				evaluations: resources,
+				compliant:   []types.NonCompliantResource{testutil.NewTestNonCompliantResource("/aws/lambda/already")},
+				logGroups:   []string{"/aws/lambda/changed", "/aws/lambda/compliant", "/aws/lambda/already"},
+			}
+			processor, err := NewCommandProcessor(stub.config(), ProcessorOptions{
+				DryRun:           true,
+				IncludeCompliant: includeCompliant,
+				Logger:           slog.New(slog.DiscardHandler),
+			})
+			require.NoError(t, err)
+
+			result, err := processor.Execute(ctx, CommandRequest{
+				Type:           "config-rule-evaluation",
+				ConfigRuleName: "unclassified-rule",
+				Region:         "ca-central-1",
+				BatchSize:      10,
+			})
+
+			// A rule that checks neither requirement skips its resources, as apply does, so they are
+			// listed whether or not compliant resources are included
+			require.NoError(t, err)
+			assert.Equal(t, 0, result.DryRunSummary.AlreadyCompliant)
+			assert.Equal(t, 2, result.SkippedCount)
+			statuses := resourceStatuses(result)
+			assert.Equal(t, "skipped", statuses["/aws/lambda/changed"])
+			assert.Equal(t, "skipped", statuses["/aws/lambda/compliant"])
+			if includeCompliant {
+				assert.Len(t, statuses, 3)
+				assert.Equal(t, "compliant", statuses["/aws/lambda/already"])
+			} else {
+				assert.Len(t, statuses, 2)
+			}
+		})
+	}
+}
+
+// awsAPIStub answers AWS JSON protocol requests from an in-memory account: Config reports the
+// evaluations, CloudWatch Logs describes the log groups, and every other operation succeeds empty.
+// It records the operations called, by X-Amz-Target.
+type awsAPIStub struct {
+	mu          sync.Mutex
+	evaluations []types.NonCompliantResource // Reported NON_COMPLIANT
+	compliant   []types.NonCompliantResource // Reported COMPLIANT
+	logGroups   []string
+	operations  []string
+}
+
+// config returns an AWS configuration whose clients call the stub
+func (s *awsAPIStub) config() aws.Config {
+	return aws.Config{
+		Region:      "ca-central-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+		HTTPClient:  s,
+	}
+}
+
+func (s *awsAPIStub) Do(req *http.Request) (*http.Response, error) {
+	// CloudWatch metrics use the CBOR protocol; an empty body is an empty output
+	if req.Header.Get("Smithy-Protocol") == "rpc-v2-cbor" {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Smithy-Protocol": []string{"rpc-v2-cbor"}},
+			Body:       http.NoBody,
+			Request:    req,
+		}, nil
+	}
+
+	target := req.Header.Get("X-Amz-Target")
+	s.mu.Lock()
+	s.operations = append(s.operations, target)
+	s.mu.Unlock()
+
+	var input map[string]any
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		_ = json.Unmarshal(body, &input)
+	}
+
+	var output any = map[string]any{}
+	switch target {
+	case "StarlingDoveService.GetComplianceDetailsByConfigRule":
+		output = s.complianceDetails(input)
+	case "Logs_20140328.DescribeLogGroups":
+		output = s.describeLogGroups(input)
+	}
+	body, err := json.Marshal(output)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/x-amz-json-1.1"}},
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+// complianceDetails reports the evaluations of the requested compliance type
+func (s *awsAPIStub) complianceDetails(input map[string]any) map[string]any {
+	complianceType, resources := "NON_COMPLIANT", s.evaluations
+	if complianceTypes, _ := input["ComplianceTypes"].([]any); len(complianceTypes) > 0 && complianceTypes[0] == "COMPLIANT" {
+		complianceType, resources = "COMPLIANT", s.compliant
+	}
+
+	results := []map[string]any{}
+	for _, resource := range resources {
+		results = append(results, map[string]any{
+			"EvaluationResultIdentifier": map[string]any{
+				"EvaluationResultQualifier": map[string]any{
+					"ConfigRuleName": input["ConfigRuleName"],
+					"ResourceType":   resource.ResourceType,
+					"ResourceId":     resource.ResourceId,
+				},
+			},
+			"ComplianceType": complianceType,
+			"Annotation":     resource.Annotation,
+		})
+	}
+	return map[string]any{"EvaluationResults": results}
+}
+
+// describeLogGroups lists the log groups matching the request's prefix or identifiers, without
+// encryption or retention
+func (s *awsAPIStub) describeLogGroups(input map[string]any) map[string]any {
+	prefix, _ := input["logGroupNamePrefix"].(string)
+	identifiers, _ := input["logGroupIdentifiers"].([]any)
+	logGroups := []map[string]any{}
+	for _, name := range s.logGroups {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if len(identifiers) > 0 && !slices.Contains(identifiers, any(name)) {
+			continue
+		}
+		logGroups = append(logGroups, map[string]any{
+			"logGroupName": name,
+			"arn":          "arn:aws:logs:ca-central-1:123456789012:log-group:" + name + ":*",
+		})
+	}
+	return map[string]any{"logGroups": logGroups}
+}
+
+// called reports whether the stub received target
+func (s *awsAPIStub) called(target string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Contains(s.operations, target)
+}
+
+func TestCommandProcessor_Execute_DryRunMatchesApply(t *testing.T) {
+	t.Setenv("AWS_REGION", "ca-central-1")
+	ctx := context.Background()
+	evaluations := []types.NonCompliantResource{
+		testutil.NewNonCompliantResourceBuilder("/aws/lambda/kept").WithAnnotation("retention below minimum").Build(),
+		testutil.NewNonCompliantResourceBuilder("/aws/lambda/kept").WithAnnotation("retention below minimum").Build(),
+		testutil.NewNonCompliantResourceBuilder("/aws/lambda/other-keyword").WithAnnotation("not encrypted").Build(),
+		testutil.NewNonCompliantResourceBuilder("/aws/lambda/deleted").WithAnnotation("retention below minimum").Build(),
+		testutil.NewNonCompliantResourceBuilder("my-bucket").WithResourceType("AWS::S3::Bucket").WithAnnotation("retention").Build(),
+		testutil.NewNonCompliantResourceBuilder("/aws/lambda/second").WithAnnotation("retention below minimum").Build(),
+	}
+	logGroups := []string{"/aws/lambda/kept", "/aws/lambda/other-keyword", "/aws/lambda/second"}
+
+	// decision is what a run decided for one resource; a simulated change counts as a success
+	type decision struct {
+		Status            string
+		EncryptionApplied bool
+		RetentionApplied  bool
+	}
+	decisions := func(result *ExecutionResult) map[string]decision {
+		byName := make(map[string]decision, len(result.Resources))
+		for _, r := range result.Resources {
+			status := r.Status
+			if status == "dry-run" {
+				status = "success"
+			}
+			byName[r.ResourceName] = decision{status, r.EncryptionApplied, r.RetentionApplied}
+		}
+		return byName
+	}
+
+	for _, configRuleName := range []string{"cw-lg-retention-min", "unclassified-rule"} {
+		t.Run(configRuleName, func(t *testing.T) {
+			results := make(map[bool]*ExecutionResult, 2)
+			stubs := make(map[bool]*awsAPIStub, 2)
+			for _, dryRun := range []bool{false, true} {
+				stub := &awsAPIStub{evaluations: evaluations, logGroups: logGroups}
+				processor, err := NewCommandProcessor(stub.config(), ProcessorOptions{
+					DryRun:                dryRun,
+					AnnotationKeywords:    []string{"retention"},
+					LogGroupLookupRetries: aws.Int32(0),
+					Logger:                slog.New(slog.DiscardHandler),
+				})
+				require.NoError(t, err)
+
+				result, err := processor.Execute(ctx, CommandRequest{
+					Type:           "config-rule-evaluation",
+					ConfigRuleName: configRuleName,
+					Region:         "ca-central-1",
+					BatchSize:      10,
+				})
+				require.NoError(t, err)
+				results[dryRun], stubs[dryRun] = result, stub
+			}
+
+			// Config's evaluation is trusted, so the deleted group is attempted in both modes
+			apply, dryRun := results[false], results[true]
+			require.Len(t, apply.Resources, 3)
+			assert.Equal(t, decisions(apply), decisions(dryRun))
+			assert.Equal(t, apply.Reconciliation, dryRun.Reconciliation)
+			assert.Equal(t, apply.TotalProcessed, dryRun.TotalProcessed)
+			assert.Equal(t, apply.SuccessCount, dryRun.SuccessCount)
+			assert.Equal(t, apply.SkippedCount, dryRun.SkippedCount)
+
+			// Only apply mode changes the log groups
+			assert.False(t, stubs[true].called("Logs_20140328.PutRetentionPolicy"))
+			if configRuleName == "cw-lg-retention-min" {
+				assert.True(t, stubs[false].called("Logs_20140328.PutRetentionPolicy"))
+			}
+		})
+	}
+}
+
 func TestCommandProcessor_GetMode(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -253,31 +862,96 @@ func TestCommandProcessor_GetMode(t *testing.T) {
 	}
 }
 
-func TestGetResourceStatus(t *testing.T) {
+func TestExecutionResultFromBatch(t *testing.T) {
+	batch := &types.BatchRemediationResult{
+		TotalProcessed:     3,
+		SuccessCount:       2,
+		NoActionCount:      1,
+		FailureCount:       1,
+		ProcessingDuration: 1500 * time.Millisecond,
+		Results: []types.RemediationResult{
+			{LogGroupName: "/aws/lambda/remediated", Success: true, EncryptionApplied: true, RetentionApplied: true},
+			{LogGroupName: "/aws/lambda/compliant", Success: true, NoActionNeeded: true},
+			{LogGroupName: "/aws/lambda/failed", Error: errors.New("AccessDeniedException")},
+		},
+	}
+
+	result := ExecutionResultFromBatch(batch, "exec-1", "apply", "test-rule", "ca-central-1")
+
+	assert.Equal(t, "exec-1", result.ExecutionID)
+	assert.Equal(t, ExecutionStatusPartial, result.Status)
+	assert.Equal(t, "apply", result.Mode)
+	assert.Equal(t, "test-rule", result.ConfigRuleName)
+	assert.Equal(t, "ca-central-1", result.Region)
+	assert.Equal(t, 3, result.TotalProcessed)
+	assert.Equal(t, 2, result.SuccessCount)
+	assert.Equal(t, 1, result.NoActionCount)
+	assert.Equal(t, 1, result.FailureCount)
+	assert.Equal(t, "1.5s", result.Duration)
+	assert.False(t, result.Timestamp.IsZero())
+
+	require.Len(t, result.Resources, 3)
+	remediated, compliant, failed := result.Resources[0], result.Resources[1], result.Resources[2]
+
+	assert.Equal(t, "/aws/lambda/remediated", remediated.ResourceID)
+	assert.Equal(t, "/aws/lambda/remediated", remediated.ResourceName)
+	assert.Equal(t, "success", remediated.Status)
+	assert.True(t, remediated.EncryptionApplied)
+	assert.True(t, remediated.RetentionApplied)
+	assert.Empty(t, remediated.Error)
+
+	assert.Equal(t, "no-action", compliant.Status)
+	assert.False(t, compliant.EncryptionApplied)
+	assert.False(t, compliant.RetentionApplied)
+
+	assert.Equal(t, "failed", failed.Status)
+	assert.Equal(t, "AccessDeniedException", failed.Error)
+
+	t.Run("interrupted batch fails", func(t *testing.T) {
+		for _, interrupted := range []types.BatchRemediationResult{{Cancelled: true}, {TimedOut: true}, {AbortedOnFailure: true}} {
+			assert.Equal(t, "failed", ExecutionResultFromBatch(&interrupted, "exec-1", "apply", "test-rule", "ca-central-1").Status)
+		}
+	})
+
+	t.Run("skipped remediations are counted", func(t *testing.T) {
+		skipped := &types.BatchRemediationResult{
+			TotalProcessed: 2,
+			SuccessCount:   2,
+			NoActionCount:  1,
+			Results: []types.RemediationResult{
+				{LogGroupName: "/aws/lambda/remediated", Success: true, EncryptionApplied: true},
+				{LogGroupName: "/aws/lambda/protected", Success: true, NoActionNeeded: true, SkipReason: "retention reduction blocked"},
+			},
+		}
+
+		result := ExecutionResultFromBatch(skipped, "exec-1", "apply", "test-rule", "ca-central-1")
+
+		assert.Equal(t, ExecutionStatusCompletedWithSkips, result.Status)
+		assert.Equal(t, 1, result.SkippedCount)
+		assert.Equal(t, "skipped", result.Resources[1].Status)
+	})
+}
+
+func TestExecutionStatus(t *testing.T) {
 	tests := []struct {
 		name     string
-		result   types.RemediationResult
+		success  int
+		failure  int
+		skipped  int
 		expected string
 	}{
-		{
-			name: "successful result",
-			result: types.RemediationResult{
-				Success: true,
-			},
-			expected: "success",
-		},
-		{
-			name: "failed result",
-			result: types.RemediationResult{
-				Success: false,
-			},
-			expected: "failed",
-		},
+		{name: "nothing processed", expected: ExecutionStatusCompleted},
+		{name: "all succeeded", success: 3, expected: ExecutionStatusCompleted},
+		{name: "succeeded with skips", success: 3, skipped: 1, expected: ExecutionStatusCompletedWithSkips},
+		{name: "some failed", success: 2, failure: 1, expected: ExecutionStatusPartial},
+		{name: "some failed with skips", success: 2, failure: 1, skipped: 1, expected: ExecutionStatusPartial},
+		{name: "all failed", failure: 3, expected: ExecutionStatusFailed},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			assert.Equal(t, tt.expected, getResourceStatus(tt.result))
+			result := &ExecutionResult{SuccessCount: tt.success, FailureCount: tt.failure, SkippedCount: tt.skipped}
+			assert.Equal(t, tt.expected, executionStatus(result))
 		})
 	}
 }
@@ -290,12 +964,12 @@ func TestCommandProcessor_LogEntry(t *testing.T) {
 	testCases := []struct {
 		level   string
 		message string
-		details any
+		details map[string]any
 	}{
 		{"INFO", "Test info message", map[string]any{"key": "value"}},
 		{"WARN", "Test warning", nil},
-		{"ERROR", "Test error", "error details"},
-		{"DEBUG", "Test debug", 123},
+		{"ERROR", "Test error", map[string]any{LogDetailError: "error details"}},
+		{"DEBUG", "Test debug", map[string]any{"count": 123}},
 	}
 
 	for i, tc := range testCases {
@@ -309,3 +983,74 @@ func TestCommandProcessor_LogEntry(t *testing.T) {
 		assert.WithinDuration(t, time.Now(), entry.Timestamp, time.Second)
 	}
 }
+
+func TestExecutionResult_FilterExecutionLog(t *testing.T) {
+	processor := &CommandProcessor{executionLog: []ExecutionLogEntry{}}
+	processor.logEntry("DEBUG", "debug", nil)
+	processor.logEntry("INFO", "info", nil)
+	processor.logEntry("WARN", "warn", nil)
+	processor.logEntry("ERROR", "error", nil)
+	result := &ExecutionResult{ExecutionLog: processor.executionLog}
+
+	messages := func(entries []ExecutionLogEntry) []string {
+		var out []string
+		for _, entry := range entries {
+			out = append(out, entry.Message)
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"warn", "error"}, messages(result.FilterExecutionLog("WARN")))
+	assert.Equal(t, []string{"error"}, messages(result.FilterExecutionLog("error")))
+	assert.Equal(t, []string{"debug", "info", "warn", "error"}, messages(result.FilterExecutionLog("DEBUG")))
+	assert.Len(t, result.FilterExecutionLog("unknown"), 4)
+}
+
+func TestCommandProcessor_ExecutionLogDetailsSerializeAsObjects(t *testing.T) {
+	ctx := context.Background()
+	mockService := new(MockComplianceService)
+	mockService.On("EvaluateCompliance", ctx, "/aws/lambda/foo", "ca-central-1").
+		Return(types.ComplianceResult{}, errors.New("log group /aws/lambda/foo not found"))
+
+	processor := &CommandProcessor{service: mockService, executionLog: []ExecutionLogEntry{}}
+	result, err := processor.Execute(ctx, CommandRequest{
+		Type:           "config-rule-evaluation",
+		ConfigRuleName: "cloudwatch-log-group-retention",
+		Region:         "ca-central-1",
+		LogGroupName:   "/aws/lambda/foo",
+	})
+	require.Error(t, err)
+
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+	var decoded struct {
+		ExecutionLog []struct {
+			Level   string         `json:"level"`
+			Details map[string]any `json:"details"`
+		} `json:"execution_log"`
+	}
+	require.NoError(t, json.Unmarshal(data, &decoded), "every entry's details must decode as an object")
+
+	require.NotEmpty(t, decoded.ExecutionLog)
+	assert.Equal(t, "ca-central-1", decoded.ExecutionLog[0].Details[LogDetailRegion])
+	assert.Equal(t, "/aws/lambda/foo", decoded.ExecutionLog[1].Details[LogDetailLogGroup])
+	last := decoded.ExecutionLog[len(decoded.ExecutionLog)-1]
+	assert.Equal(t, "ERROR", last.Level)
+	assert.Contains(t, last.Details[LogDetailError], "not found")
+}
+
+func TestCommandProcessor_LogEntryUsesInjectedLogger(t *testing.T) {
+	logger, logs := testutil.CaptureLogs(t)
+
+	processor := &CommandProcessor{
+		options:      ProcessorOptions{Logger: logger},
+		executionLog: []ExecutionLogEntry{},
+	}
+
+	processor.logEntry("WARN", "Failed to analyze resource", map[string]any{"resource": "log-group-1"})
+
+	records := logs.Records()
+	assert.Len(t, records, 1)
+	assert.Equal(t, "WARN", records[0].Level)
+	assert.Equal(t, "Failed to analyze resource", records[0].Message)
+}
@@ -0,0 +1,43 @@
+package container
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+// prometheusContentType is the content type of the Prometheus text exposition format
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// prometheusCounter is one counter rendered on /metrics
+type prometheusCounter struct {
+	name  string
+	help  string
+	value *int64
+}
+
+// counters lists the metrics in the order they are rendered
+func (m *ServiceMetrics) counters() []prometheusCounter {
+	return []prometheusCounter{
+		{"logguardian_service_calls_total", "AWS API calls made by the run's clients.", &m.TotalCalls},
+		{"logguardian_service_calls_successful_total", "AWS API calls that succeeded.", &m.SuccessfulCalls},
+		{"logguardian_service_calls_failed_total", "AWS API calls that failed after any retries.", &m.FailedCalls},
+		{"logguardian_service_call_retries_total", "Retried attempts of AWS API calls.", &m.RetryCount},
+		{"logguardian_service_call_throttles_total", "AWS API call attempts rejected by throttling.", &m.ThrottleCount},
+		{"logguardian_remediations_processed_total", "Log groups processed by remediation runs.", &m.RemediationsProcessed},
+		{"logguardian_remediations_succeeded_total", "Log groups remediated or already compliant.", &m.RemediationsSucceeded},
+		{"logguardian_remediations_failed_total", "Log groups whose remediation failed.", &m.RemediationsFailed},
+		{"logguardian_remediations_skipped_total", "Log groups with a required change deliberately skipped.", &m.RemediationsSkipped},
+	}
+}
+
+// WritePrometheus renders the counters in the Prometheus text exposition format
+func (m *ServiceMetrics) WritePrometheus(w io.Writer) error {
+	for _, counter := range m.counters() {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n%s %d\n",
+			counter.name, counter.help, counter.name, counter.name, atomic.LoadInt64(counter.value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultRunPlanBatchSize is used for run plan entries that do not set batchSize
+const DefaultRunPlanBatchSize = 10
+
+// S3GetObjectAPI is the subset of the S3 client used to fetch a run plan
+type S3GetObjectAPI interface {
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+}
+
+// RunPlanEntry is a single rule evaluation in a run plan
+type RunPlanEntry struct {
+	Rule      string `json:"rule" yaml:"rule"`
+	Region    string `json:"region" yaml:"region"`
+	BatchSize int    `json:"batchSize" yaml:"batchSize"`
+}
+
+// RunPlanResult aggregates the results of every entry in a run plan
+type RunPlanResult struct {
+	ExecutionID    string             `json:"execution_id"`
+	Status         string             `json:"status"`
+	Mode           string             `json:"mode"`
+	TotalProcessed int                `json:"total_processed"`
+	SuccessCount   int                `json:"success_count"`
+	FailureCount   int                `json:"failure_count"`
+	NoActionCount  int                `json:"no_action_count"`
+	FailedEntries  int                `json:"failed_entries"`
+	Duration       string             `json:"duration"`
+	Timestamp      time.Time          `json:"timestamp"`
+	Entries        []*ExecutionResult `json:"entries"`
+}
+
+// PlanProcessorFactory returns the processor that executes a run plan entry, typically one
+// configured for the entry's region
+type PlanProcessorFactory func(entry RunPlanEntry) (*CommandProcessor, error)
+
+// ParseS3URI splits an s3://bucket/key URI into its bucket and key
+func ParseS3URI(uri string) (string, string, error) {
+	rest, ok := strings.CutPrefix(uri, "s3://")
+	if !ok {
+		return "", "", fmt.Errorf("invalid S3 URI %q: must start with s3://", uri)
+	}
+	bucket, key, _ := strings.Cut(rest, "/")
+	if bucket == "" || key == "" {
+		return "", "", fmt.Errorf("invalid S3 URI %q: must be s3://bucket/key", uri)
+	}
+	return bucket, key, nil
+}
+
+// ParseRunPlan parses a JSON or YAML list of run plan entries. Entries without a region use
+// defaultRegion and entries without a batch size use DefaultRunPlanBatchSize.
+func ParseRunPlan(data []byte, defaultRegion string) ([]RunPlanEntry, error) {
+	var entries []RunPlanEntry
+	// YAML is a superset of JSON, so one decoder handles both formats
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse run plan: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("run plan contains no entries")
+	}
+
+	for i := range entries {
+		entry := &entries[i]
+		entry.Rule = strings.TrimSpace(entry.Rule)
+		entry.Region = strings.TrimSpace(entry.Region)
+		if entry.Rule == "" {
+			return nil, fmt.Errorf("run plan entry %d: rule is required", i)
+		}
+		if entry.Region == "" {
+			entry.Region = defaultRegion
+		}
+		if entry.Region == "" {
+			return nil, fmt.Errorf("run plan entry %d: region is required", i)
+		}
+		if entry.BatchSize == 0 {
+			entry.BatchSize = DefaultRunPlanBatchSize
+		}
+		if entry.BatchSize < 1 || entry.BatchSize > 100 {
+			return nil, fmt.Errorf("run plan entry %d: batch size must be between 1 and 100", i)
+		}
+	}
+
+	return entries, nil
+}
+
+// LoadRunPlan fetches the run plan at an s3://bucket/key URI and parses it
+func LoadRunPlan(ctx context.Context, client S3GetObjectAPI, uri, defaultRegion string) ([]RunPlanEntry, error) {
+	bucket, key, err := ParseS3URI(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch run plan %s: %w", uri, err)
+	}
+	defer func() { _ = output.Body.Close() }()
+
+	data, err := io.ReadAll(output.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run plan %s: %w", uri, err)
+	}
+
+	return ParseRunPlan(data, defaultRegion)
+}
+
+// ExecuteRunPlan runs each entry in order and aggregates the results. A failed entry is recorded
+// and the remaining entries still run; the returned error reports how many entries failed.
+func ExecuteRunPlan(ctx context.Context, entries []RunPlanEntry, executionID string, newProcessor PlanProcessorFactory) (*RunPlanResult, error) {
+	startTime := time.Now()
+	result := &RunPlanResult{
+		ExecutionID: executionID,
+		Status:      "running",
+		Timestamp:   startTime,
+		Entries:     make([]*ExecutionResult, 0, len(entries)),
+	}
+
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			result.Status = "failed"
+			result.Duration = time.Since(startTime).String()
+			return result, fmt.Errorf("run plan cancelled after %d of %d entries: %w", len(result.Entries), len(entries), err)
+		}
+
+		processor, err := newProcessor(entry)
+		if err != nil {
+			result.FailedEntries++
+			result.Entries = append(result.Entries, &ExecutionResult{
+				ExecutionID:    executionID,
+				Status:         ExecutionStatusFailed,
+				ConfigRuleName: entry.Rule,
+				Region:         entry.Region,
+				Error:          err.Error(),
+				Timestamp:      time.Now(),
+			})
+			continue
+		}
+		result.Mode = processor.getMode()
+
+		entryResult, err := processor.Execute(ctx, CommandRequest{
+			Type:           "config-rule-evaluation",
+			ConfigRuleName: entry.Rule,
+			Region:         entry.Region,
+			BatchSize:      entry.BatchSize,
+		})
+		if err != nil {
+			result.FailedEntries++
+		}
+		if entryResult == nil {
+			continue
+		}
+
+		result.Entries = append(result.Entries, entryResult)
+		result.TotalProcessed += entryResult.TotalProcessed
+		result.SuccessCount += entryResult.SuccessCount
+		result.FailureCount += entryResult.FailureCount
+		result.NoActionCount += entryResult.NoActionCount
+	}
+
+	result.Duration = time.Since(startTime).String()
+	if result.FailedEntries > 0 {
+		result.Status = "failed"
+		return result, fmt.Errorf("%d of %d run plan entries failed", result.FailedEntries, len(entries))
+	}
+	result.Status = "completed"
+	return result, nil
+}
@@ -0,0 +1,219 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/zsoftly/logguardian/internal/testutil"
+	"github.com/zsoftly/logguardian/internal/types"
+)
+
+type fakeS3Client struct {
+	body  string
+	err   error
+	input *s3.GetObjectInput
+}
+
+func (f *fakeS3Client) GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	f.input = params
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader(f.body))}, nil
+}
+
+func TestParseS3URI(t *testing.T) {
+	bucket, key, err := ParseS3URI("s3://plans/logguardian/nightly.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, "plans", bucket)
+	assert.Equal(t, "logguardian/nightly.yaml", key)
+
+	for _, uri := range []string{"plans/nightly.yaml", "s3://plans", "s3://plans/", "s3:///nightly.yaml"} {
+		_, _, err := ParseS3URI(uri)
+		assert.Error(t, err, uri)
+	}
+}
+
+func TestParseRunPlan(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected []RunPlanEntry
+		wantErr  string
+	}{
+		{
+			name: "yaml with defaults",
+			data: "- rule: encryption-rule\n  region: us-east-1\n  batchSize: 25\n- rule: retention-rule\n",
+			expected: []RunPlanEntry{
+				{Rule: "encryption-rule", Region: "us-east-1", BatchSize: 25},
+				{Rule: "retention-rule", Region: "ca-central-1", BatchSize: DefaultRunPlanBatchSize},
+			},
+		},
+		{
+			name: "json",
+			data: `[{"rule": "encryption-rule", "region": "ca-west-1", "batchSize": 5}, {"rule": "retention-rule", "batchSize": 50}]`,
+			expected: []RunPlanEntry{
+				{Rule: "encryption-rule", Region: "ca-west-1", BatchSize: 5},
+				{Rule: "retention-rule", Region: "ca-central-1", BatchSize: 50},
+			},
+		},
+		{name: "malformed", data: `[{"rule": "encryption-rule"`, wantErr: "failed to parse run plan"},
+		{name: "not a list", data: "rule: encryption-rule\n", wantErr: "failed to parse run plan"},
+		{name: "empty", data: "[]", wantErr: "no entries"},
+		{name: "missing rule", data: `[{"region": "us-east-1"}]`, wantErr: "entry 0: rule is required"},
+		{name: "batch size out of range", data: `[{"rule": "r", "batchSize": 101}]`, wantErr: "entry 0: batch size"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := ParseRunPlan([]byte(tt.data), "ca-central-1")
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, entries)
+		})
+	}
+}
+
+func TestLoadRunPlan(t *testing.T) {
+	ctx := context.Background()
+
+	client := &fakeS3Client{body: "- rule: encryption-rule\n  region: us-east-1\n"}
+	entries, err := LoadRunPlan(ctx, client, "s3://plans/nightly.yaml", "ca-central-1")
+	assert.NoError(t, err)
+	assert.Equal(t, []RunPlanEntry{{Rule: "encryption-rule", Region: "us-east-1", BatchSize: DefaultRunPlanBatchSize}}, entries)
+	assert.Equal(t, "plans", aws.ToString(client.input.Bucket))
+	assert.Equal(t, "nightly.yaml", aws.ToString(client.input.Key))
+
+	_, err = LoadRunPlan(ctx, &fakeS3Client{err: errors.New("access denied")}, "s3://plans/nightly.yaml", "ca-central-1")
+	assert.ErrorContains(t, err, "access denied")
+
+	_, err = LoadRunPlan(ctx, &fakeS3Client{body: "{not: [valid"}, "s3://plans/nightly.yaml", "ca-central-1")
+	assert.ErrorContains(t, err, "failed to parse run plan")
+}
+
+func TestExecuteRunPlan(t *testing.T) {
+	ctx := context.Background()
+
+	encryptionResources := []types.NonCompliantResource{
+		testutil.NewTestNonCompliantResource("/aws/lambda/a"),
+		testutil.NewTestNonCompliantResource("/aws/lambda/b"),
+	}
+	retentionResources := []types.NonCompliantResource{
+		testutil.NewTestNonCompliantResource("/aws/lambda/c"),
+	}
+
+	mockService := new(MockComplianceService)
+	mockService.On("GetNonCompliantResources", ctx, "encryption-rule", "us-east-1").Return(encryptionResources, nil).Once()
+	mockService.On("ValidateResourceExistence", ctx, encryptionResources).Return(encryptionResources, nil).Once()
+	mockService.On("ProcessNonCompliantResourcesOptimized", ctx, mock.MatchedBy(func(request types.BatchComplianceRequest) bool {
+		return request.ConfigRuleName == "encryption-rule" && request.BatchSize == 25
+	})).Return(&types.BatchRemediationResult{TotalProcessed: 2, SuccessCount: 2}, nil).Once()
+	mockService.On("GetNonCompliantResources", ctx, "retention-rule", "ca-central-1").Return(retentionResources, nil).Once()
+	mockService.On("ValidateResourceExistence", ctx, retentionResources).Return(retentionResources, nil).Once()
+	mockService.On("ProcessNonCompliantResourcesOptimized", ctx, mock.MatchedBy(func(request types.BatchComplianceRequest) bool {
+		return request.ConfigRuleName == "retention-rule" && request.BatchSize == 10
+	})).Return(&types.BatchRemediationResult{TotalProcessed: 1, FailureCount: 1}, nil).Once()
+
+	entries := []RunPlanEntry{
+		{Rule: "encryption-rule", Region: "us-east-1", BatchSize: 25},
+		{Rule: "retention-rule", Region: "ca-central-1", BatchSize: 10},
+	}
+
+	var order []string
+	result, err := ExecuteRunPlan(ctx, entries, "test-plan", func(entry RunPlanEntry) (*CommandProcessor, error) {
+		order = append(order, entry.Rule)
+		return &CommandProcessor{
+			service:      mockService,
+			options:      ProcessorOptions{ExecutionID: "test-plan"},
+			executionLog: []ExecutionLogEntry{},
+		}, nil
+	})
+
+	assert.NoError(t, err)
+	mockService.AssertExpectations(t)
+	assert.Equal(t, []string{"encryption-rule", "retention-rule"}, order)
+	assert.Equal(t, "completed", result.Status)
+	assert.Len(t, result.Entries, 2)
+	assert.Equal(t, "encryption-rule", result.Entries[0].ConfigRuleName)
+	assert.Equal(t, "retention-rule", result.Entries[1].ConfigRuleName)
+	assert.Equal(t, 3, result.TotalProcessed)
+	assert.Equal(t, 2, result.SuccessCount)
+	assert.Equal(t, 1, result.FailureCount)
+	assert.Equal(t, 0, result.FailedEntries)
+}
+
+func TestExecuteRunPlan_ContinuesAfterFailedEntry(t *testing.T) {
+	ctx := context.Background()
+
+	resources := []types.NonCompliantResource{testutil.NewTestNonCompliantResource("/aws/lambda/a")}
+
+	mockService := new(MockComplianceService)
+	mockService.On("GetNonCompliantResources", ctx, "missing-rule", "ca-central-1").
+		Return([]types.NonCompliantResource{}, errors.New("NoSuchConfigRuleException")).Once()
+	mockService.On("GetNonCompliantResources", ctx, "encryption-rule", "ca-central-1").Return(resources, nil).Once()
+	mockService.On("ValidateResourceExistence", ctx, resources).Return(resources, nil).Once()
+	mockService.On("ProcessNonCompliantResourcesOptimized", ctx, mock.Anything).
+		Return(&types.BatchRemediationResult{TotalProcessed: 1, SuccessCount: 1}, nil).Once()
+
+	entries := []RunPlanEntry{
+		{Rule: "missing-rule", Region: "ca-central-1", BatchSize: 10},
+		{Rule: "encryption-rule", Region: "ca-central-1", BatchSize: 10},
+	}
+
+	result, err := ExecuteRunPlan(ctx, entries, "test-plan", func(entry RunPlanEntry) (*CommandProcessor, error) {
+		return &CommandProcessor{service: mockService, executionLog: []ExecutionLogEntry{}}, nil
+	})
+
+	assert.ErrorContains(t, err, "1 of 2 run plan entries failed")
+	mockService.AssertExpectations(t)
+	assert.Equal(t, "failed", result.Status)
+	assert.Equal(t, 1, result.FailedEntries)
+	assert.Len(t, result.Entries, 2)
+	assert.Equal(t, "failed", result.Entries[0].Status)
+	assert.Equal(t, "completed", result.Entries[1].Status)
+	assert.Equal(t, 1, result.SuccessCount)
+}
+
+func TestExecuteRunPlan_ProcessorCreationFails(t *testing.T) {
+	ctx := context.Background()
+
+	resources := []types.NonCompliantResource{testutil.NewTestNonCompliantResource("/aws/lambda/a")}
+
+	mockService := new(MockComplianceService)
+	mockService.On("GetNonCompliantResources", ctx, "encryption-rule", "ca-central-1").Return(resources, nil).Once()
+	mockService.On("ValidateResourceExistence", ctx, resources).Return(resources, nil).Once()
+	mockService.On("ProcessNonCompliantResourcesOptimized", ctx, mock.Anything).
+		Return(&types.BatchRemediationResult{TotalProcessed: 1, SuccessCount: 1}, nil).Once()
+
+	entries := []RunPlanEntry{
+		{Rule: "retention-rule", Region: "", BatchSize: 10},
+		{Rule: "encryption-rule", Region: "ca-central-1", BatchSize: 10},
+	}
+
+	result, err := ExecuteRunPlan(ctx, entries, "test-plan", func(entry RunPlanEntry) (*CommandProcessor, error) {
+		if entry.Region == "" {
+			return nil, errors.New("region not configured")
+		}
+		return &CommandProcessor{service: mockService, executionLog: []ExecutionLogEntry{}}, nil
+	})
+
+	assert.ErrorContains(t, err, "1 of 2 run plan entries failed")
+	mockService.AssertExpectations(t)
+	assert.Equal(t, 1, result.FailedEntries)
+	require.Len(t, result.Entries, 2)
+	assert.Equal(t, "failed", result.Entries[0].Status)
+	assert.Equal(t, "retention-rule", result.Entries[0].ConfigRuleName)
+	assert.Equal(t, "region not configured", result.Entries[0].Error)
+	assert.Equal(t, "completed", result.Entries[1].Status)
+}
@@ -8,6 +8,8 @@ import (
 	"log/slog"
 	"math"
 	"math/big"
+	"slices"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -17,6 +19,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/configservice"
 	"github.com/aws/aws-sdk-go-v2/service/kms"
 	"github.com/aws/smithy-go"
+	"github.com/zsoftly/logguardian/internal/service"
 )
 
 const (
@@ -41,6 +44,9 @@ type RetryOptions struct {
 	InitialDelay    time.Duration
 	MaxDelay        time.Duration
 	BackoffFunction func(attempt int, err error) time.Duration
+	// RetryPredicate reports whether an error should be retried. It defaults to the built-in
+	// classification; an option can wrap the previous predicate to add or exclude error codes.
+	RetryPredicate func(err error) bool
 }
 
 // DefaultRetryOptions provides sensible defaults for retry behavior
@@ -50,6 +56,7 @@ func DefaultRetryOptions() RetryOptions {
 		InitialDelay:    100 * time.Millisecond,
 		MaxDelay:        30 * time.Second,
 		BackoffFunction: exponentialBackoff,
+		RetryPredicate:  isRetryableError,
 	}
 }
 
@@ -60,6 +67,8 @@ func NewServiceAdapter(config aws.Config, opts ...func(*RetryOptions)) *ServiceA
 		opt(&retryOpts)
 	}
 
+	config = service.ApplyHTTPTimeout(service.ApplyUserAgent(config), service.HTTPTimeoutFromEnvironment())
+
 	// Configure AWS SDK retry behavior
 	config.Retryer = func() aws.Retryer {
 		return retry.NewStandard(func(o *retry.StandardOptions) {
@@ -102,7 +111,7 @@ func (s *ServiceAdapter) ExecuteWithRetry(ctx context.Context, operation func()
 		lastErr = err
 
 		// Check if error is retryable
-		if !isRetryableError(err) {
+		if !s.isRetryable(err) {
 			slog.Error("Non-retryable error encountered",
 				"attempt", attempt,
 				"error", err)
@@ -138,6 +147,14 @@ func (s *ServiceAdapter) ExecuteWithRetry(ctx context.Context, operation func()
 	return fmt.Errorf("operation failed after %d attempts: %w", s.retryOptions.MaxAttempts, lastErr)
 }
 
+// isRetryable applies the configured retry predicate, falling back to the built-in classification
+func (s *ServiceAdapter) isRetryable(err error) bool {
+	if s.retryOptions.RetryPredicate == nil {
+		return isRetryableError(err)
+	}
+	return s.retryOptions.RetryPredicate(err)
+}
+
 // ExecuteWithRateLimit performs an operation with rate limit handling
 func (s *ServiceAdapter) ExecuteWithRateLimit(ctx context.Context, operation func() error, rateLimit *RateLimiter) error {
 	return s.ExecuteWithRetry(ctx, func() error {
@@ -198,7 +215,8 @@ func isRetryableError(err error) bool {
 	return false
 }
 
-// isThrottlingError checks if an error is due to API throttling
+// isThrottlingError checks if an error is due to API throttling, including the non-standard
+// throttling codes listed in ADDITIONAL_RETRYABLE_CODES
 func isThrottlingError(err error) bool {
 	if err == nil {
 		return false
@@ -212,6 +230,7 @@ func isThrottlingError(err error) bool {
 			"RequestLimitExceededException":
 			return true
 		}
+		return slices.Contains(service.AdditionalRetryableCodes(), apiErr.ErrorCode())
 	}
 
 	return false
@@ -264,13 +283,25 @@ type RateLimiter struct {
 	refillTicker  *time.Ticker
 	throttleCount atomic.Int32
 	successCount  atomic.Int32
+
+	stop       chan struct{} // Closed by Stop to end the refill goroutine
+	stopOnce   sync.Once
+	refillDone chan struct{} // Closed once the refill goroutine has exited
 }
 
-// NewRateLimiter creates a new rate limiter
+// NewRateLimiter creates a new rate limiter whose refill goroutine runs until Stop is called
 func NewRateLimiter(ratePerSecond int) *RateLimiter {
+	return NewRateLimiterWithContext(context.Background(), ratePerSecond)
+}
+
+// NewRateLimiterWithContext creates a new rate limiter whose refill goroutine exits when ctx is
+// done or Stop is called, whichever comes first, so context-scoped runs do not leak it
+func NewRateLimiterWithContext(ctx context.Context, ratePerSecond int) *RateLimiter {
 	rl := &RateLimiter{
 		tokens:       make(chan struct{}, ratePerSecond),
 		refillTicker: time.NewTicker(time.Second / time.Duration(ratePerSecond)),
+		stop:         make(chan struct{}),
+		refillDone:   make(chan struct{}),
 	}
 
 	// Fill initial tokens
@@ -279,7 +310,7 @@ func NewRateLimiter(ratePerSecond int) *RateLimiter {
 	}
 
 	// Start refill goroutine
-	go rl.refill()
+	go rl.refill(ctx)
 
 	return rl
 }
@@ -315,20 +346,33 @@ func (rl *RateLimiter) Success() {
 	}
 }
 
-// refill adds tokens to the rate limiter
-func (rl *RateLimiter) refill() {
-	for range rl.refillTicker.C {
+// refill adds tokens to the rate limiter until ctx is done or Stop is called. Stopping the ticker
+// alone never closes its channel, so the goroutine also waits on both signals to exit.
+func (rl *RateLimiter) refill(ctx context.Context) {
+	defer close(rl.refillDone)
+	defer rl.refillTicker.Stop()
+
+	for {
 		select {
-		case rl.tokens <- struct{}{}:
-		default:
-			// Bucket is full
+		case <-rl.refillTicker.C:
+			select {
+			case rl.tokens <- struct{}{}:
+			default:
+				// Bucket is full
+			}
+		case <-rl.stop:
+			return
+		case <-ctx.Done():
+			return
 		}
 	}
 }
 
-// Stop cleanly stops the rate limiter
+// Stop cleanly stops the rate limiter; it is safe to call more than once
 func (rl *RateLimiter) Stop() {
-	rl.refillTicker.Stop()
+	rl.stopOnce.Do(func() {
+		close(rl.stop)
+	})
 }
 
 // GetThrottleCount returns the current throttle count (thread-safe)
@@ -341,33 +385,64 @@ func (rl *RateLimiter) GetSuccessCount() int32 {
 	return rl.successCount.Load()
 }
 
-// ServiceMetrics tracks service call metrics
+// ServiceMetrics tracks service call and remediation metrics. Counters are updated atomically,
+// so they may be read, for example by the /metrics endpoint, while a run records them. Service
+// calls are recorded by clients built from an InstrumentAWSConfig config. A nil *ServiceMetrics
+// records nothing.
 type ServiceMetrics struct {
 	TotalCalls      int64
 	SuccessfulCalls int64
 	FailedCalls     int64
 	RetryCount      int64
 	ThrottleCount   int64
+
+	RemediationsProcessed int64
+	RemediationsSucceeded int64
+	RemediationsFailed    int64
+	RemediationsSkipped   int64
 }
 
 // RecordSuccess records a successful service call
 func (m *ServiceMetrics) RecordSuccess() {
-	m.TotalCalls++
-	m.SuccessfulCalls++
+	if m == nil {
+		return
+	}
+	atomic.AddInt64(&m.TotalCalls, 1)
+	atomic.AddInt64(&m.SuccessfulCalls, 1)
 }
 
 // RecordFailure records a failed service call
 func (m *ServiceMetrics) RecordFailure() {
-	m.TotalCalls++
-	m.FailedCalls++
+	if m == nil {
+		return
+	}
+	atomic.AddInt64(&m.TotalCalls, 1)
+	atomic.AddInt64(&m.FailedCalls, 1)
 }
 
 // RecordRetry records a retry attempt
 func (m *ServiceMetrics) RecordRetry() {
-	m.RetryCount++
+	if m == nil {
+		return
+	}
+	atomic.AddInt64(&m.RetryCount, 1)
 }
 
 // RecordThrottle records a throttling event
 func (m *ServiceMetrics) RecordThrottle() {
-	m.ThrottleCount++
+	if m == nil {
+		return
+	}
+	atomic.AddInt64(&m.ThrottleCount, 1)
+}
+
+// RecordExecution adds the remediation counts of a finished execution
+func (m *ServiceMetrics) RecordExecution(result *ExecutionResult) {
+	if m == nil || result == nil {
+		return
+	}
+	atomic.AddInt64(&m.RemediationsProcessed, int64(result.TotalProcessed))
+	atomic.AddInt64(&m.RemediationsSucceeded, int64(result.SuccessCount))
+	atomic.AddInt64(&m.RemediationsFailed, int64(result.FailureCount))
+	atomic.AddInt64(&m.RemediationsSkipped, int64(result.SkippedCount))
 }
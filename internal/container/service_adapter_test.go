@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/smithy-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -19,6 +20,7 @@ func TestDefaultRetryOptions(t *testing.T) {
 	assert.Equal(t, 100*time.Millisecond, opts.InitialDelay)
 	assert.Equal(t, 30*time.Second, opts.MaxDelay)
 	assert.NotNil(t, opts.BackoffFunction)
+	assert.NotNil(t, opts.RetryPredicate)
 }
 
 func TestNewServiceAdapter(t *testing.T) {
@@ -33,6 +35,17 @@ func TestNewServiceAdapter(t *testing.T) {
 	assert.Equal(t, 5, adapter.retryOptions.MaxAttempts)
 }
 
+func TestNewServiceAdapter_HTTPTimeout(t *testing.T) {
+	t.Setenv("AWS_HTTP_TIMEOUT_MS", "3000")
+
+	adapter := NewServiceAdapter(aws.Config{Region: "us-east-1"})
+
+	client, ok := adapter.config.HTTPClient.(*awshttp.BuildableClient)
+	require.True(t, ok)
+	assert.Equal(t, 3*time.Second, client.GetDialer().Timeout)
+	assert.Equal(t, 3*time.Second, client.GetTransport().ResponseHeaderTimeout)
+}
+
 func TestServiceAdapter_WithCustomRetryOptions(t *testing.T) {
 	cfg := aws.Config{
 		Region: "us-east-1",
@@ -175,6 +188,78 @@ func TestExecuteWithRetry(t *testing.T) {
 	})
 }
 
+func TestExecuteWithRetry_RetryPredicate(t *testing.T) {
+	cfg := aws.Config{
+		Region: "us-east-1",
+	}
+	fastBackoff := func(opts *RetryOptions) {
+		opts.MaxAttempts = 3
+		opts.BackoffFunction = func(attempt int, err error) time.Duration { return time.Millisecond }
+	}
+
+	t.Run("Custom predicate makes a non-retryable error retryable", func(t *testing.T) {
+		adapter := NewServiceAdapter(cfg, fastBackoff, func(opts *RetryOptions) {
+			base := opts.RetryPredicate
+			opts.RetryPredicate = func(err error) bool {
+				var apiErr smithy.APIError
+				if errors.As(err, &apiErr) && apiErr.ErrorCode() == "OperationAbortedException" {
+					return true
+				}
+				return base(err)
+			}
+		})
+
+		callCount := 0
+		err := adapter.ExecuteWithRetry(context.Background(), func() error {
+			callCount++
+			if callCount < 3 {
+				return &smithy.GenericAPIError{Code: "OperationAbortedException"}
+			}
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, callCount)
+	})
+
+	t.Run("Custom predicate excludes a normally retryable error", func(t *testing.T) {
+		adapter := NewServiceAdapter(cfg, fastBackoff, func(opts *RetryOptions) {
+			base := opts.RetryPredicate
+			opts.RetryPredicate = func(err error) bool {
+				var apiErr smithy.APIError
+				if errors.As(err, &apiErr) && apiErr.ErrorCode() == "ServiceUnavailable" {
+					return false
+				}
+				return base(err)
+			}
+		})
+
+		callCount := 0
+		err := adapter.ExecuteWithRetry(context.Background(), func() error {
+			callCount++
+			return &smithy.GenericAPIError{Code: "ServiceUnavailable"}
+		})
+
+		assert.Error(t, err)
+		assert.Equal(t, 1, callCount)
+	})
+
+	t.Run("Nil predicate keeps the default classification", func(t *testing.T) {
+		adapter := NewServiceAdapter(cfg, fastBackoff, func(opts *RetryOptions) {
+			opts.RetryPredicate = nil
+		})
+
+		callCount := 0
+		err := adapter.ExecuteWithRetry(context.Background(), func() error {
+			callCount++
+			return &smithy.GenericAPIError{Code: "ThrottlingException"}
+		})
+
+		assert.Error(t, err)
+		assert.Equal(t, 3, callCount)
+	})
+}
+
 func TestIsRetryableError(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -311,6 +396,27 @@ func TestIsThrottlingError(t *testing.T) {
 	}
 }
 
+func TestAdditionalRetryableCodes(t *testing.T) {
+	custom := &smithy.GenericAPIError{Code: "SlowDown"}
+
+	t.Run("unconfigured code is not retryable", func(t *testing.T) {
+		t.Setenv("ADDITIONAL_RETRYABLE_CODES", "")
+		assert.False(t, isThrottlingError(custom))
+		assert.False(t, isRetryableError(custom))
+	})
+
+	t.Run("configured code is retryable", func(t *testing.T) {
+		t.Setenv("ADDITIONAL_RETRYABLE_CODES", " EC2ThrottledException , SlowDown,")
+		assert.True(t, isThrottlingError(custom))
+		assert.True(t, isRetryableError(custom))
+		assert.True(t, isRetryableError(&smithy.GenericAPIError{Code: "EC2ThrottledException"}))
+
+		// The built-in codes still apply and other codes stay non-retryable
+		assert.True(t, isRetryableError(&smithy.GenericAPIError{Code: "ThrottlingException"}))
+		assert.False(t, isRetryableError(&smithy.GenericAPIError{Code: "ValidationException"}))
+	})
+}
+
 func TestExponentialBackoff(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -401,6 +507,33 @@ func TestRateLimiter(t *testing.T) {
 		assert.Equal(t, context.Canceled, err)
 	})
 
+	t.Run("Refill exits on context cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		rl := NewRateLimiterWithContext(ctx, 100)
+
+		cancel()
+
+		select {
+		case <-rl.refillDone:
+		case <-time.After(time.Second):
+			t.Fatal("Expected the refill goroutine to exit after the context was cancelled")
+		}
+		rl.Stop()
+	})
+
+	t.Run("Refill exits on Stop", func(t *testing.T) {
+		rl := NewRateLimiter(100)
+
+		rl.Stop()
+		rl.Stop()
+
+		select {
+		case <-rl.refillDone:
+		case <-time.After(time.Second):
+			t.Fatal("Expected the refill goroutine to exit after Stop")
+		}
+	})
+
 	t.Run("Throttle behavior", func(t *testing.T) {
 		rl := NewRateLimiter(10)
 		defer rl.Stop()
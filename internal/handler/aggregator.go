@@ -0,0 +1,230 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/zsoftly/logguardian/internal/types"
+)
+
+// DefaultAggregationMax is the number of buffered events that forces a flush when
+// AGGREGATION_MAX is not set
+const DefaultAggregationMax = 100
+
+// EventAggregator buffers Config events in a long-lived process and remediates them together
+// through the batch path, so one KMS validation covers many events. Buffered events are flushed
+// when the window since the first buffered event elapses, when the buffer reaches its maximum
+// size, and on Close.
+type EventAggregator struct {
+	handler   *ComplianceHandler
+	window    time.Duration
+	maxEvents int
+
+	mu      sync.Mutex
+	pending []bufferedEvent
+	timer   *time.Timer
+	flushes sync.WaitGroup
+	closed  bool
+}
+
+// bufferedEvent is a parsed Config event waiting for the next flush
+type bufferedEvent struct {
+	event types.ConfigEvent
+	key   string
+}
+
+// NewEventAggregator creates an aggregator that flushes window after the first buffered event
+// or once maxEvents are buffered; a maxEvents below one uses DefaultAggregationMax
+func NewEventAggregator(h *ComplianceHandler, window time.Duration, maxEvents int) *EventAggregator {
+	if maxEvents < 1 {
+		maxEvents = DefaultAggregationMax
+	}
+	return &EventAggregator{
+		handler:   h,
+		window:    window,
+		maxEvents: maxEvents,
+	}
+}
+
+// NewEventAggregatorFromEnvironment creates an aggregator from AGGREGATION_WINDOW_MS and
+// AGGREGATION_MAX. Aggregation is off by default, so nil is returned unless a positive window is set.
+func NewEventAggregatorFromEnvironment(h *ComplianceHandler) *EventAggregator {
+	windowMs, err := strconv.Atoi(os.Getenv("AGGREGATION_WINDOW_MS"))
+	if err != nil || windowMs <= 0 {
+		return nil
+	}
+	maxEvents, err := strconv.Atoi(os.Getenv("AGGREGATION_MAX"))
+	if err != nil {
+		maxEvents = DefaultAggregationMax
+	}
+	return NewEventAggregator(h, time.Duration(windowMs)*time.Millisecond, maxEvents)
+}
+
+// Add parses a Config event and buffers it for the next flush. Events already handled are
+// dropped. When the buffer reaches its maximum size it is flushed before Add returns, and the
+// flush error is returned.
+func (a *EventAggregator) Add(ctx context.Context, event json.RawMessage) error {
+	var configEvent types.ConfigEvent
+	if err := json.Unmarshal(event, &configEvent); err != nil {
+		slog.Error("Failed to parse Config event", "error", err)
+		return fmt.Errorf("failed to parse Config event: %w", err)
+	}
+	normalizeConfigEvent(&configEvent)
+
+	key := idempotencyKey(configEvent, a.handler.ruleClassifier.ClassifyRule(configEvent.ConfigRuleName))
+	if key != "" && !a.handler.idempotency.Claim(ctx, key) {
+		slog.Info("Skipping duplicate Config event",
+			"resource_name", configEvent.ConfigRuleInvokingEvent.ConfigurationItem.ResourceName,
+			"config_rule", configEvent.ConfigRuleName,
+			"idempotency_key", key,
+			"audit_action", "duplicate_event_skipped")
+		return nil
+	}
+
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		if key != "" {
+			a.handler.idempotency.Release(ctx, key)
+		}
+		return errors.New("event aggregator is closed")
+	}
+	a.pending = append(a.pending, bufferedEvent{event: configEvent, key: key})
+	if len(a.pending) >= a.maxEvents {
+		events := a.takeLocked()
+		a.mu.Unlock()
+		return a.flush(ctx, events, "max_events")
+	}
+	if a.timer == nil {
+		// Window flushes outlive the caller that started the window, so they keep only its values
+		flushCtx := context.WithoutCancel(ctx)
+		a.flushes.Add(1)
+		a.timer = time.AfterFunc(a.window, func() {
+			defer a.flushes.Done()
+			a.mu.Lock()
+			events := a.takeLocked()
+			a.mu.Unlock()
+			if err := a.flush(flushCtx, events, "window"); err != nil {
+				slog.Error("Aggregated batch flush failed", "error", err)
+			}
+		})
+	}
+	a.mu.Unlock()
+	return nil
+}
+
+// Close stops accepting events and flushes anything still buffered, waiting for a window flush
+// already in progress to finish
+func (a *EventAggregator) Close(ctx context.Context) error {
+	a.mu.Lock()
+	a.closed = true
+	events := a.takeLocked()
+	a.mu.Unlock()
+
+	err := a.flush(ctx, events, "shutdown")
+	a.flushes.Wait()
+	return err
+}
+
+// takeLocked removes and returns the buffered events and stops any pending window flush;
+// the caller must hold a.mu
+func (a *EventAggregator) takeLocked() []bufferedEvent {
+	if a.timer != nil && a.timer.Stop() {
+		a.flushes.Done()
+	}
+	a.timer = nil
+	events := a.pending
+	a.pending = nil
+	return events
+}
+
+// flush remediates the events in batches per Config rule, marking events handled once their
+// log group has been remediated successfully
+func (a *EventAggregator) flush(ctx context.Context, events []bufferedEvent, trigger string) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	var rules []string
+	byRule := make(map[string][]bufferedEvent)
+	for _, buffered := range events {
+		rule := buffered.event.ConfigRuleName
+		if _, ok := byRule[rule]; !ok {
+			rules = append(rules, rule)
+		}
+		byRule[rule] = append(byRule[rule], buffered)
+	}
+
+	slog.Info("Flushing aggregated Config events",
+		"trigger", trigger,
+		"event_count", len(events),
+		"rule_count", len(rules))
+
+	var errs []error
+	for _, rule := range rules {
+		ruleEvents := byRule[rule]
+		configEvents := make([]types.ConfigEvent, len(ruleEvents))
+		for i, buffered := range ruleEvents {
+			configEvents[i] = buffered.event
+		}
+
+		unremediated := make(map[string]bool)
+		for _, request := range a.handler.BuildBatchRequests(configEvents, rule) {
+			result, err := a.handler.complianceService.ProcessNonCompliantResourcesOptimized(ctx, request)
+			if err != nil {
+				slog.Error("Aggregated batch remediation failed",
+					"config_rule", rule,
+					"resource_count", len(request.NonCompliantResults),
+					"error", err)
+				errs = append(errs, fmt.Errorf("batch remediation failed for rule %s: %w", rule, err))
+				for _, resource := range request.NonCompliantResults {
+					unremediated[resource.ResourceName] = true
+				}
+				continue
+			}
+			slog.Info("Aggregated batch remediation completed",
+				"config_rule", rule,
+				"total_processed", result.TotalProcessed,
+				"success_count", result.SuccessCount,
+				"failure_count", result.FailureCount)
+			maps.Copy(unremediated, unremediatedLogGroups(request, result))
+		}
+
+		// Events whose log group failed or was never reached are released, so a redelivery retries them
+		for _, buffered := range ruleEvents {
+			if buffered.key == "" {
+				continue
+			}
+			logGroupName := buffered.event.ConfigRuleInvokingEvent.ConfigurationItem.Configuration.Normalized().LogGroupName
+			if unremediated[logGroupName] {
+				a.handler.idempotency.Release(ctx, buffered.key)
+			} else {
+				a.handler.idempotency.Mark(ctx, buffered.key)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// unremediatedLogGroups returns the requested log groups the batch did not remediate
+// successfully, including any it never reached
+func unremediatedLogGroups(request types.BatchComplianceRequest, result *types.BatchRemediationResult) map[string]bool {
+	unremediated := make(map[string]bool, len(request.NonCompliantResults))
+	for _, resource := range request.NonCompliantResults {
+		unremediated[resource.ResourceName] = true
+	}
+	for _, r := range result.Results {
+		if r.Success {
+			delete(unremediated, r.LogGroupName)
+		}
+	}
+	return unremediated
+}
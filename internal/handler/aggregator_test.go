@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zsoftly/logguardian/internal/types"
+)
+
+// batchRecordingService records the batch requests an aggregator flushes and fails to remediate
+// the log groups in failing
+type batchRecordingService struct {
+	MockComplianceService
+	mu       sync.Mutex
+	requests []types.BatchComplianceRequest
+	flushed  chan struct{}
+	failing  map[string]bool
+}
+
+func newBatchRecordingService() *batchRecordingService {
+	return &batchRecordingService{flushed: make(chan struct{}, 10)}
+}
+
+func (s *batchRecordingService) ProcessNonCompliantResourcesOptimized(ctx context.Context, request types.BatchComplianceRequest) (*types.BatchRemediationResult, error) {
+	s.mu.Lock()
+	s.requests = append(s.requests, request)
+	s.mu.Unlock()
+	s.flushed <- struct{}{}
+
+	result := &types.BatchRemediationResult{TotalProcessed: len(request.NonCompliantResults)}
+	for _, resource := range request.NonCompliantResults {
+		success := !s.failing[resource.ResourceName]
+		if success {
+			result.SuccessCount++
+		} else {
+			result.FailureCount++
+		}
+		result.Results = append(result.Results, types.RemediationResult{
+			LogGroupName:      resource.ResourceName,
+			Region:            resource.Region,
+			EncryptionApplied: success,
+			Success:           success,
+		})
+	}
+	return result, nil
+}
+
+func (s *batchRecordingService) recorded() []types.BatchComplianceRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]types.BatchComplianceRequest(nil), s.requests...)
+}
+
+func unencryptedLogGroupEvent(t *testing.T, name string) json.RawMessage {
+	t.Helper()
+	event, err := json.Marshal(types.ConfigEvent{
+		ConfigRuleName: "cloudwatch-log-group-encrypted",
+		AccountId:      "123456789012",
+		ConfigRuleInvokingEvent: types.ConfigRuleInvokingEvent{
+			ConfigurationItem: types.ConfigurationItem{
+				ResourceType:              "AWS::Logs::LogGroup",
+				ResourceId:                name,
+				ResourceName:              name,
+				AwsRegion:                 "ca-central-1",
+				AwsAccountId:              "123456789012",
+				ConfigurationItemStatus:   "OK",
+				ConfigurationStateMd5Hash: "hash-" + name,
+				Configuration:             types.LogGroupConfiguration{LogGroupName: name},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal event: %v", err)
+	}
+	return event
+}
+
+func TestEventAggregator_WindowFlush(t *testing.T) {
+	svc := newBatchRecordingService()
+	aggregator := NewEventAggregator(NewComplianceHandler(svc), 20*time.Millisecond, 10)
+
+	for i := range 3 {
+		if err := aggregator.Add(context.Background(), unencryptedLogGroupEvent(t, fmt.Sprintf("/aws/lambda/fn-%d", i))); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+	if len(svc.recorded()) != 0 {
+		t.Fatal("Expected no flush before the window elapses")
+	}
+
+	select {
+	case <-svc.flushed:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the window to flush buffered events")
+	}
+	requests := svc.recorded()
+	if len(requests) != 1 || len(requests[0].NonCompliantResults) != 3 {
+		t.Fatalf("Expected one batch of 3 resources, got %+v", requests)
+	}
+	if err := aggregator.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if len(svc.recorded()) != 1 {
+		t.Error("Expected Close with an empty buffer not to flush again")
+	}
+}
+
+func TestEventAggregator_CountFlush(t *testing.T) {
+	svc := newBatchRecordingService()
+	aggregator := NewEventAggregator(NewComplianceHandler(svc), time.Hour, 2)
+
+	for i := range 2 {
+		if err := aggregator.Add(context.Background(), unencryptedLogGroupEvent(t, fmt.Sprintf("/aws/lambda/fn-%d", i))); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+
+	requests := svc.recorded()
+	if len(requests) != 1 || len(requests[0].NonCompliantResults) != 2 {
+		t.Fatalf("Expected the second event to flush a batch of 2, got %+v", requests)
+	}
+	if requests[0].ConfigRuleName != "cloudwatch-log-group-encrypted" || requests[0].Region != "ca-central-1" {
+		t.Errorf("Unexpected batch request: %+v", requests[0])
+	}
+
+	// A redelivered event is already handled and is not buffered again
+	if err := aggregator.Add(context.Background(), unencryptedLogGroupEvent(t, "/aws/lambda/fn-0")); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := aggregator.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if len(svc.recorded()) != 1 {
+		t.Error("Expected the duplicate event to be skipped")
+	}
+}
+
+func TestEventAggregator_FailedResourceNotMarked(t *testing.T) {
+	svc := newBatchRecordingService()
+	svc.failing = map[string]bool{"/aws/lambda/failed": true}
+	aggregator := NewEventAggregator(NewComplianceHandler(svc), time.Hour, 2)
+
+	for _, name := range []string{"/aws/lambda/failed", "/aws/lambda/remediated"} {
+		if err := aggregator.Add(context.Background(), unencryptedLogGroupEvent(t, name)); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+
+	// Only the event whose remediation failed is buffered again on redelivery
+	for _, name := range []string{"/aws/lambda/failed", "/aws/lambda/remediated"} {
+		if err := aggregator.Add(context.Background(), unencryptedLogGroupEvent(t, name)); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+	if err := aggregator.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	requests := svc.recorded()
+	if len(requests) != 2 {
+		t.Fatalf("Expected a retry batch after the failure, got %+v", requests)
+	}
+	retried := requests[1].NonCompliantResults
+	if len(retried) != 1 || retried[0].ResourceName != "/aws/lambda/failed" {
+		t.Errorf("Expected only the failed log group to be retried, got %+v", retried)
+	}
+}
+
+func TestEventAggregator_ShutdownFlush(t *testing.T) {
+	svc := newBatchRecordingService()
+	aggregator := NewEventAggregator(NewComplianceHandler(svc), time.Hour, 10)
+
+	if err := aggregator.Add(context.Background(), unencryptedLogGroupEvent(t, "/aws/lambda/fn")); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := aggregator.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	requests := svc.recorded()
+	if len(requests) != 1 || len(requests[0].NonCompliantResults) != 1 {
+		t.Fatalf("Expected Close to flush the buffered event, got %+v", requests)
+	}
+	if err := aggregator.Add(context.Background(), unencryptedLogGroupEvent(t, "/aws/lambda/late")); err == nil {
+		t.Error("Expected Add after Close to fail")
+	}
+}
+
+func TestNewEventAggregatorFromEnvironment(t *testing.T) {
+	h := NewComplianceHandler(&MockComplianceService{})
+
+	t.Setenv("AGGREGATION_WINDOW_MS", "")
+	if NewEventAggregatorFromEnvironment(h) != nil {
+		t.Error("Expected aggregation to be off without AGGREGATION_WINDOW_MS")
+	}
+
+	t.Setenv("AGGREGATION_WINDOW_MS", "250")
+	t.Setenv("AGGREGATION_MAX", "25")
+	aggregator := NewEventAggregatorFromEnvironment(h)
+	if aggregator == nil || aggregator.window != 250*time.Millisecond || aggregator.maxEvents != 25 {
+		t.Errorf("Unexpected aggregator from environment: %+v", aggregator)
+	}
+}
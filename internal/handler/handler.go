@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/zsoftly/logguardian/internal/service"
 	"github.com/zsoftly/logguardian/internal/types"
@@ -14,26 +16,144 @@ import (
 type ComplianceHandler struct {
 	complianceService service.ComplianceServiceInterface
 	ruleClassifier    *types.RuleClassifier
+	idempotency       IdempotencyStore
+}
+
+// ComplianceHandlerOption configures optional ComplianceHandler behaviour
+type ComplianceHandlerOption func(*ComplianceHandler)
+
+// WithIdempotencyStore replaces the default in-memory store used to skip redelivered Config events
+func WithIdempotencyStore(store IdempotencyStore) ComplianceHandlerOption {
+	return func(h *ComplianceHandler) {
+		h.idempotency = store
+	}
 }
 
 // NewComplianceHandler creates a new compliance handler
-func NewComplianceHandler(complianceService service.ComplianceServiceInterface) *ComplianceHandler {
-	return &ComplianceHandler{
+func NewComplianceHandler(complianceService service.ComplianceServiceInterface, opts ...ComplianceHandlerOption) *ComplianceHandler {
+	h := &ComplianceHandler{
 		complianceService: complianceService,
 		ruleClassifier:    types.NewRuleClassifier(),
+		idempotency:       NewMemoryIdempotencyStore(DefaultIdempotencyTTL),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// executionSummary is the single consolidated record logged at the end of each handler path,
+// so every invocation can be found with one CloudWatch Logs Insights query
+type executionSummary struct {
+	requestType string
+	configRule  string
+	region      string
+	total       int
+	success     int
+	failure     int
+	skipped     int
+	deferred    int
+	cancelled   bool
+	aborted     bool
+	timedOut    bool
+	resources   []types.ResourceOutcome
+	startTime   time.Time
+}
+
+func newExecutionSummary(requestType string) *executionSummary {
+	return &executionSummary{
+		requestType: requestType,
+		startTime:   time.Now(),
+	}
+}
+
+// log emits the summary; err is the error the handler is about to return, if any
+func (s *executionSummary) log(err error) {
+	slog.Info("Execution summary",
+		"request_type", s.requestType,
+		"config_rule", s.configRule,
+		"region", s.region,
+		"total", s.total,
+		"success", s.success,
+		"failure", s.failure,
+		"skipped", s.skipped,
+		"deferred", s.deferred,
+		"duration_ms", time.Since(s.startTime).Milliseconds(),
+		"status", summaryStatus(err),
+		"audit_action", "execution_summary")
+}
+
+// report converts the summary into the invocation response
+func (s *executionSummary) report(err error) types.RunReport {
+	return types.RunReport{
+		RequestType:      s.requestType,
+		ConfigRuleName:   s.configRule,
+		Region:           s.region,
+		Total:            s.total,
+		Success:          s.success,
+		Failure:          s.failure,
+		Skipped:          s.skipped,
+		Deferred:         s.deferred,
+		Cancelled:        s.cancelled,
+		AbortedOnFailure: s.aborted,
+		TimedOut:         s.timedOut,
+		DurationMs:       time.Since(s.startTime).Milliseconds(),
+		Status:           summaryStatus(err),
+		Resources:        s.resources,
+	}
+}
+
+// addResult records the outcome of one log group's remediation
+func (s *executionSummary) addResult(result types.RemediationResult) {
+	outcome := types.ResourceOutcome{
+		LogGroupName:      result.LogGroupName,
+		Region:            result.Region,
+		Status:            result.Status(),
+		EncryptionApplied: result.EncryptionApplied,
+		RetentionApplied:  result.RetentionApplied,
+		SkipReason:        result.SkipReason,
+		DurationMs:        result.Duration.Milliseconds(),
+	}
+	if result.Error != nil {
+		outcome.Error = result.Error.Error()
+	}
+	s.resources = append(s.resources, outcome)
+}
+
+func summaryStatus(err error) string {
+	if err != nil {
+		return "failed"
 	}
+	return "success"
 }
 
 // HandleConfigEvent handles AWS Config rule evaluation events
 func (h *ComplianceHandler) HandleConfigEvent(ctx context.Context, event json.RawMessage) error {
+	_, err := h.HandleConfigEventWithReport(ctx, event)
+	return err
+}
+
+// HandleConfigEventWithReport handles an AWS Config rule evaluation event and returns a report of the outcome
+func (h *ComplianceHandler) HandleConfigEventWithReport(ctx context.Context, event json.RawMessage) (report types.RunReport, err error) {
 	slog.Info("Received Config compliance event", "event_size", len(event))
 
+	summary := newExecutionSummary("config-event")
+	defer func() {
+		summary.log(err)
+		report = summary.report(err)
+	}()
+
 	// Parse the event
 	var configEvent types.ConfigEvent
 	if err := json.Unmarshal(event, &configEvent); err != nil {
 		slog.Error("Failed to parse Config event", "error", err)
-		return fmt.Errorf("failed to parse Config event: %w", err)
+		return report, fmt.Errorf("failed to parse Config event: %w", err)
 	}
+	normalizeConfigEvent(&configEvent)
+
+	summary.configRule = configEvent.ConfigRuleName
+	summary.region = configEvent.ConfigRuleInvokingEvent.ConfigurationItem.AwsRegion
+	summary.total = 1
 
 	slog.Info("Processing compliance event",
 		"config_rule", configEvent.ConfigRuleName,
@@ -46,16 +166,41 @@ func (h *ComplianceHandler) HandleConfigEvent(ctx context.Context, event json.Ra
 	configItem := configEvent.ConfigRuleInvokingEvent.ConfigurationItem
 	if configItem.ConfigurationItemStatus == "ResourceDeleted" {
 		slog.Info("Skipping deleted resource", "resource_name", configItem.ResourceName)
-		return nil
+		summary.skipped = 1
+		return report, nil
+	}
+
+	// A resource Config no longer records has an incomplete configuration snapshot, and acting
+	// on it could remediate a log group that is already compliant or no longer exists
+	if configItem.ConfigurationItemStatus == "ResourceNotRecorded" {
+		slog.Warn("Skipping resource not recorded by Config",
+			"resource_name", configItem.ResourceName,
+			"config_rule", configEvent.ConfigRuleName,
+			"audit_action", "not_recorded_resource_skipped")
+		summary.skipped = 1
+		return report, nil
 	}
 
-	if configItem.ResourceType != "AWS::Logs::LogGroup" {
+	if configItem.ResourceType != service.LogGroupResourceType {
 		slog.Warn("Unexpected resource type", "resource_type", configItem.ResourceType)
-		return nil
+		summary.skipped = 1
+		return report, nil
+	}
+
+	// Skip events already claimed or handled, such as an SQS redelivery of the same configuration state
+	key := idempotencyKey(configEvent, h.ruleClassifier.ClassifyRule(configEvent.ConfigRuleName))
+	if key != "" && !h.idempotency.Claim(ctx, key) {
+		slog.Info("Skipping duplicate Config event",
+			"resource_name", configItem.ResourceName,
+			"config_rule", configEvent.ConfigRuleName,
+			"idempotency_key", key,
+			"audit_action", "duplicate_event_skipped")
+		summary.skipped = 1
+		return report, nil
 	}
 
 	// Check compliance status based on specific rule
-	compliance := h.analyzeComplianceForRule(configEvent.ConfigRuleName, configItem)
+	compliance := h.analyzeComplianceForRule(configEvent.ConfigRuleName, configEvent.RuleParameters, configItem)
 
 	slog.Info("Rule-specific compliance analysis completed",
 		"log_group", compliance.LogGroupName,
@@ -71,42 +216,102 @@ func (h *ComplianceHandler) HandleConfigEvent(ctx context.Context, event json.Ra
 			slog.Error("Remediation failed",
 				"log_group", compliance.LogGroupName,
 				"error", err)
-			return fmt.Errorf("remediation failed for %s: %w", compliance.LogGroupName, err)
+			summary.failure = 1
+			failed := types.RemediationResult{
+				LogGroupName: compliance.LogGroupName,
+				Region:       compliance.Region,
+				Error:        err,
+			}
+			summary.addResult(failed)
+			h.reportEvaluation(ctx, configEvent, &failed)
+			if key != "" {
+				h.idempotency.Release(ctx, key)
+			}
+			return report, fmt.Errorf("remediation failed for %s: %w", compliance.LogGroupName, err)
 		}
 
+		if result.NoActionNeeded || (!result.EncryptionApplied && !result.RetentionApplied) {
+			summary.skipped = 1
+		} else {
+			summary.success = 1
+		}
+		summary.addResult(*result)
+
 		slog.Info("Remediation completed",
 			"log_group", result.LogGroupName,
 			"encryption_applied", result.EncryptionApplied,
 			"retention_applied", result.RetentionApplied,
+			"skip_reason", result.SkipReason,
 			"success", result.Success)
+		h.reportEvaluation(ctx, configEvent, result)
 	} else {
 		slog.Info("Log group already compliant", "log_group", compliance.LogGroupName)
+		summary.skipped = 1
+		compliant := types.RemediationResult{
+			LogGroupName:   compliance.LogGroupName,
+			Region:         compliance.Region,
+			NoActionNeeded: true,
+			Success:        true,
+		}
+		summary.addResult(compliant)
+		h.reportEvaluation(ctx, configEvent, &compliant)
 	}
 
-	return nil
+	if key != "" {
+		h.idempotency.Mark(ctx, key)
+	}
+	return report, nil
+}
+
+// reportEvaluation reports the outcome back to Config; a reporting failure never fails the event
+func (h *ComplianceHandler) reportEvaluation(ctx context.Context, configEvent types.ConfigEvent, result *types.RemediationResult) {
+	configItem := configEvent.ConfigRuleInvokingEvent.ConfigurationItem
+	if err := h.complianceService.ReportEvaluation(ctx, configEvent.ResultToken, configItem, result); err != nil {
+		slog.Warn("Failed to report evaluation to Config",
+			"log_group", result.LogGroupName,
+			"config_rule", configEvent.ConfigRuleName,
+			"error", err)
+	}
 }
 
 // HandleConfigRuleEvaluationRequest handles requests to process Config rule evaluation results
 func (h *ComplianceHandler) HandleConfigRuleEvaluationRequest(ctx context.Context, configRuleName, region string, batchSize int) error {
+	_, err := h.HandleConfigRuleEvaluationRequestWithReport(ctx, configRuleName, region, batchSize)
+	return err
+}
+
+// HandleConfigRuleEvaluationRequestWithReport processes Config rule evaluation results and returns
+// a report of the batch outcome
+func (h *ComplianceHandler) HandleConfigRuleEvaluationRequestWithReport(ctx context.Context, configRuleName, region string, batchSize int) (report types.RunReport, err error) {
 	slog.Info("Processing Config rule evaluation request",
 		"config_rule", configRuleName,
 		"region", region,
 		"batch_size", batchSize)
 
+	summary := newExecutionSummary("config-rule-evaluation")
+	summary.configRule = configRuleName
+	summary.region = region
+	defer func() {
+		summary.log(err)
+		report = summary.report(err)
+	}()
+
 	// Step 1: Get non-compliant resources from Config API
 	nonCompliantResources, err := h.complianceService.GetNonCompliantResources(ctx, configRuleName, region)
 	if err != nil {
 		slog.Error("Failed to retrieve non-compliant resources",
 			"config_rule", configRuleName,
 			"error", err)
-		return fmt.Errorf("failed to retrieve non-compliant resources: %w", err)
+		return report, fmt.Errorf("failed to retrieve non-compliant resources: %w", err)
 	}
 
+	summary.total = len(nonCompliantResources)
+
 	if len(nonCompliantResources) == 0 {
 		slog.Info("No non-compliant resources found",
 			"config_rule", configRuleName,
 			"region", region)
-		return nil
+		return report, nil
 	}
 
 	slog.Info("Found non-compliant resources",
@@ -120,14 +325,16 @@ func (h *ComplianceHandler) HandleConfigRuleEvaluationRequest(ctx context.Contex
 		slog.Error("Failed to validate resource existence",
 			"config_rule", configRuleName,
 			"error", err)
-		return fmt.Errorf("failed to validate resource existence: %w", err)
+		return report, fmt.Errorf("failed to validate resource existence: %w", err)
 	}
 
+	summary.skipped = len(nonCompliantResources) - len(validResources)
+
 	if len(validResources) == 0 {
 		slog.Info("No valid resources found after validation",
 			"config_rule", configRuleName,
 			"region", region)
-		return nil
+		return report, nil
 	}
 
 	slog.Info("Validated resources for processing",
@@ -136,10 +343,26 @@ func (h *ComplianceHandler) HandleConfigRuleEvaluationRequest(ctx context.Contex
 		"valid_count", len(validResources),
 		"filtered_count", len(nonCompliantResources)-len(validResources))
 
+	filteredResources, skipped := service.NewDefaultFilterChain().Apply(ctx, validResources)
+	for _, skip := range skipped {
+		slog.Info("Skipping filtered resource",
+			"config_rule", configRuleName,
+			"log_group", skip.Resource.ResourceName,
+			"reason", string(skip.Reason))
+	}
+	summary.skipped += len(skipped)
+
+	if len(filteredResources) == 0 {
+		slog.Info("No resources left to process after filtering",
+			"config_rule", configRuleName,
+			"region", region)
+		return report, nil
+	}
+
 	// Step 3: Create batch request and process
 	batchRequest := types.BatchComplianceRequest{
 		ConfigRuleName:      configRuleName,
-		NonCompliantResults: validResources,
+		NonCompliantResults: filteredResources,
 		Region:              region,
 		BatchSize:           batchSize,
 	}
@@ -150,7 +373,7 @@ func (h *ComplianceHandler) HandleConfigRuleEvaluationRequest(ctx context.Contex
 		slog.Error("Optimized batch processing failed",
 			"config_rule", configRuleName,
 			"error", err)
-		return fmt.Errorf("optimized batch processing failed: %w", err)
+		return report, fmt.Errorf("optimized batch processing failed: %w", err)
 	}
 
 	slog.Info("Config rule evaluation processing completed",
@@ -160,21 +383,149 @@ func (h *ComplianceHandler) HandleConfigRuleEvaluationRequest(ctx context.Contex
 		"success_count", result.SuccessCount,
 		"failure_count", result.FailureCount,
 		"duration", result.ProcessingDuration,
-		"rate_limit_hits", result.RateLimitHits)
+		"rate_limit_hits", result.RateLimitHits,
+		"cancelled", result.Cancelled,
+		"aborted_on_failure", result.AbortedOnFailure)
+
+	summary.success = result.SuccessCount - result.NoActionCount - result.SkippedCount
+	summary.failure = result.FailureCount
+	summary.skipped += result.NoActionCount + result.SkippedCount + result.NotApplicableCount
+	summary.cancelled = result.Cancelled
+	summary.aborted = result.AbortedOnFailure
+	summary.timedOut = result.TimedOut
+	summary.deferred = len(result.Deferred)
+	for _, r := range result.Results {
+		summary.addResult(r)
+	}
 
-	return nil
+	return report, nil
 }
 
-// analyzeComplianceForRule checks what remediation is needed based on the specific Config rule
-func (h *ComplianceHandler) analyzeComplianceForRule(configRuleName string, configItem types.ConfigurationItem) types.ComplianceResult {
-	config := configItem.Configuration
+// BuildBatchRequests converts buffered Config events into batch requests for configRuleName.
+// Each event is evaluated with the same rule-type-aware analysis as HandleConfigEvent; deleted or
+// unrecorded resources, non-log-group resources and groups already compliant for the rule are left out.
+// Events are grouped by region, by the retention and KMS key targets their rule parameters set and by
+// their batch size and delays, with one request per group in order of first appearance.
+func (h *ComplianceHandler) BuildBatchRequests(events []types.ConfigEvent, configRuleName string) []types.BatchComplianceRequest {
+	var requests []types.BatchComplianceRequest
+	indexByKey := make(map[string]int)
+	included := 0
+
+	for _, event := range events {
+		normalizeConfigEvent(&event)
+		configItem := event.ConfigRuleInvokingEvent.ConfigurationItem
+		status := configItem.ConfigurationItemStatus
+		if status == "ResourceDeleted" || status == "ResourceNotRecorded" || configItem.ResourceType != service.LogGroupResourceType {
+			continue
+		}
+
+		compliance := h.analyzeComplianceForRule(configRuleName, event.RuleParameters, configItem)
+		if !compliance.MissingEncryption && !compliance.MissingRetention {
+			continue
+		}
+
+		var missing []string
+		if compliance.MissingEncryption {
+			missing = append(missing, "encryption")
+		}
+		if compliance.MissingRetention {
+			missing = append(missing, "retention")
+		}
+
+		request := types.BatchComplianceRequest{
+			ConfigRuleName:      configRuleName,
+			Region:              configItem.AwsRegion,
+			TargetRetentionDays: compliance.TargetRetentionDays,
+			TargetKMSKeyId:      compliance.TargetKMSKeyId,
+		}
+		applyBatchParameters(&request, event.RuleParameters)
+		key := batchRequestKey(request)
+		index, ok := indexByKey[key]
+		if !ok {
+			index = len(requests)
+			indexByKey[key] = index
+			requests = append(requests, request)
+		}
+		requests[index].NonCompliantResults = append(requests[index].NonCompliantResults, types.NonCompliantResource{
+			ResourceId:     configItem.ResourceId,
+			ResourceType:   configItem.ResourceType,
+			ResourceName:   compliance.LogGroupName,
+			Region:         compliance.Region,
+			AccountId:      compliance.AccountId,
+			ComplianceType: "NON_COMPLIANT",
+			Annotation:     "Missing " + strings.Join(missing, " and "),
+			LastEvaluated:  configItem.ConfigurationItemCaptureTime,
+		})
+		included++
+	}
+
+	slog.Info("Built batch requests from Config events",
+		"config_rule", configRuleName,
+		"event_count", len(events),
+		"non_compliant_count", included,
+		"request_count", len(requests))
+
+	return requests
+}
+
+// batchRequestKey identifies the settings a batch request applies to all its resources, so
+// resources are batched together only when they share them
+func batchRequestKey(request types.BatchComplianceRequest) string {
+	key := fmt.Sprintf("%s|%s|%d", request.Region, request.TargetKMSKeyId, request.BatchSize)
+	if request.TargetRetentionDays != nil {
+		key += fmt.Sprintf("|retention=%d", *request.TargetRetentionDays)
+	}
+	if request.ResourceDelayMs != nil {
+		key += fmt.Sprintf("|resource_delay=%d", *request.ResourceDelayMs)
+	}
+	if request.GroupDelayMs != nil {
+		key += fmt.Sprintf("|group_delay=%d", *request.GroupDelayMs)
+	}
+	return key
+}
+
+// normalizeConfigEvent gives an event without rule parameters, which Config sends as null or
+// omits entirely, an empty parameter map so later code never meets a nil map
+func normalizeConfigEvent(event *types.ConfigEvent) {
+	if event.RuleParameters == nil {
+		event.RuleParameters = map[string]string{}
+	}
+}
+
+// applyBatchParameters applies the batch tuning a Config rule supplies through its parameters.
+// Absent or invalid values leave the service defaults in place.
+func applyBatchParameters(request *types.BatchComplianceRequest, ruleParameters map[string]string) {
+	if batchSize, ok := types.BatchSizeFromParameters(ruleParameters); ok {
+		request.BatchSize = batchSize
+	}
+	if delayMs, ok := types.DelayMsFromParameters(ruleParameters, types.ResourceDelayParameterKey); ok {
+		request.ResourceDelayMs = &delayMs
+	}
+	if delayMs, ok := types.DelayMsFromParameters(ruleParameters, types.GroupDelayParameterKey); ok {
+		request.GroupDelayMs = &delayMs
+	}
+}
+
+// analyzeComplianceForRule checks what remediation is needed based on the specific Config rule.
+// Well-known rule parameters override the service defaults; absent parameters fall back to global config.
+func (h *ComplianceHandler) analyzeComplianceForRule(configRuleName string, ruleParameters map[string]string, configItem types.ConfigurationItem) types.ComplianceResult {
+	config := configItem.Configuration.Normalized()
 
 	result := types.ComplianceResult{
-		LogGroupName:     config.LogGroupName,
-		Region:           configItem.AwsRegion,
-		AccountId:        configItem.AwsAccountId,
-		CurrentRetention: config.RetentionInDays,
-		CurrentKmsKeyId:  config.KmsKeyId,
+		LogGroupName:         config.LogGroupName,
+		Region:               configItem.AwsRegion,
+		AccountId:            configItem.AwsAccountId,
+		CurrentRetention:     config.RetentionInDays,
+		CurrentKmsKeyId:      config.KmsKeyId,
+		DataProtectionStatus: config.DataProtectionStatus,
+	}
+
+	if invalid := types.InvalidRuleParameters(ruleParameters); len(invalid) > 0 {
+		slog.Warn("Ignoring invalid Config rule parameters, falling back to service defaults",
+			"config_rule", configRuleName,
+			"log_group", config.LogGroupName,
+			"invalid_parameters", invalid,
+			"audit_action", "invalid_rule_parameters")
 	}
 
 	// Each Config rule evaluates ONLY its specific compliance requirement
@@ -187,10 +538,25 @@ func (h *ComplianceHandler) analyzeComplianceForRule(configRuleName string, conf
 		result.MissingEncryption = config.KmsKeyId == ""
 		result.MissingRetention = false // Not this rule's concern
 
+		// A rule-supplied key must match exactly; any other key is non-compliant
+		if requiredKeyId, ok := types.KMSKeyIdFromParameters(ruleParameters); ok {
+			result.TargetKMSKeyId = requiredKeyId
+			// An alias cannot be compared with the current key ARN without KMS, so a required
+			// alias is unverifiable and only selects the key used for unencrypted groups
+			if !types.IsKMSKeyAlias(requiredKeyId) {
+				result.MissingEncryption = !types.KMSKeyMatches(config.KmsKeyId, requiredKeyId)
+			}
+		} else if keyAlias, ok := types.KMSKeyAliasFromParameters(ruleParameters); ok {
+			// An alias cannot be compared with the current key ARN without KMS, so it only
+			// selects the key used for unencrypted groups
+			result.TargetKMSKeyId = keyAlias
+		}
+
 		slog.Info("Encryption rule evaluation",
 			"log_group", config.LogGroupName,
 			"has_encryption", config.KmsKeyId != "",
 			"kms_key_id", config.KmsKeyId,
+			"required_kms_key_id", result.TargetKMSKeyId,
 			"rule_type", ruleType.String(),
 			"audit_action", "encryption_compliance_check")
 
@@ -199,10 +565,17 @@ func (h *ComplianceHandler) analyzeComplianceForRule(configRuleName string, conf
 		result.MissingRetention = config.RetentionInDays == nil
 		result.MissingEncryption = false // Not this rule's concern
 
+		// A rule-supplied retention is a minimum; shorter retention is non-compliant
+		if desiredDays, ok := types.RetentionDaysFromParameters(ruleParameters); ok {
+			result.TargetRetentionDays = &desiredDays
+			result.MissingRetention = config.RetentionInDays == nil || *config.RetentionInDays < desiredDays
+		}
+
 		slog.Info("Retention rule evaluation",
 			"log_group", config.LogGroupName,
 			"has_retention", config.RetentionInDays != nil,
 			"retention_days", config.RetentionInDays,
+			"desired_retention_days", result.TargetRetentionDays,
 			"rule_type", ruleType.String(),
 			"audit_action", "retention_compliance_check")
 
@@ -3,9 +3,14 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"log/slog"
+	"reflect"
 	"testing"
 	"time"
 
+	"github.com/zsoftly/logguardian/internal/service"
+	"github.com/zsoftly/logguardian/internal/testutil"
 	"github.com/zsoftly/logguardian/internal/types"
 )
 
@@ -57,6 +62,26 @@ func TestComplianceHandler_HandleConfigEvent(t *testing.T) {
 			expectError: false,
 			expectCall:  false,
 		},
+		{
+			name: "resource not recorded should be skipped",
+			event: types.ConfigEvent{
+				ConfigRuleName: "cloudwatch-log-group-encrypted",
+				AccountId:      "123456789012",
+				ConfigRuleInvokingEvent: types.ConfigRuleInvokingEvent{
+					ConfigurationItem: types.ConfigurationItem{
+						ResourceType:            "AWS::Logs::LogGroup",
+						ResourceName:            "/aws/lambda/test-function",
+						AwsRegion:               "ca-central-1",
+						ConfigurationItemStatus: "ResourceNotRecorded",
+						Configuration: types.LogGroupConfiguration{
+							LogGroupName: "/aws/lambda/test-function",
+						},
+					},
+				},
+			},
+			expectError: false,
+			expectCall:  false,
+		},
 		{
 			name: "non-log-group resource should be skipped",
 			event: types.ConfigEvent{
@@ -134,15 +159,739 @@ func TestComplianceHandler_HandleConfigEvent(t *testing.T) {
 	}
 }
 
+func TestComplianceHandler_RuleParametersOverrideDefaults(t *testing.T) {
+	tests := []struct {
+		name                string
+		configRule          string
+		ruleParameters      map[string]string
+		retentionInDays     *int32
+		kmsKeyId            string
+		expectCall          bool
+		expectTargetDays    *int32
+		expectTargetKMSKey  string
+		expectMissingRetain bool
+	}{
+		{
+			name:                "rule retention overrides default when group has no retention",
+			configRule:          "logguardian-retention-dev",
+			ruleParameters:      map[string]string{"desiredRetentionInDays": "90"},
+			retentionInDays:     nil,
+			expectCall:          true,
+			expectTargetDays:    intPtr(90),
+			expectMissingRetain: true,
+		},
+		{
+			name:                "retention shorter than rule minimum is non-compliant",
+			configRule:          "cw-lg-retention-min",
+			ruleParameters:      map[string]string{"minRetentionDays": "365"},
+			retentionInDays:     intPtr(30),
+			expectCall:          true,
+			expectTargetDays:    intPtr(365),
+			expectMissingRetain: true,
+		},
+		{
+			name:            "retention meeting rule minimum is compliant",
+			configRule:      "cw-lg-retention-min",
+			ruleParameters:  map[string]string{"retentionPeriod": "30"},
+			retentionInDays: intPtr(90),
+			expectCall:      false,
+		},
+		{
+			name:                "invalid parameter falls back to default",
+			configRule:          "cw-lg-retention-min",
+			ruleParameters:      map[string]string{"desiredRetentionInDays": "forever"},
+			retentionInDays:     nil,
+			expectCall:          true,
+			expectMissingRetain: true,
+		},
+		{
+			name:               "encryption rule with required key flags a different key",
+			configRule:         "cloudwatch-log-group-encrypted",
+			ruleParameters:     map[string]string{"KmsKeyId": "arn:aws:kms:ca-central-1:123456789012:key/required"},
+			kmsKeyId:           "arn:aws:kms:ca-central-1:123456789012:key/other",
+			expectCall:         true,
+			expectTargetKMSKey: "arn:aws:kms:ca-central-1:123456789012:key/required",
+		},
+		{
+			name:           "required key alias leaves an encrypted group alone",
+			configRule:     "cloudwatch-log-group-encrypted",
+			ruleParameters: map[string]string{"KmsKeyId": "alias/team-logs"},
+			kmsKeyId:       "arn:aws:kms:ca-central-1:123456789012:key/current",
+			expectCall:     false,
+		},
+		{
+			name:               "required key alias encrypts an unencrypted group",
+			configRule:         "cloudwatch-log-group-encrypted",
+			ruleParameters:     map[string]string{"KmsKeyId": "alias/team-logs"},
+			expectCall:         true,
+			expectTargetKMSKey: "alias/team-logs",
+		},
+		{
+			name:                "retentionDays parameter overrides default",
+			configRule:          "cw-lg-retention-min",
+			ruleParameters:      map[string]string{"retentionDays": "731"},
+			retentionInDays:     nil,
+			expectCall:          true,
+			expectTargetDays:    intPtr(731),
+			expectMissingRetain: true,
+		},
+		{
+			name:                "retention not accepted by CloudWatch falls back to default",
+			configRule:          "cw-lg-retention-min",
+			ruleParameters:      map[string]string{"retentionDays": "100"},
+			retentionInDays:     nil,
+			expectCall:          true,
+			expectMissingRetain: true,
+		},
+		{
+			name:               "kmsKeyAlias parameter overrides default alias",
+			configRule:         "cloudwatch-log-group-encrypted",
+			ruleParameters:     map[string]string{"kmsKeyAlias": "alias/team-logs"},
+			expectCall:         true,
+			expectTargetKMSKey: "alias/team-logs",
+		},
+		{
+			name:           "AWS-managed alias parameter falls back to default",
+			configRule:     "cloudwatch-log-group-encrypted",
+			ruleParameters: map[string]string{"kmsKeyAlias": "alias/aws/logs"},
+			expectCall:     true,
+		},
+		{
+			name:           "kmsKeyAlias does not flag an already encrypted group",
+			configRule:     "cloudwatch-log-group-encrypted",
+			ruleParameters: map[string]string{"kmsKeyAlias": "alias/team-logs"},
+			kmsKeyId:       "arn:aws:kms:ca-central-1:123456789012:key/existing",
+			expectCall:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockComplianceService{}
+			handler := NewComplianceHandler(mockService)
+
+			event := types.ConfigEvent{
+				ConfigRuleName: tt.configRule,
+				RuleParameters: tt.ruleParameters,
+				ConfigRuleInvokingEvent: types.ConfigRuleInvokingEvent{
+					ConfigurationItem: types.ConfigurationItem{
+						ResourceType:            "AWS::Logs::LogGroup",
+						ResourceName:            "/aws/lambda/param-test",
+						AwsRegion:               "ca-central-1",
+						ConfigurationItemStatus: "OK",
+						Configuration: types.LogGroupConfiguration{
+							LogGroupName:    "/aws/lambda/param-test",
+							RetentionInDays: tt.retentionInDays,
+							KmsKeyId:        tt.kmsKeyId,
+						},
+					},
+				},
+			}
+
+			eventBytes, err := json.Marshal(event)
+			if err != nil {
+				t.Fatalf("Failed to marshal event: %v", err)
+			}
+
+			if err := handler.HandleConfigEvent(context.Background(), eventBytes); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if mockService.RemediateLogGroupCalled != tt.expectCall {
+				t.Fatalf("Expected RemediateLogGroup called=%v, got %v", tt.expectCall, mockService.RemediateLogGroupCalled)
+			}
+			if !tt.expectCall {
+				return
+			}
+
+			got := mockService.LastCompliance
+			if tt.expectTargetDays == nil && got.TargetRetentionDays != nil {
+				t.Errorf("Expected no target retention, got %d", *got.TargetRetentionDays)
+			}
+			if tt.expectTargetDays != nil && (got.TargetRetentionDays == nil || *got.TargetRetentionDays != *tt.expectTargetDays) {
+				t.Errorf("Expected target retention %d, got %v", *tt.expectTargetDays, got.TargetRetentionDays)
+			}
+			if got.MissingRetention != tt.expectMissingRetain {
+				t.Errorf("Expected MissingRetention=%v, got %v", tt.expectMissingRetain, got.MissingRetention)
+			}
+			if got.TargetKMSKeyId != tt.expectTargetKMSKey {
+				t.Errorf("Expected target KMS key %q, got %q", tt.expectTargetKMSKey, got.TargetKMSKeyId)
+			}
+		})
+	}
+}
+
+func TestComplianceHandler_NormalizesWhitespaceConfiguration(t *testing.T) {
+	tests := []struct {
+		name              string
+		configRule        string
+		kmsKeyId          string
+		retentionInDays   *int32
+		expectCall        bool
+		expectCurrentKMS  string
+		expectMissingKMS  bool
+		expectMissingDays bool
+	}{
+		{
+			name:             "empty key id is missing encryption",
+			configRule:       "cloudwatch-log-group-encrypted",
+			kmsKeyId:         "",
+			expectCall:       true,
+			expectMissingKMS: true,
+		},
+		{
+			name:             "whitespace key id is missing encryption",
+			configRule:       "cloudwatch-log-group-encrypted",
+			kmsKeyId:         "   ",
+			expectCall:       true,
+			expectMissingKMS: true,
+		},
+		{
+			name:       "valid key id is encrypted",
+			configRule: "cloudwatch-log-group-encrypted",
+			kmsKeyId:   "arn:aws:kms:ca-central-1:123456789012:key/abc",
+			expectCall: false,
+		},
+		{
+			name:       "valid key id with trailing whitespace is encrypted",
+			configRule: "cloudwatch-log-group-encrypted",
+			kmsKeyId:   "arn:aws:kms:ca-central-1:123456789012:key/abc \n",
+			expectCall: false,
+		},
+		{
+			name:              "zero retention is missing retention",
+			configRule:        "cw-lg-retention-min",
+			retentionInDays:   intPtr(0),
+			expectCall:        true,
+			expectMissingDays: true,
+		},
+		{
+			name:              "current key is propagated to remediation",
+			configRule:        "cw-lg-retention-min",
+			kmsKeyId:          "arn:aws:kms:ca-central-1:123456789012:key/abc",
+			expectCall:        true,
+			expectCurrentKMS:  "arn:aws:kms:ca-central-1:123456789012:key/abc",
+			expectMissingDays: true,
+		},
+		{
+			name:              "current key is propagated without surrounding whitespace",
+			configRule:        "cw-lg-retention-min",
+			kmsKeyId:          " arn:aws:kms:ca-central-1:123456789012:key/abc \n",
+			expectCall:        true,
+			expectCurrentKMS:  "arn:aws:kms:ca-central-1:123456789012:key/abc",
+			expectMissingDays: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockComplianceService{}
+			handler := NewComplianceHandler(mockService)
+
+			event := types.ConfigEvent{
+				ConfigRuleName: tt.configRule,
+				ConfigRuleInvokingEvent: types.ConfigRuleInvokingEvent{
+					ConfigurationItem: types.ConfigurationItem{
+						ResourceType:            "AWS::Logs::LogGroup",
+						ResourceName:            "/aws/lambda/normalize-test",
+						AwsRegion:               "ca-central-1",
+						ConfigurationItemStatus: "OK",
+						Configuration: types.LogGroupConfiguration{
+							LogGroupName:    "/aws/lambda/normalize-test",
+							RetentionInDays: tt.retentionInDays,
+							KmsKeyId:        tt.kmsKeyId,
+						},
+					},
+				},
+			}
+
+			eventBytes, err := json.Marshal(event)
+			if err != nil {
+				t.Fatalf("Failed to marshal event: %v", err)
+			}
+
+			if err := handler.HandleConfigEvent(context.Background(), eventBytes); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if mockService.RemediateLogGroupCalled != tt.expectCall {
+				t.Fatalf("Expected RemediateLogGroup called=%v, got %v", tt.expectCall, mockService.RemediateLogGroupCalled)
+			}
+			if !tt.expectCall {
+				return
+			}
+
+			got := mockService.LastCompliance
+			if got.MissingEncryption != tt.expectMissingKMS {
+				t.Errorf("Expected MissingEncryption=%v, got %v", tt.expectMissingKMS, got.MissingEncryption)
+			}
+			if got.MissingRetention != tt.expectMissingDays {
+				t.Errorf("Expected MissingRetention=%v, got %v", tt.expectMissingDays, got.MissingRetention)
+			}
+			if got.CurrentKmsKeyId != tt.expectCurrentKMS {
+				t.Errorf("Expected CurrentKmsKeyId %q, got %q", tt.expectCurrentKMS, got.CurrentKmsKeyId)
+			}
+			if got.CurrentRetention != nil {
+				t.Errorf("Expected no current retention, got %d", *got.CurrentRetention)
+			}
+		})
+	}
+}
+
+func TestComplianceHandler_HandleConfigEvent_ReportsEvaluation(t *testing.T) {
+	event := types.ConfigEvent{
+		ConfigRuleName: "cloudwatch-log-group-encrypted",
+		ResultToken:    "token-123",
+		ConfigRuleInvokingEvent: types.ConfigRuleInvokingEvent{
+			ConfigurationItem: types.ConfigurationItem{
+				ResourceType:            "AWS::Logs::LogGroup",
+				ResourceId:              "/aws/lambda/test-function",
+				ResourceName:            "/aws/lambda/test-function",
+				AwsRegion:               "ca-central-1",
+				ConfigurationItemStatus: "OK",
+				Configuration: types.LogGroupConfiguration{
+					LogGroupName: "/aws/lambda/test-function",
+				},
+			},
+		},
+	}
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		t.Fatalf("Failed to marshal event: %v", err)
+	}
+
+	mockService := &MockComplianceService{}
+	handler := NewComplianceHandler(mockService)
+
+	if err := handler.HandleConfigEvent(context.Background(), eventJSON); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if mockService.ReportedResultToken != "token-123" {
+		t.Errorf("Expected result token token-123 to be reported, got %q", mockService.ReportedResultToken)
+	}
+	if mockService.ReportedResult == nil || !mockService.ReportedResult.EncryptionApplied {
+		t.Errorf("Expected remediation result to be reported, got %+v", mockService.ReportedResult)
+	}
+}
+
+func TestComplianceHandler_HandleConfigEvent_NilRuleParameters(t *testing.T) {
+	// Config sends ruleParameters as null, or leaves it out, for rules without parameters
+	events := map[string]string{
+		"null":    `{"configRuleName":"cloudwatch-log-group-retention","ruleParameters":null,"configRuleInvokingEvent":{"configurationItem":{"resourceType":"AWS::Logs::LogGroup","resourceName":"/aws/lambda/no-params","awsRegion":"ca-central-1","configurationItemStatus":"OK","configuration":{"logGroupName":"/aws/lambda/no-params"}}}}`,
+		"omitted": `{"configRuleName":"cloudwatch-log-group-retention","configRuleInvokingEvent":{"configurationItem":{"resourceType":"AWS::Logs::LogGroup","resourceName":"/aws/lambda/no-params","awsRegion":"ca-central-1","configurationItemStatus":"OK","configuration":{"logGroupName":"/aws/lambda/no-params"}}}}`,
+	}
+
+	for name, event := range events {
+		t.Run(name, func(t *testing.T) {
+			mockService := &MockComplianceService{}
+			handler := NewComplianceHandler(mockService)
+
+			if err := handler.HandleConfigEvent(context.Background(), json.RawMessage(event)); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if !mockService.RemediateLogGroupCalled {
+				t.Fatal("Expected the log group without retention to be remediated")
+			}
+			got := mockService.LastCompliance
+			if !got.MissingRetention || got.TargetRetentionDays != nil || got.TargetKMSKeyId != "" {
+				t.Errorf("Expected service defaults without rule parameters, got %+v", got)
+			}
+
+			var configEvent types.ConfigEvent
+			if err := json.Unmarshal([]byte(event), &configEvent); err != nil {
+				t.Fatalf("Failed to parse event: %v", err)
+			}
+			requests := handler.BuildBatchRequests([]types.ConfigEvent{configEvent}, configEvent.ConfigRuleName)
+			if len(requests) != 1 {
+				t.Fatalf("Expected one batch request, got %+v", requests)
+			}
+			request := requests[0]
+			if len(request.NonCompliantResults) != 1 || request.BatchSize != 0 || request.ResourceDelayMs != nil || request.TargetRetentionDays != nil {
+				t.Errorf("Expected one resource with default batch settings, got %+v", request)
+			}
+		})
+	}
+}
+
+func TestComplianceHandler_BuildBatchRequests(t *testing.T) {
+	newEvent := func(name, status, kmsKeyId string, retentionInDays *int32) types.ConfigEvent {
+		return types.ConfigEvent{
+			ConfigRuleInvokingEvent: types.ConfigRuleInvokingEvent{
+				ConfigurationItem: types.ConfigurationItem{
+					ResourceType:            "AWS::Logs::LogGroup",
+					ResourceId:              name,
+					ResourceName:            name,
+					AwsRegion:               "ca-central-1",
+					AwsAccountId:            "123456789012",
+					ConfigurationItemStatus: status,
+					Configuration: types.LogGroupConfiguration{
+						LogGroupName:    name,
+						KmsKeyId:        kmsKeyId,
+						RetentionInDays: retentionInDays,
+					},
+				},
+			},
+		}
+	}
+
+	events := []types.ConfigEvent{
+		newEvent("/aws/lambda/unencrypted", "OK", "", intPtr(30)),
+		newEvent("/aws/lambda/no-retention", "OK", "arn:aws:kms:ca-central-1:123456789012:key/abc", nil),
+		newEvent("/aws/lambda/neither", "OK", "", nil),
+		newEvent("/aws/lambda/deleted", "ResourceDeleted", "", nil),
+		newEvent("/aws/lambda/not-recorded", "ResourceNotRecorded", "", nil),
+	}
+
+	tests := []struct {
+		name          string
+		configRule    string
+		expectedNames []string
+		expectedNote  string
+	}{
+		{
+			name:          "encryption rule keeps unencrypted groups",
+			configRule:    "cloudwatch-log-group-encrypted",
+			expectedNames: []string{"/aws/lambda/unencrypted", "/aws/lambda/neither"},
+			expectedNote:  "Missing encryption",
+		},
+		{
+			name:          "retention rule keeps groups without retention",
+			configRule:    "cloudwatch-log-group-retention",
+			expectedNames: []string{"/aws/lambda/no-retention", "/aws/lambda/neither"},
+			expectedNote:  "Missing retention",
+		},
+		{
+			name:       "unknown rule keeps nothing",
+			configRule: "some-other-rule",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewComplianceHandler(&MockComplianceService{})
+
+			requests := handler.BuildBatchRequests(events, tt.configRule)
+
+			if len(tt.expectedNames) == 0 {
+				if len(requests) != 0 {
+					t.Errorf("Expected no batch requests, got %+v", requests)
+				}
+				return
+			}
+			if len(requests) != 1 {
+				t.Fatalf("Expected one batch request, got %d", len(requests))
+			}
+			request := requests[0]
+			if request.ConfigRuleName != tt.configRule {
+				t.Errorf("Expected config rule %s, got %s", tt.configRule, request.ConfigRuleName)
+			}
+			if len(request.NonCompliantResults) != len(tt.expectedNames) {
+				t.Fatalf("Expected %d resources, got %d", len(tt.expectedNames), len(request.NonCompliantResults))
+			}
+			for i, resource := range request.NonCompliantResults {
+				if resource.ResourceName != tt.expectedNames[i] {
+					t.Errorf("Expected resource %s, got %s", tt.expectedNames[i], resource.ResourceName)
+				}
+				if resource.Annotation != tt.expectedNote {
+					t.Errorf("Expected annotation %q, got %q", tt.expectedNote, resource.Annotation)
+				}
+				if resource.ComplianceType != "NON_COMPLIANT" || resource.Region != "ca-central-1" {
+					t.Errorf("Unexpected resource fields: %+v", resource)
+				}
+			}
+			if request.Region != "ca-central-1" {
+				t.Errorf("Expected region ca-central-1, got %s", request.Region)
+			}
+		})
+	}
+}
+
+func TestComplianceHandler_BuildBatchRequests_RuleParameters(t *testing.T) {
+	tests := []struct {
+		name                  string
+		ruleParameters        map[string]string
+		expectedBatchSize     int
+		expectedResourceDelay *int
+		expectedGroupDelay    *int
+	}{
+		{
+			name:           "no parameters keeps service defaults",
+			ruleParameters: nil,
+		},
+		{
+			name: "rule supplies its own batch size and delays",
+			ruleParameters: map[string]string{
+				"batchSize":       "25",
+				"resourceDelayMs": "0",
+				"groupDelayMs":    "500",
+			},
+			expectedBatchSize:     25,
+			expectedResourceDelay: func() *int { v := 0; return &v }(),
+			expectedGroupDelay:    func() *int { v := 500; return &v }(),
+		},
+		{
+			name: "out of range values are rejected",
+			ruleParameters: map[string]string{
+				"batchSize":       "0",
+				"resourceDelayMs": "-5",
+				"groupDelayMs":    "soon",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewComplianceHandler(&MockComplianceService{})
+			events := []types.ConfigEvent{{
+				RuleParameters: tt.ruleParameters,
+				ConfigRuleInvokingEvent: types.ConfigRuleInvokingEvent{
+					ConfigurationItem: types.ConfigurationItem{
+						ResourceType:            "AWS::Logs::LogGroup",
+						ResourceName:            "/aws/lambda/tuned",
+						AwsRegion:               "ca-central-1",
+						ConfigurationItemStatus: "OK",
+						Configuration: types.LogGroupConfiguration{
+							LogGroupName: "/aws/lambda/tuned",
+						},
+					},
+				},
+			}}
+
+			requests := handler.BuildBatchRequests(events, "cloudwatch-log-group-encrypted")
+			if len(requests) != 1 {
+				t.Fatalf("Expected one batch request, got %d", len(requests))
+			}
+			request := requests[0]
+
+			if request.BatchSize != tt.expectedBatchSize {
+				t.Errorf("Expected batch size %d, got %d", tt.expectedBatchSize, request.BatchSize)
+			}
+			if !equalIntPtr(request.ResourceDelayMs, tt.expectedResourceDelay) {
+				t.Errorf("Expected resource delay %v, got %v", tt.expectedResourceDelay, request.ResourceDelayMs)
+			}
+			if !equalIntPtr(request.GroupDelayMs, tt.expectedGroupDelay) {
+				t.Errorf("Expected group delay %v, got %v", tt.expectedGroupDelay, request.GroupDelayMs)
+			}
+		})
+	}
+}
+
+func TestComplianceHandler_BuildBatchRequests_RuleTargets(t *testing.T) {
+	newEvent := func(name string, ruleParameters map[string]string) types.ConfigEvent {
+		return types.ConfigEvent{
+			RuleParameters: ruleParameters,
+			ConfigRuleInvokingEvent: types.ConfigRuleInvokingEvent{
+				ConfigurationItem: types.ConfigurationItem{
+					ResourceType:            "AWS::Logs::LogGroup",
+					ResourceName:            name,
+					AwsRegion:               "ca-central-1",
+					ConfigurationItemStatus: "OK",
+					Configuration: types.LogGroupConfiguration{
+						LogGroupName:    name,
+						RetentionInDays: intPtr(30),
+					},
+				},
+			},
+		}
+	}
+
+	handler := NewComplianceHandler(&MockComplianceService{})
+	requests := handler.BuildBatchRequests([]types.ConfigEvent{
+		newEvent("/aws/lambda/long-a", map[string]string{"desiredRetentionInDays": "731"}),
+		newEvent("/aws/lambda/default", nil),
+		newEvent("/aws/lambda/long-b", map[string]string{"desiredRetentionInDays": "731"}),
+		newEvent("/aws/lambda/short", map[string]string{"desiredRetentionInDays": "90", "batchSize": "5"}),
+	}, "cloudwatch-log-group-retention")
+
+	// Without a rule minimum any retention is compliant, so the default group is left out
+	if len(requests) != 2 {
+		t.Fatalf("Expected a batch request per distinct rule target, got %+v", requests)
+	}
+	if requests[0].TargetRetentionDays == nil || *requests[0].TargetRetentionDays != 731 {
+		t.Errorf("Expected the first request to target 731 days, got %v", requests[0].TargetRetentionDays)
+	}
+	if len(requests[0].NonCompliantResults) != 2 ||
+		requests[0].NonCompliantResults[0].ResourceName != "/aws/lambda/long-a" ||
+		requests[0].NonCompliantResults[1].ResourceName != "/aws/lambda/long-b" {
+		t.Errorf("Expected both 731-day groups in the first request, got %+v", requests[0].NonCompliantResults)
+	}
+	if requests[1].TargetRetentionDays == nil || *requests[1].TargetRetentionDays != 90 || requests[1].BatchSize != 5 {
+		t.Errorf("Expected the second request to keep its own target and batch size, got %+v", requests[1])
+	}
+}
+
+func equalIntPtr(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func TestComplianceHandler_HandleConfigRuleEvaluationRequest_LogsExecutionSummary(t *testing.T) {
+	logger, logs := testutil.CaptureLogs(t)
+	previous := slog.Default()
+	slog.SetDefault(logger)
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	request := testutil.NewTestBatchComplianceRequest(4)
+	mockService := &MockComplianceService{
+		NonCompliantResources: request.NonCompliantResults,
+		BatchResult: &types.BatchRemediationResult{
+			TotalProcessed: 4,
+			SuccessCount:   3,
+			NoActionCount:  1,
+			FailureCount:   1,
+		},
+	}
+	handler := NewComplianceHandler(mockService)
+
+	err := handler.HandleConfigRuleEvaluationRequest(context.Background(), request.ConfigRuleName, request.Region, request.BatchSize)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	summaries := logs.WithAttr("audit_action", "execution_summary")
+	if len(summaries) != 1 {
+		t.Fatalf("Expected exactly one execution summary, got %d", len(summaries))
+	}
+
+	attrs := summaries[0].Attrs
+	expected := map[string]any{
+		"request_type": "config-rule-evaluation",
+		"config_rule":  request.ConfigRuleName,
+		"region":       request.Region,
+		"total":        float64(4),
+		"success":      float64(2),
+		"failure":      float64(1),
+		"skipped":      float64(1),
+		"status":       "success",
+	}
+	for key, want := range expected {
+		if attrs[key] != want {
+			t.Errorf("Expected %s=%v, got %v", key, want, attrs[key])
+		}
+	}
+	if _, ok := attrs["duration_ms"]; !ok {
+		t.Error("Expected duration_ms in execution summary")
+	}
+}
+
+func TestComplianceHandler_HandleConfigRuleEvaluationRequestWithReport(t *testing.T) {
+	request := testutil.NewTestBatchComplianceRequest(5)
+	mockService := &MockComplianceService{
+		NonCompliantResources: request.NonCompliantResults,
+		BatchResult: &types.BatchRemediationResult{
+			TotalProcessed:   5,
+			SuccessCount:     3,
+			NoActionCount:    1,
+			FailureCount:     2,
+			Cancelled:        true,
+			AbortedOnFailure: true,
+			Deferred:         []string{"/aws/lambda/test-4"},
+			Results: []types.RemediationResult{
+				{LogGroupName: "/aws/lambda/test-0", Region: "ca-central-1", EncryptionApplied: true, Success: true, Duration: 1500 * time.Millisecond},
+				{LogGroupName: "/aws/lambda/test-1", Region: "ca-central-1", NoActionNeeded: true, Success: true},
+				{LogGroupName: "/aws/lambda/test-2", Region: "ca-central-1", Success: true, SkipReason: "retention reduction not allowed"},
+				{LogGroupName: "/aws/lambda/test-3", Region: "ca-central-1", Error: errors.New("AccessDeniedException")},
+			},
+		},
+	}
+	handler := NewComplianceHandler(mockService)
+
+	report, err := handler.HandleConfigRuleEvaluationRequestWithReport(context.Background(), request.ConfigRuleName, request.Region, request.BatchSize)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := types.RunReport{
+		RequestType:      "config-rule-evaluation",
+		ConfigRuleName:   request.ConfigRuleName,
+		Region:           request.Region,
+		Total:            5,
+		Success:          2,
+		Failure:          2,
+		Skipped:          1,
+		Deferred:         1,
+		Cancelled:        true,
+		AbortedOnFailure: true,
+		Status:           "success",
+		DurationMs:       report.DurationMs,
+		Resources: []types.ResourceOutcome{
+			{LogGroupName: "/aws/lambda/test-0", Region: "ca-central-1", Status: "success", EncryptionApplied: true, DurationMs: 1500},
+			{LogGroupName: "/aws/lambda/test-1", Region: "ca-central-1", Status: "no-action"},
+			{LogGroupName: "/aws/lambda/test-2", Region: "ca-central-1", Status: "skipped", SkipReason: "retention reduction not allowed"},
+			{LogGroupName: "/aws/lambda/test-3", Region: "ca-central-1", Status: "failed", Error: "AccessDeniedException"},
+		},
+	}
+	if !reflect.DeepEqual(report, expected) {
+		t.Errorf("Expected report %+v, got %+v", expected, report)
+	}
+}
+
+func TestComplianceHandler_HandleConfigEventWithReport(t *testing.T) {
+	handler := NewComplianceHandler(&MockComplianceService{})
+	event := types.ConfigEvent{
+		ConfigRuleName: "cloudwatch-log-group-encrypted",
+		ConfigRuleInvokingEvent: types.ConfigRuleInvokingEvent{
+			ConfigurationItem: types.ConfigurationItem{
+				ResourceType:            "AWS::Logs::LogGroup",
+				ResourceName:            "/aws/lambda/report-test",
+				AwsRegion:               "ca-central-1",
+				ConfigurationItemStatus: "ResourceDiscovered",
+				Configuration: types.LogGroupConfiguration{
+					LogGroupName: "/aws/lambda/report-test",
+				},
+			},
+		},
+	}
+	eventBytes, err := json.Marshal(event)
+	if err != nil {
+		t.Fatalf("Failed to marshal event: %v", err)
+	}
+
+	report, err := handler.HandleConfigEventWithReport(context.Background(), eventBytes)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if report.RequestType != "config-event" || report.Total != 1 || report.Success != 1 || report.Status != "success" {
+		t.Errorf("Unexpected report for remediated event: %+v", report)
+	}
+	expectedResources := []types.ResourceOutcome{
+		{LogGroupName: "/aws/lambda/report-test", Region: "ca-central-1", Status: "success", EncryptionApplied: true},
+	}
+	if !reflect.DeepEqual(report.Resources, expectedResources) {
+		t.Errorf("Expected resources %+v, got %+v", expectedResources, report.Resources)
+	}
+
+	report, err = handler.HandleConfigEventWithReport(context.Background(), json.RawMessage(`{invalid`))
+	if err == nil {
+		t.Fatal("Expected error for malformed event")
+	}
+	if report.Status != "failed" {
+		t.Errorf("Expected failed status, got %q", report.Status)
+	}
+}
+
 // MockComplianceService provides a mock implementation for testing
 type MockComplianceService struct {
 	RemediateLogGroupCalled bool
+	RemediateLogGroupCalls  int
 	RemediateLogGroupError  error
 	RemediateLogGroupResult *types.RemediationResult
+	LastCompliance          types.ComplianceResult
+	NonCompliantResources   []types.NonCompliantResource
+	BatchResult             *types.BatchRemediationResult
+	ReportedResultToken     string
+	ReportedResult          *types.RemediationResult
 }
 
 func (m *MockComplianceService) RemediateLogGroup(ctx context.Context, compliance types.ComplianceResult) (*types.RemediationResult, error) {
 	m.RemediateLogGroupCalled = true
+	m.RemediateLogGroupCalls++
+	m.LastCompliance = compliance
 
 	if m.RemediateLogGroupError != nil {
 		return nil, m.RemediateLogGroupError
@@ -163,16 +912,36 @@ func (m *MockComplianceService) RemediateLogGroup(ctx context.Context, complianc
 }
 
 func (m *MockComplianceService) GetNonCompliantResources(ctx context.Context, configRuleName string, region string) ([]types.NonCompliantResource, error) {
+	if m.NonCompliantResources != nil {
+		return m.NonCompliantResources, nil
+	}
+
 	// Mock implementation - return empty list for testing
 	return []types.NonCompliantResource{}, nil
 }
 
+func (m *MockComplianceService) GetCompliantResources(ctx context.Context, configRuleName string, region string) ([]types.NonCompliantResource, error) {
+	return []types.NonCompliantResource{}, nil
+}
+
 func (m *MockComplianceService) ValidateResourceExistence(ctx context.Context, resources []types.NonCompliantResource) ([]types.NonCompliantResource, error) {
 	// Mock implementation - return all resources as valid
 	return resources, nil
 }
 
+func (m *MockComplianceService) RemediateSingleLogGroup(ctx context.Context, region, logGroupName string, ruleType types.RuleType) (*types.RemediationResult, error) {
+	compliance, err := m.EvaluateCompliance(ctx, logGroupName, region)
+	if err != nil {
+		return nil, err
+	}
+	return m.RemediateLogGroup(ctx, service.ScopeComplianceToRule(compliance, ruleType))
+}
+
 func (m *MockComplianceService) ProcessNonCompliantResourcesOptimized(ctx context.Context, request types.BatchComplianceRequest) (*types.BatchRemediationResult, error) {
+	if m.BatchResult != nil {
+		return m.BatchResult, nil
+	}
+
 	// Mock implementation for optimized batch processing
 	return &types.BatchRemediationResult{
 		TotalProcessed: len(request.NonCompliantResults),
@@ -182,6 +951,17 @@ func (m *MockComplianceService) ProcessNonCompliantResourcesOptimized(ctx contex
 	}, nil
 }
 
+func (m *MockComplianceService) EvaluateCompliance(ctx context.Context, logGroupName, region string) (types.ComplianceResult, error) {
+	// Mock implementation - report the log group as fully compliant
+	return types.ComplianceResult{LogGroupName: logGroupName, Region: region}, nil
+}
+
+func (m *MockComplianceService) ReportEvaluation(ctx context.Context, resultToken string, configItem types.ConfigurationItem, result *types.RemediationResult) error {
+	m.ReportedResultToken = resultToken
+	m.ReportedResult = result
+	return nil
+}
+
 // Helper function to create int32 pointer
 func intPtr(i int32) *int32 {
 	return &i
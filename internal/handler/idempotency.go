@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/zsoftly/logguardian/internal/types"
+)
+
+// DefaultIdempotencyTTL is how long a handled event is remembered; redeliveries arrive well within it
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+// IdempotencyClaimTTL is how long a claimed event still being handled blocks redeliveries. It
+// matches the 15-minute Lambda timeout ceiling, so the claim of a crashed invocation lapses.
+const IdempotencyClaimTTL = 15 * time.Minute
+
+// IdempotencyStore remembers which Config events have already been handled, so an event delivered
+// more than once is remediated only once. Claim checks and records a key in one atomic step, so
+// concurrent deliveries of the same event cannot both proceed; store failures let the caller claim.
+type IdempotencyStore interface {
+	// Claim reports whether the caller may handle key, recording it as in progress until Mark or
+	// Release; false means another delivery has claimed or handled it
+	Claim(ctx context.Context, key string) bool
+	// Mark records a claimed key as handled until the TTL elapses
+	Mark(ctx context.Context, key string)
+	// Release drops a claim whose event failed, so a redelivery is handled again
+	Release(ctx context.Context, key string)
+}
+
+// idempotencyKey identifies an event by account, region, resource, remediation action and the
+// configuration state it was evaluated against. Events without a state hash return an empty key
+// and are never treated as duplicates, since a later state change could not be told apart.
+func idempotencyKey(configEvent types.ConfigEvent, ruleType types.RuleType) string {
+	configItem := configEvent.ConfigRuleInvokingEvent.ConfigurationItem
+	if configItem.ConfigurationStateMd5Hash == "" {
+		return ""
+	}
+	return strings.Join([]string{
+		configEvent.AccountId,
+		configItem.AwsRegion,
+		configItem.ResourceName,
+		ruleType.String(),
+		configItem.ConfigurationStateMd5Hash,
+	}, "|")
+}
+
+// MemoryIdempotencyStore keeps handled events in memory for the life of the process, which
+// catches redeliveries to the same warm Lambda environment
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	expires map[string]time.Time
+}
+
+// NewMemoryIdempotencyStore creates an in-memory store that forgets events after ttl
+func NewMemoryIdempotencyStore(ttl time.Duration) *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		ttl:     ttl,
+		expires: make(map[string]time.Time),
+	}
+}
+
+// Claim records key as in progress unless it holds an unexpired claim or mark
+func (s *MemoryIdempotencyStore) Claim(ctx context.Context, key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if expiresAt, ok := s.expires[key]; ok && now.Before(expiresAt) {
+		return false
+	}
+	s.pruneLocked(now)
+	s.expires[key] = now.Add(IdempotencyClaimTTL)
+	return true
+}
+
+// Mark records key as handled, dropping expired keys so the store does not grow without bound
+func (s *MemoryIdempotencyStore) Mark(ctx context.Context, key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.pruneLocked(now)
+	s.expires[key] = now.Add(s.ttl)
+}
+
+// Release forgets key
+func (s *MemoryIdempotencyStore) Release(ctx context.Context, key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.expires, key)
+}
+
+// pruneLocked drops keys expired at now; the caller must hold s.mu
+func (s *MemoryIdempotencyStore) pruneLocked(now time.Time) {
+	for k, expiresAt := range s.expires {
+		if !now.Before(expiresAt) {
+			delete(s.expires, k)
+		}
+	}
+}
+
+// DynamoDBClientInterface defines the DynamoDB operations used by the idempotency store
+type DynamoDBClientInterface interface {
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+}
+
+// DynamoDBIdempotencyStore shares handled events across Lambda environments through a DynamoDB
+// table keyed by the string attribute idempotencyKey. Items carry an expiresAt epoch-seconds
+// attribute, which the table's TTL setting should use to delete them. Claims are conditional
+// writes, so concurrent Lambda environments cannot both claim the same event.
+type DynamoDBIdempotencyStore struct {
+	client    DynamoDBClientInterface
+	tableName string
+	ttl       time.Duration
+}
+
+// NewDynamoDBIdempotencyStore creates a store backed by tableName that forgets events after ttl
+func NewDynamoDBIdempotencyStore(client DynamoDBClientInterface, tableName string, ttl time.Duration) *DynamoDBIdempotencyStore {
+	return &DynamoDBIdempotencyStore{
+		client:    client,
+		tableName: tableName,
+		ttl:       ttl,
+	}
+}
+
+// Claim writes an in-progress item for key unless an unexpired item exists; TTL deletion lags
+// expiry, so the condition checks expiresAt rather than the item's presence alone
+func (s *DynamoDBIdempotencyStore) Claim(ctx context.Context, key string) bool {
+	now := time.Now()
+	_, err := s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(s.tableName),
+		Item:                idempotencyItem(key, now.Add(IdempotencyClaimTTL)),
+		ConditionExpression: aws.String("attribute_not_exists(idempotencyKey) OR expiresAt <= :now"),
+		ExpressionAttributeValues: map[string]dynamodbtypes.AttributeValue{
+			":now": &dynamodbtypes.AttributeValueMemberN{Value: strconv.FormatInt(now.Unix(), 10)},
+		},
+	})
+	if err != nil {
+		var conditionErr *dynamodbtypes.ConditionalCheckFailedException
+		if errors.As(err, &conditionErr) {
+			return false
+		}
+		slog.Warn("Failed to claim idempotency key, handling event",
+			"idempotency_key", key,
+			"table", s.tableName,
+			"error", err)
+	}
+	return true
+}
+
+// Mark records key as handled until the TTL elapses
+func (s *DynamoDBIdempotencyStore) Mark(ctx context.Context, key string) {
+	_, err := s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.tableName),
+		Item:      idempotencyItem(key, time.Now().Add(s.ttl)),
+	})
+	if err != nil {
+		slog.Warn("Failed to record idempotency key",
+			"idempotency_key", key,
+			"table", s.tableName,
+			"error", err)
+	}
+}
+
+// Release deletes the item for key
+func (s *DynamoDBIdempotencyStore) Release(ctx context.Context, key string) {
+	_, err := s.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(s.tableName),
+		Key:       map[string]dynamodbtypes.AttributeValue{"idempotencyKey": &dynamodbtypes.AttributeValueMemberS{Value: key}},
+	})
+	if err != nil {
+		slog.Warn("Failed to release idempotency key",
+			"idempotency_key", key,
+			"table", s.tableName,
+			"error", err)
+	}
+}
+
+// idempotencyItem is the table item for key, expiring at expiresAt
+func idempotencyItem(key string, expiresAt time.Time) map[string]dynamodbtypes.AttributeValue {
+	return map[string]dynamodbtypes.AttributeValue{
+		"idempotencyKey": &dynamodbtypes.AttributeValueMemberS{Value: key},
+		"expiresAt":      &dynamodbtypes.AttributeValueMemberN{Value: strconv.FormatInt(expiresAt.Unix(), 10)},
+	}
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zsoftly/logguardian/internal/types"
+)
+
+func newIdempotencyTestEvent(stateHash string) types.ConfigEvent {
+	return types.ConfigEvent{
+		ConfigRuleName: "cloudwatch-log-group-encrypted",
+		AccountId:      "123456789012",
+		ConfigRuleInvokingEvent: types.ConfigRuleInvokingEvent{
+			ConfigurationItem: types.ConfigurationItem{
+				ResourceType:              "AWS::Logs::LogGroup",
+				ResourceName:              "/aws/lambda/test-function",
+				AwsRegion:                 "ca-central-1",
+				AwsAccountId:              "123456789012",
+				ConfigurationItemStatus:   "OK",
+				ConfigurationStateMd5Hash: stateHash,
+				Configuration: types.LogGroupConfiguration{
+					LogGroupName: "/aws/lambda/test-function",
+				},
+			},
+		},
+	}
+}
+
+func TestComplianceHandler_HandleConfigEvent_SkipsDuplicateEvent(t *testing.T) {
+	mockService := &MockComplianceService{}
+	handler := NewComplianceHandler(mockService)
+
+	event, err := json.Marshal(newIdempotencyTestEvent("abc123"))
+	require.NoError(t, err)
+
+	report, err := handler.HandleConfigEventWithReport(context.Background(), event)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Success)
+
+	// The redelivered event is skipped without remediating again
+	report, err = handler.HandleConfigEventWithReport(context.Background(), event)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Equal(t, 1, mockService.RemediateLogGroupCalls)
+
+	// A new configuration state of the same log group is handled
+	changed, err := json.Marshal(newIdempotencyTestEvent("def456"))
+	require.NoError(t, err)
+	_, err = handler.HandleConfigEventWithReport(context.Background(), changed)
+	require.NoError(t, err)
+	assert.Equal(t, 2, mockService.RemediateLogGroupCalls)
+}
+
+func TestComplianceHandler_HandleConfigEvent_IdempotencyEdgeCases(t *testing.T) {
+	t.Run("failed remediation is retried", func(t *testing.T) {
+		mockService := &MockComplianceService{RemediateLogGroupError: errors.New("throttled")}
+		handler := NewComplianceHandler(mockService)
+
+		event, err := json.Marshal(newIdempotencyTestEvent("abc123"))
+		require.NoError(t, err)
+
+		assert.Error(t, handler.HandleConfigEvent(context.Background(), event))
+		assert.Error(t, handler.HandleConfigEvent(context.Background(), event))
+		assert.Equal(t, 2, mockService.RemediateLogGroupCalls)
+	})
+
+	t.Run("events without a state hash are never duplicates", func(t *testing.T) {
+		mockService := &MockComplianceService{}
+		handler := NewComplianceHandler(mockService)
+
+		event, err := json.Marshal(newIdempotencyTestEvent(""))
+		require.NoError(t, err)
+
+		require.NoError(t, handler.HandleConfigEvent(context.Background(), event))
+		require.NoError(t, handler.HandleConfigEvent(context.Background(), event))
+		assert.Equal(t, 2, mockService.RemediateLogGroupCalls)
+	})
+}
+
+func TestMemoryIdempotencyStore_Expiry(t *testing.T) {
+	store := NewMemoryIdempotencyStore(time.Millisecond)
+	ctx := context.Background()
+
+	require.True(t, store.Claim(ctx, "key"))
+	store.Mark(ctx, "key")
+	time.Sleep(5 * time.Millisecond)
+
+	store.Mark(ctx, "other")
+	assert.NotContains(t, store.expires, "key", "expired keys are pruned on Mark")
+	assert.True(t, store.Claim(ctx, "key"))
+}
+
+func TestMemoryIdempotencyStore_Claim(t *testing.T) {
+	store := NewMemoryIdempotencyStore(time.Hour)
+	ctx := context.Background()
+
+	// Concurrent deliveries of one event claim it exactly once
+	var claimed atomic.Int32
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if store.Claim(ctx, "key") {
+				claimed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), claimed.Load())
+
+	store.Mark(ctx, "key")
+	assert.False(t, store.Claim(ctx, "key"))
+
+	// A released claim can be claimed again
+	require.True(t, store.Claim(ctx, "failed"))
+	store.Release(ctx, "failed")
+	assert.True(t, store.Claim(ctx, "failed"))
+}
+
+// fakeDynamoDBClient stores items in memory, keyed by idempotencyKey, and evaluates the store's
+// claim condition
+type fakeDynamoDBClient struct {
+	items  map[string]map[string]dynamodbtypes.AttributeValue
+	putErr error
+}
+
+func (f *fakeDynamoDBClient) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	if f.putErr != nil {
+		return nil, f.putErr
+	}
+	key := params.Item["idempotencyKey"].(*dynamodbtypes.AttributeValueMemberS).Value
+	if existing, ok := f.items[key]; ok && params.ConditionExpression != nil {
+		now, _ := strconv.ParseInt(params.ExpressionAttributeValues[":now"].(*dynamodbtypes.AttributeValueMemberN).Value, 10, 64)
+		expiresAt, _ := strconv.ParseInt(existing["expiresAt"].(*dynamodbtypes.AttributeValueMemberN).Value, 10, 64)
+		if expiresAt > now {
+			return nil, &dynamodbtypes.ConditionalCheckFailedException{Message: aws.String("The conditional request failed")}
+		}
+	}
+	f.items[key] = params.Item
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (f *fakeDynamoDBClient) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	delete(f.items, params.Key["idempotencyKey"].(*dynamodbtypes.AttributeValueMemberS).Value)
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+func TestDynamoDBIdempotencyStore(t *testing.T) {
+	ctx := context.Background()
+	client := &fakeDynamoDBClient{items: map[string]map[string]dynamodbtypes.AttributeValue{}}
+	store := NewDynamoDBIdempotencyStore(client, "logguardian-idempotency", time.Hour)
+
+	require.True(t, store.Claim(ctx, "key"))
+	assert.False(t, store.Claim(ctx, "key"), "a claimed key cannot be claimed again")
+	store.Mark(ctx, "key")
+	assert.False(t, store.Claim(ctx, "key"))
+
+	require.True(t, store.Claim(ctx, "failed"))
+	store.Release(ctx, "failed")
+	assert.True(t, store.Claim(ctx, "failed"))
+
+	// Expired items awaiting TTL deletion can be claimed
+	client.items["stale"] = map[string]dynamodbtypes.AttributeValue{
+		"idempotencyKey": &dynamodbtypes.AttributeValueMemberS{Value: "stale"},
+		"expiresAt":      &dynamodbtypes.AttributeValueMemberN{Value: "1"},
+	}
+	assert.True(t, store.Claim(ctx, "stale"))
+
+	// Write failures let the event be handled
+	client.putErr = errors.New("ProvisionedThroughputExceededException")
+	assert.True(t, store.Claim(ctx, "key"))
+}
@@ -0,0 +1,146 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DedupEnvVar names the environment variable that enables collapsing repeated audit logs
+const DedupEnvVar = "LOG_DEDUP_AUDIT"
+
+// DedupEnabledFromEnv reports whether LOG_DEDUP_AUDIT is set to true
+func DedupEnabledFromEnv() bool {
+	return strings.ToLower(os.Getenv(DedupEnvVar)) == "true"
+}
+
+// dedupState is shared by a DedupHandler and the handlers derived from it with WithAttrs and
+// WithGroup, so repeats are collapsed across every logger built on the same output
+type dedupState struct {
+	mu      sync.Mutex
+	key     string       // Level, message and attributes of the last audit record written
+	handler slog.Handler // Handler that wrote the last audit record
+	level   slog.Level
+	action  string
+	repeats int // Records identical to the last one that were dropped
+}
+
+// DedupHandler collapses consecutive audit records with the same level, message and attributes,
+// including audit_action, into the first record followed by a single "<audit_action> xN" summary.
+// Records that differ in any attribute, such as the log group, are all written, so large batches do not
+// repeat the same audit message thousands of times. The summary is written when a different record
+// arrives or on Flush. A disabled handler passes every record through unchanged.
+type DedupHandler struct {
+	next    slog.Handler
+	enabled bool
+	state   *dedupState
+}
+
+// NewDedupHandler wraps next, collapsing repeated audit records when enabled
+func NewDedupHandler(next slog.Handler, enabled bool) *DedupHandler {
+	return &DedupHandler{next: next, enabled: enabled, state: &dedupState{}}
+}
+
+// Enabled reports whether next handles records at level
+func (h *DedupHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle writes r unless it repeats the previous audit record
+func (h *DedupHandler) Handle(ctx context.Context, r slog.Record) error {
+	if !h.enabled {
+		return h.next.Handle(ctx, r)
+	}
+
+	action := auditAction(r)
+	key := ""
+	if action != "" {
+		key = fmt.Sprintf("%s|%s|%s", r.Level, r.Message, recordAttrs(r))
+	}
+
+	h.state.mu.Lock()
+	defer h.state.mu.Unlock()
+
+	if key != "" && key == h.state.key {
+		h.state.repeats++
+		return nil
+	}
+
+	flushErr := h.state.flush(ctx)
+	h.state.key = key
+	h.state.handler = h.next
+	h.state.level = r.Level
+	h.state.action = action
+
+	if err := h.next.Handle(ctx, r); err != nil {
+		return err
+	}
+	return flushErr
+}
+
+// WithAttrs returns a handler adding attrs to next that shares this handler's repeat tracking
+func (h *DedupHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &DedupHandler{next: h.next.WithAttrs(attrs), enabled: h.enabled, state: h.state}
+}
+
+// WithGroup returns a handler grouping next's attributes that shares this handler's repeat tracking
+func (h *DedupHandler) WithGroup(name string) slog.Handler {
+	return &DedupHandler{next: h.next.WithGroup(name), enabled: h.enabled, state: h.state}
+}
+
+// Flush writes the summary of any audit records still being collapsed. Like slog.Logger, it
+// ignores a failure to write the summary.
+func (h *DedupHandler) Flush(ctx context.Context) {
+	h.state.mu.Lock()
+	defer h.state.mu.Unlock()
+
+	_ = h.state.flush(ctx)
+	h.state.key = ""
+}
+
+// flush writes the summary of the dropped repeats, if any; the caller holds mu
+func (s *dedupState) flush(ctx context.Context) error {
+	if s.repeats == 0 {
+		return nil
+	}
+
+	total := s.repeats + 1
+	s.repeats = 0
+
+	summary := slog.NewRecord(time.Now(), s.level, fmt.Sprintf("%s x%d", s.action, total), 0)
+	summary.AddAttrs(
+		slog.String("collapsed_audit_action", s.action),
+		slog.Int("repeat_count", total),
+	)
+	return s.handler.Handle(ctx, summary)
+}
+
+// recordAttrs returns the record's attributes as sorted key=value pairs, so records are only
+// identical when every attribute matches regardless of the order they were added in
+func recordAttrs(r slog.Record) string {
+	attrs := make([]string, 0, r.NumAttrs())
+	r.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, attr.Key+"="+attr.Value.Resolve().String())
+		return true
+	})
+	sort.Strings(attrs)
+	return strings.Join(attrs, "|")
+}
+
+// auditAction returns the record's audit_action attribute, or "" when it has none
+func auditAction(r slog.Record) string {
+	action := ""
+	r.Attrs(func(attr slog.Attr) bool {
+		if attr.Key == "audit_action" {
+			action = attr.Value.String()
+			return false
+		}
+		return true
+	})
+	return action
+}
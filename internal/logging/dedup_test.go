@@ -0,0 +1,88 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zsoftly/logguardian/internal/testutil"
+)
+
+func TestDedupHandler_CollapsesRepeatedAuditRecords(t *testing.T) {
+	base, logs := testutil.CaptureLogs(t)
+	dedup := NewDedupHandler(base.Handler(), true)
+	logger := slog.New(dedup).With("tenant", "acme")
+
+	for i := 0; i < 50; i++ {
+		logger.Info("Initializing batch remediation context", "region", "ca-central-1", "audit_action", "batch_context_init")
+	}
+	logger.Info("Batch started")
+	logger.Info("Applied encryption", "audit_action", "encryption_success")
+	logger.Info("Applied encryption", "audit_action", "encryption_success")
+	dedup.Flush(context.Background())
+
+	records := logs.Records()
+	require.Len(t, records, 5)
+
+	assert.Equal(t, "Initializing batch remediation context", records[0].Message)
+	assert.Equal(t, "ca-central-1", records[0].Attrs["region"])
+
+	assert.Equal(t, "batch_context_init x50", records[1].Message)
+	assert.Equal(t, "batch_context_init", records[1].Attrs["collapsed_audit_action"])
+	assert.EqualValues(t, 50, records[1].Attrs["repeat_count"])
+	assert.Equal(t, "acme", records[1].Attrs["tenant"])
+
+	assert.Equal(t, "Batch started", records[2].Message)
+	assert.Equal(t, "Applied encryption", records[3].Message)
+	assert.Equal(t, "encryption_success x2", records[4].Message)
+}
+
+func TestDedupHandler_DifferentAttributesAreNotCollapsed(t *testing.T) {
+	base, logs := testutil.CaptureLogs(t)
+	dedup := NewDedupHandler(base.Handler(), true)
+	logger := slog.New(dedup)
+
+	logger.Info("Applied encryption", "log_group", "/aws/lambda/a", "audit_action", "encryption_success")
+	logger.Info("Applied encryption", "log_group", "/aws/lambda/b", "audit_action", "encryption_success")
+	logger.Info("Applied encryption", "audit_action", "encryption_success", "log_group", "/aws/lambda/b")
+	dedup.Flush(context.Background())
+
+	records := logs.Records()
+	require.Len(t, records, 3)
+	assert.Equal(t, "/aws/lambda/a", records[0].Attrs["log_group"])
+	assert.Equal(t, "/aws/lambda/b", records[1].Attrs["log_group"])
+	assert.Equal(t, "encryption_success x2", records[2].Message)
+}
+
+func TestDedupHandler_DisabledPassesThrough(t *testing.T) {
+	base, logs := testutil.CaptureLogs(t)
+	dedup := NewDedupHandler(base.Handler(), false)
+	logger := slog.New(dedup)
+
+	for i := 0; i < 3; i++ {
+		logger.Info("Initializing batch remediation context", "audit_action", "batch_context_init")
+	}
+	dedup.Flush(context.Background())
+
+	assert.Len(t, logs.Records(), 3)
+}
+
+func TestDedupHandler_DifferentLevelsAreNotCollapsed(t *testing.T) {
+	base, logs := testutil.CaptureLogs(t)
+	logger := slog.New(NewDedupHandler(base.Handler(), true))
+
+	logger.Info("Key validated", "audit_action", "key_validation")
+	logger.Warn("Key validated", "audit_action", "key_validation")
+
+	assert.Len(t, logs.Records(), 2)
+}
+
+func TestDedupEnabledFromEnv(t *testing.T) {
+	t.Setenv(DedupEnvVar, "")
+	assert.False(t, DedupEnabledFromEnv())
+
+	t.Setenv(DedupEnvVar, "TRUE")
+	assert.True(t, DedupEnabledFromEnv())
+}
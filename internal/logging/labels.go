@@ -0,0 +1,56 @@
+// Package logging provides helpers shared by the Lambda and container entrypoints for
+// configuring structured logging.
+package logging
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// LabelsEnvVar names the environment variable holding comma-separated key=value log labels
+const LabelsEnvVar = "LOG_LABELS"
+
+// ParseLabels converts "key=value,key2=value2" into slog attributes, in order.
+// Entries without a key or without '=' are ignored; later duplicates override earlier ones.
+func ParseLabels(value string) []slog.Attr {
+	var attrs []slog.Attr
+	index := make(map[string]int)
+
+	for _, pair := range strings.Split(value, ",") {
+		key, val, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			continue
+		}
+
+		attr := slog.String(key, strings.TrimSpace(val))
+		if i, seen := index[key]; seen {
+			attrs[i] = attr
+			continue
+		}
+		index[key] = len(attrs)
+		attrs = append(attrs, attr)
+	}
+
+	return attrs
+}
+
+// WithLabels returns a logger that stamps every record with the labels parsed from value
+func WithLabels(logger *slog.Logger, value string) *slog.Logger {
+	attrs := ParseLabels(value)
+	if len(attrs) == 0 {
+		return logger
+	}
+
+	args := make([]any, len(attrs))
+	for i, attr := range attrs {
+		args[i] = attr
+	}
+	return logger.With(args...)
+}
+
+// WithEnvLabels applies labels from LOG_LABELS to logger
+func WithEnvLabels(logger *slog.Logger) *slog.Logger {
+	return WithLabels(logger, os.Getenv(LabelsEnvVar))
+}
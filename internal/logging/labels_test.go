@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zsoftly/logguardian/internal/testutil"
+)
+
+func TestParseLabels(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected []slog.Attr
+	}{
+		{name: "empty", value: ""},
+		{
+			name:  "multiple pairs with whitespace",
+			value: "tenant=acme, environment = prod",
+			expected: []slog.Attr{
+				slog.String("tenant", "acme"),
+				slog.String("environment", "prod"),
+			},
+		},
+		{
+			name:     "malformed entries skipped",
+			value:    "tenant=acme,novalue,=orphan,,",
+			expected: []slog.Attr{slog.String("tenant", "acme")},
+		},
+		{
+			name:     "value may contain equals",
+			value:    "query=a=b",
+			expected: []slog.Attr{slog.String("query", "a=b")},
+		},
+		{
+			name:     "later duplicate overrides earlier",
+			value:    "tenant=acme,tenant=globex",
+			expected: []slog.Attr{slog.String("tenant", "globex")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ParseLabels(tt.value))
+		})
+	}
+}
+
+func TestWithEnvLabels(t *testing.T) {
+	t.Setenv(LabelsEnvVar, "tenant=acme,environment=prod")
+
+	logger, logs := testutil.CaptureLogs(t)
+	labelled := WithEnvLabels(logger)
+
+	labelled.Info("Applied encryption", "audit_action", "encryption_success")
+	labelled.Warn("Key in other region")
+
+	records := logs.Records()
+	require.Len(t, records, 2)
+	for _, record := range records {
+		assert.Equal(t, "acme", record.Attrs["tenant"])
+		assert.Equal(t, "prod", record.Attrs["environment"])
+	}
+	assert.Equal(t, "encryption_success", records[0].Attrs["audit_action"])
+}
+
+func TestWithLabels_NoLabelsReturnsSameLogger(t *testing.T) {
+	logger, _ := testutil.CaptureLogs(t)
+	assert.Same(t, logger, WithLabels(logger, ""))
+}
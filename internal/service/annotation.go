@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/zsoftly/logguardian/internal/types"
+)
+
+const (
+	// maxEvaluationAnnotationLength is the longest annotation Config accepts on an evaluation
+	maxEvaluationAnnotationLength = 256
+
+	// DefaultEvaluationAnnotationTemplate reproduces the built-in annotation for each outcome
+	DefaultEvaluationAnnotationTemplate = "{summary}"
+
+	// annotationTruncationSuffix marks an annotation shortened to fit the Config limit
+	annotationTruncationSuffix = "..."
+
+	// LastActionTagKey is the log group tag describing the most recent change LogGuardian applied
+	LastActionTagKey = "logguardian:last-action"
+)
+
+// evaluationAnnotation renders the configured annotation template for a remediation result.
+// Supported placeholders are {summary}, {status}, {actions}, {reason}, {log_group} and
+// {execution_id}; the execution ID is the one ctx carries, see ContextWithExecutionID.
+func (s *ComplianceService) evaluationAnnotation(ctx context.Context, logGroupName string, result *types.RemediationResult) string {
+	template := s.config.AnnotationTemplate
+	if strings.TrimSpace(template) == "" {
+		template = DefaultEvaluationAnnotationTemplate
+	}
+
+	executionID := ExecutionIDFromContext(ctx)
+
+	status, reason := annotationStatus(result)
+	replacer := strings.NewReplacer(
+		"{summary}", annotationSummary(result),
+		"{status}", status,
+		"{actions}", annotationActions(result),
+		"{reason}", reason,
+		"{log_group}", logGroupName,
+		"{execution_id}", executionID,
+	)
+	return truncateAnnotation(strings.TrimSpace(replacer.Replace(template)))
+}
+
+// annotationSummary is the annotation LogGuardian reports when no template is configured
+func annotationSummary(result *types.RemediationResult) string {
+	switch {
+	case !result.Success && result.Error != nil:
+		return fmt.Sprintf("LogGuardian remediation failed: %v", result.Error)
+	case !result.Success:
+		return "LogGuardian remediation failed"
+	case result.SkipReason != "":
+		return result.SkipReason
+	case result.NoActionNeeded:
+		return "Already compliant"
+	default:
+		return "Remediated by LogGuardian"
+	}
+}
+
+// annotationStatus describes the outcome and, for skipped or failed remediations, why
+func annotationStatus(result *types.RemediationResult) (string, string) {
+	switch {
+	case !result.Success:
+		if result.Error != nil {
+			return "failed", result.Error.Error()
+		}
+		return "failed", ""
+	case result.SkipReason != "":
+		return "skipped", result.SkipReason
+	case result.NoActionNeeded:
+		return "already compliant", ""
+	default:
+		return "remediated", ""
+	}
+}
+
+// annotationActions lists the changes remediation applied
+func annotationActions(result *types.RemediationResult) string {
+	switch {
+	case result.EncryptionApplied && result.RetentionApplied:
+		return "applied encryption and retention"
+	case result.EncryptionApplied:
+		return "applied encryption"
+	case result.RetentionApplied:
+		return "applied retention"
+	default:
+		return "no changes applied"
+	}
+}
+
+// tagLastAction records the applied change on the log group under LastActionTagKey when
+// TAG_LAST_ACTION is enabled. The tag is evidence only, so failing to set it is logged rather than
+// failing the remediation.
+func (s *ComplianceService) tagLastAction(ctx context.Context, compliance types.ComplianceResult, result *types.RemediationResult) {
+	if !s.config.TagLastAction || s.config.DryRun || (!result.EncryptionApplied && !result.RetentionApplied) {
+		return
+	}
+
+	accountId := compliance.AccountId
+	if accountId == "" {
+		accountId = s.config.AccountId
+	}
+	if accountId == "" {
+		s.getLogger().Warn("Account unknown, cannot tag log group with last action",
+			"log_group", compliance.LogGroupName,
+			"tag_key", LastActionTagKey)
+		return
+	}
+	region := compliance.Region
+	if region == "" {
+		region = s.getCurrentRegion()
+	}
+
+	action := annotationActions(result)
+	_, err := s.logsClient.TagResource(ctx, &cloudwatchlogs.TagResourceInput{
+		ResourceArn: aws.String(fmt.Sprintf("arn:%s:logs:%s:%s:log-group:%s", regionPartition(region), region, accountId, compliance.LogGroupName)),
+		Tags:        map[string]string{LastActionTagKey: action},
+	})
+	if err != nil {
+		s.getLogger().Warn("Failed to tag log group with last action",
+			"log_group", compliance.LogGroupName,
+			"tag_key", LastActionTagKey,
+			"error", err)
+		return
+	}
+
+	s.getLogger().Info("Tagged log group with last action",
+		"log_group", compliance.LogGroupName,
+		"tag_key", LastActionTagKey,
+		"tag_value", action)
+}
+
+// truncateAnnotation shortens an annotation to the Config limit without splitting a multi-byte
+// character, marking the cut with a suffix
+func truncateAnnotation(annotation string) string {
+	if utf8.RuneCountInString(annotation) <= maxEvaluationAnnotationLength {
+		return annotation
+	}
+	runes := []rune(annotation)
+	return string(runes[:maxEvaluationAnnotationLength-utf8.RuneCountInString(annotationTruncationSuffix)]) + annotationTruncationSuffix
+}
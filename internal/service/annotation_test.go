@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cloudwatchlogstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zsoftly/logguardian/internal/types"
+)
+
+func TestComplianceService_EvaluationAnnotation(t *testing.T) {
+	const customTemplate = "Remediated by LogGuardian exec {execution_id}: {actions} ({status})"
+
+	tests := []struct {
+		name     string
+		template string
+		result   types.RemediationResult
+		expected string
+	}{
+		{
+			name:     "default template remediated",
+			result:   types.RemediationResult{Success: true, EncryptionApplied: true},
+			expected: "Remediated by LogGuardian",
+		},
+		{
+			name:     "default template already compliant",
+			result:   types.RemediationResult{Success: true, NoActionNeeded: true},
+			expected: "Already compliant",
+		},
+		{
+			name:     "default template failure",
+			result:   types.RemediationResult{Error: errors.New("access denied")},
+			expected: "LogGuardian remediation failed: access denied",
+		},
+		{
+			name:     "custom template encryption",
+			template: customTemplate,
+			result:   types.RemediationResult{Success: true, EncryptionApplied: true},
+			expected: "Remediated by LogGuardian exec req-123: applied encryption (remediated)",
+		},
+		{
+			name:     "custom template encryption and retention",
+			template: customTemplate,
+			result:   types.RemediationResult{Success: true, EncryptionApplied: true, RetentionApplied: true},
+			expected: "Remediated by LogGuardian exec req-123: applied encryption and retention (remediated)",
+		},
+		{
+			name:     "custom template skipped with reason",
+			template: "{log_group} {status}: {reason}",
+			result:   types.RemediationResult{Success: true, SkipReason: "retention reduction blocked"},
+			expected: "/aws/lambda/test skipped: retention reduction blocked",
+		},
+	}
+
+	ctx := ContextWithExecutionID(context.Background(), "req-123")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &ComplianceService{config: ServiceConfig{AnnotationTemplate: tt.template}}
+			assert.Equal(t, tt.expected, service.evaluationAnnotation(ctx, "/aws/lambda/test", &tt.result))
+		})
+	}
+}
+
+func TestComplianceService_EvaluationAnnotation_Truncated(t *testing.T) {
+	service := &ComplianceService{config: ServiceConfig{AnnotationTemplate: "{status}: {reason}"}}
+	result := &types.RemediationResult{Error: errors.New(strings.Repeat("é", 300))}
+
+	annotation := service.evaluationAnnotation(context.Background(), "/aws/lambda/test", result)
+
+	assert.True(t, utf8.ValidString(annotation))
+	assert.Equal(t, maxEvaluationAnnotationLength, utf8.RuneCountInString(annotation))
+	assert.True(t, strings.HasPrefix(annotation, "failed: é"))
+	assert.True(t, strings.HasSuffix(annotation, annotationTruncationSuffix))
+}
+
+func TestComplianceService_ReportEvaluation_Annotation(t *testing.T) {
+	configClient := &MockConfigServiceClient{}
+	service := &ComplianceService{
+		configClient: configClient,
+		config: ServiceConfig{
+			ReportEvaluations:  true,
+			AnnotationTemplate: "LogGuardian {status}: {actions}",
+		},
+	}
+	configItem := types.ConfigurationItem{ResourceId: "/aws/lambda/test", ResourceType: LogGroupResourceType}
+
+	err := service.ReportEvaluation(context.Background(), "token-123", configItem,
+		&types.RemediationResult{Success: true, RetentionApplied: true})
+	require.NoError(t, err)
+
+	require.NotNil(t, configClient.PutEvaluationsInput)
+	require.Len(t, configClient.PutEvaluationsInput.Evaluations, 1)
+	assert.Equal(t, "LogGuardian remediated: applied retention", aws.ToString(configClient.PutEvaluationsInput.Evaluations[0].Annotation))
+}
+
+func TestComplianceService_RemediateLogGroup_TagsLastAction(t *testing.T) {
+	tests := []struct {
+		name              string
+		missingEncryption bool
+		missingRetention  bool
+		tagLastAction     bool
+		dryRun            bool
+		expectedTag       string
+	}{
+		{name: "encryption", missingEncryption: true, tagLastAction: true, expectedTag: "applied encryption"},
+		{name: "retention", missingRetention: true, tagLastAction: true, expectedTag: "applied retention"},
+		{name: "both", missingEncryption: true, missingRetention: true, tagLastAction: true, expectedTag: "applied encryption and retention"},
+		{name: "already compliant is not tagged", tagLastAction: true},
+		{name: "dry run is not tagged", missingRetention: true, tagLastAction: true, dryRun: true},
+		{name: "disabled", missingEncryption: true, missingRetention: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logsClient := &MockCloudWatchLogsClient{LogGroups: []cloudwatchlogstypes.LogGroup{{LogGroupName: aws.String("/aws/lambda/test")}}}
+			service := &ComplianceService{
+				logsClient: logsClient,
+				kmsClient:  &MockKMSClient{KeyState: kmstypes.KeyStateEnabled},
+				config: ServiceConfig{
+					DefaultKMSKeyAlias:   "alias/test-key",
+					DefaultRetentionDays: 365,
+					AccountId:            "123456789012",
+					MaxKMSRetries:        3,
+					DryRun:               tt.dryRun,
+					TagLastAction:        tt.tagLastAction,
+				},
+			}
+
+			result, err := service.RemediateLogGroup(context.Background(), types.ComplianceResult{
+				LogGroupName:      "/aws/lambda/test",
+				Region:            "ca-central-1",
+				MissingEncryption: tt.missingEncryption,
+				MissingRetention:  tt.missingRetention,
+			})
+			require.NoError(t, err)
+			require.True(t, result.Success)
+
+			if tt.expectedTag == "" {
+				assert.Nil(t, logsClient.TagResourceInput)
+				return
+			}
+			require.NotNil(t, logsClient.TagResourceInput)
+			assert.Equal(t, "arn:aws:logs:ca-central-1:123456789012:log-group:/aws/lambda/test", aws.ToString(logsClient.TagResourceInput.ResourceArn))
+			assert.Equal(t, map[string]string{LastActionTagKey: tt.expectedTag}, logsClient.TagResourceInput.Tags)
+		})
+	}
+}
+
+func TestComplianceService_RemediateLogGroup_TagFailureDoesNotFailRemediation(t *testing.T) {
+	logsClient := &MockCloudWatchLogsClient{
+		TagResourceError: errors.New("AccessDeniedException"),
+		LogGroups:        []cloudwatchlogstypes.LogGroup{{LogGroupName: aws.String("/aws/lambda/test")}},
+	}
+	service := &ComplianceService{
+		logsClient: logsClient,
+		config: ServiceConfig{
+			DefaultRetentionDays: 365,
+			AccountId:            "123456789012",
+			TagLastAction:        true,
+		},
+	}
+
+	result, err := service.RemediateLogGroup(context.Background(), types.ComplianceResult{
+		LogGroupName:     "/aws/lambda/test",
+		Region:           "ca-central-1",
+		MissingRetention: true,
+	})
+
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.True(t, result.RetentionApplied)
+	assert.NotNil(t, logsClient.TagResourceInput)
+}
+
+func TestComplianceService_RemediateLogGroup_TagUsesRegionPartition(t *testing.T) {
+	logsClient := &MockCloudWatchLogsClient{LogGroups: []cloudwatchlogstypes.LogGroup{{LogGroupName: aws.String("/aws/lambda/test")}}}
+	service := &ComplianceService{
+		logsClient: logsClient,
+		config: ServiceConfig{
+			DefaultRetentionDays: 365,
+			AccountId:            "123456789012",
+			TagLastAction:        true,
+		},
+	}
+
+	_, err := service.RemediateLogGroup(context.Background(), types.ComplianceResult{
+		LogGroupName:     "/aws/lambda/test",
+		Region:           "us-gov-west-1",
+		MissingRetention: true,
+	})
+
+	require.NoError(t, err)
+	require.NotNil(t, logsClient.TagResourceInput)
+	assert.Equal(t, "arn:aws-us-gov:logs:us-gov-west-1:123456789012:log-group:/aws/lambda/test", aws.ToString(logsClient.TagResourceInput.ResourceArn))
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/zsoftly/logguardian/internal/types"
+)
+
+// AuditSink receives the audit record of every remediation that changed a log group or failed
+type AuditSink interface {
+	WriteAuditRecord(ctx context.Context, record types.AuditRecord)
+}
+
+// logAuditSink writes each audit record as a single structured log line
+type logAuditSink struct {
+	logger *slog.Logger
+}
+
+func (l logAuditSink) WriteAuditRecord(ctx context.Context, record types.AuditRecord) {
+	l.logger.InfoContext(ctx, "Remediation audit record",
+		"log_group", record.LogGroupName,
+		"record", record,
+		"audit_action", AuditActionRemediationRecord)
+}
+
+// WithAuditSink sends remediation audit records to sink, whether or not AUDIT_RECORDS is set
+func WithAuditSink(sink AuditSink) ComplianceServiceOption {
+	return func(s *ComplianceService) {
+		s.auditSink = sink
+	}
+}
+
+// WithAuditActor names the run audit records attribute changes to. Without an execution ID,
+// records carry the one the remediation's context holds, see ContextWithExecutionID.
+func WithAuditActor(actor types.AuditActor) ComplianceServiceOption {
+	return func(s *ComplianceService) {
+		s.auditActor = actor
+	}
+}
+
+// executionIDKey holds the execution ID of the invocation in progress
+type executionIDKey struct{}
+
+// ContextWithExecutionID returns ctx carrying the ID of the invocation in progress, such as the
+// Lambda request ID. Audit records without a configured execution ID and the {execution_id}
+// annotation placeholder use it.
+func ContextWithExecutionID(ctx context.Context, executionID string) context.Context {
+	return context.WithValue(ctx, executionIDKey{}, executionID)
+}
+
+// ExecutionIDFromContext returns the execution ID ctx carries, or "" when it carries none
+func ExecutionIDFromContext(ctx context.Context) string {
+	executionID, _ := ctx.Value(executionIDKey{}).(string)
+	return executionID
+}
+
+// auditRecordSink returns the sink audit records are written to, or nil when they are not recorded
+func (s *ComplianceService) auditRecordSink() AuditSink {
+	if s.auditSink == nil && s.config.AuditRecords {
+		return logAuditSink{logger: s.getLogger()}
+	}
+	return s.auditSink
+}
+
+// auditNeedsLiveState reports whether a remediation that may change the log group will be recorded
+// without knowing the group's live state, which the record's before state needs
+func (s *ComplianceService) auditNeedsLiveState(compliance types.ComplianceResult) bool {
+	changes := compliance.MissingEncryption || compliance.MissingRetention
+	return changes && !compliance.LiveState && !s.config.DryRun && s.auditRecordSink() != nil
+}
+
+// recordAudit writes the audit record of a remediation that changed the log group or failed.
+// Before comes from the compliance result, which remediation fills from the live log group whenever
+// records are kept; after projects the changes applied with keyArn, the resolved ARN of the key
+// associated, and retentionDays. Dry runs and groups that needed nothing are not recorded.
+func (s *ComplianceService) recordAudit(ctx context.Context, compliance types.ComplianceResult, result *types.RemediationResult, keyArn string, retentionDays int32) {
+	sink := s.auditRecordSink()
+	if sink == nil || s.config.DryRun || (result.Success && !result.EncryptionApplied && !result.RetentionApplied) {
+		return
+	}
+
+	record := types.AuditRecord{
+		LogGroupName: compliance.LogGroupName,
+		Region:       compliance.Region,
+		AccountId:    compliance.AccountId,
+		Action:       annotationActions(result),
+		Success:      result.Success,
+		Before: types.AuditState{
+			KmsKeyId:        compliance.CurrentKmsKeyId,
+			RetentionInDays: compliance.CurrentRetention,
+		},
+		Actor:     s.auditActor,
+		Timestamp: s.now().UTC(),
+	}
+	if !result.Success {
+		record.Action = "remediation failed"
+		if result.Error != nil {
+			record.Error = result.Error.Error()
+		}
+	}
+
+	record.After = record.Before
+	if result.EncryptionApplied {
+		record.After.KmsKeyId = keyArn
+	}
+	if result.RetentionApplied {
+		days := retentionDays
+		record.After.RetentionInDays = &days
+	}
+
+	if record.Actor.ExecutionID == "" {
+		record.Actor.ExecutionID = ExecutionIDFromContext(ctx)
+	}
+	if record.AccountId == "" {
+		record.AccountId = s.config.AccountId
+	}
+
+	sink.WriteAuditRecord(ctx, record)
+}
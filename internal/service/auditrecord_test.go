@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cloudwatchlogstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zsoftly/logguardian/internal/testutil"
+	"github.com/zsoftly/logguardian/internal/types"
+)
+
+// recordingAuditSink keeps every audit record written to it
+type recordingAuditSink struct {
+	records []types.AuditRecord
+}
+
+func (r *recordingAuditSink) WriteAuditRecord(ctx context.Context, record types.AuditRecord) {
+	r.records = append(r.records, record)
+}
+
+func newAuditRecordTestService(sink AuditSink, logGroups ...cloudwatchlogstypes.LogGroup) (*ComplianceService, *MockCloudWatchLogsClient) {
+	mockLogsClient := &MockCloudWatchLogsClient{LogGroups: logGroups}
+	fixed := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	service := &ComplianceService{
+		logsClient:     mockLogsClient,
+		kmsClient:      &MockKMSClient{},
+		ruleClassifier: types.NewRuleClassifier(),
+		clock:          func() time.Time { return fixed },
+		auditSink:      sink,
+		auditActor:     types.AuditActor{ExecutionID: "exec-1", Role: "arn:aws:iam::123456789012:role/LogGuardian"},
+		config: ServiceConfig{
+			DefaultKMSKeyAlias:      "alias/test-key",
+			DefaultRetentionDays:    365,
+			Region:                  "ca-central-1",
+			MaxKMSRetries:           3,
+			RetryBaseDelay:          time.Millisecond,
+			AllowRetentionReduction: true,
+		},
+	}
+	return service, mockLogsClient
+}
+
+func TestComplianceService_AuditRecord_Encryption(t *testing.T) {
+	sink := &recordingAuditSink{}
+	service, _ := newAuditRecordTestService(sink,
+		cloudwatchlogstypes.LogGroup{LogGroupName: aws.String("/aws/lambda/unencrypted"), RetentionInDays: aws.Int32(30)})
+
+	_, err := service.RemediateLogGroup(context.Background(), types.ComplianceResult{
+		LogGroupName:      "/aws/lambda/unencrypted",
+		Region:            "ca-central-1",
+		AccountId:         "123456789012",
+		MissingEncryption: true,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, sink.records, 1)
+	record := sink.records[0]
+	assert.Equal(t, "/aws/lambda/unencrypted", record.LogGroupName)
+	assert.Equal(t, "123456789012", record.AccountId)
+	assert.Equal(t, "applied encryption", record.Action)
+	assert.True(t, record.Success)
+	assert.Equal(t, types.AuditState{RetentionInDays: aws.Int32(30)}, record.Before)
+	assert.Equal(t, types.AuditState{KmsKeyId: "arn:aws:kms:ca-central-1:123456789012:key/12345678-1234-1234-1234-123456789012", RetentionInDays: aws.Int32(30)}, record.After)
+	assert.Equal(t, types.AuditActor{ExecutionID: "exec-1", Role: "arn:aws:iam::123456789012:role/LogGuardian"}, record.Actor)
+	assert.Equal(t, time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC), record.Timestamp)
+}
+
+func TestComplianceService_AuditRecord_Retention(t *testing.T) {
+	const keyArn = "arn:aws:kms:ca-central-1:123456789012:key/12345678-1234-1234-1234-123456789012"
+	sink := &recordingAuditSink{}
+	service, _ := newAuditRecordTestService(sink, cloudwatchlogstypes.LogGroup{
+		LogGroupName:    aws.String("/aws/lambda/short-retention"),
+		RetentionInDays: aws.Int32(7),
+		KmsKeyId:        aws.String(keyArn),
+	})
+	service.auditActor = types.AuditActor{}
+
+	ctx := ContextWithExecutionID(context.Background(), "request-1")
+	_, err := service.RemediateLogGroup(ctx, types.ComplianceResult{
+		LogGroupName:     "/aws/lambda/short-retention",
+		Region:           "ca-central-1",
+		MissingRetention: true,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, sink.records, 1)
+	record := sink.records[0]
+	assert.Equal(t, "applied retention", record.Action)
+	assert.Equal(t, types.AuditState{KmsKeyId: keyArn, RetentionInDays: aws.Int32(7)}, record.Before)
+	assert.Equal(t, types.AuditState{KmsKeyId: keyArn, RetentionInDays: aws.Int32(365)}, record.After)
+	assert.Equal(t, "request-1", record.Actor.ExecutionID)
+}
+
+func TestProcessNonCompliantResourcesOptimized_AuditRecordUsesLiveState(t *testing.T) {
+	const oldKeyArn = "arn:aws:kms:ca-central-1:123456789012:key/old-key"
+	sink := &recordingAuditSink{}
+	service, _ := newAuditRecordTestService(sink, cloudwatchlogstypes.LogGroup{
+		LogGroupName:    aws.String("/aws/lambda/test-0"),
+		RetentionInDays: aws.Int32(14),
+		KmsKeyId:        aws.String(oldKeyArn),
+	})
+
+	result, err := service.ProcessNonCompliantResourcesOptimized(context.Background(), testutil.NewTestBatchComplianceRequest(1))
+	require.NoError(t, err)
+	require.Equal(t, 1, result.SuccessCount)
+
+	require.Len(t, sink.records, 1)
+	record := sink.records[0]
+	assert.Equal(t, "applied retention", record.Action)
+	assert.Equal(t, types.AuditState{KmsKeyId: oldKeyArn, RetentionInDays: aws.Int32(14)}, record.Before)
+	assert.Equal(t, types.AuditState{KmsKeyId: oldKeyArn, RetentionInDays: aws.Int32(365)}, record.After)
+}
+
+func TestComplianceService_AuditRecord_NotWritten(t *testing.T) {
+	t.Run("nothing to change", func(t *testing.T) {
+		sink := &recordingAuditSink{}
+		service, _ := newAuditRecordTestService(sink)
+
+		_, err := service.RemediateLogGroup(context.Background(), types.ComplianceResult{LogGroupName: "/aws/lambda/compliant"})
+		require.NoError(t, err)
+		assert.Empty(t, sink.records)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		sink := &recordingAuditSink{}
+		service, _ := newAuditRecordTestService(sink)
+		service.config.DryRun = true
+
+		_, err := service.RemediateLogGroup(context.Background(), types.ComplianceResult{LogGroupName: "/aws/lambda/test", MissingRetention: true})
+		require.NoError(t, err)
+		assert.Empty(t, sink.records)
+	})
+}
+
+func TestComplianceService_AuditRecord_LoggedWhenEnabled(t *testing.T) {
+	logger, logs := testutil.CaptureLogs(t)
+	service, _ := newAuditRecordTestService(nil, cloudwatchlogstypes.LogGroup{LogGroupName: aws.String("/aws/lambda/test")})
+	service.logger = logger
+	service.config.AuditRecords = true
+
+	_, err := service.RemediateLogGroup(context.Background(), types.ComplianceResult{LogGroupName: "/aws/lambda/test", MissingRetention: true})
+	require.NoError(t, err)
+
+	records := logs.WithAttr("audit_action", AuditActionRemediationRecord)
+	require.Len(t, records, 1)
+	assert.Equal(t, "Remediation audit record", records[0].Message)
+	assert.Equal(t, "/aws/lambda/test", records[0].Attrs["log_group"])
+	assert.NotNil(t, records[0].Attrs["record"])
+}
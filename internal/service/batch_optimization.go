@@ -3,7 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
-	"log/slog"
+	"slices"
 	"sync"
 	"time"
 
@@ -23,6 +23,7 @@ const (
 	BatchKMSValidationFailedTemplate = "failed to validate KMS key '%s' for batch operation in region '%s' (config rule: %s): %w"
 	BatchContextInitFailedTemplate   = "failed to initialize batch remediation context for config rule '%s' in region '%s': %w"
 	KMSKeyNotValidatedTemplate       = "KMS key '%s' not validated for batch operation in region '%s' (config rule: %s): %w"
+	BatchRegionMismatchTemplate      = "log group %s is in region '%s' but the batch validated KMS for region '%s'; process it through the multi-region service"
 )
 
 // BatchKMSValidationCache caches KMS key validation results for a batch operation
@@ -44,52 +45,98 @@ type BatchRemediationContext struct {
 	dryRun             bool
 	defaultKMSKeyAlias string
 	retentionDays      int32
+	kmsValidationTime  time.Duration
+}
+
+// batchTimer accumulates a batch run's timing breakdown from concurrent batches
+type batchTimer struct {
+	now       func() time.Time
+	mu        sync.Mutex
+	breakdown types.TimingBreakdown
+}
+
+// track adds the time elapsed since start to a component of the breakdown and returns it
+func (t *batchTimer) track(component *time.Duration, start time.Time) time.Duration {
+	elapsed := t.now().Sub(start)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	*component += elapsed
+	return elapsed
+}
+
+// sleep waits like sleepWithContext, counting the time spent as sleep
+func (t *batchTimer) sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	start := t.now()
+	defer t.track(&t.breakdown.Sleep, start)
+	return sleepWithContext(ctx, d)
+}
+
+// snapshot returns the breakdown accumulated so far
+func (t *batchTimer) snapshot() types.TimingBreakdown {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.breakdown
 }
 
 // NewBatchRemediationContext creates a new batch context with KMS validation only for encryption rules
 func (s *ComplianceService) NewBatchRemediationContext(ctx context.Context, request types.BatchComplianceRequest) (*BatchRemediationContext, error) {
+	keyAlias := s.config.DefaultKMSKeyAlias
+	if request.TargetKMSKeyId != "" {
+		keyAlias = request.TargetKMSKeyId
+	}
+	retentionDays := s.config.DefaultRetentionDays
+	if request.TargetRetentionDays != nil {
+		retentionDays = *request.TargetRetentionDays
+	}
+
 	batchCtx := &BatchRemediationContext{
 		region:             request.Region,
 		configRuleName:     request.ConfigRuleName,
-		batchStartTime:     time.Now(),
+		batchStartTime:     s.now(),
 		dryRun:             s.config.DryRun,
-		defaultKMSKeyAlias: s.config.DefaultKMSKeyAlias,
-		retentionDays:      s.config.DefaultRetentionDays,
-		kmsCache:           &BatchKMSValidationCache{keyAlias: s.config.DefaultKMSKeyAlias},
+		defaultKMSKeyAlias: keyAlias,
+		retentionDays:      retentionDays,
+		kmsCache:           &BatchKMSValidationCache{keyAlias: keyAlias},
 	}
 
 	// Determine rule type to decide if KMS validation is needed
 	ruleType := s.ruleClassifier.ClassifyRule(request.ConfigRuleName)
 
-	slog.Info("Initializing batch remediation context",
+	s.getLogger().Info("Initializing batch remediation context",
 		"config_rule", request.ConfigRuleName,
 		"region", request.Region,
 		"rule_type", ruleType.String(),
-		"kms_key_alias", s.config.DefaultKMSKeyAlias,
+		"kms_key_alias", keyAlias,
 		"dry_run", s.config.DryRun,
 		"audit_action", "batch_context_init")
 
-	// Only validate KMS key for encryption rules
-	if ruleType == types.RuleTypeEncryption {
+	// Only validate KMS key for encryption rules, or merged resources requiring encryption
+	if ruleType == types.RuleTypeEncryption || requiresEncryption(request.NonCompliantResults) {
 		// Pre-validate KMS key once for the entire batch
-		if err := batchCtx.validateKMSKeyForBatch(ctx, s); err != nil {
-			slog.Error("Failed to validate KMS key for batch operation",
+		validationStart := s.now()
+		err := batchCtx.validateKMSKeyForBatch(ctx, s)
+		batchCtx.kmsValidationTime = s.now().Sub(validationStart)
+		if err != nil {
+			s.getLogger().Error("Failed to validate KMS key for batch operation",
 				"config_rule", request.ConfigRuleName,
 				"region", request.Region,
-				"kms_key_alias", s.config.DefaultKMSKeyAlias,
+				"kms_key_alias", keyAlias,
 				"error", err,
 				"audit_action", "batch_kms_validation_failed")
-			return nil, fmt.Errorf(BatchKMSValidationFailedTemplate, s.config.DefaultKMSKeyAlias, request.Region, request.ConfigRuleName, err)
+			return nil, fmt.Errorf(BatchKMSValidationFailedTemplate, keyAlias, request.Region, request.ConfigRuleName, err)
 		}
 
-		slog.Info("Batch remediation context initialized successfully with KMS validation",
+		s.getLogger().Info("Batch remediation context initialized successfully with KMS validation",
 			"config_rule", request.ConfigRuleName,
 			"region", request.Region,
 			"kms_key_validated", batchCtx.kmsCache.keyInfo != nil,
 			"policy_validated", batchCtx.kmsCache.policyValidated,
 			"audit_action", "batch_context_ready")
 	} else {
-		slog.Info("Batch remediation context initialized successfully (no KMS validation needed)",
+		s.getLogger().Info("Batch remediation context initialized successfully (no KMS validation needed)",
 			"config_rule", request.ConfigRuleName,
 			"region", request.Region,
 			"rule_type", ruleType.String(),
@@ -104,7 +151,7 @@ func (bctx *BatchRemediationContext) validateKMSKeyForBatch(ctx context.Context,
 	bctx.kmsCache.mu.Lock()
 	defer bctx.kmsCache.mu.Unlock()
 
-	slog.Info("Performing batch KMS key validation",
+	s.getLogger().Info("Performing batch KMS key validation",
 		"kms_key_alias", bctx.kmsCache.keyAlias,
 		"region", bctx.region,
 		"audit_action", "batch_kms_validation_start")
@@ -113,7 +160,7 @@ func (bctx *BatchRemediationContext) validateKMSKeyForBatch(ctx context.Context,
 	keyInfo, err := s.validateKMSKeyAccessibility(ctx, bctx.kmsCache.keyAlias)
 	if err != nil {
 		bctx.kmsCache.validationError = fmt.Errorf("KMS key accessibility validation failed: %w", err)
-		slog.Error("Batch KMS key accessibility validation failed",
+		s.getLogger().Error("Batch KMS key accessibility validation failed",
 			"kms_key_alias", bctx.kmsCache.keyAlias,
 			"region", bctx.region,
 			"error", err,
@@ -123,7 +170,7 @@ func (bctx *BatchRemediationContext) validateKMSKeyForBatch(ctx context.Context,
 
 	bctx.kmsCache.keyInfo = keyInfo
 
-	slog.Info("Batch KMS key accessibility validation successful",
+	s.getLogger().Info("Batch KMS key accessibility validation successful",
 		"kms_key_alias", bctx.kmsCache.keyAlias,
 		"kms_key_id", keyInfo.KeyId,
 		"kms_key_arn", keyInfo.Arn,
@@ -136,21 +183,21 @@ func (bctx *BatchRemediationContext) validateKMSKeyForBatch(ctx context.Context,
 	// Step 2: Validate KMS key policy for CloudWatch Logs
 	if err := s.validateKMSKeyPolicyForCloudWatchLogs(ctx, keyInfo.KeyId); err != nil {
 		// Policy validation failure is a warning, not a fatal error
-		slog.Warn("Batch KMS key policy validation warning",
+		s.getLogger().Warn("Batch KMS key policy validation warning",
 			"kms_key_id", keyInfo.KeyId,
 			"error", err,
 			"audit_action", "batch_kms_policy_validation_warning",
 			"note", "Proceeding with batch operation - ensure key policy allows CloudWatch Logs service")
 	} else {
 		bctx.kmsCache.policyValidated = true
-		slog.Info("Batch KMS key policy validation successful",
+		s.getLogger().Info("Batch KMS key policy validation successful",
 			"kms_key_id", keyInfo.KeyId,
 			"audit_action", "batch_kms_policy_validation_success")
 	}
 
 	bctx.kmsCache.validatedAt = time.Now()
 
-	slog.Info("Batch KMS validation completed successfully",
+	s.getLogger().Info("Batch KMS validation completed successfully",
 		"kms_key_alias", bctx.kmsCache.keyAlias,
 		"kms_key_id", keyInfo.KeyId,
 		"policy_validated", bctx.kmsCache.policyValidated,
@@ -160,6 +207,16 @@ func (bctx *BatchRemediationContext) validateKMSKeyForBatch(ctx context.Context,
 	return nil
 }
 
+// requiresEncryption reports whether any resource merged from several rules needs encryption
+func requiresEncryption(resources []types.NonCompliantResource) bool {
+	for _, resource := range resources {
+		if slices.Contains(resource.Requirements, types.RuleTypeEncryption) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetValidatedKMSKeyInfo returns the pre-validated KMS key info for the batch
 func (bctx *BatchRemediationContext) GetValidatedKMSKeyInfo() (*KMSKeyInfo, error) {
 	bctx.kmsCache.mu.RLock()
@@ -179,9 +236,9 @@ func (bctx *BatchRemediationContext) GetValidatedKMSKeyInfo() (*KMSKeyInfo, erro
 
 // ProcessNonCompliantResourcesOptimized processes multiple non-compliant resources with optimized KMS validation
 func (s *ComplianceService) ProcessNonCompliantResourcesOptimized(ctx context.Context, request types.BatchComplianceRequest) (*types.BatchRemediationResult, error) {
-	startTime := time.Now()
+	startTime := s.now()
 
-	slog.Info("Starting optimized batch remediation",
+	s.getLogger().Info("Starting optimized batch remediation",
 		"config_rule", request.ConfigRuleName,
 		"region", request.Region,
 		"total_resources", len(request.NonCompliantResults),
@@ -198,6 +255,12 @@ func (s *ComplianceService) ProcessNonCompliantResourcesOptimized(ctx context.Co
 		TotalProcessed: len(request.NonCompliantResults),
 		Results:        make([]types.RemediationResult, 0, len(request.NonCompliantResults)),
 	}
+	timer := &batchTimer{now: s.now}
+	timer.breakdown.KMSValidation = batchCtx.kmsValidationTime
+
+	// A resource from another region would be remediated with this region's KMS key and clients,
+	// so it is recorded as a failure instead of being processed
+	resources := s.rejectRegionMismatches(request, result)
 
 	// Process resources in batches to avoid overwhelming the AWS APIs
 	batchSize := request.BatchSize
@@ -205,123 +268,427 @@ func (s *ComplianceService) ProcessNonCompliantResourcesOptimized(ctx context.Co
 		batchSize = DefaultBatchSize
 	}
 
+	// Per-request delays, such as those from Config rule parameters, override the service defaults
+	resourceDelay := s.config.BatchResourceDelay
+	if request.ResourceDelayMs != nil {
+		resourceDelay = time.Duration(*request.ResourceDelayMs) * time.Millisecond
+	}
+	groupDelay := s.config.BatchGroupDelay
+	if request.GroupDelayMs != nil {
+		groupDelay = time.Duration(*request.GroupDelayMs) * time.Millisecond
+	}
+
+	// In fail-fast mode the first failure, and with a batch timeout an expired wait, cancels the
+	// remaining batch through a shared context
+	abort := func() {}
+	if s.config.FailFast || s.config.BatchTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+		abort = cancel
+	}
+
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 	rateLimitCounter := 0
+	inFlight := make(map[int]types.NonCompliantResource) // Resource currently being remediated, by batch index
+	timedOut := false
+	var deferred []types.NonCompliantResource // Resources whose batch was never started
+	var durations []RemediationDuration       // Guarded by mu, like result
+
+	// processBatch remediates one batch of resources in order, recording each outcome
+	processBatch := func(batchResources []types.NonCompliantResource, batchIndex int) {
+		s.getLogger().Info("Processing optimized batch",
+			"batch_index", batchIndex,
+			"batch_size", len(batchResources),
+			"config_rule", request.ConfigRuleName)
+
+		// Process each resource in the batch using pre-validated KMS info
+		for _, resource := range batchResources {
+			if ctx.Err() != nil {
+				s.getLogger().Info("Stopping optimized batch due to context cancellation",
+					"batch_index", batchIndex,
+					"config_rule", request.ConfigRuleName)
+				return
+			}
 
-	// Process in parallel batches
-	for i := 0; i < len(request.NonCompliantResults); i += batchSize {
-		end := i + batchSize
-		if end > len(request.NonCompliantResults) {
-			end = len(request.NonCompliantResults)
-		}
-
-		batch := request.NonCompliantResults[i:end]
-		wg.Add(1)
+			// Convert to ComplianceResult format for this specific Config rule
+			compliance := s.convertToComplianceResultForRule(batchCtx.configRuleName, resource)
+			if compliance.NotApplicable {
+				// The rule does not evaluate this resource, so it is neither remediated nor a success
+				mu.Lock()
+				if timedOut {
+					mu.Unlock()
+					return
+				}
+				result.NotApplicableCount++
+				result.Results = append(result.Results, types.RemediationResult{
+					LogGroupName: compliance.LogGroupName,
+					Region:       compliance.Region,
+					Success:      true,
+					SkipReason:   string(SkipReasonRuleNotApplicable),
+				})
+				mu.Unlock()
+				continue
+			}
 
-		go func(batchResources []types.NonCompliantResource, batchIndex int) {
-			defer wg.Done()
+			// Each account is throttled independently when ACCOUNT_RATE_LIMIT is set
+			if !s.waitForAccount(ctx, resource.AccountId) {
+				return
+			}
 
-			slog.Info("Processing optimized batch",
-				"batch_index", batchIndex,
-				"batch_size", len(batchResources),
-				"config_rule", request.ConfigRuleName)
+			mu.Lock()
+			inFlight[batchIndex] = resource
+			mu.Unlock()
 
-			// Process each resource in the batch using pre-validated KMS info
-			for _, resource := range batchResources {
-				// Convert to ComplianceResult format for this specific Config rule
-				compliance := s.convertToComplianceResultForRule(batchCtx.configRuleName, resource)
+			// Config reports only that a group is non-compliant, so the live group is read before the
+			// first attempt; the guards, the already-compliant retention check, drift and audit records
+			// all describe the group as it was before any change, including after a retry
+			attempt := func() (*types.RemediationResult, error) {
+				if !compliance.LiveState {
+					live, err := s.withLiveState(ctx, compliance)
+					if err != nil {
+						return nil, err
+					}
+					compliance = live
+				}
+				return s.remediateLogGroupWithBatchContext(ctx, compliance, batchCtx)
+			}
 
-				// Use optimized remediation with pre-validated KMS info
-				remediationResult, err := s.remediateLogGroupWithBatchContext(ctx, compliance, batchCtx)
+			// Use optimized remediation with pre-validated KMS info
+			remediationStart := s.now()
+			remediationResult, err := attempt()
+			elapsed := timer.track(&timer.breakdown.Remediation, remediationStart)
 
+			// Handle rate limiting with exponential backoff
+			if err != nil && isRateLimitError(err) {
 				mu.Lock()
-				if err != nil {
-					// Handle rate limiting with exponential backoff
-					if isRateLimitError(err) {
-						rateLimitCounter++
-						slog.Warn("Rate limit encountered in optimized batch",
-							"resource", resource.ResourceName,
-							"batch_index", batchIndex,
-							"error", err)
-
-						// Exponential backoff with jitter
-						delay := time.Duration(1+rateLimitCounter) * time.Second
-						slog.Info("Retrying with exponential backoff", "delay", delay, "batch_index", batchIndex)
-						time.Sleep(delay)
-
-						// Retry with batch context
-						remediationResult, err = s.remediateLogGroupWithBatchContext(ctx, compliance, batchCtx)
-					}
-
-					if err != nil {
-						result.FailureCount++
-						remediationResult = &types.RemediationResult{
-							LogGroupName: compliance.LogGroupName,
-							Region:       compliance.Region,
-							Success:      false,
-							Error:        err,
-						}
-					} else {
-						result.SuccessCount++
-					}
-				} else {
-					result.SuccessCount++
+				rateLimitCounter++
+				delay := time.Duration(1+rateLimitCounter) * time.Second
+				mu.Unlock()
+				s.getLogger().Warn("Rate limit encountered in optimized batch",
+					"resource", resource.ResourceName,
+					"batch_index", batchIndex,
+					"error", err)
+
+				// Exponential backoff with jitter
+				s.getLogger().Info("Retrying with exponential backoff", "delay", delay, "batch_index", batchIndex)
+				if timer.sleep(ctx, delay) {
+					// Retry with batch context
+					retryStart := s.now()
+					remediationResult, err = attempt()
+					elapsed += timer.track(&timer.breakdown.Remediation, retryStart)
 				}
+			}
 
-				result.Results = append(result.Results, *remediationResult)
+			// Drift reflects the final outcome, so a success after a retry is recorded too
+			var drift *types.DriftEntry
+			if err == nil {
+				drift = s.driftEntry(ctx, compliance, remediationResult, batchCtx)
+			}
+
+			mu.Lock()
+			if timedOut {
+				// The result was already returned with this resource recorded as timed out
 				mu.Unlock()
+				return
+			}
+			delete(inFlight, batchIndex)
+			if err != nil {
+				result.FailureCount++
+				remediationResult = &types.RemediationResult{
+					LogGroupName: compliance.LogGroupName,
+					Region:       compliance.Region,
+					Success:      false,
+					Error:        err,
+				}
 
-				// Configurable delay between resources in the same batch to prevent overwhelming APIs
-				time.Sleep(s.config.BatchResourceDelay)
+				if s.config.FailFast && !result.AbortedOnFailure {
+					result.AbortedOnFailure = true
+					s.getLogger().Error("Aborting batch remediation after first failure",
+						"resource", resource.ResourceName,
+						"batch_index", batchIndex,
+						"config_rule", request.ConfigRuleName,
+						"error", err,
+						"audit_action", "batch_remediation_aborted")
+					abort()
+				}
+			} else {
+				result.SuccessCount++
 			}
 
-			slog.Info("Optimized batch completed",
-				"batch_index", batchIndex,
-				"batch_size", len(batchResources))
+			if remediationResult.NoActionNeeded {
+				result.NoActionCount++
+			}
+			if remediationResult.Success && remediationResult.SkipReason != "" &&
+				!remediationResult.EncryptionApplied && !remediationResult.RetentionApplied {
+				result.SkippedCount++
+			}
+			durations = append(durations, RemediationDuration{
+				Region:   compliance.Region,
+				Action:   remediationAction(compliance),
+				Duration: elapsed,
+			})
+			if drift != nil {
+				result.Drift = append(result.Drift, *drift)
+			}
 
-		}(batch, i/batchSize)
+			remediationResult.Duration = elapsed
+			result.Results = append(result.Results, *remediationResult)
+			mu.Unlock()
 
-		// Rate limiting: configurable delay between batches
-		time.Sleep(s.config.BatchGroupDelay)
+			// Configurable delay between resources in the same batch to prevent overwhelming APIs
+			timer.sleep(ctx, resourceDelay)
+		}
+
+		s.getLogger().Info("Optimized batch completed",
+			"batch_index", batchIndex,
+			"batch_size", len(batchResources))
 	}
 
-	// Wait for all batches to complete
-	wg.Wait()
+	sequential := s.runsSequentially(len(resources))
+	if sequential {
+		// Small runs are remediated inline as a single batch, without goroutines or group delays
+		processBatch(resources, 0)
+	} else {
+		// Process in parallel batches, stopping dispatch as soon as the context is cancelled or,
+		// with SOFT_TIME_BUDGET_MS, once the budget is spent so in-flight batches finish in time
+	dispatch:
+		for i := 0; i < len(resources); i += batchSize {
+			if ctx.Err() != nil {
+				break
+			}
+			if s.config.SoftTimeBudget > 0 && s.now().Sub(startTime) >= s.config.SoftTimeBudget {
+				deferred = resources[i:]
+				break
+			}
+
+			end := i + batchSize
+			if end > len(resources) {
+				end = len(resources)
+			}
 
-	result.ProcessingDuration = time.Since(startTime)
+			batch := resources[i:end]
+			wg.Add(1)
+
+			go func(batchResources []types.NonCompliantResource, batchIndex int) {
+				defer wg.Done()
+				processBatch(batchResources, batchIndex)
+			}(batch, i/batchSize)
+
+			// Rate limiting: configurable delay between batches
+			if !timer.sleep(ctx, groupDelay) {
+				break dispatch
+			}
+		}
+	}
+
+	// Wait for in-flight batches to finish their current resource, bounded by the batch timeout
+	if !waitWithTimeout(&wg, s.config.BatchTimeout) {
+		mu.Lock()
+		timedOut = true
+		result.TimedOut = true
+		for _, resource := range inFlight {
+			result.FailureCount++
+			result.Results = append(result.Results, types.RemediationResult{
+				LogGroupName: resource.ResourceName,
+				Region:       resource.Region,
+				Success:      false,
+				Error:        fmt.Errorf("remediation of %s did not complete within batch timeout %s", resource.ResourceName, s.config.BatchTimeout),
+			})
+		}
+
+		// Resources queued behind the stuck ones were never started; they are reported as failed
+		// too, so the result accounts for every resource the batch was given
+		recorded := make(map[string]int, len(result.Results)+len(deferred))
+		for _, r := range result.Results {
+			recorded[r.LogGroupName]++
+		}
+		for _, resource := range deferred {
+			recorded[resource.ResourceName]++
+		}
+		notStarted := 0
+		for _, resource := range resources {
+			if recorded[resource.ResourceName] > 0 {
+				recorded[resource.ResourceName]--
+				continue
+			}
+			notStarted++
+			result.FailureCount++
+			result.Results = append(result.Results, types.RemediationResult{
+				LogGroupName: resource.ResourceName,
+				Region:       resource.Region,
+				Success:      false,
+				Error:        fmt.Errorf("remediation of %s was not started within batch timeout %s", resource.ResourceName, s.config.BatchTimeout),
+			})
+		}
+		s.getLogger().Warn("Batch timeout exceeded, returning partial results",
+			"config_rule", request.ConfigRuleName,
+			"batch_timeout", s.config.BatchTimeout,
+			"timed_out_resources", len(inFlight),
+			"not_started_resources", notStarted,
+			"audit_action", "batch_remediation_timeout")
+		mu.Unlock()
+		abort()
+	}
+
+	if len(deferred) > 0 {
+		result.TotalProcessed -= len(deferred)
+		for _, resource := range deferred {
+			result.Deferred = append(result.Deferred, resource.ResourceName)
+		}
+		s.getLogger().Warn("Soft time budget spent, deferring remaining resources",
+			"config_rule", request.ConfigRuleName,
+			"soft_time_budget", s.config.SoftTimeBudget,
+			"deferred_resources", len(deferred),
+			"audit_action", "batch_remediation_deferred")
+	}
+
+	result.ProcessingDuration = s.now().Sub(startTime)
 	result.RateLimitHits = rateLimitCounter
+	result.Timing = timer.snapshot()
 
-	slog.Info("Optimized batch remediation completed",
+	// A context that ends after the last resource finished leaves a complete run, so the run is
+	// cancelled only when resources were left undispatched or unfinished
+	if ctx.Err() != nil && len(result.Results)+len(result.Deferred) < len(request.NonCompliantResults) {
+		result.Cancelled = true
+		s.getLogger().Warn("Optimized batch remediation cancelled before completion",
+			"config_rule", request.ConfigRuleName,
+			"region", request.Region,
+			"total_resources", result.TotalProcessed,
+			"completed_resources", len(result.Results),
+			"success_count", result.SuccessCount,
+			"failure_count", result.FailureCount,
+			"aborted_on_failure", result.AbortedOnFailure,
+			"error", ctx.Err(),
+			"audit_action", "batch_remediation_cancelled")
+		result.TotalProcessed = len(result.Results)
+	}
+
+	s.getLogger().Info("Optimized batch remediation completed",
 		"total_processed", result.TotalProcessed,
 		"success_count", result.SuccessCount,
 		"failure_count", result.FailureCount,
+		"no_action_count", result.NoActionCount,
+		"skipped_count", result.SkippedCount,
+		"not_applicable_count", result.NotApplicableCount,
 		"processing_duration", result.ProcessingDuration,
+		"kms_validation_duration", result.Timing.KMSValidation,
+		"remediation_duration", result.Timing.Remediation,
+		"sleep_duration", result.Timing.Sleep,
 		"rate_limit_hits", rateLimitCounter,
+		"cancelled", result.Cancelled,
+		"deferred_count", len(result.Deferred),
+		"sequential", sequential,
 		"kms_validation_cached", true,
-		"batch_resource_delay_ms", s.config.BatchResourceDelay.Milliseconds(),
-		"batch_group_delay_ms", s.config.BatchGroupDelay.Milliseconds(),
+		"batch_resource_delay_ms", resourceDelay.Milliseconds(),
+		"batch_group_delay_ms", groupDelay.Milliseconds(),
 		"performance_improvement", "eliminated_repeated_kms_validation",
 		"audit_action", "batch_remediation_complete")
 
 	// Publish metrics to CloudWatch
 	if s.metricsService != nil {
 		metrics := MetricsData{
-			LogGroupsProcessed:  result.TotalProcessed,
-			LogGroupsRemediated: result.SuccessCount,
-			RemediationErrors:   result.FailureCount,
+			LogGroupsProcessed:   result.TotalProcessed,
+			LogGroupsRemediated:  result.SuccessCount - result.NoActionCount - result.SkippedCount,
+			RemediationErrors:    result.FailureCount,
+			RemediationDurations: durations,
 		}
 
 		if err := s.metricsService.PublishBatchMetrics(ctx, metrics); err != nil {
 			// Log error but don't fail the operation
-			slog.Warn("Failed to publish batch metrics", "error", err)
+			s.getLogger().Warn("Failed to publish batch metrics", "error", err)
 		}
 	}
 
 	return result, nil
 }
 
-// remediateLogGroupWithBatchContext applies compliance remediation using pre-validated batch context
+// runsSequentially reports whether a run of n resources is below PARALLEL_THRESHOLD and can be
+// remediated inline. Runs with a batch timeout or soft time budget always use the parallel path,
+// which is what lets the timeout return while a resource is still in flight and the budget stop
+// dispatching further batches.
+func (s *ComplianceService) runsSequentially(n int) bool {
+	return n < s.config.ParallelThreshold && s.config.BatchTimeout <= 0 && s.config.SoftTimeBudget <= 0
+}
+
+// rejectRegionMismatches records resources whose region differs from the batch region as failed
+// results and returns the resources that belong to the batch region. Resources without a region
+// are assumed to belong to it.
+func (s *ComplianceService) rejectRegionMismatches(request types.BatchComplianceRequest, result *types.BatchRemediationResult) []types.NonCompliantResource {
+	batchRegion := request.Region
+	if batchRegion == "" {
+		batchRegion = s.getCurrentRegion()
+	}
+
+	resources := make([]types.NonCompliantResource, 0, len(request.NonCompliantResults))
+	for _, resource := range request.NonCompliantResults {
+		if resource.Region == "" || resource.Region == batchRegion {
+			resources = append(resources, resource)
+			continue
+		}
+
+		s.getLogger().Error("Resource region does not match batch region, skipping remediation",
+			"log_group", resource.ResourceName,
+			"resource_region", resource.Region,
+			"batch_region", batchRegion,
+			"config_rule", request.ConfigRuleName,
+			"audit_action", "batch_region_mismatch")
+		result.FailureCount++
+		result.Results = append(result.Results, types.RemediationResult{
+			LogGroupName: resource.ResourceName,
+			Region:       resource.Region,
+			Success:      false,
+			Error:        fmt.Errorf(BatchRegionMismatchTemplate, resource.ResourceName, resource.Region, batchRegion),
+		})
+	}
+	return resources
+}
+
+// waitWithTimeout waits for wg, reporting false if timeout elapses first; a zero timeout waits indefinitely
+func waitWithTimeout(wg *sync.WaitGroup, timeout time.Duration) bool {
+	if timeout <= 0 {
+		wg.Wait()
+		return true
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// sleepWithContext waits for the given duration, returning false early if the context is cancelled
+func sleepWithContext(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// remediateLogGroupWithBatchContext applies compliance remediation using pre-validated batch context.
+// compliance carries the live log group state whenever it could be read; without it the guards skip
+// the changes they cannot verify.
 func (s *ComplianceService) remediateLogGroupWithBatchContext(ctx context.Context, compliance types.ComplianceResult, batchCtx *BatchRemediationContext) (*types.RemediationResult, error) {
 	result := &types.RemediationResult{
 		LogGroupName: compliance.LogGroupName,
@@ -329,45 +696,75 @@ func (s *ComplianceService) remediateLogGroupWithBatchContext(ctx context.Contex
 		Success:      true,
 	}
 
-	slog.Info("Starting optimized remediation with batch context",
+	s.getLogger().Info("Starting optimized remediation with batch context",
 		"log_group", compliance.LogGroupName,
 		"region", compliance.Region,
 		"dry_run", batchCtx.dryRun,
 		"kms_pre_validated", batchCtx.kmsCache.keyInfo != nil)
+	defer func() {
+		// Encryption is only applied with validated key info, so its ARN is known whenever it matters
+		var keyArn string
+		if batchCtx.kmsCache.keyInfo != nil {
+			keyArn = batchCtx.kmsCache.keyInfo.Arn
+		}
+		s.recordAudit(ctx, compliance, result, keyArn, batchCtx.retentionDays)
+	}()
 
-	// Apply KMS encryption if missing (using pre-validated KMS info)
+	// Apply KMS encryption if missing (using pre-validated KMS info), unless the re-key policy keeps an existing key
 	if compliance.MissingEncryption {
-		if err := s.applyEncryptionWithBatchContext(ctx, compliance.LogGroupName, batchCtx); err != nil {
+		skip, skipReason, err := s.applyRekeyPolicy(ctx, compliance, batchCtx.kmsCache.keyAlias, batchCtx.kmsCache.keyInfo)
+		if err == nil && !skip {
+			err = s.applyEncryptionWithBatchContext(ctx, compliance.LogGroupName, compliance.AccountId, batchCtx)
+		}
+		if err != nil {
 			result.Success = false
 			result.Error = fmt.Errorf("failed to apply encryption: %w", err)
 			return result, err
 		}
-		result.EncryptionApplied = true
-		slog.Info("Applied KMS encryption using batch context",
-			"log_group", compliance.LogGroupName,
-			"kms_key_id", batchCtx.kmsCache.keyInfo.KeyId)
+		if skip {
+			addSkipReason(result, skipReason)
+		} else {
+			result.EncryptionApplied = true
+			s.getLogger().Info("Applied KMS encryption using batch context",
+				"log_group", compliance.LogGroupName,
+				"kms_key_id", batchCtx.kmsCache.keyInfo.KeyId)
+		}
 	}
 
-	// Apply retention policy if missing (no optimization needed here, but using batch context for consistency)
+	// Apply retention policy if missing (no optimization needed here, but using batch context for consistency),
+	// unless it would shorten retention on a data-protected group
 	if compliance.MissingRetention {
-		if err := s.applyRetentionPolicyWithBatchContext(ctx, compliance.LogGroupName, batchCtx); err != nil {
+		applied := false
+		var err error
+		skipReason := s.checkRetentionReduction(compliance, batchCtx.retentionDays)
+		if skipReason == "" {
+			applied, err = s.applyRetentionPolicyWithBatchContext(ctx, compliance, batchCtx)
+		}
+		if err != nil {
 			result.Success = false
 			result.Error = fmt.Errorf("failed to apply retention policy: %w", err)
 			return result, err
 		}
-		result.RetentionApplied = true
-		slog.Info("Applied retention policy using batch context",
-			"log_group", compliance.LogGroupName,
-			"retention_days", batchCtx.retentionDays)
+		if skipReason != "" {
+			addSkipReason(result, skipReason)
+		} else if applied {
+			result.RetentionApplied = true
+			s.getLogger().Info("Applied retention policy using batch context",
+				"log_group", compliance.LogGroupName,
+				"retention_days", batchCtx.retentionDays)
+		}
 	}
 
+	result.NoActionNeeded = !result.EncryptionApplied && !result.RetentionApplied && result.SkipReason == ""
+	s.tagLastAction(ctx, compliance, result)
+
 	return result, nil
 }
 
 // applyEncryptionWithBatchContext applies KMS encryption using pre-validated batch context
-func (s *ComplianceService) applyEncryptionWithBatchContext(ctx context.Context, logGroupName string, batchCtx *BatchRemediationContext) error {
+func (s *ComplianceService) applyEncryptionWithBatchContext(ctx context.Context, logGroupName, accountId string, batchCtx *BatchRemediationContext) error {
 	if batchCtx.dryRun {
-		slog.Info("DRY RUN: Would apply KMS encryption with batch context",
+		s.getLogger().Info("DRY RUN: Would apply KMS encryption with batch context",
 			"log_group", logGroupName,
 			"kms_key_alias", batchCtx.kmsCache.keyAlias,
 			"kms_key_id", batchCtx.kmsCache.keyInfo.KeyId,
@@ -379,10 +776,14 @@ func (s *ComplianceService) applyEncryptionWithBatchContext(ctx context.Context,
 	// Get pre-validated KMS key info from batch context
 	keyInfo, err := batchCtx.GetValidatedKMSKeyInfo()
 	if err != nil {
-		return fmt.Errorf("failed to get validated KMS key info: %w", err)
+		return auditError(FailureStageKeyValidation, "", logGroupName, err)
+	}
+
+	if err := s.validateKMSKeyAccount(keyInfo, logGroupName, accountId); err != nil {
+		return err
 	}
 
-	slog.Info("Applying KMS encryption with pre-validated key info",
+	s.getLogger().Info("Applying KMS encryption with pre-validated key info",
 		"log_group", logGroupName,
 		"kms_key_id", keyInfo.KeyId,
 		"kms_key_arn", keyInfo.Arn,
@@ -391,15 +792,16 @@ func (s *ComplianceService) applyEncryptionWithBatchContext(ctx context.Context,
 
 	// Associate KMS key with retry logic (same as before)
 	if err := s.associateKMSKeyWithRetry(ctx, logGroupName, keyInfo.Arn); err != nil {
-		slog.Error("Failed to associate KMS key with batch context",
+		s.invalidateKMSKeyInfo(batchCtx.kmsCache.keyAlias, err)
+		s.getLogger().Error("Failed to associate KMS key with batch context",
 			"log_group", logGroupName,
 			"kms_key_arn", keyInfo.Arn,
 			"error", err,
 			"audit_action", AuditActionEncryptionFailed)
-		return fmt.Errorf("failed to associate KMS key %s with log group %s: %w", keyInfo.Arn, logGroupName, err)
+		return auditError(FailureStageKeyAssociation, "", logGroupName, fmt.Errorf("failed to associate KMS key %s: %w", keyInfo.Arn, err))
 	}
 
-	slog.Info("Successfully applied KMS encryption with batch optimization",
+	s.getLogger().Info("Successfully applied KMS encryption with batch optimization",
 		"log_group", logGroupName,
 		"kms_key_id", keyInfo.KeyId,
 		"kms_key_arn", keyInfo.Arn,
@@ -409,14 +811,26 @@ func (s *ComplianceService) applyEncryptionWithBatchContext(ctx context.Context,
 	return nil
 }
 
-// applyRetentionPolicyWithBatchContext applies retention policy using batch context
-func (s *ComplianceService) applyRetentionPolicyWithBatchContext(ctx context.Context, logGroupName string, batchCtx *BatchRemediationContext) error {
+// applyRetentionPolicyWithBatchContext applies retention policy using batch context and reports whether
+// it was applied. When the live log group already has the target retention, PutRetentionPolicy is
+// skipped so a stale Config evaluation does not cause a spurious change.
+func (s *ComplianceService) applyRetentionPolicyWithBatchContext(ctx context.Context, compliance types.ComplianceResult, batchCtx *BatchRemediationContext) (bool, error) {
+	logGroupName := compliance.LogGroupName
+	if compliance.LiveState && compliance.CurrentRetention != nil && *compliance.CurrentRetention == batchCtx.retentionDays {
+		s.getLogger().Info("Retention already matches target, skipping retention policy update",
+			"log_group", logGroupName,
+			"retention_days", batchCtx.retentionDays,
+			"batch_optimized", true,
+			"audit_action", AuditActionRetentionAlreadyCompliant)
+		return false, nil
+	}
+
 	if batchCtx.dryRun {
-		slog.Info("DRY RUN: Would apply retention policy with batch context",
+		s.getLogger().Info("DRY RUN: Would apply retention policy with batch context",
 			"log_group", logGroupName,
 			"retention_days", batchCtx.retentionDays,
 			"batch_optimized", true)
-		return nil
+		return true, nil
 	}
 
 	input := &cloudwatchlogs.PutRetentionPolicyInput{
@@ -426,13 +840,13 @@ func (s *ComplianceService) applyRetentionPolicyWithBatchContext(ctx context.Con
 
 	_, err := s.logsClient.PutRetentionPolicy(ctx, input)
 	if err != nil {
-		return fmt.Errorf("failed to set retention policy for log group %s: %w", logGroupName, err)
+		return false, auditError(FailureStageRetentionPolicy, "", logGroupName, err)
 	}
 
-	slog.Info("Successfully set retention policy with batch optimization",
+	s.getLogger().Info("Successfully set retention policy with batch optimization",
 		"log_group", logGroupName,
 		"retention_days", batchCtx.retentionDays,
 		"batch_optimized", true)
 
-	return nil
+	return true, nil
 }
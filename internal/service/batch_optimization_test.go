@@ -3,15 +3,21 @@ package service
 import (
 	"context"
 	"errors"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cloudwatchtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	cloudwatchlogstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
 	"github.com/aws/aws-sdk-go-v2/service/kms"
 	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/zsoftly/logguardian/internal/testutil"
 	"github.com/zsoftly/logguardian/internal/types"
 )
 
@@ -35,6 +41,11 @@ func (m *MockKMSClientOptimized) ListGrants(ctx context.Context, params *kms.Lis
 	return args.Get(0).(*kms.ListGrantsOutput), args.Error(1)
 }
 
+func (m *MockKMSClientOptimized) ListKeyRotations(ctx context.Context, params *kms.ListKeyRotationsInput, optFns ...func(*kms.Options)) (*kms.ListKeyRotationsOutput, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(*kms.ListKeyRotationsOutput), args.Error(1)
+}
+
 // MockLogsClient for testing batch optimization
 type MockLogsClientOptimized struct {
 	mock.Mock
@@ -52,9 +63,25 @@ func (m *MockLogsClientOptimized) PutRetentionPolicy(ctx context.Context, params
 
 func (m *MockLogsClientOptimized) DescribeLogGroups(ctx context.Context, params *cloudwatchlogs.DescribeLogGroupsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeLogGroupsOutput, error) {
 	args := m.Called(ctx, params)
+	if describe, ok := args.Get(0).(func(*cloudwatchlogs.DescribeLogGroupsInput) *cloudwatchlogs.DescribeLogGroupsOutput); ok {
+		return describe(params), args.Error(1)
+	}
 	return args.Get(0).(*cloudwatchlogs.DescribeLogGroupsOutput), args.Error(1)
 }
 
+// describeRequestedLogGroup answers DescribeLogGroups with an unencrypted, unprotected log group
+// named by the requested prefix, so every remediated group exists
+func describeRequestedLogGroup(params *cloudwatchlogs.DescribeLogGroupsInput) *cloudwatchlogs.DescribeLogGroupsOutput {
+	return &cloudwatchlogs.DescribeLogGroupsOutput{
+		LogGroups: []cloudwatchlogstypes.LogGroup{{LogGroupName: params.LogGroupNamePrefix}},
+	}
+}
+
+func (m *MockLogsClientOptimized) TagResource(ctx context.Context, params *cloudwatchlogs.TagResourceInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.TagResourceOutput, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(*cloudwatchlogs.TagResourceOutput), args.Error(1)
+}
+
 func TestBatchKMSValidationCache(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -84,6 +111,7 @@ func TestBatchKMSValidationCache(t *testing.T) {
 			// Setup mocks
 			mockKMS := new(MockKMSClientOptimized)
 			mockLogs := new(MockLogsClientOptimized)
+			mockLogs.On("DescribeLogGroups", mock.Anything, mock.Anything).Return(describeRequestedLogGroup, nil).Maybe()
 
 			service := &ComplianceService{
 				kmsClient:      mockKMS,
@@ -204,6 +232,7 @@ func TestProcessNonCompliantResourcesOptimized(t *testing.T) {
 			// Setup mocks
 			mockKMS := new(MockKMSClientOptimized)
 			mockLogs := new(MockLogsClientOptimized)
+			mockLogs.On("DescribeLogGroups", mock.Anything, mock.Anything).Return(describeRequestedLogGroup, nil).Maybe()
 
 			service := &ComplianceService{
 				kmsClient:      mockKMS,
@@ -278,6 +307,583 @@ func TestProcessNonCompliantResourcesOptimized(t *testing.T) {
 	}
 }
 
+func TestProcessNonCompliantResourcesOptimized_RuleNotApplicable(t *testing.T) {
+	mockLogs := new(MockLogsClientOptimized)
+	mockLogs.On("DescribeLogGroups", mock.Anything, mock.Anything).Return(describeRequestedLogGroup, nil).Maybe()
+
+	service := &ComplianceService{
+		kmsClient:      new(MockKMSClientOptimized),
+		logsClient:     mockLogs,
+		ruleClassifier: types.NewRuleClassifier(),
+		config: ServiceConfig{
+			DefaultRetentionDays: 365,
+			Region:               "ca-central-1",
+		},
+	}
+
+	// Unsupported rules evaluate nothing, so every resource is skipped rather than succeeding
+	request := types.BatchComplianceRequest{
+		ConfigRuleName: "some-unsupported-rule",
+		Region:         "ca-central-1",
+		NonCompliantResults: []types.NonCompliantResource{
+			{ResourceName: "/aws/lambda/test1", ResourceType: "AWS::Logs::LogGroup"},
+			{ResourceName: "/aws/lambda/test2", ResourceType: "AWS::Logs::LogGroup"},
+		},
+		BatchSize: 2,
+	}
+
+	result, err := service.ProcessNonCompliantResourcesOptimized(context.Background(), request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.TotalProcessed)
+	assert.Equal(t, 0, result.SuccessCount)
+	assert.Equal(t, 0, result.NoActionCount)
+	assert.Equal(t, 0, result.FailureCount)
+	assert.Equal(t, 2, result.NotApplicableCount)
+	require.Len(t, result.Results, 2)
+	for _, r := range result.Results {
+		assert.True(t, r.Success)
+		assert.False(t, r.NoActionNeeded)
+		assert.Equal(t, string(SkipReasonRuleNotApplicable), r.SkipReason)
+		assert.False(t, r.EncryptionApplied)
+		assert.False(t, r.RetentionApplied)
+	}
+
+	// No remediation API calls should have been made
+	mockLogs.AssertExpectations(t)
+}
+
+func TestConvertToComplianceResultForRule_Applicability(t *testing.T) {
+	service := &ComplianceService{ruleClassifier: types.NewRuleClassifier()}
+	resource := types.NonCompliantResource{ResourceName: "/aws/lambda/test", Region: "ca-central-1"}
+
+	encryption := service.convertToComplianceResultForRule("cw-lg-kms-encryption", resource)
+	assert.False(t, encryption.NotApplicable)
+	assert.True(t, encryption.MissingEncryption)
+
+	unknown := service.convertToComplianceResultForRule("some-unsupported-rule", resource)
+	assert.True(t, unknown.NotApplicable)
+	assert.False(t, unknown.MissingEncryption)
+	assert.False(t, unknown.MissingRetention)
+}
+
+func TestRemediateLogGroupWithBatchContext_RetentionAlreadyMatchesTarget(t *testing.T) {
+	tests := []struct {
+		name             string
+		currentRetention *int32
+		expectPut        bool
+	}{
+		{name: "equal retention is a no-op", currentRetention: aws.Int32(365), expectPut: false},
+		{name: "different retention is updated", currentRetention: aws.Int32(30), expectPut: true},
+		{name: "missing retention is set", currentRetention: nil, expectPut: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockLogs := new(MockLogsClientOptimized)
+			mockLogs.On("DescribeLogGroups", mock.Anything, mock.Anything).Return(describeRequestedLogGroup, nil).Maybe()
+			if tt.expectPut {
+				mockLogs.On("PutRetentionPolicy", mock.Anything, mock.MatchedBy(func(input *cloudwatchlogs.PutRetentionPolicyInput) bool {
+					return aws.ToInt32(input.RetentionInDays) == 365
+				})).Return(&cloudwatchlogs.PutRetentionPolicyOutput{}, nil).Once()
+			}
+
+			service := &ComplianceService{
+				logsClient:     mockLogs,
+				ruleClassifier: types.NewRuleClassifier(),
+				config: ServiceConfig{
+					DefaultRetentionDays: 365,
+					Region:               "ca-central-1",
+				},
+			}
+			batchCtx := &BatchRemediationContext{
+				retentionDays: 365,
+				kmsCache:      &BatchKMSValidationCache{},
+			}
+			compliance := types.ComplianceResult{
+				LogGroupName:         "/aws/lambda/live-state",
+				Region:               "ca-central-1",
+				MissingRetention:     true,
+				CurrentRetention:     tt.currentRetention,
+				DataProtectionStatus: "DISABLED",
+				LiveState:            true,
+			}
+
+			result, err := service.remediateLogGroupWithBatchContext(context.Background(), compliance, batchCtx)
+
+			assert.NoError(t, err)
+			assert.True(t, result.Success)
+			assert.Equal(t, tt.expectPut, result.RetentionApplied)
+			assert.Equal(t, !tt.expectPut, result.NoActionNeeded)
+			mockLogs.AssertExpectations(t)
+			if !tt.expectPut {
+				mockLogs.AssertNotCalled(t, "PutRetentionPolicy", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestProcessNonCompliantResourcesOptimized_RetentionAlreadyMatchesLiveState(t *testing.T) {
+	mockLogs := &MockCloudWatchLogsClient{LogGroups: []cloudwatchlogstypes.LogGroup{
+		{LogGroupName: aws.String("/aws/lambda/test-0"), RetentionInDays: aws.Int32(365)},
+		{LogGroupName: aws.String("/aws/lambda/test-1"), RetentionInDays: aws.Int32(30)},
+	}}
+	service := &ComplianceService{
+		logsClient:     mockLogs,
+		ruleClassifier: types.NewRuleClassifier(),
+		config: ServiceConfig{
+			DefaultRetentionDays: 365,
+			Region:               "ca-central-1",
+		},
+	}
+
+	result, err := service.ProcessNonCompliantResourcesOptimized(context.Background(), testutil.NewTestBatchComplianceRequest(2))
+
+	require.NoError(t, err)
+	require.Len(t, result.Results, 2)
+	assert.Equal(t, 2, result.SuccessCount)
+	assert.Equal(t, 1, result.NoActionCount)
+	assert.True(t, result.Results[0].NoActionNeeded)
+	assert.False(t, result.Results[0].RetentionApplied)
+	assert.True(t, result.Results[1].RetentionApplied)
+	require.NotNil(t, mockLogs.PutRetentionPolicyInput)
+	assert.Equal(t, "/aws/lambda/test-1", aws.ToString(mockLogs.PutRetentionPolicyInput.LogGroupName))
+}
+
+func TestProcessNonCompliantResourcesOptimized_BlockedReductionCountedAsSkipped(t *testing.T) {
+	mockLogs := &MockCloudWatchLogsClient{LogGroups: []cloudwatchlogstypes.LogGroup{
+		{
+			LogGroupName:         aws.String("/aws/lambda/test-0"),
+			RetentionInDays:      aws.Int32(3653),
+			DataProtectionStatus: cloudwatchlogstypes.DataProtectionStatusActivated,
+		},
+		{LogGroupName: aws.String("/aws/lambda/test-1"), RetentionInDays: aws.Int32(365)},
+	}}
+	service := &ComplianceService{
+		logsClient:     mockLogs,
+		ruleClassifier: types.NewRuleClassifier(),
+		config: ServiceConfig{
+			DefaultRetentionDays: 365,
+			Region:               "ca-central-1",
+		},
+	}
+
+	result, err := service.ProcessNonCompliantResourcesOptimized(context.Background(), testutil.NewTestBatchComplianceRequest(2))
+
+	require.NoError(t, err)
+	require.Len(t, result.Results, 2)
+	assert.Equal(t, 2, result.SuccessCount)
+	assert.Equal(t, 1, result.SkippedCount)
+	assert.Equal(t, 1, result.NoActionCount)
+	assert.False(t, result.Results[0].NoActionNeeded)
+	assert.NotEmpty(t, result.Results[0].SkipReason)
+	assert.True(t, result.Results[1].NoActionNeeded)
+	assert.Nil(t, mockLogs.PutRetentionPolicyInput)
+}
+
+func TestProcessNonCompliantResourcesOptimized_DryRunMakesApplyDecisions(t *testing.T) {
+	mockLogs := &MockCloudWatchLogsClient{LogGroups: []cloudwatchlogstypes.LogGroup{
+		{
+			LogGroupName:         aws.String("/aws/lambda/test-0"),
+			RetentionInDays:      aws.Int32(3653),
+			DataProtectionStatus: cloudwatchlogstypes.DataProtectionStatusActivated,
+		},
+		{LogGroupName: aws.String("/aws/lambda/test-1"), RetentionInDays: aws.Int32(365)},
+		{LogGroupName: aws.String("/aws/lambda/test-2"), RetentionInDays: aws.Int32(30)},
+	}}
+	service := &ComplianceService{
+		logsClient:     mockLogs,
+		ruleClassifier: types.NewRuleClassifier(),
+		config: ServiceConfig{
+			DefaultRetentionDays: 365,
+			Region:               "ca-central-1",
+			DryRun:               true,
+		},
+	}
+
+	result, err := service.ProcessNonCompliantResourcesOptimized(context.Background(), testutil.NewTestBatchComplianceRequest(3))
+
+	require.NoError(t, err)
+	require.Len(t, result.Results, 3)
+	assert.NotEmpty(t, result.Results[0].SkipReason, "blocked reduction is skipped as in apply mode")
+	assert.True(t, result.Results[1].NoActionNeeded, "matching retention needs no change as in apply mode")
+	assert.True(t, result.Results[2].RetentionApplied)
+	assert.Nil(t, mockLogs.PutRetentionPolicyInput, "dry run changes nothing")
+}
+
+func TestProcessNonCompliantResourcesOptimized_RequestTargetRetention(t *testing.T) {
+	mockLogs := &MockCloudWatchLogsClient{LogGroups: []cloudwatchlogstypes.LogGroup{
+		{LogGroupName: aws.String("/aws/lambda/test-0"), RetentionInDays: aws.Int32(30)},
+	}}
+	service := &ComplianceService{
+		logsClient:     mockLogs,
+		ruleClassifier: types.NewRuleClassifier(),
+		config: ServiceConfig{
+			DefaultRetentionDays: 365,
+			Region:               "ca-central-1",
+		},
+	}
+	request := testutil.NewTestBatchComplianceRequest(1)
+	request.TargetRetentionDays = aws.Int32(731)
+
+	result, err := service.ProcessNonCompliantResourcesOptimized(context.Background(), request)
+
+	require.NoError(t, err)
+	require.Len(t, result.Results, 1)
+	assert.True(t, result.Results[0].RetentionApplied)
+	require.NotNil(t, mockLogs.PutRetentionPolicyInput)
+	assert.Equal(t, int32(731), aws.ToInt32(mockLogs.PutRetentionPolicyInput.RetentionInDays))
+}
+
+func TestProcessNonCompliantResourcesOptimized_ContextEndsAfterCompletion(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockLogs := new(MockLogsClientOptimized)
+	mockLogs.On("DescribeLogGroups", mock.Anything, mock.Anything).
+		Return(describeRequestedLogGroup, nil)
+	// The context expires as the last resource's remediation returns
+	mockLogs.On("PutRetentionPolicy", mock.Anything, mock.AnythingOfType("*cloudwatchlogs.PutRetentionPolicyInput")).
+		Run(func(mock.Arguments) { cancel() }).
+		Return(&cloudwatchlogs.PutRetentionPolicyOutput{}, nil)
+
+	service := &ComplianceService{
+		kmsClient:      new(MockKMSClientOptimized),
+		logsClient:     mockLogs,
+		ruleClassifier: types.NewRuleClassifier(),
+		config: ServiceConfig{
+			DefaultRetentionDays: 365,
+			Region:               "ca-central-1",
+		},
+	}
+
+	request := testutil.NewTestBatchComplianceRequest(1)
+	result, err := service.ProcessNonCompliantResourcesOptimized(ctx, request)
+
+	require.NoError(t, err)
+	require.Error(t, ctx.Err())
+	assert.False(t, result.Cancelled, "a run whose every resource finished is not cancelled")
+	assert.Equal(t, 1, result.TotalProcessed)
+	assert.Equal(t, 1, result.SuccessCount)
+	require.Len(t, result.Results, 1)
+}
+
+func TestProcessNonCompliantResourcesOptimized_CancelledMidRun(t *testing.T) {
+	mockLogs := new(MockLogsClientOptimized)
+	mockLogs.On("DescribeLogGroups", mock.Anything, mock.Anything).
+		Return(describeRequestedLogGroup, nil)
+	mockLogs.On("PutRetentionPolicy", mock.Anything, mock.AnythingOfType("*cloudwatchlogs.PutRetentionPolicyInput")).
+		Return(&cloudwatchlogs.PutRetentionPolicyOutput{}, nil)
+
+	service := &ComplianceService{
+		kmsClient:      new(MockKMSClientOptimized),
+		logsClient:     mockLogs,
+		ruleClassifier: types.NewRuleClassifier(),
+		config: ServiceConfig{
+			DefaultRetentionDays: 365,
+			Region:               "ca-central-1",
+			BatchResourceDelay:   100 * time.Millisecond,
+			BatchGroupDelay:      100 * time.Millisecond,
+		},
+	}
+
+	request := testutil.NewTestBatchComplianceRequest(20, testutil.WithBatchSize(2))
+
+	// An uncancelled run needs at least 10 inter-batch delays (~1s)
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	result, err := service.ProcessNonCompliantResourcesOptimized(ctx, request)
+	elapsed := time.Since(start)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.True(t, result.Cancelled)
+	assert.Less(t, elapsed, 500*time.Millisecond, "Expected prompt return after cancellation")
+
+	// Completed work is preserved and counts match the partial results
+	assert.Greater(t, result.SuccessCount, 0)
+	assert.Less(t, result.SuccessCount, len(request.NonCompliantResults))
+	assert.Equal(t, len(result.Results), result.SuccessCount+result.FailureCount)
+	assert.Equal(t, len(result.Results), result.TotalProcessed)
+}
+
+func TestProcessNonCompliantResourcesOptimized_FailFast(t *testing.T) {
+	tests := []struct {
+		name            string
+		failFast        bool
+		expectedResults int
+		expectedAborted bool
+	}{
+		{name: "fail-fast stops after first failure", failFast: true, expectedResults: 1, expectedAborted: true},
+		{name: "default continues on error", failFast: false, expectedResults: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockLogs := new(MockLogsClientOptimized)
+			mockLogs.On("DescribeLogGroups", mock.Anything, mock.Anything).
+				Return(describeRequestedLogGroup, nil)
+			mockLogs.On("PutRetentionPolicy", mock.Anything, mock.AnythingOfType("*cloudwatchlogs.PutRetentionPolicyInput")).
+				Return((*cloudwatchlogs.PutRetentionPolicyOutput)(nil), errors.New("AccessDeniedException: not authorized"))
+
+			service := &ComplianceService{
+				kmsClient:      new(MockKMSClientOptimized),
+				logsClient:     mockLogs,
+				ruleClassifier: types.NewRuleClassifier(),
+				config: ServiceConfig{
+					DefaultRetentionDays: 365,
+					Region:               "ca-central-1",
+					FailFast:             tt.failFast,
+				},
+			}
+
+			request := testutil.NewTestBatchComplianceRequest(5)
+			result, err := service.ProcessNonCompliantResourcesOptimized(context.Background(), request)
+
+			assert.NoError(t, err)
+			assert.Len(t, result.Results, tt.expectedResults)
+			assert.Equal(t, tt.expectedResults, result.FailureCount)
+			assert.Equal(t, tt.expectedResults, result.TotalProcessed)
+			assert.Equal(t, tt.expectedAborted, result.AbortedOnFailure)
+			assert.Equal(t, tt.expectedAborted, result.Cancelled)
+			mockLogs.AssertNumberOfCalls(t, "PutRetentionPolicy", tt.expectedResults)
+		})
+	}
+}
+
+func TestProcessNonCompliantResourcesOptimized_BatchTimeout(t *testing.T) {
+	mockLogs := new(MockLogsClientOptimized)
+	mockLogs.On("DescribeLogGroups", mock.Anything, mock.Anything).
+		Return(describeRequestedLogGroup, nil)
+	mockLogs.On("PutRetentionPolicy", mock.Anything, mock.MatchedBy(func(params *cloudwatchlogs.PutRetentionPolicyInput) bool {
+		return aws.ToString(params.LogGroupName) == "/aws/lambda/test-0"
+	})).Return(&cloudwatchlogs.PutRetentionPolicyOutput{}, nil)
+	// Deliberately stuck calls that ignore context cancellation
+	mockLogs.On("PutRetentionPolicy", mock.Anything, mock.AnythingOfType("*cloudwatchlogs.PutRetentionPolicyInput")).
+		After(2*time.Second).Return(&cloudwatchlogs.PutRetentionPolicyOutput{}, nil)
+
+	service := &ComplianceService{
+		kmsClient:      new(MockKMSClientOptimized),
+		logsClient:     mockLogs,
+		ruleClassifier: types.NewRuleClassifier(),
+		config: ServiceConfig{
+			DefaultRetentionDays: 365,
+			Region:               "ca-central-1",
+			BatchTimeout:         100 * time.Millisecond,
+		},
+	}
+
+	request := testutil.NewTestBatchComplianceRequest(3, testutil.WithBatchSize(1))
+
+	start := time.Now()
+	result, err := service.ProcessNonCompliantResourcesOptimized(context.Background(), request)
+	elapsed := time.Since(start)
+
+	assert.NoError(t, err)
+	assert.Less(t, elapsed, time.Second, "Expected the batch timeout to bound the wait")
+	assert.True(t, result.TimedOut)
+	assert.Equal(t, 1, result.SuccessCount)
+	assert.Equal(t, 2, result.FailureCount)
+	assert.Len(t, result.Results, 3)
+	assert.Equal(t, 3, result.TotalProcessed)
+
+	timedOut := 0
+	for _, r := range result.Results {
+		if !r.Success {
+			assert.ErrorContains(t, r.Error, "did not complete within batch timeout")
+			timedOut++
+		}
+	}
+	assert.Equal(t, 2, timedOut)
+}
+
+func TestProcessNonCompliantResourcesOptimized_BatchTimeoutReportsQueued(t *testing.T) {
+	mockLogs := new(MockLogsClientOptimized)
+	mockLogs.On("DescribeLogGroups", mock.Anything, mock.Anything).
+		Return(describeRequestedLogGroup, nil)
+	mockLogs.On("PutRetentionPolicy", mock.Anything, mock.MatchedBy(func(params *cloudwatchlogs.PutRetentionPolicyInput) bool {
+		return aws.ToString(params.LogGroupName) == "/aws/lambda/test-0"
+	})).Return(&cloudwatchlogs.PutRetentionPolicyOutput{}, nil)
+	// The second resource is stuck, so the third waits behind it in the same batch
+	mockLogs.On("PutRetentionPolicy", mock.Anything, mock.AnythingOfType("*cloudwatchlogs.PutRetentionPolicyInput")).
+		After(2*time.Second).Return(&cloudwatchlogs.PutRetentionPolicyOutput{}, nil)
+
+	service := &ComplianceService{
+		kmsClient:      new(MockKMSClientOptimized),
+		logsClient:     mockLogs,
+		ruleClassifier: types.NewRuleClassifier(),
+		config: ServiceConfig{
+			DefaultRetentionDays: 365,
+			Region:               "ca-central-1",
+			BatchTimeout:         100 * time.Millisecond,
+		},
+	}
+
+	request := testutil.NewTestBatchComplianceRequest(3, testutil.WithBatchSize(3))
+
+	result, err := service.ProcessNonCompliantResourcesOptimized(context.Background(), request)
+
+	assert.NoError(t, err)
+	assert.True(t, result.TimedOut)
+	assert.Equal(t, 3, result.TotalProcessed)
+	assert.Equal(t, 1, result.SuccessCount)
+	assert.Equal(t, 2, result.FailureCount)
+	require.Len(t, result.Results, 3)
+
+	errorsByGroup := make(map[string]string, 2)
+	for _, r := range result.Results {
+		if !r.Success {
+			errorsByGroup[r.LogGroupName] = r.Error.Error()
+		}
+	}
+	assert.Contains(t, errorsByGroup["/aws/lambda/test-1"], "did not complete within batch timeout")
+	assert.Contains(t, errorsByGroup["/aws/lambda/test-2"], "was not started within batch timeout")
+}
+
+func TestProcessNonCompliantResourcesOptimized_SoftTimeBudget(t *testing.T) {
+	service := newTimedEncryptionService()
+	service.config.SoftTimeBudget = 30 * time.Millisecond
+	// The first batch starts within the budget; the group delay spends it before the next one
+	service.config.BatchGroupDelay = 100 * time.Millisecond
+
+	request := testutil.NewTestBatchComplianceRequest(3,
+		testutil.WithRuleName("cloudwatch-log-group-encrypted"),
+		testutil.WithBatchSize(1))
+
+	result, err := service.ProcessNonCompliantResourcesOptimized(context.Background(), request)
+
+	require.NoError(t, err)
+	assert.False(t, result.Cancelled)
+	assert.Equal(t, 1, result.TotalProcessed)
+	assert.Equal(t, 1, result.SuccessCount)
+	require.Len(t, result.Results, 1)
+	assert.Equal(t, "/aws/lambda/test-0", result.Results[0].LogGroupName)
+	assert.Equal(t, []string{"/aws/lambda/test-1", "/aws/lambda/test-2"}, result.Deferred)
+}
+
+// steppingClock advances by a fixed step every time it is read
+type steppingClock struct {
+	mu      sync.Mutex
+	current time.Time
+	step    time.Duration
+}
+
+func (c *steppingClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.current = c.current.Add(c.step)
+	return c.current
+}
+
+// newTimedEncryptionService returns a dry-run encryption service whose key validates once
+func newTimedEncryptionService(opts ...ComplianceServiceOption) *ComplianceService {
+	mockKMS := new(MockKMSClientOptimized)
+	mockKMS.On("DescribeKey", mock.Anything, mock.Anything).Return(&kms.DescribeKeyOutput{
+		KeyMetadata: &kmstypes.KeyMetadata{
+			KeyId:    aws.String("key-12345"),
+			Arn:      aws.String("arn:aws:kms:ca-central-1:123456789012:key/key-12345"),
+			KeyState: kmstypes.KeyStateEnabled,
+		},
+	}, nil).Once()
+	mockKMS.On("GetKeyPolicy", mock.Anything, mock.Anything).Return(&kms.GetKeyPolicyOutput{
+		Policy: aws.String(`{"Statement":[{"Effect":"Allow","Principal":{"Service":"logs.amazonaws.com"},"Action":["kms:Encrypt"]}]}`),
+	}, nil).Once()
+
+	mockLogs := new(MockLogsClientOptimized)
+	mockLogs.On("DescribeLogGroups", mock.Anything, mock.Anything).Return(describeRequestedLogGroup, nil).Maybe()
+
+	service := &ComplianceService{
+		kmsClient:      mockKMS,
+		logsClient:     mockLogs,
+		ruleClassifier: types.NewRuleClassifier(),
+		config: ServiceConfig{
+			DefaultKMSKeyAlias:   "alias/test-key",
+			DefaultRetentionDays: 365,
+			DryRun:               true,
+			Region:               "ca-central-1",
+		},
+	}
+	for _, opt := range opts {
+		opt(service)
+	}
+	return service
+}
+
+func TestProcessNonCompliantResourcesOptimized_TimingBreakdown(t *testing.T) {
+	clock := &steppingClock{current: time.Unix(0, 0), step: 10 * time.Millisecond}
+	service := newTimedEncryptionService(WithClock(clock.Now))
+
+	// One batch and no delays keeps every clock read sequential
+	request := testutil.NewTestBatchComplianceRequest(2,
+		testutil.WithRuleName("cloudwatch-log-group-encrypted"),
+		testutil.WithBatchSize(2))
+
+	result, err := service.ProcessNonCompliantResourcesOptimized(context.Background(), request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.SuccessCount)
+	assert.Equal(t, 10*time.Millisecond, result.Timing.KMSValidation)
+	assert.Equal(t, 20*time.Millisecond, result.Timing.Remediation)
+	assert.Zero(t, result.Timing.Sleep)
+	// Batch start, two validation reads, four remediation reads and the run end follow the run start
+	assert.Equal(t, 80*time.Millisecond, result.ProcessingDuration)
+}
+
+func TestProcessNonCompliantResourcesOptimized_TimingBreakdownSums(t *testing.T) {
+	service := newTimedEncryptionService()
+	service.config.BatchResourceDelay = 5 * time.Millisecond
+
+	request := testutil.NewTestBatchComplianceRequest(2,
+		testutil.WithRuleName("cloudwatch-log-group-encrypted"),
+		testutil.WithBatchSize(2))
+
+	result, err := service.ProcessNonCompliantResourcesOptimized(context.Background(), request)
+
+	assert.NoError(t, err)
+	timing := result.Timing
+	assert.Greater(t, timing.KMSValidation, time.Duration(0))
+	assert.Greater(t, timing.Remediation, time.Duration(0))
+	assert.GreaterOrEqual(t, timing.Sleep, 10*time.Millisecond, "Expected both resource delays to count as sleep")
+	// A single batch runs its resources sequentially, so the components fit within the run
+	assert.LessOrEqual(t, timing.KMSValidation+timing.Remediation+timing.Sleep, result.ProcessingDuration)
+}
+
+func TestProcessNonCompliantResourcesOptimized_RegionMismatch(t *testing.T) {
+	service := newTimedEncryptionService()
+
+	request := testutil.NewTestBatchComplianceRequest(2,
+		testutil.WithRuleName("cloudwatch-log-group-encrypted"),
+		testutil.WithBatchSize(10))
+	westResource := testutil.NewTestNonCompliantResource("/aws/lambda/west")
+	westResource.Region = "ca-west-1"
+	request.NonCompliantResults = append(request.NonCompliantResults, westResource)
+
+	result, err := service.ProcessNonCompliantResourcesOptimized(context.Background(), request)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.TotalProcessed)
+	assert.Equal(t, 2, result.SuccessCount)
+	assert.Equal(t, 1, result.FailureCount)
+	require.Len(t, result.Results, 3)
+
+	var mismatched *types.RemediationResult
+	for i := range result.Results {
+		if result.Results[i].LogGroupName == "/aws/lambda/west" {
+			mismatched = &result.Results[i]
+		} else {
+			assert.True(t, result.Results[i].Success)
+		}
+	}
+	require.NotNil(t, mismatched)
+	assert.False(t, mismatched.Success)
+	assert.Equal(t, "ca-west-1", mismatched.Region)
+	require.Error(t, mismatched.Error)
+	assert.Contains(t, mismatched.Error.Error(), "region 'ca-west-1'")
+	assert.Contains(t, mismatched.Error.Error(), "'ca-central-1'")
+}
+
 func TestBatchRemediationContext_GetValidatedKMSKeyInfo(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -343,25 +949,18 @@ func TestBatchRemediationContext_GetValidatedKMSKeyInfo(t *testing.T) {
 
 // Performance benchmark to compare optimized vs non-optimized batch processing
 func BenchmarkBatchProcessing(b *testing.B) {
-	// Create test resources
-	resources := make([]types.NonCompliantResource, 10)
-	for i := 0; i < 10; i++ {
-		resources[i] = types.NonCompliantResource{
-			ResourceName: "/aws/lambda/test-" + string(rune(i)),
-			ResourceType: "AWS::Logs::LogGroup",
-		}
-	}
-
+	// Retention rule request for ten distinct log groups
 	request := types.BatchComplianceRequest{
-		ConfigRuleName:      "cloudwatch-log-group-retention", // Use retention rule for this test
+		ConfigRuleName:      "cloudwatch-log-group-retention",
 		Region:              "ca-central-1",
-		NonCompliantResults: resources,
+		NonCompliantResults: testutil.NewNonCompliantResources(10),
 		BatchSize:           5,
 	}
 
 	// Setup service with mocks
 	mockKMS := new(MockKMSClientOptimized)
 	mockLogs := new(MockLogsClientOptimized)
+	mockLogs.On("DescribeLogGroups", mock.Anything, mock.Anything).Return(describeRequestedLogGroup, nil).Maybe()
 
 	service := &ComplianceService{
 		kmsClient:      mockKMS,
@@ -387,9 +986,11 @@ func BenchmarkBatchProcessing(b *testing.B) {
 	}, nil)
 
 	mockKMS.On("GetKeyPolicy", ctx, mock.Anything).Return(&kms.GetKeyPolicyOutput{
-		Policy: aws.String(`{"Statement":[{"Effect":"Allow","Principal":{"Service":"logs.amazonaws.com"}}]}`),
+		Policy: aws.String(`{"Statement":[{"Effect":"Allow","Principal":{"Service":"logs.amazonaws.com"},"Action":["kms:Encrypt"]}]}`),
 	}, nil)
 
+	mockLogs.On("DescribeLogGroups", ctx, mock.Anything).Return(describeRequestedLogGroup, nil)
+
 	b.ResetTimer()
 
 	b.Run("OptimizedBatchProcessing", func(b *testing.B) {
@@ -401,3 +1002,268 @@ func BenchmarkBatchProcessing(b *testing.B) {
 		}
 	})
 }
+
+func TestProcessNonCompliantResourcesOptimized_Drift(t *testing.T) {
+	const keyArn = "arn:aws:kms:ca-central-1:123456789012:key/key-12345"
+
+	tests := []struct {
+		name           string
+		verifyAfter    bool
+		expectVerified bool
+	}{
+		{name: "projected after state by default"},
+		{name: "live after state with VERIFY_AFTER", verifyAfter: true, expectVerified: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTimedEncryptionService()
+			service.config.DryRun = false
+			service.config.MaxKMSRetries = 1
+			service.config.VerifyAfter = tt.verifyAfter
+
+			mockLogs := new(MockLogsClientOptimized)
+			service.logsClient = mockLogs
+			mockLogs.On("DescribeLogGroups", mock.Anything, mock.Anything).Return(&cloudwatchlogs.DescribeLogGroupsOutput{
+				LogGroups: []cloudwatchlogstypes.LogGroup{{
+					LogGroupName:    aws.String("/aws/lambda/test-0"),
+					RetentionInDays: aws.Int32(30),
+				}},
+			}, nil).Once()
+			mockLogs.On("AssociateKmsKey", mock.Anything, mock.Anything).Return(&cloudwatchlogs.AssociateKmsKeyOutput{}, nil).Once()
+			if tt.verifyAfter {
+				mockLogs.On("DescribeLogGroups", mock.Anything, mock.Anything).Return(&cloudwatchlogs.DescribeLogGroupsOutput{
+					LogGroups: []cloudwatchlogstypes.LogGroup{{
+						LogGroupName:    aws.String("/aws/lambda/test-0"),
+						KmsKeyId:        aws.String(keyArn),
+						RetentionInDays: aws.Int32(30),
+					}},
+				}, nil).Once()
+			}
+
+			request := testutil.NewTestBatchComplianceRequest(1,
+				testutil.WithRuleName("cloudwatch-log-group-encrypted"))
+
+			result, err := service.ProcessNonCompliantResourcesOptimized(context.Background(), request)
+
+			require.NoError(t, err)
+			require.Len(t, result.Drift, 1)
+			drift := result.Drift[0]
+			assert.Equal(t, "/aws/lambda/test-0", drift.LogGroupName)
+			assert.Empty(t, drift.Before.KmsKeyId)
+			assert.Equal(t, int32(30), aws.ToInt32(drift.Before.RetentionInDays), "before state is read from the live log group")
+			assert.Equal(t, keyArn, drift.After.KmsKeyId)
+			assert.Equal(t, int32(30), aws.ToInt32(drift.After.RetentionInDays))
+			assert.Equal(t, tt.expectVerified, drift.Verified)
+			mockLogs.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProcessNonCompliantResourcesOptimized_DriftAfterRateLimitRetry(t *testing.T) {
+	mockLogs := &MockCloudWatchLogsClient{
+		LogGroups: []cloudwatchlogstypes.LogGroup{
+			{LogGroupName: aws.String("/aws/lambda/test-0"), RetentionInDays: aws.Int32(7)},
+		},
+	}
+	throttled := &throttleOnceLogsClient{MockCloudWatchLogsClient: mockLogs}
+	service := &ComplianceService{
+		logsClient:     throttled,
+		ruleClassifier: types.NewRuleClassifier(),
+		config: ServiceConfig{
+			DefaultRetentionDays: 365,
+			Region:               "ca-central-1",
+		},
+	}
+
+	result, err := service.ProcessNonCompliantResourcesOptimized(context.Background(), testutil.NewTestBatchComplianceRequest(1))
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.RateLimitHits)
+	assert.Equal(t, 1, result.SuccessCount)
+	require.Len(t, result.Drift, 1)
+	assert.Equal(t, int32(7), aws.ToInt32(result.Drift[0].Before.RetentionInDays))
+	assert.Equal(t, int32(365), aws.ToInt32(result.Drift[0].After.RetentionInDays))
+	assert.Equal(t, 1, mockLogs.DescribeLogGroupsCalls, "the retry reuses the state read before the first attempt")
+}
+
+// throttleOnceLogsClient throttles the first PutRetentionPolicy call
+type throttleOnceLogsClient struct {
+	*MockCloudWatchLogsClient
+	throttled bool
+}
+
+func (c *throttleOnceLogsClient) PutRetentionPolicy(ctx context.Context, params *cloudwatchlogs.PutRetentionPolicyInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.PutRetentionPolicyOutput, error) {
+	if !c.throttled {
+		c.throttled = true
+		return nil, errors.New("ThrottlingException: Rate exceeded")
+	}
+	return c.MockCloudWatchLogsClient.PutRetentionPolicy(ctx, params, optFns...)
+}
+
+func TestProcessNonCompliantResourcesOptimized_DriftSkippedInDryRun(t *testing.T) {
+	service := newTimedEncryptionService()
+
+	request := testutil.NewTestBatchComplianceRequest(2,
+		testutil.WithRuleName("cloudwatch-log-group-encrypted"))
+
+	result, err := service.ProcessNonCompliantResourcesOptimized(context.Background(), request)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.SuccessCount)
+	assert.Empty(t, result.Drift)
+}
+
+func TestProcessNonCompliantResourcesOptimized_ParallelThreshold(t *testing.T) {
+	request := testutil.NewTestBatchComplianceRequest(3,
+		testutil.WithRuleName("cloudwatch-log-group-encrypted"),
+		testutil.WithBatchSize(1))
+
+	run := func(threshold int) (*types.BatchRemediationResult, *testutil.LogCapture) {
+		logger, logs := testutil.CaptureLogs(t)
+		service := newTimedEncryptionService(WithLogger(logger))
+		service.config.ParallelThreshold = threshold
+		service.config.BatchGroupDelay = time.Hour // Only the parallel path waits between batches
+
+		result, err := service.ProcessNonCompliantResourcesOptimized(context.Background(), request)
+		require.NoError(t, err)
+		return result, logs
+	}
+
+	sequential, logs := run(5)
+	assert.Len(t, logs.WithAttr("sequential", true), 1)
+	assert.Len(t, logs.WithAttr("batch_index", 0), 2, "Expected one inline batch to start and complete")
+	assert.Less(t, sequential.ProcessingDuration, time.Hour)
+
+	names := func(result *types.BatchRemediationResult) []string {
+		var names []string
+		for _, r := range result.Results {
+			names = append(names, r.LogGroupName)
+		}
+		return names
+	}
+	assert.Equal(t, []string{"/aws/lambda/test-0", "/aws/lambda/test-1", "/aws/lambda/test-2"}, names(sequential))
+
+	// At the threshold the parallel path is used; with no group delay it produces the same results
+	logger, parallelLogs := testutil.CaptureLogs(t)
+	service := newTimedEncryptionService(WithLogger(logger))
+	service.config.ParallelThreshold = 3
+	parallel, err := service.ProcessNonCompliantResourcesOptimized(context.Background(), request)
+	require.NoError(t, err)
+	assert.Len(t, parallelLogs.WithAttr("sequential", false), 1)
+	assert.Equal(t, sequential.SuccessCount, parallel.SuccessCount)
+	assert.Equal(t, sequential.FailureCount, parallel.FailureCount)
+	assert.ElementsMatch(t, names(sequential), names(parallel))
+}
+
+// BenchmarkBatchProcessing_ParallelThreshold compares a small run remediated inline with the
+// same run dispatched as parallel batches
+func BenchmarkBatchProcessing_ParallelThreshold(b *testing.B) {
+	request := testutil.NewTestBatchComplianceRequest(3,
+		testutil.WithRuleName("cloudwatch-log-group-encrypted"),
+		testutil.WithBatchSize(1))
+
+	for _, bm := range []struct {
+		name      string
+		threshold int
+	}{
+		{name: "Sequential", threshold: 10},
+		{name: "Parallel", threshold: 0},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				service := newTimedEncryptionService()
+				service.config.ParallelThreshold = bm.threshold
+				if _, err := service.ProcessNonCompliantResourcesOptimized(context.Background(), request); err != nil {
+					b.Fatalf("Batch processing failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+// MockCloudWatchClient records the metrics published through PutMetricData
+type MockCloudWatchClient struct {
+	mu     sync.Mutex
+	Inputs []*cloudwatch.PutMetricDataInput
+}
+
+func (m *MockCloudWatchClient) PutMetricData(ctx context.Context, params *cloudwatch.PutMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.PutMetricDataOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Inputs = append(m.Inputs, params)
+	return &cloudwatch.PutMetricDataOutput{}, nil
+}
+
+func TestProcessNonCompliantResourcesOptimized_RemediationDurationMetrics(t *testing.T) {
+	cloudwatchClient := &MockCloudWatchClient{}
+	service := newTimedEncryptionService()
+	service.metricsService = &MetricsService{
+		cloudwatchClient: cloudwatchClient,
+		environment:      "test",
+		region:           "ca-central-1",
+		namespace:        "LogGuardian",
+	}
+
+	// One resource per batch remediates every resource from its own goroutine
+	request := testutil.NewTestBatchComplianceRequest(4,
+		testutil.WithRuleName("cloudwatch-log-group-encrypted"),
+		testutil.WithBatchSize(1))
+
+	result, err := service.ProcessNonCompliantResourcesOptimized(context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, 4, result.TotalProcessed)
+
+	require.Len(t, cloudwatchClient.Inputs, 1)
+	var durations []cloudwatchtypes.MetricDatum
+	for _, datum := range cloudwatchClient.Inputs[0].MetricData {
+		if aws.ToString(datum.MetricName) == "RemediationDuration" {
+			durations = append(durations, datum)
+		}
+	}
+	require.Len(t, durations, 1, "Expected one datum for the region and action")
+	assert.Len(t, durations[0].Values, 4, "Expected a duration for each processed resource")
+	assert.Equal(t, cloudwatchtypes.StandardUnitMilliseconds, durations[0].Unit)
+
+	dimensions := make(map[string]string)
+	for _, dimension := range durations[0].Dimensions {
+		dimensions[aws.ToString(dimension.Name)] = aws.ToString(dimension.Value)
+	}
+	assert.Equal(t, map[string]string{"Environment": "test", "Region": "ca-central-1", "Action": "encryption"}, dimensions)
+}
+
+func TestProcessNonCompliantResourcesOptimized_MergedRulesDurationAction(t *testing.T) {
+	cloudwatchClient := &MockCloudWatchClient{}
+	service := newTimedEncryptionService()
+	service.metricsService = &MetricsService{
+		cloudwatchClient: cloudwatchClient,
+		environment:      "test",
+		region:           "ca-central-1",
+		namespace:        "LogGuardian",
+	}
+
+	// A --rules run joins its rule names, so only the merged requirements say what each resource needs
+	request := testutil.NewTestBatchComplianceRequest(2,
+		testutil.WithRuleName("cloudwatch-log-group-encrypted,cw-loggroup-retention-period-check"))
+	for i := range request.NonCompliantResults {
+		request.NonCompliantResults[i].Requirements = []types.RuleType{types.RuleTypeEncryption, types.RuleTypeRetention}
+	}
+
+	result, err := service.ProcessNonCompliantResourcesOptimized(context.Background(), request)
+	require.NoError(t, err)
+	require.Equal(t, 2, result.TotalProcessed)
+
+	require.Len(t, cloudwatchClient.Inputs, 1)
+	var actions []string
+	for _, datum := range cloudwatchClient.Inputs[0].MetricData {
+		if aws.ToString(datum.MetricName) != "RemediationDuration" {
+			continue
+		}
+		for _, dimension := range datum.Dimensions {
+			if aws.ToString(dimension.Name) == "Action" {
+				actions = append(actions, aws.ToString(dimension.Value))
+			}
+		}
+	}
+	assert.Equal(t, []string{"encryption_and_retention"}, actions)
+}
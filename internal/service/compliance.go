@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -15,6 +17,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
 	cloudwatchlogstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
 	"github.com/aws/aws-sdk-go-v2/service/configservice"
+	configtypes "github.com/aws/aws-sdk-go-v2/service/configservice/types"
 	"github.com/aws/aws-sdk-go-v2/service/kms"
 	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
 	"github.com/aws/smithy-go"
@@ -28,38 +31,108 @@ const (
 	AuditActionEncryptionSuccess = "encryption_success"
 	AuditActionEncryptionFailed  = "encryption_failed"
 	AuditActionEncryptionDryRun  = "encryption_dry_run"
+	AuditActionRekeyDecision     = "rekey_decision"
+	AuditActionKeyMigration      = "key_migration"
+
+	// Config evaluation reporting audit actions
+	AuditActionEvaluationReported     = "evaluation_reported"
+	AuditActionEvaluationTokenExpired = "evaluation_token_expired"
+	AuditActionEvaluationBuffered     = "evaluation_buffered"
+
+	// Retention audit actions
+	AuditActionRetentionReductionBlocked = "retention_reduction_blocked"
+	AuditActionRetentionAlreadyCompliant = "retention_already_compliant"
+
+	// Remediation evidence audit actions
+	AuditActionRemediationRecord = "remediation_record"
+
+	// Live state audit actions
+	AuditActionDescribeAccessDenied = "describe_access_denied"
 
 	// Key validation audit actions
 	AuditActionKeyValidationSuccess = "key_validation_success"
 	AuditActionKeyValidationFailed  = "key_validation_failed"
 	AuditActionCrossRegionKeyUsage  = "cross_region_key_usage"
+	AuditActionCrossAccountKeyUsage = "cross_account_key_usage"
 
 	// Policy validation audit actions
 	AuditActionPolicyValidationSuccess = "policy_validation_success"
 	AuditActionPolicyValidationWarning = "policy_validation_warning"
+	AuditActionKMSPolicyAutofixDiff    = "kms_policy_autofix_diff"
 
 	// Comprehensive validation audit actions
 	AuditActionComprehensiveKMSValidation = "comprehensive_kms_validation"
 
 	// Failure reason constants
 	FailureReasonKeyNotFound      = "key_not_found"
+	FailureReasonAliasNotFound    = "alias_not_found"
 	FailureReasonAccessDenied     = "access_denied"
 	FailureReasonGeneralError     = "general_error"
 	FailureReasonInvalidMetadata  = "invalid_metadata"
 	FailureReasonMissingKeyID     = "missing_key_id"
 	FailureReasonMissingKeyARN    = "missing_key_arn"
 	FailureReasonUnusableKeyState = "unusable_key_state"
+	FailureReasonCrossAccountKey  = "cross_account_key_not_allowed"
+	FailureReasonCrossRegionKey   = "cross_region_key_not_allowed"
 
 	// Failure stage constants
 	FailureStageKeyValidation    = "key_validation"
+	FailureStageKeyAccount       = "key_account"
 	FailureStagePolicyValidation = "policy_validation"
 	FailureStageKeyAssociation   = "key_association"
+	FailureStageRetentionCheck   = "retention_check"
+	FailureStageRetentionPolicy  = "retention_policy"
 
 	// Retry logic constants
 	MaxExponentialBackoffAttempts = 10   // Maximum attempts before capping multiplier to prevent overflow
 	MaxBackoffMultiplier          = 1024 // 2^10, maximum multiplier for exponential backoff
+	MinRetryBaseDelay             = time.Millisecond
+	MaxRetryDelay                 = 30 * time.Second // Cap on any single backoff delay
+
+	// Config returns at most MaxConfigBatchLimit evaluation results per page
+	MinConfigBatchLimit int32 = 1
+	MaxConfigBatchLimit int32 = 100
+
+	// DefaultKMSPolicyName is the name KMS gives a key's policy unless it was created otherwise
+	DefaultKMSPolicyName = "default"
+
+	// DefaultRegion is used when no region is configured, unless STRICT_REGION is enabled
+	DefaultRegion = "ca-central-1"
+
+	// maxLogGroupLookupDelay caps the backoff between creation-lag lookups
+	maxLogGroupLookupDelay = 5 * time.Second
+)
+
+// ErrRegionNotConfigured reports that STRICT_REGION is enabled but no region is configured
+var ErrRegionNotConfigured = errors.New("no AWS region configured")
+
+// ErrLogGroupNotFound reports that a log group was still missing after any creation-lag retries
+var ErrLogGroupNotFound = errors.New("log group not found")
+
+// RekeyPolicy controls remediation of log groups already encrypted with a different KMS key
+type RekeyPolicy string
+
+const (
+	// RekeyPolicyAlways associates the compliance key regardless of any existing key
+	RekeyPolicyAlways RekeyPolicy = "always"
+	// RekeyPolicyOnlyIfUnencrypted leaves groups with a different key untouched and reports no action
+	RekeyPolicyOnlyIfUnencrypted RekeyPolicy = "only-if-unencrypted"
+	// RekeyPolicyNeverRekey refuses to replace a different key and reports the group as failed
+	RekeyPolicyNeverRekey RekeyPolicy = "never-rekey"
 )
 
+// parseRekeyPolicy converts a REKEY_POLICY value, falling back to always for unknown values
+func parseRekeyPolicy(value string) RekeyPolicy {
+	switch policy := RekeyPolicy(strings.ToLower(strings.TrimSpace(value))); policy {
+	case RekeyPolicyAlways, RekeyPolicyOnlyIfUnencrypted, RekeyPolicyNeverRekey:
+		return policy
+	default:
+		slog.Warn("Unknown REKEY_POLICY value, defaulting to always",
+			"rekey_policy", value)
+		return RekeyPolicyAlways
+	}
+}
+
 // ComplianceService handles log group compliance remediation
 type ComplianceService struct {
 	logsClient        CloudWatchLogsClientInterface
@@ -69,103 +142,353 @@ type ComplianceService struct {
 	ruleClassifier    *types.RuleClassifier
 	metricsService    *MetricsService
 	config            ServiceConfig
+	logger            *slog.Logger
+	clock             func() time.Time    // Time source for batch timing; nil means time.Now
+	keyCache          *kmsKeyCache        // Validated KMS key info shared across services; used only when KMSKeyCacheTTL is set
+	unsentEvaluations evaluationBuffer    // Evaluations throttled by Config, retried after the next successful report
+	accountLimiter    *accountRateLimiter // Per-account remediation rate; nil when ACCOUNT_RATE_LIMIT is unset
+	policyStatement   string              // Statement from KMS_POLICY_STATEMENT_FILE; empty suggests a generated one
+	auditSink         AuditSink           // Receives remediation audit records; nil logs them when AUDIT_RECORDS is set
+	auditActor        types.AuditActor    // Who audit records name as having made each change
+}
+
+// ComplianceServiceOption customizes a ComplianceService at construction time
+type ComplianceServiceOption func(*ComplianceService)
+
+// WithLogger injects the structured logger used for remediation and audit logging
+func WithLogger(logger *slog.Logger) ComplianceServiceOption {
+	return func(s *ComplianceService) {
+		s.logger = logger
+	}
+}
+
+// WithClock injects the time source used to time batch remediation
+func WithClock(now func() time.Time) ComplianceServiceOption {
+	return func(s *ComplianceService) {
+		s.clock = now
+	}
+}
+
+// WithAllowRetentionReduction permits shortening retention on data-protected log groups,
+// overriding ALLOW_RETENTION_REDUCTION
+func WithAllowRetentionReduction(allow bool) ComplianceServiceOption {
+	return func(s *ComplianceService) {
+		s.config.AllowRetentionReduction = allow
+	}
+}
+
+// WithDryRun sets whether remediation only previews changes, overriding DRY_RUN, so the service
+// follows the execution mode the entrypoint resolved
+func WithDryRun(dryRun bool) ComplianceServiceOption {
+	return func(s *ComplianceService) {
+		s.config.DryRun = dryRun
+	}
+}
+
+// WithLogGroupLookupRetries sets how many times a missing log group is looked up again before it
+// is treated as not found, overriding LOG_GROUP_LOOKUP_RETRIES
+func WithLogGroupLookupRetries(retries int32) ComplianceServiceOption {
+	return func(s *ComplianceService) {
+		s.config.LogGroupLookupRetries = retries
+	}
 }
 
 // ServiceConfig holds configuration for the compliance service
 type ServiceConfig struct {
-	DefaultKMSKeyAlias   string
-	DefaultRetentionDays int32
-	DryRun               bool
-	BatchLimit           int32
-	Region               string
-	MaxKMSRetries        int32
-	RetryBaseDelay       time.Duration
-	BatchResourceDelay   time.Duration
-	BatchGroupDelay      time.Duration
-}
-
-// NewComplianceService creates a new compliance service
-func NewComplianceService(cfg aws.Config) *ComplianceService {
-	// Load configuration from environment variables
-	region := getEnvOrDefault("AWS_REGION", "")
-	if region == "" {
-		region = getEnvOrDefault("AWS_DEFAULT_REGION", "ca-central-1")
+	DefaultKMSKeyAlias      string
+	DefaultRetentionDays    int32
+	DryRun                  bool
+	BatchLimit              int32
+	Region                  string
+	MaxKMSRetries           int32
+	RetryBaseDelay          time.Duration // Base delay for KMS retry backoff; values below MinRetryBaseDelay are raised to it
+	BatchResourceDelay      time.Duration
+	BatchGroupDelay         time.Duration
+	AlsoProcessPrefixes     []string // Log group prefixes discovered directly, in addition to Config results
+	AllowCrossAccountKMSKey bool     // Permit KMS keys owned by a different account than the log group
+	ForbidCrossRegionKey    bool     // Reject single-Region KMS keys from another region instead of warning
+	AccountId               string   // Account being remediated; empty when unknown
+	AccountRateLimit        int32    // Remediations per second allowed in each account; zero disables limiting
+	MaxConcurrentKMSCalls   int32    // KMS API calls allowed in flight at once; zero leaves them unbounded
+	RekeyPolicy             RekeyPolicy
+	OldKeyArns              []string      // KMS keys being retired; groups still using one are re-encrypted with the default key
+	AllowRetentionReduction bool          // Permit shortening retention on log groups with active data protection
+	FailFast                bool          // Abort batch remediation on the first failed resource
+	BatchTimeout            time.Duration // Bound on waiting for in-flight batches; zero waits indefinitely
+	SoftTimeBudget          time.Duration // Run time after which no new batches are started; zero disables the budget
+	ReportEvaluations       bool          // Report post-remediation compliance back to Config via PutEvaluations
+	EvaluationRetries       int32         // PutEvaluations attempts while throttled before the evaluation is buffered
+	StrictEvaluations       bool          // Return an error when a throttled evaluation cannot be reported
+	StrictDescribe          bool          // Fail instead of trusting Config when DescribeLogGroups is denied
+	VerifyAfter             bool          // Re-read each remediated log group for the batch drift report
+	ParallelThreshold       int           // Runs with fewer resources are remediated sequentially; zero always uses parallel batches
+	KMSPolicyName           string        // Key policy name passed to GetKeyPolicy; empty means DefaultKMSPolicyName
+	KMSPolicyStatementFile  string        // JSON key policy statement suggested in place of the generated one; empty generates it
+	LogGroupLookupRetries   int32         // Extra lookups of a missing log group to tolerate creation lag
+	LogGroupLookupDelay     time.Duration // Base backoff between log group lookups, doubled per retry
+	AnnotationTemplate      string        // Template for annotations reported to Config; empty means DefaultEvaluationAnnotationTemplate
+	MaxKeyPolicyBytes       int           // Largest key policy parsed for CloudWatch Logs access; zero means DefaultMaxKeyPolicyBytes
+	TagLastAction           bool          // Tag remediated log groups with LastActionTagKey describing the change applied
+	AuditRecords            bool          // Log an AuditRecord of before and after state for every remediation
+	HTTPTimeout             time.Duration // Dial and response-header timeout for AWS clients; zero keeps the SDK defaults
+	KMSKeyCacheTTL          time.Duration // How long validated KMS key info is reused across runs; zero disables the cache
+}
+
+// NewComplianceService creates a new compliance service. It panics when STRICT_REGION is enabled
+// and no region is configured; use NewCheckedComplianceService to handle that as an error.
+func NewComplianceService(cfg aws.Config, opts ...ComplianceServiceOption) *ComplianceService {
+	service, err := NewCheckedComplianceService(cfg, opts...)
+	if err != nil {
+		panic(err)
 	}
+	return service
+}
+
+// NewCheckedComplianceService creates a new compliance service, returning ErrRegionNotConfigured
+// instead of falling back to DefaultRegion when STRICT_REGION is enabled and no region is set
+func NewCheckedComplianceService(cfg aws.Config, opts ...ComplianceServiceOption) (*ComplianceService, error) {
+	region, err := resolveRegion(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	config := serviceConfigFromEnvironment(region)
+	policyStatement, err := loadKMSPolicyStatement(config.KMSPolicyStatementFile)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg = ApplyHTTPTimeout(ApplyUserAgent(cfg), config.HTTPTimeout)
+	service := &ComplianceService{
+		logsClient:      cloudwatchlogs.NewFromConfig(cfg),
+		kmsClient:       limitKMSCalls(kms.NewFromConfig(cfg), newKMSCallSlots(config.MaxConcurrentKMSCalls)),
+		configClient:    configservice.NewFromConfig(cfg),
+		ruleClassifier:  types.NewRuleClassifier(),
+		metricsService:  NewMetricsService(cfg),
+		config:          config,
+		logger:          slog.Default(),
+		keyCache:        sharedKMSKeyCache,
+		accountLimiter:  newAccountRateLimiter(config.AccountRateLimit),
+		policyStatement: policyStatement,
+	}
+
+	for _, opt := range opts {
+		opt(service)
+	}
+	service.normalizeConfig()
+	service.configEvalService = NewConfigEvaluationService(cfg, service.config)
+
+	return service, nil
+}
+
+// serviceConfigFromEnvironment loads a region's service configuration from environment variables
+func serviceConfigFromEnvironment(region string) ServiceConfig {
 	config := ServiceConfig{
-		DefaultKMSKeyAlias:   getEnvOrDefault("KMS_KEY_ALIAS", "alias/cloudwatch-logs-compliance"),
-		DefaultRetentionDays: getEnvAsInt32OrDefault("DEFAULT_RETENTION_DAYS", 365),
-		DryRun:               getEnvAsBoolOrDefault("DRY_RUN", false),
-		BatchLimit:           getEnvAsInt32OrDefault("BATCH_LIMIT", 100),
-		Region:               region,
-		MaxKMSRetries:        getEnvAsInt32OrDefault("MAX_KMS_RETRIES", 3),
-		RetryBaseDelay:       time.Duration(getEnvAsInt32OrDefault("RETRY_BASE_DELAY_MS", 1000)) * time.Millisecond,
-		BatchResourceDelay:   time.Duration(getEnvAsInt32OrDefault("BATCH_RESOURCE_DELAY_MS", 50)) * time.Millisecond,
-		BatchGroupDelay:      time.Duration(getEnvAsInt32OrDefault("BATCH_GROUP_DELAY_MS", 200)) * time.Millisecond,
+		DefaultKMSKeyAlias:      normalizeKMSKeyAlias(getEnvOrDefault("KMS_KEY_ALIAS", "alias/cloudwatch-logs-compliance")),
+		DefaultRetentionDays:    getEnvAsInt32OrDefault("DEFAULT_RETENTION_DAYS", 365),
+		DryRun:                  getEnvAsBoolOrDefault("DRY_RUN", false),
+		BatchLimit:              getEnvAsInt32OrDefault("BATCH_LIMIT", 100),
+		Region:                  region,
+		MaxKMSRetries:           getEnvAsInt32OrDefault("MAX_KMS_RETRIES", 3),
+		RetryBaseDelay:          time.Duration(getEnvAsInt32OrDefault("RETRY_BASE_DELAY_MS", 1000)) * time.Millisecond,
+		BatchResourceDelay:      time.Duration(getEnvAsInt32OrDefault("BATCH_RESOURCE_DELAY_MS", 50)) * time.Millisecond,
+		BatchGroupDelay:         time.Duration(getEnvAsInt32OrDefault("BATCH_GROUP_DELAY_MS", 200)) * time.Millisecond,
+		AlsoProcessPrefixes:     parseCommaDelimitedString(getEnvOrDefault("ALSO_PROCESS_PREFIXES", "")),
+		AllowCrossAccountKMSKey: getEnvAsBoolOrDefault("ALLOW_CROSS_ACCOUNT_KMS_KEY", false),
+		ForbidCrossRegionKey:    getEnvAsBoolOrDefault("FORBID_CROSS_REGION_KEY", false),
+		AccountId:               getEnvOrDefault("REMEDIATION_ACCOUNT_ID", ""),
+		AccountRateLimit:        getEnvAsInt32OrDefault("ACCOUNT_RATE_LIMIT", 0),
+		MaxConcurrentKMSCalls:   getEnvAsInt32OrDefault("MAX_CONCURRENT_KMS_CALLS", 0),
+		RekeyPolicy:             parseRekeyPolicy(getEnvOrDefault("REKEY_POLICY", string(RekeyPolicyAlways))),
+		OldKeyArns:              parseCommaDelimitedString(getEnvOrDefault("OLD_KEY_ARNS", "")),
+		AllowRetentionReduction: getEnvAsBoolOrDefault("ALLOW_RETENTION_REDUCTION", false),
+		FailFast:                getEnvAsBoolOrDefault("FAIL_FAST", false),
+		BatchTimeout:            time.Duration(getEnvAsInt32OrDefault("BATCH_TIMEOUT_MS", 0)) * time.Millisecond,
+		SoftTimeBudget:          time.Duration(getEnvAsInt32OrDefault("SOFT_TIME_BUDGET_MS", 0)) * time.Millisecond,
+		ReportEvaluations:       getEnvAsBoolOrDefault("REPORT_EVALUATIONS", false),
+		EvaluationRetries:       getEnvAsInt32OrDefault("EVALUATION_RETRIES", 3),
+		StrictEvaluations:       getEnvAsBoolOrDefault("STRICT_EVALUATIONS", false),
+		StrictDescribe:          getEnvAsBoolOrDefault("STRICT_DESCRIBE", false),
+		VerifyAfter:             getEnvAsBoolOrDefault("VERIFY_AFTER", false),
+		ParallelThreshold:       int(getEnvAsInt32OrDefault("PARALLEL_THRESHOLD", 0)),
+		KMSPolicyName:           getEnvOrDefault("KMS_POLICY_NAME", DefaultKMSPolicyName),
+		KMSPolicyStatementFile:  getEnvOrDefault("KMS_POLICY_STATEMENT_FILE", ""),
+		LogGroupLookupRetries:   getEnvAsInt32OrDefault("LOG_GROUP_LOOKUP_RETRIES", 3),
+		LogGroupLookupDelay:     time.Duration(getEnvAsInt32OrDefault("LOG_GROUP_LOOKUP_DELAY_MS", 500)) * time.Millisecond,
+		AnnotationTemplate:      getEnvOrDefault("EVALUATION_ANNOTATION_TEMPLATE", DefaultEvaluationAnnotationTemplate),
+		MaxKeyPolicyBytes:       int(getEnvAsInt32OrDefault("KMS_POLICY_MAX_BYTES", DefaultMaxKeyPolicyBytes)),
+		TagLastAction:           getEnvAsBoolOrDefault("TAG_LAST_ACTION", false),
+		AuditRecords:            getEnvAsBoolOrDefault("AUDIT_RECORDS", false),
+		HTTPTimeout:             HTTPTimeoutFromEnvironment(),
+		KMSKeyCacheTTL:          time.Duration(getEnvAsInt32OrDefault("KMS_KEY_CACHE_TTL_SECONDS", 0)) * time.Second,
+	}
+
+	return config
+}
+
+// normalizeConfig raises or clamps configured values the service cannot use as given, warning
+// through the service logger; every constructor applies it once its options have run
+func (s *ComplianceService) normalizeConfig() {
+	if s.config.RetryBaseDelay < MinRetryBaseDelay {
+		s.getLogger().Warn("RETRY_BASE_DELAY_MS below minimum, using minimum",
+			"region", s.config.Region,
+			"retry_base_delay", s.config.RetryBaseDelay,
+			"minimum", MinRetryBaseDelay)
+		s.config.RetryBaseDelay = MinRetryBaseDelay
+	}
+
+	if limit := min(max(s.config.BatchLimit, MinConfigBatchLimit), MaxConfigBatchLimit); limit != s.config.BatchLimit {
+		s.getLogger().Warn("BATCH_LIMIT outside the range Config accepts, clamping",
+			"region", s.config.Region,
+			"batch_limit", s.config.BatchLimit,
+			"clamped", limit)
+		s.config.BatchLimit = limit
 	}
+}
+
+// kmsKeyIdPattern matches raw KMS key IDs, both single-region UUIDs and multi-Region mrk- IDs
+var kmsKeyIdPattern = regexp.MustCompile(`^([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|mrk-[0-9a-fA-F]{32})$`)
+
+// normalizeKMSKeyAlias prepends the alias/ prefix to a bare alias name such as
+// cloudwatch-logs-compliance, which DescribeKey would otherwise reject. Aliases, ARNs and key IDs
+// are returned unchanged.
+func normalizeKMSKeyAlias(keyAlias string) string {
+	if keyAlias == "" || strings.HasPrefix(keyAlias, "alias/") || strings.HasPrefix(keyAlias, "arn:") || kmsKeyIdPattern.MatchString(keyAlias) {
+		return keyAlias
+	}
+
+	normalized := "alias/" + keyAlias
+	slog.Info("KMS key alias missing alias/ prefix, normalizing",
+		"kms_key_alias", keyAlias,
+		"normalized", normalized)
+	return normalized
+}
+
+// isKMSAliasIdentifier reports whether keyId names an alias, as alias/name or an alias ARN, rather
+// than a key ID or key ARN
+func isKMSAliasIdentifier(keyId string) bool {
+	return strings.HasPrefix(keyId, "alias/") || (strings.HasPrefix(keyId, "arn:") && strings.Contains(keyId, ":alias/"))
+}
 
-	return &ComplianceService{
-		logsClient:        cloudwatchlogs.NewFromConfig(cfg),
-		kmsClient:         kms.NewFromConfig(cfg),
-		configClient:      configservice.NewFromConfig(cfg),
-		configEvalService: NewConfigEvaluationService(cfg),
-		ruleClassifier:    types.NewRuleClassifier(),
-		metricsService:    NewMetricsService(cfg),
-		config:            config,
+// resolveRegion returns the region from AWS_REGION or AWS_DEFAULT_REGION. Without either it falls
+// back to DefaultRegion, unless STRICT_REGION is enabled, in which case only the region already on
+// the AWS config is accepted.
+func resolveRegion(cfg aws.Config) (string, error) {
+	if region := getEnvOrDefault("AWS_REGION", getEnvOrDefault("AWS_DEFAULT_REGION", "")); region != "" {
+		return region, nil
+	}
+	if !getEnvAsBoolOrDefault("STRICT_REGION", false) {
+		return DefaultRegion, nil
 	}
+	if cfg.Region != "" {
+		return cfg.Region, nil
+	}
+	return "", fmt.Errorf("%w: set AWS_REGION or AWS_DEFAULT_REGION, or unset STRICT_REGION to default to %s",
+		ErrRegionNotConfigured, DefaultRegion)
 }
 
 // RemediateLogGroup applies compliance remediation to a log group
 func (s *ComplianceService) RemediateLogGroup(ctx context.Context, compliance types.ComplianceResult) (*types.RemediationResult, error) {
+	start := s.now()
 	result := &types.RemediationResult{
 		LogGroupName: compliance.LogGroupName,
 		Region:       compliance.Region,
 		Success:      true,
 	}
 
-	slog.Info("Starting remediation",
+	s.getLogger().Info("Starting remediation",
 		"log_group", compliance.LogGroupName,
 		"region", compliance.Region,
 		"dry_run", s.config.DryRun)
 
-	// Apply KMS encryption if missing
+	// Rule-supplied targets take precedence over the service defaults
+	keyAlias := s.config.DefaultKMSKeyAlias
+	if compliance.TargetKMSKeyId != "" {
+		keyAlias = compliance.TargetKMSKeyId
+	}
+	retentionDays := s.config.DefaultRetentionDays
+	if compliance.TargetRetentionDays != nil {
+		retentionDays = *compliance.TargetRetentionDays
+	}
+	var appliedKeyArn string
+	defer func() { s.recordAudit(ctx, compliance, result, appliedKeyArn, retentionDays) }()
+
+	// The guards below, and the audit record's before state, read the live log group when the
+	// compliance result does not already describe it
+	if s.needsLiveState(compliance) || s.auditNeedsLiveState(compliance) {
+		live, err := s.withLiveState(ctx, compliance)
+		if err != nil {
+			result.Success = false
+			result.Error = err
+			return result, err
+		}
+		compliance = live
+	}
+
+	// Apply KMS encryption if missing, unless the re-key policy keeps an existing key
 	if compliance.MissingEncryption {
-		if err := s.applyEncryption(ctx, compliance.LogGroupName); err != nil {
+		skip, skipReason, err := s.applyRekeyPolicy(ctx, compliance, keyAlias, nil)
+		if err == nil && !skip {
+			appliedKeyArn, err = s.applyEncryption(ctx, compliance.LogGroupName, keyAlias, compliance.AccountId)
+		}
+		if err != nil {
 			result.Success = false
 			result.Error = fmt.Errorf("failed to apply encryption: %w", err)
 
 			// Publish error metric
 			if s.metricsService != nil {
 				if err := s.metricsService.PublishSingleMetric(ctx, "RemediationErrors", 1, cloudwatchtypes.StandardUnitCount); err != nil {
-					slog.Warn("Failed to publish error metric", "error", err)
+					s.getLogger().Warn("Failed to publish error metric", "error", err)
 				}
 			}
 
 			return result, err
 		}
-		result.EncryptionApplied = true
-		slog.Info("Applied KMS encryption", "log_group", compliance.LogGroupName)
+		if skip {
+			addSkipReason(result, skipReason)
+		} else {
+			result.EncryptionApplied = true
+			s.getLogger().Info("Applied KMS encryption", "log_group", compliance.LogGroupName)
+		}
 	}
 
-	// Apply retention policy if missing
+	// Apply retention policy if missing, unless it would shorten retention on a data-protected group
 	if compliance.MissingRetention {
-		if err := s.applyRetentionPolicy(ctx, compliance.LogGroupName); err != nil {
+		var err error
+		skipReason := s.checkRetentionReduction(compliance, retentionDays)
+		if skipReason == "" {
+			err = s.applyRetentionPolicy(ctx, compliance.LogGroupName, retentionDays)
+		}
+		if err != nil {
 			result.Success = false
 			result.Error = fmt.Errorf("failed to apply retention policy: %w", err)
 
 			// Publish error metric
 			if s.metricsService != nil {
 				if err := s.metricsService.PublishSingleMetric(ctx, "RemediationErrors", 1, cloudwatchtypes.StandardUnitCount); err != nil {
-					slog.Warn("Failed to publish error metric", "error", err)
+					s.getLogger().Warn("Failed to publish error metric", "error", err)
 				}
 			}
 
 			return result, err
 		}
-		result.RetentionApplied = true
-		slog.Info("Applied retention policy",
+		if skipReason != "" {
+			addSkipReason(result, skipReason)
+		} else {
+			result.RetentionApplied = true
+			s.getLogger().Info("Applied retention policy",
+				"log_group", compliance.LogGroupName,
+				"retention_days", retentionDays)
+		}
+	}
+
+	if !result.EncryptionApplied && !result.RetentionApplied && result.SkipReason == "" {
+		result.NoActionNeeded = true
+		s.getLogger().Info("Log group already compliant, no remediation needed",
 			"log_group", compliance.LogGroupName,
-			"retention_days", s.config.DefaultRetentionDays)
+			"region", compliance.Region)
 	}
+	s.tagLastAction(ctx, compliance, result)
 
 	// Publish success metrics
 	if s.metricsService != nil {
@@ -176,95 +499,414 @@ func (s *ComplianceService) RemediateLogGroup(ctx context.Context, compliance ty
 		}
 
 		if result.Success {
-			metrics.LogGroupsRemediated = 1
+			if result.EncryptionApplied || result.RetentionApplied {
+				metrics.LogGroupsRemediated = 1
+			}
 		} else {
 			metrics.RemediationErrors = 1
 		}
+		metrics.RemediationDurations = []RemediationDuration{{
+			Region:   compliance.Region,
+			Action:   remediationAction(compliance),
+			Duration: s.now().Sub(start),
+		}}
 
 		if err := s.metricsService.PublishBatchMetrics(ctx, metrics); err != nil {
 			// Log error but don't fail the operation
-			slog.Warn("Failed to publish single remediation metrics", "error", err)
+			s.getLogger().Warn("Failed to publish single remediation metrics", "error", err)
 		}
 	}
 
 	return result, nil
 }
 
-// applyEncryption associates a KMS key with the log group
-func (s *ComplianceService) applyEncryption(ctx context.Context, logGroupName string) error {
+// remediationAction names the Action dimension of a resource's RemediationDuration from what the
+// resource was missing: the rule type when one requirement applies, both names when both do
+func remediationAction(compliance types.ComplianceResult) string {
+	switch {
+	case compliance.MissingEncryption && compliance.MissingRetention:
+		return types.RuleTypeEncryption.String() + "_and_" + types.RuleTypeRetention.String()
+	case compliance.MissingEncryption:
+		return types.RuleTypeEncryption.String()
+	case compliance.MissingRetention:
+		return types.RuleTypeRetention.String()
+	default:
+		return types.RuleTypeUnknown.String()
+	}
+}
+
+// applyRekeyPolicy reports whether encryption should be skipped because the log group is already
+// encrypted with a different key, with the reason when the skip must be reported. The current key
+// comes from the compliance result, which carries live state when it was unknown; a group whose key
+// could not be read is skipped rather than assumed unencrypted. targetKey is resolved from keyAlias
+// when not already validated.
+func (s *ComplianceService) applyRekeyPolicy(ctx context.Context, compliance types.ComplianceResult, keyAlias string, targetKey *KMSKeyInfo) (bool, string, error) {
+	policy := s.config.RekeyPolicy
+	currentKey := compliance.CurrentKmsKeyId
+	if policy == "" || policy == RekeyPolicyAlways {
+		// Retired keys are recorded as migrations; any other existing key is replaced
+		if currentKey != "" && s.retiredKey(currentKey) == "" {
+			s.getLogger().Info("Log group encrypted with a different KMS key, re-keying",
+				"log_group", compliance.LogGroupName,
+				"current_kms_key_id", currentKey,
+				"target_kms_key_alias", keyAlias,
+				"rekey_policy", string(RekeyPolicyAlways),
+				"decision", "rekey",
+				"audit_action", AuditActionRekeyDecision)
+		}
+		return false, "", nil
+	}
+
+	if s.isMigrating(compliance.LogGroupName, currentKey) {
+		return false, "", nil
+	}
+	if currentKey == "" && !compliance.LiveState {
+		reason := fmt.Sprintf("existing KMS key of log group could not be read; encryption skipped under REKEY_POLICY=%s", policy)
+		s.getLogger().Warn("Cannot confirm log group is unencrypted, skipping encryption",
+			"log_group", compliance.LogGroupName,
+			"rekey_policy", string(policy),
+			"decision", "skip_unverified",
+			"reason", reason,
+			"audit_action", AuditActionRekeyDecision)
+		return true, reason, nil
+	}
+	if currentKey == "" {
+		return false, "", nil
+	}
+
+	if targetKey == nil {
+		keyInfo, err := s.validateKMSKeyAccessibility(ctx, keyAlias)
+		if err != nil {
+			return false, "", fmt.Errorf("KMS key validation failed for %s: %w", keyAlias, err)
+		}
+		targetKey = keyInfo
+	}
+	if currentKey == targetKey.Arn || types.KMSKeyMatches(currentKey, targetKey.KeyId) {
+		return false, "", nil
+	}
+
+	s.getLogger().Info("Log group already encrypted with a different KMS key",
+		"log_group", compliance.LogGroupName,
+		"current_kms_key_id", currentKey,
+		"target_kms_key_arn", targetKey.Arn,
+		"rekey_policy", string(policy),
+		"decision", "keep_existing_key",
+		"audit_action", AuditActionRekeyDecision)
+
+	if policy == RekeyPolicyNeverRekey {
+		return false, "", fmt.Errorf("log group %s is encrypted with %s; refusing to re-key under REKEY_POLICY=%s",
+			compliance.LogGroupName, currentKey, policy)
+	}
+	return true, "", nil
+}
+
+// addSkipReason records why a required change was not applied, keeping any earlier reason
+func addSkipReason(result *types.RemediationResult, reason string) {
+	if result.SkipReason != "" {
+		reason = result.SkipReason + "; " + reason
+	}
+	result.SkipReason = reason
+}
+
+// isMigrating reports whether currentKey is one of OLD_KEY_ARNS, so the log group must be
+// re-encrypted with the default key even though it is already encrypted
+func (s *ComplianceService) isMigrating(logGroupName, currentKey string) bool {
+	oldKey := s.retiredKey(currentKey)
+	if oldKey == "" {
+		return false
+	}
+	s.getLogger().Info("Log group encrypted with a retired KMS key, migrating to the default key",
+		"log_group", logGroupName,
+		"current_kms_key_id", currentKey,
+		"old_kms_key_arn", oldKey,
+		"target_kms_key_alias", s.config.DefaultKMSKeyAlias,
+		"audit_action", AuditActionKeyMigration)
+	return true
+}
+
+// retiredKey returns the OLD_KEY_ARNS entry currentKey matches, or "" when it is not being retired
+func (s *ComplianceService) retiredKey(currentKey string) string {
+	if currentKey == "" {
+		return ""
+	}
+	for _, oldKey := range s.config.OldKeyArns {
+		if types.KMSKeyMatches(currentKey, oldKey) {
+			return oldKey
+		}
+	}
+	return ""
+}
+
+// checkRetentionReduction returns a skip reason when applying retentionDays would shorten retention
+// on a log group with an active data protection policy, or "" when retention may be applied.
+// Data protection status and current retention come from the compliance result, which carries live
+// state when the status was unknown; a group whose status could not be read is skipped rather than
+// assumed unprotected. A group without retention never expires, so any retention shortens it.
+func (s *ComplianceService) checkRetentionReduction(compliance types.ComplianceResult, retentionDays int32) string {
+	if s.config.AllowRetentionReduction {
+		return ""
+	}
+
+	status := compliance.DataProtectionStatus
+	currentRetention := compliance.CurrentRetention
+	if status == "" && !compliance.LiveState {
+		reason := fmt.Sprintf("data protection status of log group could not be read; retention of %d days not applied in case it shortens retention on a data-protected log group",
+			retentionDays)
+		s.getLogger().Warn("Cannot confirm log group is not data-protected, skipping retention",
+			"log_group", compliance.LogGroupName,
+			"target_retention_days", retentionDays,
+			"reason", reason,
+			"audit_action", AuditActionRetentionReductionBlocked)
+		return reason
+	}
+
+	if status != string(cloudwatchlogstypes.DataProtectionStatusActivated) {
+		return ""
+	}
+	if currentRetention != nil && retentionDays >= *currentRetention {
+		return ""
+	}
+
+	current := "never expire"
+	if currentRetention != nil {
+		current = fmt.Sprintf("%d days", *currentRetention)
+	}
+	reason := fmt.Sprintf("retention reduction from %s to %d days blocked on data-protected log group; set --allow-retention-reduction or ALLOW_RETENTION_REDUCTION=true to apply",
+		current, retentionDays)
+
+	s.getLogger().Warn("Skipping retention reduction on data-protected log group",
+		"log_group", compliance.LogGroupName,
+		"current_retention", currentRetention,
+		"target_retention_days", retentionDays,
+		"data_protection_status", status,
+		"reason", reason,
+		"audit_action", AuditActionRetentionReductionBlocked)
+
+	return reason
+}
+
+// eachLogGroupPage calls visit with each page of log groups whose name starts with prefix, or of
+// every log group when prefix is empty, until the last page or until visit returns false. Every
+// path that lists log groups pages through it, so none stops at the first page.
+func (s *ComplianceService) eachLogGroupPage(ctx context.Context, prefix string, visit func([]cloudwatchlogstypes.LogGroup) bool) error {
+	input := &cloudwatchlogs.DescribeLogGroupsInput{}
+	if prefix != "" {
+		input.LogGroupNamePrefix = aws.String(prefix)
+	}
+
+	paginator := cloudwatchlogs.NewDescribeLogGroupsPaginator(s.logsClient, input)
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		if !visit(output.LogGroups) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// describeAllLogGroups returns every log group whose name starts with prefix, or every log group
+// when prefix is empty
+func (s *ComplianceService) describeAllLogGroups(ctx context.Context, prefix string) ([]cloudwatchlogstypes.LogGroup, error) {
+	var logGroups []cloudwatchlogstypes.LogGroup
+	err := s.eachLogGroupPage(ctx, prefix, func(page []cloudwatchlogstypes.LogGroup) bool {
+		logGroups = append(logGroups, page...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return logGroups, nil
+}
+
+// describeLogGroup returns the live log group with exactly the given name, or nil if it does not
+// exist. Paging stops at the exact match, so longer names sharing the prefix are not all listed.
+func (s *ComplianceService) describeLogGroup(ctx context.Context, logGroupName string) (*cloudwatchlogstypes.LogGroup, error) {
+	var match *cloudwatchlogstypes.LogGroup
+	err := s.eachLogGroupPage(ctx, logGroupName, func(page []cloudwatchlogstypes.LogGroup) bool {
+		for i := range page {
+			if aws.ToString(page[i].LogGroupName) == logGroupName {
+				match = &page[i]
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return match, nil
+}
+
+// describeLogGroupWithCreationRetry looks up a log group, looking again with backoff while it is
+// missing because DescribeLogGroups can lag behind a just-created group. API errors are returned
+// immediately; a nil group after the final lookup is a genuine not-found.
+func (s *ComplianceService) describeLogGroupWithCreationRetry(ctx context.Context, logGroupName string) (*cloudwatchlogstypes.LogGroup, error) {
+	retries := int(max(s.config.LogGroupLookupRetries, 0))
+	delay := max(s.config.LogGroupLookupDelay, MinRetryBaseDelay)
+
+	for attempt := 0; ; attempt++ {
+		logGroup, err := s.describeLogGroup(ctx, logGroupName)
+		if err != nil {
+			return nil, err
+		}
+		if logGroup != nil {
+			if attempt > 0 {
+				s.getLogger().Info("Log group became visible after retrying lookup",
+					"log_group", logGroupName,
+					"attempts", attempt+1)
+			}
+			return logGroup, nil
+		}
+		if attempt >= retries {
+			return nil, nil
+		}
+
+		s.getLogger().Info("Log group not yet visible, retrying lookup",
+			"log_group", logGroupName,
+			"attempt", attempt+1,
+			"delay", delay)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxLogGroupLookupDelay)
+	}
+}
+
+// needsLiveState reports whether a remediation guard needs the live log group to decide: the
+// re-key policy when the current key is unknown, or the retention reduction check when the data
+// protection status is
+func (s *ComplianceService) needsLiveState(compliance types.ComplianceResult) bool {
+	if compliance.LiveState {
+		return false
+	}
+	rekey := compliance.MissingEncryption && compliance.CurrentKmsKeyId == "" &&
+		s.config.RekeyPolicy != "" && s.config.RekeyPolicy != RekeyPolicyAlways
+	retention := compliance.MissingRetention && compliance.DataProtectionStatus == "" && !s.config.AllowRetentionReduction
+	return rekey || retention
+}
+
+// withLiveState returns compliance with its current key, retention and data protection status read
+// from the live log group. When the role is denied DescribeLogGroups it logs a warning and returns
+// compliance without live state, so the guards that need it skip their change instead of assuming
+// there is nothing to protect; with STRICT_DESCRIBE the error is returned. A log group that does
+// not exist after the creation-lag retries wraps ErrLogGroupNotFound.
+func (s *ComplianceService) withLiveState(ctx context.Context, compliance types.ComplianceResult) (types.ComplianceResult, error) {
+	logGroup, err := s.describeLogGroupWithCreationRetry(ctx, compliance.LogGroupName)
+	if err != nil {
+		if s.config.StrictDescribe || !isAccessDeniedError(err) {
+			return compliance, fmt.Errorf("failed to describe log group %s: %w", compliance.LogGroupName, err)
+		}
+		s.getLogger().Warn("DescribeLogGroups denied, changes that depend on live state will be skipped",
+			"log_group", compliance.LogGroupName,
+			"error", err,
+			"note", "Grant logs:DescribeLogGroups, or set STRICT_DESCRIBE=true to fail instead",
+			"audit_action", AuditActionDescribeAccessDenied)
+		return compliance, nil
+	}
+	if logGroup == nil {
+		return compliance, fmt.Errorf("log group %s not found: %w", compliance.LogGroupName, ErrLogGroupNotFound)
+	}
+
+	compliance.CurrentKmsKeyId = aws.ToString(logGroup.KmsKeyId)
+	compliance.CurrentRetention = logGroup.RetentionInDays
+	compliance.DataProtectionStatus = string(logGroup.DataProtectionStatus)
+	compliance.LiveState = true
+	return compliance, nil
+}
+
+// applyEncryption associates a KMS key with the log group.
+// keyAlias may be an alias, key ID or full key ARN; accountId is the log group's account, if known.
+// It returns the ARN of the key associated, or "" in a dry run.
+func (s *ComplianceService) applyEncryption(ctx context.Context, logGroupName, keyAlias, accountId string) (string, error) {
 	// Cache the current region to avoid repeated function calls
 	currentRegion := s.getCurrentRegion()
 
 	if s.config.DryRun {
-		slog.Info("DRY RUN: Would apply KMS encryption",
+		s.getLogger().Info("DRY RUN: Would apply KMS encryption",
 			"log_group", logGroupName,
-			"kms_key_alias", s.config.DefaultKMSKeyAlias,
+			"kms_key_alias", keyAlias,
 			"audit_action", AuditActionEncryptionDryRun,
 			"timestamp", time.Now().UTC().Format(time.RFC3339))
-		return nil
+		return "", nil
 	}
 
-	slog.Info("Starting KMS encryption process",
+	s.getLogger().Info("Starting KMS encryption process",
 		"log_group", logGroupName,
-		"kms_key_alias", s.config.DefaultKMSKeyAlias,
+		"kms_key_alias", keyAlias,
 		"audit_action", AuditActionEncryptionStart,
 		"timestamp", time.Now().UTC().Format(time.RFC3339))
 
 	// Step 1: Validate KMS key existence and accessibility
-	keyInfo, err := s.validateKMSKeyAccessibility(ctx, s.config.DefaultKMSKeyAlias)
+	keyInfo, err := s.validateKMSKeyAccessibility(ctx, keyAlias)
 	if err != nil {
-		slog.Error("KMS key validation failed during encryption",
+		s.getLogger().Error("KMS key validation failed during encryption",
 			"log_group", logGroupName,
-			"kms_key_alias", s.config.DefaultKMSKeyAlias,
+			"kms_key_alias", keyAlias,
 			"error", err,
 			"audit_action", AuditActionEncryptionFailed,
 			"failure_stage", FailureStageKeyValidation,
 			"timestamp", time.Now().UTC().Format(time.RFC3339))
-		return fmt.Errorf("KMS key validation failed for %s: %w", s.config.DefaultKMSKeyAlias, err)
+		// Key validation errors name the key; the remediation failure is attributed to the log group
+		var auditErr *AuditError
+		if errors.As(err, &auditErr) {
+			return "", auditError(auditErr.Stage, auditErr.Reason, logGroupName, fmt.Errorf("KMS key %s: %w", auditErr.Resource, auditErr.Err))
+		}
+		return "", auditError(FailureStageKeyValidation, "", logGroupName, err)
 	}
 
-	slog.Info("KMS key validation successful",
+	s.getLogger().Info("KMS key validation successful",
 		"log_group", logGroupName,
-		"kms_key_alias", s.config.DefaultKMSKeyAlias,
+		"kms_key_alias", keyAlias,
 		"kms_key_id", keyInfo.KeyId,
 		"kms_key_arn", keyInfo.Arn,
 		"key_state", keyInfo.KeyState,
 		"key_region", keyInfo.Region,
+		"key_account", keyInfo.AccountId,
 		"audit_action", AuditActionKeyValidationSuccess)
 
+	if err := s.validateKMSKeyAccount(keyInfo, logGroupName, accountId); err != nil {
+		return "", err
+	}
+
 	// Step 2: Verify key policies allow CloudWatch Logs service
 	if err := s.validateKMSKeyPolicyForCloudWatchLogs(ctx, keyInfo.KeyId); err != nil {
-		slog.Error("KMS key policy validation failed during encryption",
+		s.getLogger().Error("KMS key policy validation failed during encryption",
 			"log_group", logGroupName,
 			"kms_key_id", keyInfo.KeyId,
 			"error", err,
 			"audit_action", AuditActionEncryptionFailed,
 			"failure_stage", FailureStagePolicyValidation,
 			"timestamp", time.Now().UTC().Format(time.RFC3339))
-		return fmt.Errorf("KMS key policy validation failed for %s: %w", keyInfo.KeyId, err)
+		return "", auditError(FailureStagePolicyValidation, "", logGroupName, fmt.Errorf("KMS key %s: %w", keyInfo.KeyId, err))
 	}
 
-	slog.Info("KMS key policy validation successful",
+	s.getLogger().Info("KMS key policy validation successful",
 		"log_group", logGroupName,
 		"kms_key_id", keyInfo.KeyId,
 		"audit_action", AuditActionPolicyValidationSuccess)
 
 	// Step 3: Apply encryption with proper error handling
 	if err := s.associateKMSKeyWithRetry(ctx, logGroupName, keyInfo.Arn); err != nil {
-		slog.Error("Failed to associate KMS key with log group",
+		s.invalidateKMSKeyInfo(keyAlias, err)
+		s.getLogger().Error("Failed to associate KMS key with log group",
 			"log_group", logGroupName,
 			"kms_key_arn", keyInfo.Arn,
 			"error", err,
 			"audit_action", AuditActionEncryptionFailed,
 			"failure_stage", FailureStageKeyAssociation,
 			"timestamp", time.Now().UTC().Format(time.RFC3339))
-		return fmt.Errorf("failed to associate KMS key with log group %s: %w", logGroupName, err)
+		return "", auditError(FailureStageKeyAssociation, "", logGroupName, err)
 	}
 
 	// Step 4: Log operation for comprehensive audit trail
-	slog.Info("Successfully applied KMS encryption",
+	s.getLogger().Info("Successfully applied KMS encryption",
 		"log_group", logGroupName,
-		"kms_key_alias", s.config.DefaultKMSKeyAlias,
+		"kms_key_alias", keyAlias,
 		"kms_key_id", keyInfo.KeyId,
 		"kms_key_arn", keyInfo.Arn,
 		"key_region", keyInfo.Region,
@@ -276,12 +918,19 @@ func (s *ComplianceService) applyEncryption(ctx context.Context, logGroupName st
 		"compliance_status", "encryption_applied",
 		"timestamp", time.Now().UTC().Format(time.RFC3339))
 
-	return nil
+	return keyInfo.Arn, nil
 }
 
 // ValidateKMSKeyComprehensively provides a comprehensive validation report for a KMS key
 // This function is useful for troubleshooting and audit purposes
 func (s *ComplianceService) ValidateKMSKeyComprehensively(ctx context.Context, keyAlias string) (*types.KMSValidationReport, error) {
+	report, _ := s.kmsValidationReport(ctx, keyAlias)
+	return report, nil
+}
+
+// kmsValidationReport builds the comprehensive validation report for a KMS key, also returning the
+// error that made the key inaccessible so callers can classify it, as the report keeps only its text
+func (s *ComplianceService) kmsValidationReport(ctx context.Context, keyAlias string) (*types.KMSValidationReport, error) {
 	report := &types.KMSValidationReport{
 		KeyAlias:            keyAlias,
 		CurrentRegion:       s.getCurrentRegion(),
@@ -294,19 +943,32 @@ func (s *ComplianceService) ValidateKMSKeyComprehensively(ctx context.Context, k
 	// Step 1: Test key accessibility
 	keyInfo, err := s.validateKMSKeyAccessibility(ctx, keyAlias)
 	if err != nil {
-		report.KeyExists = false
+		// A key in an unusable state still exists; only its state prevents use
+		var stateErr *KMSKeyStateError
+		report.KeyExists = errors.As(err, &stateErr)
+		if report.KeyExists {
+			report.KeyState = string(stateErr.State)
+		}
 		report.KeyAccessible = false
 		report.ValidationErrors = append(report.ValidationErrors, err.Error())
 
-		if isKMSKeyNotFoundError(err) {
-			report.RecommendedActions = append(report.RecommendedActions,
-				fmt.Sprintf("Create KMS key with alias %s in region %s", keyAlias, report.CurrentRegion))
-		} else if isKMSAccessDeniedError(err) {
-			report.RecommendedActions = append(report.RecommendedActions,
-				"Ensure Lambda execution role has kms:DescribeKey permissions")
+		var auditErr *AuditError
+		if errors.As(err, &auditErr) {
+			switch auditErr.Reason {
+			case FailureReasonAliasNotFound:
+				report.RecommendedActions = append(report.RecommendedActions,
+					fmt.Sprintf("Create alias %s for an existing KMS key in region %s (aws kms create-alias --alias-name %s --target-key-id <key-id>)",
+						keyAlias, report.CurrentRegion, keyAlias))
+			case FailureReasonKeyNotFound:
+				report.RecommendedActions = append(report.RecommendedActions,
+					fmt.Sprintf("Create a KMS key in region %s and configure its key ID or ARN in place of %s", report.CurrentRegion, keyAlias))
+			case FailureReasonAccessDenied:
+				report.RecommendedActions = append(report.RecommendedActions,
+					"Ensure Lambda execution role has kms:DescribeKey permissions")
+			}
 		}
 
-		return report, nil
+		return report, err
 	}
 
 	// Key exists and is accessible
@@ -316,7 +978,20 @@ func (s *ComplianceService) ValidateKMSKeyComprehensively(ctx context.Context, k
 	report.KeyArn = keyInfo.Arn
 	report.KeyState = keyInfo.KeyState
 	report.KeyRegion = keyInfo.Region
+	report.KeyAccount = keyInfo.AccountId
 	report.IsCrossRegion = keyInfo.Region != report.CurrentRegion
+	report.IsCrossAccount = keyInfo.IsCrossAccount
+	report.KeyCreatedAt = keyInfo.CreatedAt
+
+	// A bare key ID resolves only in the caller's account, so a cross-account key is read by ARN
+	lastRotatedAt, err := s.lastKeyRotation(ctx, keyInfo.Arn)
+	if err != nil {
+		report.ValidationWarnings = append(report.ValidationWarnings,
+			fmt.Sprintf("Cannot read key rotation history: %v", err))
+		report.RecommendedActions = append(report.RecommendedActions,
+			"Ensure Lambda execution role has kms:ListKeyRotations permissions")
+	}
+	report.LastRotatedAt = lastRotatedAt
 
 	if report.IsCrossRegion {
 		report.ValidationWarnings = append(report.ValidationWarnings,
@@ -325,10 +1000,17 @@ func (s *ComplianceService) ValidateKMSKeyComprehensively(ctx context.Context, k
 			"Consider using a KMS key in the same region for better performance and to avoid cross-region charges")
 	}
 
+	if report.IsCrossAccount {
+		report.ValidationWarnings = append(report.ValidationWarnings,
+			fmt.Sprintf("KMS key is owned by account %s but remediation runs in account %s", keyInfo.AccountId, s.config.AccountId))
+		report.RecommendedActions = append(report.RecommendedActions,
+			"Confirm the key policy grants logs.amazonaws.com in this account and that key usage charges to the owning account are expected")
+	}
+
 	// Step 2: Test key policy accessibility and CloudWatch Logs permissions
 	policyInput := &kms.GetKeyPolicyInput{
 		KeyId:      aws.String(keyInfo.KeyId),
-		PolicyName: aws.String("default"),
+		PolicyName: aws.String(s.kmsPolicyName()),
 	}
 
 	policyResult, err := s.kmsClient.GetKeyPolicy(ctx, policyInput)
@@ -346,23 +1028,45 @@ func (s *ComplianceService) ValidateKMSKeyComprehensively(ctx context.Context, k
 			policy := *policyResult.Policy
 			report.CloudWatchLogsAccess = s.checkCloudWatchLogsPolicyAccess(policy)
 
+			// Only when the policy lacks access are the key's grants listed, since a grant to a
+			// CloudWatch Logs principal for an encryption operation also gives it access
+			if !report.CloudWatchLogsAccess {
+				hasGrant, err := s.hasCloudWatchLogsGrant(ctx, keyInfo.KeyId)
+				if err != nil {
+					report.ValidationWarnings = append(report.ValidationWarnings,
+						fmt.Sprintf("Cannot list key grants: %v", err))
+				}
+				report.CloudWatchLogsAccess = hasGrant
+			}
+
 			if !report.CloudWatchLogsAccess {
 				report.ValidationWarnings = append(report.ValidationWarnings,
 					"KMS key policy may not allow CloudWatch Logs service access")
 				report.RecommendedActions = append(report.RecommendedActions,
 					"Update KMS key policy to allow CloudWatch Logs service principal: logs.amazonaws.com")
+
+				accountId := s.config.AccountId
+				if accountId == "" {
+					accountId = keyInfo.AccountId
+				}
+				report.SuggestedPolicyStatement = s.policyStatement
+				if report.SuggestedPolicyStatement == "" {
+					report.SuggestedPolicyStatement = suggestedCloudWatchLogsStatement(report.CurrentRegion, accountId)
+				}
+				report.SuggestedPolicyDiff = s.suggestedPolicyDiff(keyAlias, policy, report.SuggestedPolicyStatement)
 			}
 		}
 	}
 
 	// Log the comprehensive validation results
-	slog.Info("Comprehensive KMS key validation completed",
+	s.getLogger().Info("Comprehensive KMS key validation completed",
 		"key_alias", keyAlias,
 		"key_exists", report.KeyExists,
 		"key_accessible", report.KeyAccessible,
 		"policy_accessible", report.PolicyAccessible,
 		"cloudwatch_logs_access", report.CloudWatchLogsAccess,
 		"is_cross_region", report.IsCrossRegion,
+		"is_cross_account", report.IsCrossAccount,
 		"validation_errors", len(report.ValidationErrors),
 		"validation_warnings", len(report.ValidationWarnings),
 		"audit_action", AuditActionComprehensiveKMSValidation)
@@ -371,37 +1075,89 @@ func (s *ComplianceService) ValidateKMSKeyComprehensively(ctx context.Context, k
 }
 
 // applyRetentionPolicy sets the retention policy on the log group
-func (s *ComplianceService) applyRetentionPolicy(ctx context.Context, logGroupName string) error {
+func (s *ComplianceService) applyRetentionPolicy(ctx context.Context, logGroupName string, retentionDays int32) error {
 	if s.config.DryRun {
-		slog.Info("DRY RUN: Would apply retention policy",
+		s.getLogger().Info("DRY RUN: Would apply retention policy",
 			"log_group", logGroupName,
-			"retention_days", s.config.DefaultRetentionDays)
+			"retention_days", retentionDays)
 		return nil
 	}
 
 	input := &cloudwatchlogs.PutRetentionPolicyInput{
 		LogGroupName:    aws.String(logGroupName),
-		RetentionInDays: aws.Int32(s.config.DefaultRetentionDays),
+		RetentionInDays: aws.Int32(retentionDays),
 	}
 
 	_, err := s.logsClient.PutRetentionPolicy(ctx, input)
 	if err != nil {
-		return fmt.Errorf("failed to set retention policy for log group %s: %w", logGroupName, err)
+		return auditError(FailureStageRetentionPolicy, "", logGroupName, err)
 	}
 
-	slog.Info("Successfully set retention policy",
+	s.getLogger().Info("Successfully set retention policy",
 		"log_group", logGroupName,
-		"retention_days", s.config.DefaultRetentionDays)
+		"retention_days", retentionDays)
 
 	return nil
 }
 
 // KMSKeyInfo holds comprehensive information about a KMS key
 type KMSKeyInfo struct {
-	KeyId    string
-	Arn      string
-	KeyState string
-	Region   string
+	KeyId          string
+	Arn            string
+	KeyState       string
+	Region         string
+	AccountId      string
+	IsCrossAccount bool
+	IsMultiRegion  bool
+	CreatedAt      *time.Time
+}
+
+// parseKMSKeyArn extracts the region and account from a key ARN (format: arn:partition:kms:region:account:key/key-id).
+// Parts that are not present are returned empty.
+func parseKMSKeyArn(arn string) (region, accountId string) {
+	parts := strings.Split(arn, ":")
+	if len(parts) >= 4 {
+		region = parts[3]
+	}
+	if len(parts) >= 5 {
+		accountId = parts[4]
+	}
+	return region, accountId
+}
+
+// validateKMSKeyAccount rejects a key owned by another account than the log group unless
+// cross-account keys are enabled; the remediation account is used when the log group's is not
+// known, and an unknown account on either side skips the check
+func (s *ComplianceService) validateKMSKeyAccount(keyInfo *KMSKeyInfo, logGroupName, accountId string) error {
+	if accountId == "" {
+		accountId = s.config.AccountId
+	}
+	if accountId == "" || keyInfo.AccountId == "" || keyInfo.AccountId == accountId {
+		return nil
+	}
+
+	if !s.config.AllowCrossAccountKMSKey {
+		s.getLogger().Error("KMS key belongs to a different account than the log group",
+			"log_group", logGroupName,
+			"kms_key_arn", keyInfo.Arn,
+			"key_account", keyInfo.AccountId,
+			"log_group_account", accountId,
+			"audit_action", AuditActionEncryptionFailed,
+			"failure_stage", FailureStageKeyAccount,
+			"failure_reason", FailureReasonCrossAccountKey)
+		return auditError(FailureStageKeyAccount, FailureReasonCrossAccountKey, logGroupName,
+			fmt.Errorf("KMS key %s belongs to account %s but log group is in account %s; set ALLOW_CROSS_ACCOUNT_KMS_KEY=true to use cross-account keys",
+				keyInfo.Arn, keyInfo.AccountId, accountId))
+	}
+
+	s.getLogger().Warn("Using KMS key from a different account than the log group",
+		"log_group", logGroupName,
+		"kms_key_arn", keyInfo.Arn,
+		"key_account", keyInfo.AccountId,
+		"log_group_account", accountId,
+		"audit_action", AuditActionCrossAccountKeyUsage,
+		"note", "Ensure the key policy grants CloudWatch Logs in the log group's account")
+	return nil
 }
 
 // validateKMSKeyAccessibility validates KMS key existence and accessibility
@@ -409,7 +1165,15 @@ func (s *ComplianceService) validateKMSKeyAccessibility(ctx context.Context, key
 	// Cache the current region to avoid repeated function calls
 	currentRegion := s.getCurrentRegion()
 
-	slog.Info("Validating KMS key accessibility",
+	if keyInfo, ok := s.cachedKMSKeyInfo(keyAlias); ok {
+		s.getLogger().Debug("Using cached KMS key info",
+			"kms_key_alias", keyAlias,
+			"kms_key_id", keyInfo.KeyId,
+			"current_region", currentRegion)
+		return keyInfo, nil
+	}
+
+	s.getLogger().Info("Validating KMS key accessibility",
 		"kms_key_alias", keyAlias,
 		"current_region", currentRegion)
 
@@ -421,97 +1185,129 @@ func (s *ComplianceService) validateKMSKeyAccessibility(ctx context.Context, key
 	if err != nil {
 		// Check for specific KMS errors
 		if isKMSKeyNotFoundError(err) {
+			// An alias that resolves to nothing needs a new alias, not a new key
+			reason := FailureReasonKeyNotFound
+			notFoundErr := fmt.Errorf("KMS key not found. Please ensure the key exists and is accessible in region %s", currentRegion)
+			if isKMSAliasIdentifier(keyAlias) {
+				reason = FailureReasonAliasNotFound
+				notFoundErr = fmt.Errorf("KMS alias %s not found. Please ensure the alias exists and targets a key in region %s", keyAlias, currentRegion)
+			}
+
 			// Log detailed error for audit trail
-			slog.Error("KMS key not found during validation",
+			s.getLogger().Error("KMS key not found during validation",
 				"kms_key_alias", keyAlias,
 				"current_region", currentRegion,
 				"error", err,
 				"audit_action", AuditActionKeyValidationFailed,
-				"failure_reason", FailureReasonKeyNotFound)
-			return nil, fmt.Errorf("KMS key not found: %s. Please ensure the key exists and is accessible in region %s", keyAlias, currentRegion)
+				"failure_reason", reason)
+			return nil, auditError(FailureStageKeyValidation, reason, keyAlias, notFoundErr)
 		}
 		if isKMSAccessDeniedError(err) {
 			// Log detailed error for audit trail
-			slog.Error("KMS key access denied during validation",
+			s.getLogger().Error("KMS key access denied during validation",
 				"kms_key_alias", keyAlias,
 				"current_region", currentRegion,
 				"error", err,
 				"audit_action", AuditActionKeyValidationFailed,
 				"failure_reason", FailureReasonAccessDenied)
-			return nil, fmt.Errorf("access denied to KMS key: %s. Please ensure proper IAM permissions are configured for region %s", keyAlias, currentRegion)
+			return nil, auditError(FailureStageKeyValidation, FailureReasonAccessDenied, keyAlias,
+				fmt.Errorf("access denied to KMS key. Please ensure proper IAM permissions are configured for region %s", currentRegion))
 		}
 
 		// Log general errors with audit information
-		slog.Error("KMS key validation failed",
+		s.getLogger().Error("KMS key validation failed",
 			"kms_key_alias", keyAlias,
 			"current_region", currentRegion,
 			"error", err,
 			"audit_action", AuditActionKeyValidationFailed,
 			"failure_reason", FailureReasonGeneralError)
-		return nil, fmt.Errorf("failed to describe KMS key %s in region %s: %w", keyAlias, currentRegion, err)
+		return nil, auditError(FailureStageKeyValidation, FailureReasonGeneralError, keyAlias,
+			fmt.Errorf("failed to describe KMS key in region %s: %w", currentRegion, err))
 	}
 
 	if result.KeyMetadata == nil {
-		slog.Error("Invalid KMS key metadata received",
+		s.getLogger().Error("Invalid KMS key metadata received",
 			"kms_key_alias", keyAlias,
 			"audit_action", AuditActionKeyValidationFailed,
 			"failure_reason", FailureReasonInvalidMetadata)
-		return nil, fmt.Errorf("invalid KMS key metadata for %s", keyAlias)
+		return nil, auditError(FailureStageKeyValidation, FailureReasonInvalidMetadata, keyAlias, errors.New("invalid KMS key metadata"))
 	}
 
 	keyMetadata := result.KeyMetadata
 
 	// Validate required fields
 	if keyMetadata.KeyId == nil {
-		slog.Error("KMS key ID missing in metadata",
+		s.getLogger().Error("KMS key ID missing in metadata",
 			"kms_key_alias", keyAlias,
 			"audit_action", AuditActionKeyValidationFailed,
 			"failure_reason", FailureReasonMissingKeyID)
-		return nil, fmt.Errorf("KMS key ID is missing for %s", keyAlias)
+		return nil, auditError(FailureStageKeyValidation, FailureReasonMissingKeyID, keyAlias, errors.New("KMS key ID is missing"))
 	}
 	if keyMetadata.Arn == nil {
-		slog.Error("KMS key ARN missing in metadata",
+		s.getLogger().Error("KMS key ARN missing in metadata",
 			"kms_key_alias", keyAlias,
 			"audit_action", AuditActionKeyValidationFailed,
 			"failure_reason", FailureReasonMissingKeyARN)
-		return nil, fmt.Errorf("KMS key ARN is missing for %s", keyAlias)
+		return nil, auditError(FailureStageKeyValidation, FailureReasonMissingKeyARN, keyAlias, errors.New("KMS key ARN is missing"))
 	}
 
 	keyInfo := &KMSKeyInfo{
-		KeyId:    *keyMetadata.KeyId,
-		Arn:      *keyMetadata.Arn,
-		KeyState: string(keyMetadata.KeyState),
+		KeyId:         *keyMetadata.KeyId,
+		Arn:           *keyMetadata.Arn,
+		KeyState:      string(keyMetadata.KeyState),
+		IsMultiRegion: aws.ToBool(keyMetadata.MultiRegion),
+		CreatedAt:     keyMetadata.CreationDate,
 	}
 
-	// Extract region from ARN (format: arn:aws:kms:region:account:key/key-id)
-	if parts := strings.Split(*keyMetadata.Arn, ":"); len(parts) >= 4 {
-		keyInfo.Region = parts[3]
+	keyInfo.Region, keyInfo.AccountId = parseKMSKeyArn(*keyMetadata.Arn)
 
-		// Cross-region validation: warn if key is in different region
-		if keyInfo.Region != currentRegion {
-			slog.Warn("KMS key is in different region than current",
-				"kms_key_alias", keyAlias,
-				"key_region", keyInfo.Region,
-				"current_region", currentRegion,
-				"audit_action", AuditActionCrossRegionKeyUsage,
-				"note", "Using cross-region KMS key - ensure proper permissions and network access")
-		}
+	// Cross-region validation: reject single-Region keys from another region when forbidden, otherwise
+	// warn. Multi-Region keys are exempt since a replica can serve the current region.
+	if keyInfo.Region != "" && keyInfo.Region != currentRegion && s.config.ForbidCrossRegionKey && !keyInfo.IsMultiRegion {
+		s.getLogger().Error("KMS key is in a different region than current and cross-region keys are forbidden",
+			"kms_key_alias", keyAlias,
+			"key_region", keyInfo.Region,
+			"current_region", currentRegion,
+			"audit_action", AuditActionKeyValidationFailed,
+			"failure_reason", FailureReasonCrossRegionKey)
+		return nil, auditError(FailureStageKeyValidation, FailureReasonCrossRegionKey, keyAlias,
+			fmt.Errorf("KMS key %s is in region %s but log groups are in %s; cross-region keys are forbidden by FORBID_CROSS_REGION_KEY",
+				keyInfo.Arn, keyInfo.Region, currentRegion))
+	}
+	if keyInfo.Region != "" && keyInfo.Region != currentRegion {
+		s.getLogger().Warn("KMS key is in different region than current",
+			"kms_key_alias", keyAlias,
+			"key_region", keyInfo.Region,
+			"current_region", currentRegion,
+			"audit_action", AuditActionCrossRegionKeyUsage,
+			"note", "Using cross-region KMS key - ensure proper permissions and network access")
+	}
+
+	// Cross-account validation: warn if key is owned by another account than the one being remediated
+	keyInfo.IsCrossAccount = s.config.AccountId != "" && keyInfo.AccountId != "" && keyInfo.AccountId != s.config.AccountId
+	if keyInfo.IsCrossAccount {
+		s.getLogger().Warn("KMS key is owned by a different account than the remediation account",
+			"kms_key_alias", keyAlias,
+			"key_account", keyInfo.AccountId,
+			"remediation_account", s.config.AccountId,
+			"audit_action", AuditActionCrossAccountKeyUsage,
+			"note", "Using cross-account KMS key - key usage is billed to the key owner and requires a key policy grant")
 	}
 
 	// Validate key state
 	if err := s.validateKMSKeyState(keyMetadata.KeyState); err != nil {
-		slog.Error("KMS key is not in usable state",
+		s.getLogger().Error("KMS key is not in usable state",
 			"kms_key_alias", keyAlias,
 			"kms_key_id", keyInfo.KeyId,
 			"key_state", keyInfo.KeyState,
 			"error", err,
 			"audit_action", AuditActionKeyValidationFailed,
 			"failure_reason", FailureReasonUnusableKeyState)
-		return nil, fmt.Errorf("KMS key %s is not in a usable state: %w", keyAlias, err)
+		return nil, auditError(FailureStageKeyValidation, FailureReasonUnusableKeyState, keyAlias, err)
 	}
 
 	// Log successful validation with comprehensive audit information
-	slog.Info("KMS key accessibility validation completed successfully",
+	s.getLogger().Info("KMS key accessibility validation completed successfully",
 		"kms_key_alias", keyAlias,
 		"kms_key_id", keyInfo.KeyId,
 		"kms_key_arn", keyInfo.Arn,
@@ -522,82 +1318,34 @@ func (s *ComplianceService) validateKMSKeyAccessibility(ctx context.Context, key
 		"audit_action", AuditActionKeyValidationSuccess,
 		"validation_timestamp", time.Now().UTC().Format(time.RFC3339))
 
+	s.cacheKMSKeyInfo(keyAlias, keyInfo)
 	return keyInfo, nil
 }
 
 // validateKMSKeyState checks if the KMS key is in a usable state
 func (s *ComplianceService) validateKMSKeyState(keyState kmstypes.KeyState) error {
-	switch keyState {
-	case kmstypes.KeyStateEnabled:
+	if keyState == kmstypes.KeyStateEnabled {
 		return nil // Key is usable
-	case kmstypes.KeyStateDisabled:
-		return fmt.Errorf("key is disabled")
-	case kmstypes.KeyStatePendingDeletion:
-		return fmt.Errorf("key is pending deletion")
-	case kmstypes.KeyStatePendingImport:
-		return fmt.Errorf("key is pending import")
-	case kmstypes.KeyStateUnavailable:
-		return fmt.Errorf("key is unavailable")
-	default:
-		return fmt.Errorf("KMS key is in unknown state: %s", string(keyState))
 	}
-}
-
-// checkCloudWatchLogsPolicyAccess checks if a policy contains CloudWatch Logs service access
-func (s *ComplianceService) checkCloudWatchLogsPolicyAccess(policy string) bool {
-	// Cache the current region to avoid repeated function calls
-	currentRegion := s.getCurrentRegion()
-
-	// Check if the policy contains CloudWatch Logs service principals
-	// Support both generic and region-specific service principals
-	requiredPrincipals := []string{
-		"logs.amazonaws.com",
-		fmt.Sprintf("logs.%s.amazonaws.com", currentRegion),
-	}
-
-	// Also check for AWS service principal patterns in the policy
-	servicePatterns := []string{
-		`"Service": "logs.amazonaws.com"`,
-		fmt.Sprintf(`"Service": "logs.%s.amazonaws.com"`, currentRegion),
-		`"Service": ["logs.amazonaws.com"`,
-		`"Service":["logs.amazonaws.com"`,
-		`"Service": [ "logs.amazonaws.com"`,
-		`"logs.amazonaws.com"`,
-	}
-
-	// Check for required principals first
-	for _, principal := range requiredPrincipals {
-		if strings.Contains(policy, principal) {
-			return true
-		}
-	}
-
-	// Check for service patterns
-	for _, pattern := range servicePatterns {
-		if strings.Contains(policy, pattern) {
-			return true
-		}
-	}
-
-	return false
+	return &KMSKeyStateError{State: keyState}
 }
 
 // validateKMSKeyPolicyForCloudWatchLogs verifies key policies allow CloudWatch Logs service
 func (s *ComplianceService) validateKMSKeyPolicyForCloudWatchLogs(ctx context.Context, keyId string) error {
-	slog.Info("Validating KMS key policy for CloudWatch Logs access",
+	s.getLogger().Info("Validating KMS key policy for CloudWatch Logs access",
 		"kms_key_id", keyId)
 
 	// Get the key policy
 	policyInput := &kms.GetKeyPolicyInput{
 		KeyId:      aws.String(keyId),
-		PolicyName: aws.String("default"),
+		PolicyName: aws.String(s.kmsPolicyName()),
 	}
 
 	policyResult, err := s.kmsClient.GetKeyPolicy(ctx, policyInput)
 	if err != nil {
 		// If we can't access the policy, log a warning but don't fail
 		// This allows customers to use keys where they don't have GetKeyPolicy permissions
-		slog.Warn("Cannot access KMS key policy for validation",
+		s.getLogger().Warn("Cannot access KMS key policy for validation",
 			"kms_key_id", keyId,
 			"error", err,
 			"note", "Proceeding with encryption attempt - ensure key policy allows CloudWatch Logs service")
@@ -605,7 +1353,7 @@ func (s *ComplianceService) validateKMSKeyPolicyForCloudWatchLogs(ctx context.Co
 	}
 
 	if policyResult.Policy == nil {
-		slog.Warn("KMS key policy is empty",
+		s.getLogger().Warn("KMS key policy is empty",
 			"kms_key_id", keyId,
 			"note", "Proceeding with encryption attempt - ensure key policy allows CloudWatch Logs service")
 		return nil
@@ -615,19 +1363,19 @@ func (s *ComplianceService) validateKMSKeyPolicyForCloudWatchLogs(ctx context.Co
 	policyContainsLogsService := s.checkCloudWatchLogsPolicyAccess(policy)
 
 	// Log comprehensive audit information
-	slog.Info("KMS key policy validation audit",
+	s.getLogger().Info("KMS key policy validation audit",
 		"kms_key_id", keyId,
 		"policy_accessible", true,
 		"cloudwatch_logs_access_found", policyContainsLogsService,
 		"validation_timestamp", time.Now().UTC().Format(time.RFC3339))
 
 	if !policyContainsLogsService {
-		slog.Warn("KMS key policy may not include CloudWatch Logs service access",
+		s.getLogger().Warn("KMS key policy may not include CloudWatch Logs service access",
 			"kms_key_id", keyId,
 			"note", "Ensure the key policy allows the CloudWatch Logs service to use this key",
 			"audit_action", AuditActionPolicyValidationWarning)
 	} else {
-		slog.Info("KMS key policy validation successful",
+		s.getLogger().Info("KMS key policy validation successful",
 			"kms_key_id", keyId,
 			"cloudwatch_logs_access", "confirmed",
 			"audit_action", AuditActionPolicyValidationSuccess)
@@ -636,31 +1384,48 @@ func (s *ComplianceService) validateKMSKeyPolicyForCloudWatchLogs(ctx context.Co
 	return nil
 }
 
+// retryBaseDelay returns the configured backoff base delay, raised to MinRetryBaseDelay so that
+// a bare integer (nanoseconds) cannot make retries effectively instantaneous
+func (s *ComplianceService) retryBaseDelay() time.Duration {
+	return max(s.config.RetryBaseDelay, MinRetryBaseDelay)
+}
+
+// retryBackoffDelay returns the exponential backoff before a retry: the base delay doubled per
+// attempt, with the multiplier capped at MaxBackoffMultiplier and the delay at MaxRetryDelay
+func (s *ComplianceService) retryBackoffDelay(attempt int) time.Duration {
+	// Bit shifting gives 2^attempt; capping the attempt first prevents overflow
+	var multiplier int64 = MaxBackoffMultiplier
+	if attempt < MaxExponentialBackoffAttempts {
+		multiplier = int64(1 << attempt)
+	}
+	return min(time.Duration(multiplier)*s.retryBaseDelay(), MaxRetryDelay)
+}
+
+// now returns the current time from the injected clock, falling back to time.Now
+func (s *ComplianceService) now() time.Time {
+	if s.clock == nil {
+		return time.Now()
+	}
+	return s.clock()
+}
+
+// kmsPolicyName returns the key policy name to read, falling back to DefaultKMSPolicyName
+func (s *ComplianceService) kmsPolicyName() string {
+	if s.config.KMSPolicyName == "" {
+		return DefaultKMSPolicyName
+	}
+	return s.config.KMSPolicyName
+}
+
 // associateKMSKeyWithRetry associates a KMS key with the log group with retry logic
 func (s *ComplianceService) associateKMSKeyWithRetry(ctx context.Context, logGroupName, kmsKeyArn string) error {
 	maxRetries := int(s.config.MaxKMSRetries)
 	var lastErr error
 
-	// Maximum delay cap to prevent excessive wait times (30 seconds)
-	const maxDelay = 30 * time.Second
-
 	for attempt := 0; attempt < maxRetries; attempt++ {
 		if attempt > 0 {
-			// Exponential backoff with overflow protection
-			// Use bit shifting for efficient power-of-2 calculations
-			var multiplier int64
-			if attempt < MaxExponentialBackoffAttempts {
-				// Bit shifting is faster and more direct: 1<<attempt gives us 2^attempt
-				multiplier = int64(1 << attempt)
-			} else {
-				// Cap at MaxBackoffMultiplier (2^10) to prevent excessive delays
-				multiplier = MaxBackoffMultiplier
-			}
-			delay := time.Duration(multiplier) * s.config.RetryBaseDelay
-			if delay > maxDelay {
-				delay = maxDelay
-			}
-			slog.Info("Retrying KMS key association",
+			delay := s.retryBackoffDelay(attempt)
+			s.getLogger().Info("Retrying KMS key association",
 				"log_group", logGroupName,
 				"kms_key_id", kmsKeyArn,
 				"attempt", attempt+1,
@@ -675,7 +1440,7 @@ func (s *ComplianceService) associateKMSKeyWithRetry(ctx context.Context, logGro
 
 		_, err := s.logsClient.AssociateKmsKey(ctx, input)
 		if err == nil {
-			slog.Info("Successfully associated KMS key",
+			s.getLogger().Info("Successfully associated KMS key",
 				"log_group", logGroupName,
 				"kms_key_id", kmsKeyArn,
 				"attempts", attempt+1)
@@ -686,7 +1451,7 @@ func (s *ComplianceService) associateKMSKeyWithRetry(ctx context.Context, logGro
 
 		// Check for specific errors that shouldn't be retried
 		if isKMSKeyNotFoundError(err) || isKMSAccessDeniedError(err) || isInvalidLogGroupError(err) {
-			slog.Error("Non-retryable error encountered",
+			s.getLogger().Error("Non-retryable error encountered",
 				"log_group", logGroupName,
 				"kms_key_id", kmsKeyArn,
 				"error", err,
@@ -696,7 +1461,7 @@ func (s *ComplianceService) associateKMSKeyWithRetry(ctx context.Context, logGro
 
 		// Check for rate limiting errors
 		if isRateLimitError(err) {
-			slog.Warn("Rate limit encountered during KMS key association",
+			s.getLogger().Warn("Rate limit encountered during KMS key association",
 				"log_group", logGroupName,
 				"kms_key_id", kmsKeyArn,
 				"attempt", attempt+1,
@@ -704,7 +1469,7 @@ func (s *ComplianceService) associateKMSKeyWithRetry(ctx context.Context, logGro
 			continue
 		}
 
-		slog.Warn("KMS key association failed, will retry",
+		s.getLogger().Warn("KMS key association failed, will retry",
 			"log_group", logGroupName,
 			"kms_key_id", kmsKeyArn,
 			"attempt", attempt+1,
@@ -719,6 +1484,14 @@ func (s *ComplianceService) getCurrentRegion() string {
 	return s.config.Region
 }
 
+// getLogger returns the injected logger, falling back to the process default
+func (s *ComplianceService) getLogger() *slog.Logger {
+	if s.logger == nil {
+		return slog.Default()
+	}
+	return s.logger
+}
+
 // checkAPIErrorCode is a helper function to check if an error matches any of the provided error codes
 func checkAPIErrorCode(err error, errorCodes []string) bool {
 	if err == nil {
@@ -761,11 +1534,16 @@ func isKMSKeyNotFoundError(err error) bool {
 }
 
 func isKMSAccessDeniedError(err error) bool {
+	return isAccessDeniedError(err)
+}
+
+// isAccessDeniedError reports whether an AWS API call was rejected for lack of permission
+func isAccessDeniedError(err error) bool {
 	if err == nil {
 		return false
 	}
 
-	// Check for KMS access denied exception types using errors.As with smithy.APIError
+	// Check for access denied exception types using errors.As with smithy.APIError
 	// This provides consistent error handling across different AWS SDK error types
 	return checkAPIErrorCode(err, []string{
 		"AccessDeniedException",
@@ -775,6 +1553,19 @@ func isKMSAccessDeniedError(err error) bool {
 	})
 }
 
+func isInvalidResultTokenError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var invalidTokenErr *configtypes.InvalidResultTokenException
+	if errors.As(err, &invalidTokenErr) {
+		return true
+	}
+
+	return checkAPIErrorCode(err, []string{"InvalidResultTokenException"})
+}
+
 func isInvalidLogGroupError(err error) bool {
 	if err == nil {
 		return false
@@ -831,9 +1622,139 @@ func getEnvAsIntOrDefault(key string, defaultValue int) int {
 	return defaultValue
 }
 
-// GetNonCompliantResources retrieves non-compliant log groups from Config API
+// GetNonCompliantResources retrieves non-compliant log groups from Config API, merged with any
+// non-compliant groups found under ALSO_PROCESS_PREFIXES and, for an encryption rule, the groups
+// still on a key in OLD_KEY_ARNS, which Config reports compliant
 func (s *ComplianceService) GetNonCompliantResources(ctx context.Context, configRuleName string, region string) ([]types.NonCompliantResource, error) {
-	return s.configEvalService.GetNonCompliantResources(ctx, configRuleName, region)
+	resources, err := s.configEvalService.GetNonCompliantResources(ctx, configRuleName, region)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(s.config.AlsoProcessPrefixes) > 0 {
+		discovered, err := s.discoverPrefixedResources(ctx, configRuleName, region)
+		if err != nil {
+			return nil, err
+		}
+		resources = mergeNonCompliantResources(resources, discovered)
+	}
+
+	if len(s.config.OldKeyArns) > 0 && s.ruleClassifier.ClassifyRule(configRuleName) == types.RuleTypeEncryption {
+		migrating, err := s.discoverMigratingResources(ctx, configRuleName, region)
+		if err != nil {
+			return nil, err
+		}
+		resources = mergeNonCompliantResources(resources, migrating)
+	}
+
+	return resources, nil
+}
+
+// discoverMigratingResources finds the log groups encrypted with a key in OLD_KEY_ARNS, so the
+// batch re-encrypts them with the default key
+func (s *ComplianceService) discoverMigratingResources(ctx context.Context, configRuleName string, region string) ([]types.NonCompliantResource, error) {
+	logGroups, err := s.describeAllLogGroups(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe log groups for key migration: %w", err)
+	}
+
+	var migrating []types.NonCompliantResource
+	for _, logGroup := range logGroups {
+		oldKey := s.retiredKey(aws.ToString(logGroup.KmsKeyId))
+		if oldKey == "" {
+			continue
+		}
+		migrating = append(migrating, types.NonCompliantResource{
+			ResourceId:     aws.ToString(logGroup.LogGroupName),
+			ResourceType:   LogGroupResourceType,
+			ResourceName:   aws.ToString(logGroup.LogGroupName),
+			Region:         region,
+			ComplianceType: "NON_COMPLIANT",
+			Annotation:     fmt.Sprintf("Encrypted with retired KMS key %s listed in OLD_KEY_ARNS", oldKey),
+		})
+	}
+
+	s.getLogger().Info("Discovered log groups on retired KMS keys",
+		"config_rule", configRuleName,
+		"region", region,
+		"old_key_arns", s.config.OldKeyArns,
+		"count", len(migrating))
+
+	return migrating, nil
+}
+
+// GetCompliantResources retrieves the log groups Config last evaluated as compliant with the rule
+func (s *ComplianceService) GetCompliantResources(ctx context.Context, configRuleName string, region string) ([]types.NonCompliantResource, error) {
+	return s.configEvalService.GetCompliantResources(ctx, configRuleName, region)
+}
+
+// discoverPrefixedResources finds log groups under the configured prefixes that are
+// non-compliant for the rule type; these groups are excluded from Config evaluation
+func (s *ComplianceService) discoverPrefixedResources(ctx context.Context, configRuleName string, region string) ([]types.NonCompliantResource, error) {
+	ruleType := s.ruleClassifier.ClassifyRule(configRuleName)
+	if ruleType == types.RuleTypeUnknown {
+		s.getLogger().Warn("Skipping prefix discovery for unknown rule type",
+			"config_rule", configRuleName,
+			"prefixes", s.config.AlsoProcessPrefixes)
+		return nil, nil
+	}
+
+	var discovered []types.NonCompliantResource
+	for _, prefix := range s.config.AlsoProcessPrefixes {
+		logGroups, err := s.describeAllLogGroups(ctx, prefix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe log groups with prefix %s: %w", prefix, err)
+		}
+
+		for _, logGroup := range logGroups {
+			nonCompliant := false
+			switch ruleType {
+			case types.RuleTypeEncryption:
+				nonCompliant = aws.ToString(logGroup.KmsKeyId) == ""
+			case types.RuleTypeRetention:
+				nonCompliant = logGroup.RetentionInDays == nil
+			}
+			if !nonCompliant {
+				continue
+			}
+
+			discovered = append(discovered, types.NonCompliantResource{
+				ResourceId:     aws.ToString(logGroup.LogGroupName),
+				ResourceType:   LogGroupResourceType,
+				ResourceName:   aws.ToString(logGroup.LogGroupName),
+				Region:         region,
+				ComplianceType: "NON_COMPLIANT",
+				Annotation:     fmt.Sprintf("Discovered under ALSO_PROCESS_PREFIXES prefix %s", prefix),
+			})
+		}
+	}
+
+	s.getLogger().Info("Discovered non-compliant log groups under additional prefixes",
+		"config_rule", configRuleName,
+		"region", region,
+		"rule_type", ruleType.String(),
+		"prefixes", s.config.AlsoProcessPrefixes,
+		"count", len(discovered))
+
+	return discovered, nil
+}
+
+// mergeNonCompliantResources appends discovered resources that Config did not already report
+func mergeNonCompliantResources(configResources, discovered []types.NonCompliantResource) []types.NonCompliantResource {
+	seen := make(map[string]bool, len(configResources))
+	for _, resource := range configResources {
+		seen[resource.ResourceName] = true
+	}
+
+	merged := configResources
+	for _, resource := range discovered {
+		if seen[resource.ResourceName] {
+			continue
+		}
+		seen[resource.ResourceName] = true
+		merged = append(merged, resource)
+	}
+	return merged
 }
 
 // ValidateResourceExistence checks if resources still exist before processing
@@ -841,39 +1762,186 @@ func (s *ComplianceService) ValidateResourceExistence(ctx context.Context, resou
 	return s.configEvalService.ValidateResourceExistence(ctx, resources)
 }
 
+// EvaluateCompliance fetches the live log group and reports every compliance requirement it is missing.
+// Callers scope the result to a single Config rule. A log group that is still missing after the
+// creation-lag retries wraps ErrLogGroupNotFound; other lookup failures do not.
+func (s *ComplianceService) EvaluateCompliance(ctx context.Context, logGroupName, region string) (types.ComplianceResult, error) {
+	logGroup, err := s.describeLogGroupWithCreationRetry(ctx, logGroupName)
+	if err != nil {
+		return types.ComplianceResult{}, fmt.Errorf("failed to describe log group %s: %w", logGroupName, err)
+	}
+	if logGroup == nil {
+		return types.ComplianceResult{}, fmt.Errorf("log group %s not found in region %s: %w", logGroupName, region, ErrLogGroupNotFound)
+	}
+
+	kmsKeyId := aws.ToString(logGroup.KmsKeyId)
+	result := types.ComplianceResult{
+		LogGroupName:         logGroupName,
+		Region:               region,
+		AccountId:            s.config.AccountId,
+		MissingEncryption:    kmsKeyId == "",
+		MissingRetention:     logGroup.RetentionInDays == nil,
+		CurrentRetention:     logGroup.RetentionInDays,
+		CurrentKmsKeyId:      kmsKeyId,
+		DataProtectionStatus: string(logGroup.DataProtectionStatus),
+		LiveState:            true,
+	}
+	if s.isMigrating(logGroupName, kmsKeyId) {
+		result.MissingEncryption = true
+	}
+
+	s.getLogger().Info("Evaluated live log group compliance",
+		"log_group", logGroupName,
+		"region", region,
+		"missing_encryption", result.MissingEncryption,
+		"missing_retention", result.MissingRetention,
+		"audit_action", "live_compliance_check")
+
+	return result, nil
+}
+
+// RemediateSingleLogGroup evaluates one named log group against its live state and remediates the
+// requirement checked by ruleType; an unknown rule type remediates nothing. Evaluation failures
+// return a nil result, while remediation failures return the failed result with the error.
+func (s *ComplianceService) RemediateSingleLogGroup(ctx context.Context, region, logGroupName string, ruleType types.RuleType) (*types.RemediationResult, error) {
+	compliance, err := s.EvaluateCompliance(ctx, logGroupName, region)
+	if err != nil {
+		return nil, err
+	}
+	return s.RemediateLogGroup(ctx, ScopeComplianceToRule(compliance, ruleType))
+}
+
+// ScopeComplianceToRule keeps only the requirement a Config rule of ruleType checks, so a live
+// evaluation is remediated the same way as that rule's batch results
+func ScopeComplianceToRule(compliance types.ComplianceResult, ruleType types.RuleType) types.ComplianceResult {
+	switch ruleType {
+	case types.RuleTypeEncryption:
+		compliance.MissingRetention = false
+	case types.RuleTypeRetention:
+		compliance.MissingEncryption = false
+	default:
+		compliance.MissingEncryption = false
+		compliance.MissingRetention = false
+	}
+	return compliance
+}
+
+// ReportEvaluation reports a log group's post-remediation compliance back to Config using the
+// result token from the triggering event. It is a no-op unless REPORT_EVALUATIONS is enabled, and
+// in dry-run mode where nothing was remediated. An expired or invalid token is logged, not returned.
+// Throttled submissions are retried with backoff; an evaluation still throttled is buffered for the
+// next successful report and logged for manual submission, and is only an error with STRICT_EVALUATIONS.
+func (s *ComplianceService) ReportEvaluation(ctx context.Context, resultToken string, configItem types.ConfigurationItem, result *types.RemediationResult) error {
+	if !s.config.ReportEvaluations || s.config.DryRun || resultToken == "" {
+		return nil
+	}
+
+	complianceType := configtypes.ComplianceTypeCompliant
+	if !result.Success || result.SkipReason != "" {
+		complianceType = configtypes.ComplianceTypeNonCompliant
+	}
+	annotation := s.evaluationAnnotation(ctx, configItem.ResourceId, result)
+
+	orderingTimestamp := configItem.ConfigurationItemCaptureTime
+	if orderingTimestamp.IsZero() {
+		orderingTimestamp = time.Now()
+	}
+
+	evaluation := configtypes.Evaluation{
+		ComplianceResourceId:   aws.String(configItem.ResourceId),
+		ComplianceResourceType: aws.String(configItem.ResourceType),
+		ComplianceType:         complianceType,
+		OrderingTimestamp:      aws.Time(orderingTimestamp),
+		Annotation:             aws.String(annotation),
+	}
+	output, err := s.putEvaluationWithRetry(ctx, resultToken, evaluation)
+	if err != nil {
+		if isRateLimitError(err) {
+			s.bufferUnsentEvaluation(resultToken, evaluation, err)
+			if s.config.StrictEvaluations {
+				return fmt.Errorf("failed to report evaluation for %s: %w", configItem.ResourceId, err)
+			}
+			return nil
+		}
+		if isInvalidResultTokenError(err) {
+			s.getLogger().Warn("Config result token expired or invalid, evaluation not reported",
+				"resource_id", configItem.ResourceId,
+				"compliance_type", string(complianceType),
+				"error", err,
+				"audit_action", AuditActionEvaluationTokenExpired)
+			return nil
+		}
+		return fmt.Errorf("failed to report evaluation for %s: %w", configItem.ResourceId, err)
+	}
+	if len(output.FailedEvaluations) > 0 {
+		return fmt.Errorf("config rejected evaluation for %s", configItem.ResourceId)
+	}
+
+	s.getLogger().Info("Reported evaluation to Config",
+		"resource_id", configItem.ResourceId,
+		"compliance_type", string(complianceType),
+		"audit_action", AuditActionEvaluationReported)
+
+	// Config is accepting evaluations again, so retry any that were throttled earlier
+	s.flushUnsentEvaluations(ctx)
+	return nil
+}
+
 // Helper methods
 
-// convertToComplianceResultForRule converts a NonCompliantResource to ComplianceResult based on specific Config rule
-func (s *ComplianceService) convertToComplianceResultForRule(configRuleName string, resource types.NonCompliantResource) types.ComplianceResult {
+// ComplianceForRule decides what a non-compliant resource needs for a Config rule of ruleType.
+// A resource merged from several rules carries the requirements of every rule that flagged it;
+// otherwise each rule evaluates ONLY its specific requirement, and a resource of a rule that checks
+// neither is NotApplicable. Batch remediation and the container's dry run both decide with it, so
+// a dry run previews exactly what an apply would change.
+func ComplianceForRule(ruleType types.RuleType, resource types.NonCompliantResource) types.ComplianceResult {
 	result := types.ComplianceResult{
 		LogGroupName: resource.ResourceName,
 		Region:       resource.Region,
 		AccountId:    resource.AccountId,
 	}
 
-	// Each Config rule evaluates ONLY its specific compliance requirement
-	// This ensures each rule evaluates ALL resources for its requirement independently
-	ruleType := s.ruleClassifier.ClassifyRule(configRuleName)
+	if len(resource.Requirements) > 0 {
+		result.MissingEncryption = slices.Contains(resource.Requirements, types.RuleTypeEncryption)
+		result.MissingRetention = slices.Contains(resource.Requirements, types.RuleTypeRetention)
+		return result
+	}
 
 	switch ruleType {
 	case types.RuleTypeEncryption:
-		// Encryption rule: ONLY evaluate encryption compliance
 		result.MissingEncryption = true // Resource is non-compliant for encryption
-		result.MissingRetention = false // Not this rule's concern
+	case types.RuleTypeRetention:
+		result.MissingRetention = true // Resource is non-compliant for retention
+	default:
+		result.NotApplicable = true
+	}
+	return result
+}
+
+// convertToComplianceResultForRule converts a NonCompliantResource to ComplianceResult based on specific Config rule
+func (s *ComplianceService) convertToComplianceResultForRule(configRuleName string, resource types.NonCompliantResource) types.ComplianceResult {
+	ruleType := s.ruleClassifier.ClassifyRule(configRuleName)
+	result := ComplianceForRule(ruleType, resource)
+
+	switch {
+	case len(resource.Requirements) > 0:
+		s.getLogger().Info("Merged rule batch evaluation",
+			"log_group", resource.ResourceName,
+			"config_rule", configRuleName,
+			"missing_encryption", result.MissingEncryption,
+			"missing_retention", result.MissingRetention,
+			"audit_action", "merged_batch_compliance_check")
 
-		slog.Info("Encryption rule batch evaluation",
+	case ruleType == types.RuleTypeEncryption:
+		s.getLogger().Info("Encryption rule batch evaluation",
 			"log_group", resource.ResourceName,
 			"config_rule", configRuleName,
 			"compliance_type", resource.ComplianceType,
 			"rule_type", ruleType.String(),
 			"audit_action", "encryption_batch_compliance_check")
 
-	case types.RuleTypeRetention:
-		// Retention rule: ONLY evaluate retention compliance
-		result.MissingRetention = true   // Resource is non-compliant for retention
-		result.MissingEncryption = false // Not this rule's concern
-
-		slog.Info("Retention rule batch evaluation",
+	case ruleType == types.RuleTypeRetention:
+		s.getLogger().Info("Retention rule batch evaluation",
 			"log_group", resource.ResourceName,
 			"config_rule", configRuleName,
 			"compliance_type", resource.ComplianceType,
@@ -882,13 +1950,11 @@ func (s *ComplianceService) convertToComplianceResultForRule(configRuleName stri
 
 	default:
 		// Unknown rule - log and skip
-		slog.Warn("Unsupported Config rule in batch - no compliance evaluation performed",
+		s.getLogger().Warn("Unsupported Config rule in batch - no compliance evaluation performed",
 			"config_rule", configRuleName,
 			"log_group", resource.ResourceName,
 			"rule_type", "unknown",
 			"audit_action", "unsupported_rule_batch_skip")
-		result.MissingEncryption = false
-		result.MissingRetention = false
 	}
 
 	return result
@@ -4,15 +4,25 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	cloudwatchtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/aws/aws-sdk-go-v2/service/configservice"
+	configtypes "github.com/aws/aws-sdk-go-v2/service/configservice/types"
 	"github.com/aws/aws-sdk-go-v2/service/kms"
 	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/aws/smithy-go"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"github.com/zsoftly/logguardian/internal/testutil"
 	logguardiantypes "github.com/zsoftly/logguardian/internal/types"
 )
 
@@ -129,6 +139,7 @@ func TestComplianceService_RemediateLogGroup(t *testing.T) {
 			mockLogsClient := &MockCloudWatchLogsClient{
 				AssociateKmsKeyError:    tt.kmsError,
 				PutRetentionPolicyError: tt.logsError,
+				LogGroups:               []types.LogGroup{{LogGroupName: aws.String("/aws/lambda/test")}},
 			}
 
 			keyState := kmstypes.KeyStateEnabled
@@ -151,7 +162,7 @@ func TestComplianceService_RemediateLogGroup(t *testing.T) {
 					DefaultRetentionDays: 365,
 					DryRun:               tt.dryRun,
 					MaxKMSRetries:        3,
-					RetryBaseDelay:       100,
+					RetryBaseDelay:       100 * time.Millisecond,
 				},
 			}
 
@@ -186,6 +197,11 @@ func TestComplianceService_RemediateLogGroup(t *testing.T) {
 				t.Errorf("Expected RetentionApplied=%v, got %v", tt.expectRetention, result.RetentionApplied)
 			}
 
+			expectNoAction := tt.expectedSuccess && !tt.expectEncryption && !tt.expectRetention
+			if result.NoActionNeeded != expectNoAction {
+				t.Errorf("Expected NoActionNeeded=%v, got %v", expectNoAction, result.NoActionNeeded)
+			}
+
 			// Check that the right API calls were made (unless dry run)
 			if !tt.dryRun && tt.expectedSuccess {
 				if tt.expectEncryption && !mockLogsClient.AssociateKmsKeyCalled {
@@ -202,12 +218,132 @@ func TestComplianceService_RemediateLogGroup(t *testing.T) {
 	}
 }
 
+func TestComplianceService_RemediateLogGroup_TargetRetentionOverridesDefault(t *testing.T) {
+	ruleRetention := int32(90)
+
+	tests := []struct {
+		name         string
+		target       *int32
+		expectedDays int32
+	}{
+		{name: "rule retention overrides default", target: &ruleRetention, expectedDays: 90},
+		{name: "default retention without rule parameter", target: nil, expectedDays: 365},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockLogsClient := &MockCloudWatchLogsClient{LogGroups: []types.LogGroup{{LogGroupName: aws.String("/aws/lambda/test")}}}
+			service := &ComplianceService{
+				logsClient:     mockLogsClient,
+				kmsClient:      &MockKMSClient{},
+				ruleClassifier: logguardiantypes.NewRuleClassifier(),
+				config: ServiceConfig{
+					DefaultKMSKeyAlias:   "alias/test-key",
+					DefaultRetentionDays: 365,
+				},
+			}
+
+			result, err := service.RemediateLogGroup(context.Background(), logguardiantypes.ComplianceResult{
+				LogGroupName:        "/aws/lambda/test",
+				Region:              "ca-central-1",
+				MissingRetention:    true,
+				TargetRetentionDays: tt.target,
+			})
+
+			require.NoError(t, err)
+			assert.True(t, result.RetentionApplied)
+			require.NotNil(t, mockLogsClient.PutRetentionPolicyInput)
+			assert.Equal(t, tt.expectedDays, aws.ToInt32(mockLogsClient.PutRetentionPolicyInput.RetentionInDays))
+		})
+	}
+}
+
+func TestComplianceService_RemediateLogGroup_PublishesRemediationDuration(t *testing.T) {
+	cloudwatchClient := &MockCloudWatchClient{}
+	clock := &steppingClock{current: time.Unix(0, 0), step: 10 * time.Millisecond}
+	service := &ComplianceService{
+		logsClient:     &MockCloudWatchLogsClient{LogGroups: []types.LogGroup{{LogGroupName: aws.String("/aws/lambda/test")}}},
+		kmsClient:      &MockKMSClient{},
+		ruleClassifier: logguardiantypes.NewRuleClassifier(),
+		metricsService: &MetricsService{
+			cloudwatchClient: cloudwatchClient,
+			environment:      "test",
+			region:           "ca-central-1",
+			namespace:        "LogGuardian",
+		},
+		clock:  clock.Now,
+		config: ServiceConfig{DefaultRetentionDays: 365},
+	}
+
+	result, err := service.RemediateLogGroup(context.Background(), logguardiantypes.ComplianceResult{
+		LogGroupName:     "/aws/lambda/test",
+		Region:           "ca-central-1",
+		MissingRetention: true,
+	})
+	require.NoError(t, err)
+	require.True(t, result.RetentionApplied)
+
+	require.Len(t, cloudwatchClient.Inputs, 1)
+	var durations []cloudwatchtypes.MetricDatum
+	for _, datum := range cloudwatchClient.Inputs[0].MetricData {
+		if aws.ToString(datum.MetricName) == "RemediationDuration" {
+			durations = append(durations, datum)
+		}
+	}
+	require.Len(t, durations, 1, "Expected the single remediation to publish its duration")
+	require.Len(t, durations[0].Values, 1)
+	assert.Positive(t, durations[0].Values[0])
+	assert.Equal(t, cloudwatchtypes.StandardUnitMilliseconds, durations[0].Unit)
+
+	dimensions := make(map[string]string)
+	for _, dimension := range durations[0].Dimensions {
+		dimensions[aws.ToString(dimension.Name)] = aws.ToString(dimension.Value)
+	}
+	assert.Equal(t, map[string]string{"Environment": "test", "Region": "ca-central-1", "Action": "retention"}, dimensions)
+}
+
+func TestComplianceService_RemediateLogGroup_EmitsEncryptionAuditAction(t *testing.T) {
+	logger, logs := testutil.CaptureLogs(t)
+
+	service := &ComplianceService{
+		logsClient:     &MockCloudWatchLogsClient{},
+		kmsClient:      &MockKMSClient{},
+		ruleClassifier: logguardiantypes.NewRuleClassifier(),
+		config: ServiceConfig{
+			DefaultKMSKeyAlias: "alias/test-key",
+			Region:             "ca-central-1",
+			MaxKMSRetries:      3,
+		},
+		logger: logger,
+	}
+
+	_, err := service.RemediateLogGroup(context.Background(), logguardiantypes.ComplianceResult{
+		LogGroupName:      "/aws/lambda/test",
+		Region:            "ca-central-1",
+		MissingEncryption: true,
+	})
+	require.NoError(t, err)
+
+	assert.True(t, logs.HasAuditAction(AuditActionEncryptionStart))
+	success := logs.WithAttr("audit_action", AuditActionEncryptionSuccess)
+	require.Len(t, success, 1)
+	assert.Equal(t, "/aws/lambda/test", success[0].Attrs["log_group"])
+}
+
 // MockCloudWatchLogsClient implements the CloudWatch Logs client interface for testing
 type MockCloudWatchLogsClient struct {
 	AssociateKmsKeyCalled    bool
 	AssociateKmsKeyError     error
 	PutRetentionPolicyCalled bool
 	PutRetentionPolicyError  error
+	PutRetentionPolicyInput  *cloudwatchlogs.PutRetentionPolicyInput
+	LogGroups                []types.LogGroup   // Returned by DescribeLogGroups
+	DescribeLogGroupsResults [][]types.LogGroup // Returned by successive DescribeLogGroups calls before LogGroups
+	LogGroupPages            [][]types.LogGroup // Returned one page per NextToken, in place of LogGroups, when set
+	DescribeLogGroupsError   error
+	DescribeLogGroupsCalls   int
+	TagResourceInput         *cloudwatchlogs.TagResourceInput
+	TagResourceError         error
 }
 
 func (m *MockCloudWatchLogsClient) AssociateKmsKey(ctx context.Context, params *cloudwatchlogs.AssociateKmsKeyInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.AssociateKmsKeyOutput, error) {
@@ -220,29 +356,75 @@ func (m *MockCloudWatchLogsClient) AssociateKmsKey(ctx context.Context, params *
 
 func (m *MockCloudWatchLogsClient) PutRetentionPolicy(ctx context.Context, params *cloudwatchlogs.PutRetentionPolicyInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.PutRetentionPolicyOutput, error) {
 	m.PutRetentionPolicyCalled = true
+	m.PutRetentionPolicyInput = params
 	if m.PutRetentionPolicyError != nil {
 		return nil, m.PutRetentionPolicyError
 	}
 	return &cloudwatchlogs.PutRetentionPolicyOutput{}, nil
 }
 
+func (m *MockCloudWatchLogsClient) TagResource(ctx context.Context, params *cloudwatchlogs.TagResourceInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.TagResourceOutput, error) {
+	m.TagResourceInput = params
+	if m.TagResourceError != nil {
+		return nil, m.TagResourceError
+	}
+	return &cloudwatchlogs.TagResourceOutput{}, nil
+}
+
 func (m *MockCloudWatchLogsClient) DescribeLogGroups(ctx context.Context, params *cloudwatchlogs.DescribeLogGroupsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeLogGroupsOutput, error) {
+	m.DescribeLogGroupsCalls++
+	if m.DescribeLogGroupsError != nil {
+		return nil, m.DescribeLogGroupsError
+	}
+	if len(m.LogGroupPages) > 0 {
+		return m.describeLogGroupsPage(params.NextToken), nil
+	}
+	logGroups := m.LogGroups
+	if len(m.DescribeLogGroupsResults) > 0 {
+		logGroups = m.DescribeLogGroupsResults[0]
+		m.DescribeLogGroupsResults = m.DescribeLogGroupsResults[1:]
+	}
+	if logGroups == nil {
+		logGroups = []types.LogGroup{}
+	}
 	return &cloudwatchlogs.DescribeLogGroupsOutput{
-		LogGroups: []types.LogGroup{},
+		LogGroups: logGroups,
 	}, nil
 }
 
+// describeLogGroupsPage returns the LogGroupPages page for token, which is "page-N" after the first
+func (m *MockCloudWatchLogsClient) describeLogGroupsPage(token *string) *cloudwatchlogs.DescribeLogGroupsOutput {
+	page := 0
+	if token != nil {
+		page, _ = strconv.Atoi(strings.TrimPrefix(*token, "page-"))
+	}
+	output := &cloudwatchlogs.DescribeLogGroupsOutput{LogGroups: m.LogGroupPages[page]}
+	if page+1 < len(m.LogGroupPages) {
+		output.NextToken = aws.String(fmt.Sprintf("page-%d", page+1))
+	}
+	return output
+}
+
 // MockKMSClient implements the KMS client interface for testing
 type MockKMSClient struct {
 	DescribeKeyCalled  bool
 	DescribeKeyError   error
 	KeyId              string
+	KeyArn             string // Overrides the default same-account key ARN
 	KeyState           kmstypes.KeyState
+	MultiRegion        bool
+	CreationDate       *time.Time
 	GetKeyPolicyCalled bool
 	GetKeyPolicyError  error
 	KeyPolicy          string
+	LastPolicyName     string
 	ListGrantsCalled   bool
 	ListGrantsError    error
+	GrantPages         [][]kmstypes.GrantListEntry // Returned one page per ListGrants call, linked by NextMarker
+	ListGrantsMarkers  []string                    // Marker passed to each ListGrants call
+	Rotations          []kmstypes.RotationsListEntry
+	ListRotationsError error
+	ListRotationsKeyId string // KeyId passed to the last ListKeyRotations call
 }
 
 func (m *MockKMSClient) DescribeKey(ctx context.Context, params *kms.DescribeKeyInput, optFns ...func(*kms.Options)) (*kms.DescribeKeyOutput, error) {
@@ -261,17 +443,25 @@ func (m *MockKMSClient) DescribeKey(ctx context.Context, params *kms.DescribeKey
 		keyState = m.KeyState
 	}
 
+	keyArn := fmt.Sprintf("arn:aws:kms:ca-central-1:123456789012:key/%s", keyId)
+	if m.KeyArn != "" {
+		keyArn = m.KeyArn
+	}
+
 	return &kms.DescribeKeyOutput{
 		KeyMetadata: &kmstypes.KeyMetadata{
-			KeyId:    aws.String(keyId),
-			Arn:      aws.String(fmt.Sprintf("arn:aws:kms:ca-central-1:123456789012:key/%s", keyId)),
-			KeyState: keyState,
+			KeyId:        aws.String(keyId),
+			Arn:          aws.String(keyArn),
+			KeyState:     keyState,
+			MultiRegion:  aws.Bool(m.MultiRegion),
+			CreationDate: m.CreationDate,
 		},
 	}, nil
 }
 
 func (m *MockKMSClient) GetKeyPolicy(ctx context.Context, params *kms.GetKeyPolicyInput, optFns ...func(*kms.Options)) (*kms.GetKeyPolicyOutput, error) {
 	m.GetKeyPolicyCalled = true
+	m.LastPolicyName = aws.ToString(params.PolicyName)
 	if m.GetKeyPolicyError != nil {
 		return nil, m.GetKeyPolicyError
 	}
@@ -304,15 +494,37 @@ func (m *MockKMSClient) GetKeyPolicy(ctx context.Context, params *kms.GetKeyPoli
 	}, nil
 }
 
+func (m *MockKMSClient) ListKeyRotations(ctx context.Context, params *kms.ListKeyRotationsInput, optFns ...func(*kms.Options)) (*kms.ListKeyRotationsOutput, error) {
+	m.ListRotationsKeyId = aws.ToString(params.KeyId)
+	if m.ListRotationsError != nil {
+		return nil, m.ListRotationsError
+	}
+	return &kms.ListKeyRotationsOutput{Rotations: m.Rotations}, nil
+}
+
 func (m *MockKMSClient) ListGrants(ctx context.Context, params *kms.ListGrantsInput, optFns ...func(*kms.Options)) (*kms.ListGrantsOutput, error) {
 	m.ListGrantsCalled = true
+	m.ListGrantsMarkers = append(m.ListGrantsMarkers, aws.ToString(params.Marker))
 	if m.ListGrantsError != nil {
 		return nil, m.ListGrantsError
 	}
 
-	return &kms.ListGrantsOutput{
-		Grants: []kmstypes.GrantListEntry{},
-	}, nil
+	page := 0
+	if params.Marker != nil {
+		page, _ = strconv.Atoi(*params.Marker)
+	}
+	if page >= len(m.GrantPages) {
+		return &kms.ListGrantsOutput{
+			Grants: []kmstypes.GrantListEntry{},
+		}, nil
+	}
+
+	output := &kms.ListGrantsOutput{Grants: m.GrantPages[page]}
+	if page+1 < len(m.GrantPages) {
+		output.Truncated = true
+		output.NextMarker = aws.String(strconv.Itoa(page + 1))
+	}
+	return output, nil
 }
 
 func TestNewComplianceService(t *testing.T) {
@@ -333,6 +545,134 @@ func TestNewComplianceService(t *testing.T) {
 	assert.NotZero(t, service.config.DefaultRetentionDays, "Expected default retention days to be set")
 }
 
+func TestNewCheckedComplianceService_Region(t *testing.T) {
+	tests := []struct {
+		name           string
+		strict         string
+		awsRegion      string
+		cfgRegion      string
+		expectedRegion string
+		expectErr      bool
+	}{
+		{name: "default mode falls back to default region", expectedRegion: DefaultRegion},
+		{name: "default mode ignores strict false", strict: "false", expectedRegion: DefaultRegion},
+		{name: "environment region used", strict: "true", awsRegion: "us-west-2", expectedRegion: "us-west-2"},
+		{name: "strict mode uses config region", strict: "true", cfgRegion: "eu-west-1", expectedRegion: "eu-west-1"},
+		{name: "strict mode without region fails", strict: "true", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("STRICT_REGION", tt.strict)
+			t.Setenv("AWS_REGION", tt.awsRegion)
+			t.Setenv("AWS_DEFAULT_REGION", "")
+
+			service, err := NewCheckedComplianceService(aws.Config{Region: tt.cfgRegion})
+			if tt.expectErr {
+				require.ErrorIs(t, err, ErrRegionNotConfigured)
+				assert.Nil(t, service)
+				assert.Panics(t, func() { NewComplianceService(aws.Config{}) })
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedRegion, service.config.Region)
+		})
+	}
+}
+
+func TestNewComplianceService_BatchLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		envValue string
+		expected int32
+	}{
+		{name: "default", expected: 100},
+		{name: "within range", envValue: "50", expected: 50},
+		{name: "above maximum is clamped", envValue: "500", expected: MaxConfigBatchLimit},
+		{name: "below minimum is clamped", envValue: "0", expected: MinConfigBatchLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("AWS_REGION", "ca-central-1")
+			if tt.envValue != "" {
+				t.Setenv("BATCH_LIMIT", tt.envValue)
+			}
+
+			service := NewComplianceService(aws.Config{Region: "ca-central-1"})
+			assert.Equal(t, tt.expected, service.config.BatchLimit)
+			assert.Equal(t, service.config.BatchLimit, service.configEvalService.config.BatchLimit)
+
+			// The shared limit is the page size of Config queries
+			configClient := &MockConfigServiceClient{}
+			service.configEvalService.configClient = configClient
+			_, err := service.GetNonCompliantResources(context.Background(), "test-rule", "ca-central-1")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, configClient.ComplianceDetailsInput.Limit)
+		})
+	}
+}
+
+func TestNewComplianceService_BatchLimitWarnsThroughServiceLogger(t *testing.T) {
+	t.Setenv("BATCH_LIMIT", "500")
+	logger, logs := testutil.CaptureLogs(t)
+
+	service := NewComplianceService(aws.Config{Region: "ca-central-1"}, WithLogger(logger))
+
+	assert.Equal(t, MaxConfigBatchLimit, service.config.BatchLimit)
+	require.Len(t, logs.WithAttr("clamped", float64(MaxConfigBatchLimit)), 1)
+}
+
+func TestNewComplianceService_NormalizesKMSKeyAlias(t *testing.T) {
+	tests := []struct {
+		name     string
+		envValue string
+		expected string
+	}{
+		{name: "bare name", envValue: "cloudwatch-logs-compliance", expected: "alias/cloudwatch-logs-compliance"},
+		{name: "alias prefixed", envValue: "alias/cloudwatch-logs-compliance", expected: "alias/cloudwatch-logs-compliance"},
+		{name: "full ARN", envValue: "arn:aws:kms:ca-central-1:123456789012:alias/cloudwatch-logs-compliance", expected: "arn:aws:kms:ca-central-1:123456789012:alias/cloudwatch-logs-compliance"},
+		{name: "raw key id", envValue: "1234abcd-12ab-34cd-56ef-1234567890ab", expected: "1234abcd-12ab-34cd-56ef-1234567890ab"},
+		{name: "multi-region key id", envValue: "mrk-1234abcd12ab34cd56ef1234567890ab", expected: "mrk-1234abcd12ab34cd56ef1234567890ab"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("KMS_KEY_ALIAS", tt.envValue)
+
+			service := NewComplianceService(aws.Config{Region: "ca-central-1"})
+			assert.Equal(t, tt.expected, service.config.DefaultKMSKeyAlias)
+		})
+	}
+}
+
+func TestNewComplianceService_NormalizesRetryBaseDelay(t *testing.T) {
+	t.Setenv("RETRY_BASE_DELAY_MS", "0")
+
+	service := NewComplianceService(aws.Config{Region: "ca-central-1"})
+
+	assert.Equal(t, MinRetryBaseDelay, service.config.RetryBaseDelay)
+}
+
+func TestComplianceService_RetryBaseDelay(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured time.Duration
+		expected   time.Duration
+	}{
+		{name: "bare integer nanoseconds raised to minimum", configured: 100, expected: MinRetryBaseDelay},
+		{name: "unset raised to minimum", configured: 0, expected: MinRetryBaseDelay},
+		{name: "millisecond value kept", configured: 100 * time.Millisecond, expected: 100 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &ComplianceService{config: ServiceConfig{RetryBaseDelay: tt.configured}}
+			assert.Equal(t, tt.expected, service.retryBaseDelay())
+		})
+	}
+}
+
 func TestEnvironmentVariableHandling(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -371,3 +711,1348 @@ func TestEnvironmentVariableHandling(t *testing.T) {
 		})
 	}
 }
+
+// MockConfigServiceClient implements the Config client interface for testing
+type MockConfigServiceClient struct {
+	EvaluationResults      []configtypes.EvaluationResult
+	ComplianceDetailsInput *configservice.GetComplianceDetailsByConfigRuleInput
+	PutEvaluationsInput    *configservice.PutEvaluationsInput
+	PutEvaluationsError    error
+	PutEvaluationsErrors   []error // Returned one per call before falling back to PutEvaluationsError
+	PutEvaluationsInputs   []*configservice.PutEvaluationsInput
+}
+
+func (m *MockConfigServiceClient) GetComplianceDetailsByConfigRule(ctx context.Context, params *configservice.GetComplianceDetailsByConfigRuleInput, optFns ...func(*configservice.Options)) (*configservice.GetComplianceDetailsByConfigRuleOutput, error) {
+	m.ComplianceDetailsInput = params
+	return &configservice.GetComplianceDetailsByConfigRuleOutput{
+		EvaluationResults: m.EvaluationResults,
+	}, nil
+}
+
+func (m *MockConfigServiceClient) GetComplianceDetailsByResource(ctx context.Context, params *configservice.GetComplianceDetailsByResourceInput, optFns ...func(*configservice.Options)) (*configservice.GetComplianceDetailsByResourceOutput, error) {
+	return &configservice.GetComplianceDetailsByResourceOutput{}, nil
+}
+
+func (m *MockConfigServiceClient) PutEvaluations(ctx context.Context, params *configservice.PutEvaluationsInput, optFns ...func(*configservice.Options)) (*configservice.PutEvaluationsOutput, error) {
+	m.PutEvaluationsInput = params
+	m.PutEvaluationsInputs = append(m.PutEvaluationsInputs, params)
+	if len(m.PutEvaluationsErrors) > 0 {
+		err := m.PutEvaluationsErrors[0]
+		m.PutEvaluationsErrors = m.PutEvaluationsErrors[1:]
+		if err != nil {
+			return nil, err
+		}
+		return &configservice.PutEvaluationsOutput{}, nil
+	}
+	if m.PutEvaluationsError != nil {
+		return nil, m.PutEvaluationsError
+	}
+	return &configservice.PutEvaluationsOutput{}, nil
+}
+
+func nonCompliantEvaluation(logGroupName string) configtypes.EvaluationResult {
+	return configtypes.EvaluationResult{
+		ComplianceType: configtypes.ComplianceTypeNonCompliant,
+		EvaluationResultIdentifier: &configtypes.EvaluationResultIdentifier{
+			EvaluationResultQualifier: &configtypes.EvaluationResultQualifier{
+				ResourceId:   aws.String(logGroupName),
+				ResourceType: aws.String("AWS::Logs::LogGroup"),
+			},
+		},
+	}
+}
+
+func TestComplianceService_GetNonCompliantResources_AlsoProcessPrefixes(t *testing.T) {
+	tests := []struct {
+		name           string
+		configRuleName string
+		prefixes       []string
+		expected       []string
+	}{
+		{
+			name:           "no prefixes returns Config results only",
+			configRuleName: "cloudwatch-log-group-retention",
+			expected:       []string{"/aws/lambda/app", "/aws/system/no-retention-no-key"},
+		},
+		{
+			name:           "retention rule merges groups without retention and skips duplicates",
+			configRuleName: "cloudwatch-log-group-retention",
+			prefixes:       []string{"/aws/system/"},
+			expected:       []string{"/aws/lambda/app", "/aws/system/no-retention-no-key", "/aws/system/key-only"},
+		},
+		{
+			name:           "encryption rule merges groups without a KMS key",
+			configRuleName: "cloudwatch-log-group-encrypted",
+			prefixes:       []string{"/aws/system/"},
+			expected:       []string{"/aws/lambda/app", "/aws/system/no-retention-no-key", "/aws/system/retention-only"},
+		},
+		{
+			name:           "unknown rule type skips discovery",
+			configRuleName: "some-other-rule",
+			prefixes:       []string{"/aws/system/"},
+			expected:       []string{"/aws/lambda/app", "/aws/system/no-retention-no-key"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockLogs := new(MockLogsClientOptimized)
+			mockLogs.On("DescribeLogGroups", mock.Anything, mock.MatchedBy(func(params *cloudwatchlogs.DescribeLogGroupsInput) bool {
+				return aws.ToString(params.LogGroupNamePrefix) == "/aws/system/"
+			})).Return(&cloudwatchlogs.DescribeLogGroupsOutput{
+				LogGroups: []types.LogGroup{
+					{LogGroupName: aws.String("/aws/system/no-retention-no-key")},
+					{LogGroupName: aws.String("/aws/system/retention-only"), RetentionInDays: aws.Int32(30)},
+					{LogGroupName: aws.String("/aws/system/key-only"), KmsKeyId: aws.String("arn:aws:kms:ca-central-1:123456789012:key/abc")},
+					{LogGroupName: aws.String("/aws/system/compliant"), RetentionInDays: aws.Int32(30), KmsKeyId: aws.String("arn:aws:kms:ca-central-1:123456789012:key/abc")},
+				},
+			}, nil).Maybe()
+
+			service := &ComplianceService{
+				logsClient: mockLogs,
+				configEvalService: &ConfigEvaluationService{
+					configClient: &MockConfigServiceClient{
+						EvaluationResults: []configtypes.EvaluationResult{
+							nonCompliantEvaluation("/aws/lambda/app"),
+							// Also matched by prefix discovery; must not be duplicated
+							nonCompliantEvaluation("/aws/system/no-retention-no-key"),
+						},
+					},
+				},
+				ruleClassifier: logguardiantypes.NewRuleClassifier(),
+				config: ServiceConfig{
+					Region:              "ca-central-1",
+					AlsoProcessPrefixes: tt.prefixes,
+				},
+			}
+
+			resources, err := service.GetNonCompliantResources(context.Background(), tt.configRuleName, "ca-central-1")
+			require.NoError(t, err)
+
+			var names []string
+			for _, resource := range resources {
+				names = append(names, resource.ResourceName)
+			}
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+// threeLogGroupPages splits seven /aws/system/ log groups across three DescribeLogGroups pages
+func threeLogGroupPages() [][]types.LogGroup {
+	return [][]types.LogGroup{
+		{
+			{LogGroupName: aws.String("/aws/system/a")},
+			{LogGroupName: aws.String("/aws/system/b"), RetentionInDays: aws.Int32(30)},
+			{LogGroupName: aws.String("/aws/system/c")},
+		},
+		{
+			{LogGroupName: aws.String("/aws/system/d"), RetentionInDays: aws.Int32(30)},
+			{LogGroupName: aws.String("/aws/system/e")},
+		},
+		{
+			{LogGroupName: aws.String("/aws/system/f")},
+			{LogGroupName: aws.String("/aws/system/g"), RetentionInDays: aws.Int32(7)},
+		},
+	}
+}
+
+func TestComplianceService_DescribeAllLogGroups_Pagination(t *testing.T) {
+	mockLogs := &MockCloudWatchLogsClient{LogGroupPages: threeLogGroupPages()}
+	service := &ComplianceService{logsClient: mockLogs}
+
+	logGroups, err := service.describeAllLogGroups(context.Background(), "/aws/system/")
+	require.NoError(t, err)
+
+	var names []string
+	for _, logGroup := range logGroups {
+		names = append(names, aws.ToString(logGroup.LogGroupName))
+	}
+	assert.Equal(t, []string{"/aws/system/a", "/aws/system/b", "/aws/system/c", "/aws/system/d", "/aws/system/e", "/aws/system/f", "/aws/system/g"}, names)
+	assert.Equal(t, 3, mockLogs.DescribeLogGroupsCalls)
+
+	// A group on the last page is still found by exact-name lookups
+	mockLogs.DescribeLogGroupsCalls = 0
+	logGroup, err := service.describeLogGroup(context.Background(), "/aws/system/g")
+	require.NoError(t, err)
+	require.NotNil(t, logGroup)
+	assert.Equal(t, int32(7), aws.ToInt32(logGroup.RetentionInDays))
+	assert.Equal(t, 3, mockLogs.DescribeLogGroupsCalls)
+
+	// Lookups stop paging at the exact match
+	mockLogs.DescribeLogGroupsCalls = 0
+	logGroup, err = service.describeLogGroup(context.Background(), "/aws/system/b")
+	require.NoError(t, err)
+	require.NotNil(t, logGroup)
+	assert.Equal(t, "/aws/system/b", aws.ToString(logGroup.LogGroupName))
+	assert.Equal(t, 1, mockLogs.DescribeLogGroupsCalls)
+}
+
+func TestComplianceService_GetNonCompliantResources_AlsoProcessPrefixesPagination(t *testing.T) {
+	service := &ComplianceService{
+		logsClient: &MockCloudWatchLogsClient{LogGroupPages: threeLogGroupPages()},
+		configEvalService: &ConfigEvaluationService{
+			configClient: &MockConfigServiceClient{},
+		},
+		ruleClassifier: logguardiantypes.NewRuleClassifier(),
+		config: ServiceConfig{
+			Region:              "ca-central-1",
+			AlsoProcessPrefixes: []string{"/aws/system/"},
+		},
+	}
+
+	resources, err := service.GetNonCompliantResources(context.Background(), "cloudwatch-log-group-retention", "ca-central-1")
+	require.NoError(t, err)
+
+	var names []string
+	for _, resource := range resources {
+		names = append(names, resource.ResourceName)
+	}
+	assert.Equal(t, []string{"/aws/system/a", "/aws/system/c", "/aws/system/e", "/aws/system/f"}, names)
+}
+
+func TestComplianceService_GetNonCompliantResources_OldKeyArns(t *testing.T) {
+	const oldKeyArn = "arn:aws:kms:ca-central-1:123456789012:key/old"
+
+	for _, tt := range []struct {
+		configRuleName string
+		expected       []string
+	}{
+		{configRuleName: "cloudwatch-log-group-encrypted", expected: []string{"/aws/lambda/app", "/aws/lambda/old-key"}},
+		{configRuleName: "cloudwatch-log-group-retention", expected: []string{"/aws/lambda/app"}},
+	} {
+		t.Run(tt.configRuleName, func(t *testing.T) {
+			mockLogs := new(MockLogsClientOptimized)
+			mockLogs.On("DescribeLogGroups", mock.Anything, mock.MatchedBy(func(params *cloudwatchlogs.DescribeLogGroupsInput) bool {
+				return params.LogGroupNamePrefix == nil
+			})).Return(&cloudwatchlogs.DescribeLogGroupsOutput{
+				LogGroups: []types.LogGroup{
+					{LogGroupName: aws.String("/aws/lambda/app")},
+					{LogGroupName: aws.String("/aws/lambda/old-key"), KmsKeyId: aws.String(oldKeyArn)},
+					{LogGroupName: aws.String("/aws/lambda/new-key"), KmsKeyId: aws.String("arn:aws:kms:ca-central-1:123456789012:key/new")},
+				},
+			}, nil).Maybe()
+
+			// Config reports the group on the retired key compliant, so only discovery finds it
+			service := &ComplianceService{
+				logsClient: mockLogs,
+				configEvalService: &ConfigEvaluationService{
+					configClient: &MockConfigServiceClient{
+						EvaluationResults: []configtypes.EvaluationResult{nonCompliantEvaluation("/aws/lambda/app")},
+					},
+				},
+				ruleClassifier: logguardiantypes.NewRuleClassifier(),
+				config: ServiceConfig{
+					Region:     "ca-central-1",
+					OldKeyArns: []string{oldKeyArn},
+				},
+			}
+
+			resources, err := service.GetNonCompliantResources(context.Background(), tt.configRuleName, "ca-central-1")
+			require.NoError(t, err)
+
+			var names []string
+			for _, resource := range resources {
+				names = append(names, resource.ResourceName)
+			}
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+func TestComplianceService_RemediateLogGroup_KMSKeyAccount(t *testing.T) {
+	const crossAccountKeyArn = "arn:aws:kms:ca-central-1:210987654321:key/12345678-1234-1234-1234-123456789012"
+
+	tests := []struct {
+		name              string
+		keyAlias          string
+		keyArn            string
+		allowCrossAccount bool
+		expectSuccess     bool
+		expectAuditAction string
+	}{
+		{
+			name:          "same-account alias",
+			keyAlias:      "alias/test-key",
+			expectSuccess: true,
+		},
+		{
+			name:          "cross-account key ARN rejected by default",
+			keyAlias:      crossAccountKeyArn,
+			keyArn:        crossAccountKeyArn,
+			expectSuccess: false,
+		},
+		{
+			name:              "cross-account key ARN allowed when opted in",
+			keyAlias:          crossAccountKeyArn,
+			keyArn:            crossAccountKeyArn,
+			allowCrossAccount: true,
+			expectSuccess:     true,
+			expectAuditAction: AuditActionCrossAccountKeyUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, logs := testutil.CaptureLogs(t)
+			mockLogsClient := &MockCloudWatchLogsClient{}
+
+			service := &ComplianceService{
+				logsClient:     mockLogsClient,
+				kmsClient:      &MockKMSClient{KeyArn: tt.keyArn},
+				ruleClassifier: logguardiantypes.NewRuleClassifier(),
+				logger:         logger,
+				config: ServiceConfig{
+					DefaultKMSKeyAlias:      tt.keyAlias,
+					DefaultRetentionDays:    365,
+					Region:                  "ca-central-1",
+					MaxKMSRetries:           3,
+					RetryBaseDelay:          100 * time.Millisecond,
+					AllowCrossAccountKMSKey: tt.allowCrossAccount,
+				},
+			}
+
+			result, err := service.RemediateLogGroup(context.Background(), logguardiantypes.ComplianceResult{
+				LogGroupName:      "/aws/lambda/test",
+				Region:            "ca-central-1",
+				AccountId:         "123456789012",
+				MissingEncryption: true,
+			})
+
+			if tt.expectSuccess {
+				require.NoError(t, err)
+				assert.True(t, result.EncryptionApplied)
+				assert.True(t, mockLogsClient.AssociateKmsKeyCalled)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "ALLOW_CROSS_ACCOUNT_KMS_KEY")
+				assert.False(t, mockLogsClient.AssociateKmsKeyCalled)
+			}
+
+			if tt.expectAuditAction != "" {
+				assert.True(t, logs.HasAuditAction(tt.expectAuditAction))
+			}
+		})
+	}
+}
+
+func TestComplianceService_RemediateLogGroup_ForbidCrossRegionKey(t *testing.T) {
+	const westKeyArn = "arn:aws:kms:ca-west-1:123456789012:key/12345678-1234-1234-1234-123456789012"
+
+	tests := []struct {
+		name              string
+		keyArn            string
+		multiRegion       bool
+		forbid            bool
+		expectSuccess     bool
+		expectAuditAction string
+	}{
+		{
+			name:          "same-region key allowed when forbidden",
+			forbid:        true,
+			expectSuccess: true,
+		},
+		{
+			name:   "cross-region single-Region key rejected when forbidden",
+			keyArn: westKeyArn,
+			forbid: true,
+		},
+		{
+			name:              "cross-region Multi-Region key allowed when forbidden",
+			keyArn:            westKeyArn,
+			multiRegion:       true,
+			forbid:            true,
+			expectSuccess:     true,
+			expectAuditAction: AuditActionCrossRegionKeyUsage,
+		},
+		{
+			name:              "cross-region key only warned by default",
+			keyArn:            westKeyArn,
+			expectSuccess:     true,
+			expectAuditAction: AuditActionCrossRegionKeyUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, logs := testutil.CaptureLogs(t)
+			mockLogsClient := &MockCloudWatchLogsClient{}
+
+			service := &ComplianceService{
+				logsClient:     mockLogsClient,
+				kmsClient:      &MockKMSClient{KeyArn: tt.keyArn, MultiRegion: tt.multiRegion},
+				ruleClassifier: logguardiantypes.NewRuleClassifier(),
+				logger:         logger,
+				config: ServiceConfig{
+					DefaultKMSKeyAlias:   "alias/test-key",
+					DefaultRetentionDays: 365,
+					Region:               "ca-central-1",
+					MaxKMSRetries:        3,
+					RetryBaseDelay:       100 * time.Millisecond,
+					ForbidCrossRegionKey: tt.forbid,
+				},
+			}
+
+			result, err := service.RemediateLogGroup(context.Background(), logguardiantypes.ComplianceResult{
+				LogGroupName:      "/aws/lambda/test",
+				Region:            "ca-central-1",
+				MissingEncryption: true,
+			})
+
+			if tt.expectSuccess {
+				require.NoError(t, err)
+				assert.True(t, result.EncryptionApplied)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "FORBID_CROSS_REGION_KEY")
+				var auditErr *AuditError
+				require.ErrorAs(t, err, &auditErr)
+				assert.Equal(t, FailureReasonCrossRegionKey, auditErr.Reason)
+				assert.False(t, mockLogsClient.AssociateKmsKeyCalled)
+				assert.True(t, logs.HasAuditAction(AuditActionKeyValidationFailed))
+			}
+
+			if tt.expectAuditAction != "" {
+				assert.True(t, logs.HasAuditAction(tt.expectAuditAction))
+			}
+		})
+	}
+}
+
+func TestParseKMSKeyArn(t *testing.T) {
+	tests := []struct {
+		name            string
+		arn             string
+		expectedRegion  string
+		expectedAccount string
+	}{
+		{
+			name:            "same-account key ARN",
+			arn:             "arn:aws:kms:ca-central-1:123456789012:key/12345678-1234-1234-1234-123456789012",
+			expectedRegion:  "ca-central-1",
+			expectedAccount: "123456789012",
+		},
+		{
+			name:            "cross-account key ARN",
+			arn:             "arn:aws:kms:ca-west-1:210987654321:key/12345678-1234-1234-1234-123456789012",
+			expectedRegion:  "ca-west-1",
+			expectedAccount: "210987654321",
+		},
+		{
+			name: "not an ARN",
+			arn:  "alias/test-key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			region, accountId := parseKMSKeyArn(tt.arn)
+			assert.Equal(t, tt.expectedRegion, region)
+			assert.Equal(t, tt.expectedAccount, accountId)
+		})
+	}
+}
+
+func TestComplianceService_ValidateKMSKeyComprehensively_CrossAccount(t *testing.T) {
+	tests := []struct {
+		name               string
+		keyArn             string
+		expectCrossAccount bool
+	}{
+		{
+			name:   "same-account key",
+			keyArn: "arn:aws:kms:ca-central-1:123456789012:key/12345678-1234-1234-1234-123456789012",
+		},
+		{
+			name:               "cross-account key",
+			keyArn:             "arn:aws:kms:ca-central-1:210987654321:key/12345678-1234-1234-1234-123456789012",
+			expectCrossAccount: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &ComplianceService{
+				kmsClient: &MockKMSClient{KeyArn: tt.keyArn},
+				config: ServiceConfig{
+					Region:    "ca-central-1",
+					AccountId: "123456789012",
+				},
+			}
+
+			report, err := service.ValidateKMSKeyComprehensively(context.Background(), tt.keyArn)
+			require.NoError(t, err)
+
+			expectedWarnings := 0
+			if tt.expectCrossAccount {
+				expectedWarnings = 1
+			}
+			testutil.AssertKMSReport(t, report, testutil.KMSReportExpectation{
+				KeyExists:      testutil.Bool(true),
+				KeyAccessible:  testutil.Bool(true),
+				IsCrossAccount: testutil.Bool(tt.expectCrossAccount),
+				ErrorCount:     testutil.Int(0),
+				WarningCount:   testutil.Int(expectedWarnings),
+			})
+			if tt.expectCrossAccount {
+				assert.Equal(t, "210987654321", report.KeyAccount)
+				assert.Contains(t, strings.Join(report.RecommendedActions, "\n"), "owning account")
+			} else {
+				assert.Equal(t, "123456789012", report.KeyAccount)
+			}
+		})
+	}
+}
+
+func TestComplianceService_ValidateKMSKeyComprehensively_KeyState(t *testing.T) {
+	tests := []struct {
+		name        string
+		kmsClient   *MockKMSClient
+		expectExist bool
+		expectState string
+	}{
+		{
+			name:        "disabled key exists but is not accessible",
+			kmsClient:   &MockKMSClient{KeyState: kmstypes.KeyStateDisabled},
+			expectExist: true,
+			expectState: "Disabled",
+		},
+		{
+			name:      "missing key",
+			kmsClient: &MockKMSClient{DescribeKeyError: &kmstypes.NotFoundException{Message: aws.String("not found")}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &ComplianceService{
+				kmsClient: tt.kmsClient,
+				config:    ServiceConfig{Region: "ca-central-1"},
+			}
+
+			report, err := service.ValidateKMSKeyComprehensively(context.Background(), "alias/test-key")
+			require.NoError(t, err)
+
+			testutil.AssertKMSReport(t, report, testutil.KMSReportExpectation{
+				KeyExists:     testutil.Bool(tt.expectExist),
+				KeyAccessible: testutil.Bool(false),
+				ErrorCount:    testutil.Int(1),
+			})
+			assert.Equal(t, tt.expectState, report.KeyState)
+		})
+	}
+}
+
+func TestComplianceService_ValidateKMSKeyComprehensively_NotFoundRecommendation(t *testing.T) {
+	tests := []struct {
+		name           string
+		keyId          string
+		expectReason   string
+		expectedAction string
+	}{
+		{
+			name:           "alias not found recommends creating the alias",
+			keyId:          "alias/missing-key",
+			expectReason:   FailureReasonAliasNotFound,
+			expectedAction: "Create alias alias/missing-key for an existing KMS key in region ca-central-1",
+		},
+		{
+			name:           "alias ARN not found recommends creating the alias",
+			keyId:          "arn:aws:kms:ca-central-1:123456789012:alias/missing-key",
+			expectReason:   FailureReasonAliasNotFound,
+			expectedAction: "Create alias arn:aws:kms:ca-central-1:123456789012:alias/missing-key",
+		},
+		{
+			name:           "key ID not found recommends creating a key",
+			keyId:          "12345678-1234-1234-1234-123456789012",
+			expectReason:   FailureReasonKeyNotFound,
+			expectedAction: "Create a KMS key in region ca-central-1",
+		},
+		{
+			name:           "key ARN not found recommends creating a key",
+			keyId:          "arn:aws:kms:ca-central-1:123456789012:key/12345678-1234-1234-1234-123456789012",
+			expectReason:   FailureReasonKeyNotFound,
+			expectedAction: "Create a KMS key in region ca-central-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &ComplianceService{
+				kmsClient: &MockKMSClient{DescribeKeyError: &kmstypes.NotFoundException{Message: aws.String("not found")}},
+				config:    ServiceConfig{Region: "ca-central-1"},
+			}
+
+			_, err := service.validateKMSKeyAccessibility(context.Background(), tt.keyId)
+			var auditErr *AuditError
+			require.ErrorAs(t, err, &auditErr)
+			assert.Equal(t, tt.expectReason, auditErr.Reason)
+
+			report, err := service.ValidateKMSKeyComprehensively(context.Background(), tt.keyId)
+			require.NoError(t, err)
+			assert.False(t, report.KeyExists)
+			require.Len(t, report.RecommendedActions, 1)
+			assert.True(t, strings.HasPrefix(report.RecommendedActions[0], tt.expectedAction), report.RecommendedActions[0])
+		})
+	}
+}
+
+func TestComplianceService_KMSPolicyName(t *testing.T) {
+	tests := []struct {
+		name           string
+		configured     string
+		expectedPolicy string
+	}{
+		{name: "unset uses default policy", configured: "", expectedPolicy: DefaultKMSPolicyName},
+		{name: "custom policy name", configured: "logs-policy", expectedPolicy: "logs-policy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kmsClient := &MockKMSClient{}
+			service := &ComplianceService{
+				kmsClient: kmsClient,
+				config: ServiceConfig{
+					Region:        "ca-central-1",
+					KMSPolicyName: tt.configured,
+				},
+			}
+
+			_, err := service.ValidateKMSKeyComprehensively(context.Background(), "alias/test-key")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedPolicy, kmsClient.LastPolicyName)
+
+			kmsClient.LastPolicyName = ""
+			require.NoError(t, service.validateKMSKeyPolicyForCloudWatchLogs(context.Background(), "key-12345"))
+			assert.Equal(t, tt.expectedPolicy, kmsClient.LastPolicyName)
+		})
+	}
+}
+
+func TestComplianceService_RemediateLogGroup_RetentionReductionGuard(t *testing.T) {
+	tests := []struct {
+		name             string
+		status           string
+		currentRetention *int32
+		liveStatus       types.DataProtectionStatus
+		liveRetention    *int32
+		allowReduction   bool
+		expectApplied    bool
+	}{
+		{
+			name:             "reduction blocked on data-protected group",
+			status:           "ACTIVATED",
+			currentRetention: aws.Int32(3653),
+		},
+		{
+			name:             "reduction allowed with explicit opt-in",
+			status:           "ACTIVATED",
+			currentRetention: aws.Int32(3653),
+			allowReduction:   true,
+			expectApplied:    true,
+		},
+		{
+			name:             "increase always allowed on data-protected group",
+			status:           "ACTIVATED",
+			currentRetention: aws.Int32(30),
+			expectApplied:    true,
+		},
+		{
+			name:   "never-expire group counts as a reduction",
+			status: "ACTIVATED",
+		},
+		{
+			name:          "data protection read from live state",
+			liveStatus:    types.DataProtectionStatusActivated,
+			liveRetention: aws.Int32(3653),
+		},
+		{
+			name:             "reduction allowed without data protection",
+			status:           "DISABLED",
+			currentRetention: aws.Int32(3653),
+			expectApplied:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, logs := testutil.CaptureLogs(t)
+			mockLogsClient := &MockCloudWatchLogsClient{
+				LogGroups: []types.LogGroup{
+					{
+						LogGroupName:         aws.String("/aws/lambda/test"),
+						DataProtectionStatus: tt.liveStatus,
+						RetentionInDays:      tt.liveRetention,
+					},
+				},
+			}
+
+			service := &ComplianceService{
+				logsClient:     mockLogsClient,
+				ruleClassifier: logguardiantypes.NewRuleClassifier(),
+				logger:         logger,
+				config: ServiceConfig{
+					DefaultRetentionDays:    365,
+					Region:                  "ca-central-1",
+					AllowRetentionReduction: tt.allowReduction,
+				},
+			}
+
+			result, err := service.RemediateLogGroup(context.Background(), logguardiantypes.ComplianceResult{
+				LogGroupName:         "/aws/lambda/test",
+				Region:               "ca-central-1",
+				MissingRetention:     true,
+				CurrentRetention:     tt.currentRetention,
+				DataProtectionStatus: tt.status,
+			})
+
+			require.NoError(t, err)
+			assert.True(t, result.Success)
+			assert.Equal(t, tt.expectApplied, result.RetentionApplied)
+			assert.Equal(t, tt.expectApplied, mockLogsClient.PutRetentionPolicyCalled)
+			assert.False(t, result.NoActionNeeded, "a skipped reduction is not reported as already compliant")
+			assert.Equal(t, !tt.expectApplied, result.SkipReason != "")
+			assert.Equal(t, !tt.expectApplied, logs.HasAuditAction(AuditActionRetentionReductionBlocked))
+		})
+	}
+}
+
+func TestComplianceService_RemediateLogGroup_DescribeAccessDenied(t *testing.T) {
+	accessDenied := &smithy.GenericAPIError{Code: "AccessDeniedException", Message: "not authorized to perform logs:DescribeLogGroups"}
+
+	newService := func(logger *slog.Logger, strict bool) (*ComplianceService, *MockCloudWatchLogsClient) {
+		mockLogsClient := &MockCloudWatchLogsClient{DescribeLogGroupsError: accessDenied}
+		return &ComplianceService{
+			logsClient:     mockLogsClient,
+			kmsClient:      &MockKMSClient{},
+			ruleClassifier: logguardiantypes.NewRuleClassifier(),
+			logger:         logger,
+			config: ServiceConfig{
+				DefaultKMSKeyAlias:   "alias/test-key",
+				DefaultRetentionDays: 365,
+				Region:               "ca-central-1",
+				MaxKMSRetries:        3,
+				RetryBaseDelay:       time.Millisecond,
+				RekeyPolicy:          RekeyPolicyOnlyIfUnencrypted,
+				StrictDescribe:       strict,
+			},
+		}, mockLogsClient
+	}
+	compliance := logguardiantypes.ComplianceResult{
+		LogGroupName:      "/aws/lambda/test",
+		Region:            "ca-central-1",
+		MissingEncryption: true,
+		MissingRetention:  true,
+	}
+
+	t.Run("re-key guard skips encryption it cannot verify", func(t *testing.T) {
+		logger, logs := testutil.CaptureLogs(t)
+		service, mockLogsClient := newService(logger, false)
+		service.config.AllowRetentionReduction = true
+
+		result, err := service.RemediateLogGroup(context.Background(), compliance)
+
+		require.NoError(t, err)
+		assert.True(t, result.Success)
+		assert.False(t, result.EncryptionApplied)
+		assert.False(t, mockLogsClient.AssociateKmsKeyCalled)
+		assert.True(t, result.RetentionApplied)
+		assert.Contains(t, result.SkipReason, "existing KMS key of log group could not be read")
+		assert.Len(t, logs.WithAttr("audit_action", AuditActionDescribeAccessDenied), 1)
+	})
+
+	t.Run("retention guard skips retention it cannot verify", func(t *testing.T) {
+		logger, logs := testutil.CaptureLogs(t)
+		service, mockLogsClient := newService(logger, false)
+		service.config.RekeyPolicy = RekeyPolicyAlways
+
+		result, err := service.RemediateLogGroup(context.Background(), compliance)
+
+		require.NoError(t, err)
+		assert.True(t, result.Success)
+		assert.True(t, result.EncryptionApplied)
+		assert.False(t, result.RetentionApplied)
+		assert.False(t, mockLogsClient.PutRetentionPolicyCalled)
+		assert.Contains(t, result.SkipReason, "data protection status of log group could not be read")
+		assert.True(t, logs.HasAuditAction(AuditActionRetentionReductionBlocked))
+	})
+
+	t.Run("both guards share one describe", func(t *testing.T) {
+		logger, logs := testutil.CaptureLogs(t)
+		service, mockLogsClient := newService(logger, false)
+
+		result, err := service.RemediateLogGroup(context.Background(), compliance)
+
+		require.NoError(t, err)
+		assert.False(t, result.EncryptionApplied)
+		assert.False(t, result.RetentionApplied)
+		assert.Contains(t, result.SkipReason, "existing KMS key of log group could not be read")
+		assert.Contains(t, result.SkipReason, "data protection status of log group could not be read")
+		assert.Equal(t, 1, mockLogsClient.DescribeLogGroupsCalls)
+		assert.Len(t, logs.WithAttr("audit_action", AuditActionDescribeAccessDenied), 1)
+	})
+
+	t.Run("strict mode fails", func(t *testing.T) {
+		logger, logs := testutil.CaptureLogs(t)
+		service, mockLogsClient := newService(logger, true)
+
+		result, err := service.RemediateLogGroup(context.Background(), compliance)
+
+		require.Error(t, err)
+		assert.ErrorAs(t, err, new(smithy.APIError))
+		assert.False(t, result.Success)
+		assert.False(t, mockLogsClient.AssociateKmsKeyCalled)
+		assert.False(t, logs.HasAuditAction(AuditActionDescribeAccessDenied))
+	})
+
+	t.Run("other errors are not trusted away", func(t *testing.T) {
+		logger, _ := testutil.CaptureLogs(t)
+		service, mockLogsClient := newService(logger, false)
+		mockLogsClient.DescribeLogGroupsError = &smithy.GenericAPIError{Code: "ServiceUnavailableException"}
+
+		_, err := service.RemediateLogGroup(context.Background(), compliance)
+		require.Error(t, err)
+	})
+
+	t.Run("missing log group is not treated as unprotected", func(t *testing.T) {
+		logger, _ := testutil.CaptureLogs(t)
+		service, mockLogsClient := newService(logger, false)
+		mockLogsClient.DescribeLogGroupsError = nil
+
+		result, err := service.RemediateLogGroup(context.Background(), compliance)
+
+		require.ErrorIs(t, err, ErrLogGroupNotFound)
+		assert.False(t, result.Success)
+		assert.False(t, mockLogsClient.AssociateKmsKeyCalled)
+		assert.False(t, mockLogsClient.PutRetentionPolicyCalled)
+	})
+}
+
+func TestComplianceService_EvaluateCompliance(t *testing.T) {
+	mockLogsClient := &MockCloudWatchLogsClient{
+		LogGroups: []types.LogGroup{
+			{LogGroupName: aws.String("/aws/lambda/encrypted"), KmsKeyId: aws.String("arn:aws:kms:ca-central-1:123456789012:key/abc")},
+			{LogGroupName: aws.String("/aws/lambda/retained"), RetentionInDays: aws.Int32(30)},
+		},
+	}
+	service := &ComplianceService{
+		logsClient: mockLogsClient,
+		config:     ServiceConfig{Region: "ca-central-1"},
+	}
+
+	encrypted, err := service.EvaluateCompliance(context.Background(), "/aws/lambda/encrypted", "ca-central-1")
+	require.NoError(t, err)
+	assert.False(t, encrypted.MissingEncryption)
+	assert.True(t, encrypted.MissingRetention)
+
+	retained, err := service.EvaluateCompliance(context.Background(), "/aws/lambda/retained", "ca-central-1")
+	require.NoError(t, err)
+	assert.True(t, retained.MissingEncryption)
+	assert.False(t, retained.MissingRetention)
+	assert.Equal(t, int32(30), *retained.CurrentRetention)
+
+	_, err = service.EvaluateCompliance(context.Background(), "/aws/lambda/missing", "ca-central-1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "log group /aws/lambda/missing not found in region ca-central-1")
+	assert.ErrorIs(t, err, ErrLogGroupNotFound)
+}
+
+func TestComplianceService_RemediateSingleLogGroup(t *testing.T) {
+	tests := []struct {
+		name             string
+		logGroup         types.LogGroup
+		ruleType         logguardiantypes.RuleType
+		expectEncryption bool
+		expectRetention  bool
+	}{
+		{
+			name:             "encryption needed",
+			logGroup:         types.LogGroup{LogGroupName: aws.String("/aws/lambda/test")},
+			ruleType:         logguardiantypes.RuleTypeEncryption,
+			expectEncryption: true,
+		},
+		{
+			name:            "retention needed",
+			logGroup:        types.LogGroup{LogGroupName: aws.String("/aws/lambda/test")},
+			ruleType:        logguardiantypes.RuleTypeRetention,
+			expectRetention: true,
+		},
+		{
+			name: "already compliant",
+			logGroup: types.LogGroup{
+				LogGroupName:    aws.String("/aws/lambda/test"),
+				KmsKeyId:        aws.String("arn:aws:kms:ca-central-1:123456789012:key/test-key-id"),
+				RetentionInDays: aws.Int32(365),
+			},
+			ruleType: logguardiantypes.RuleTypeEncryption,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockLogsClient := &MockCloudWatchLogsClient{LogGroups: []types.LogGroup{tt.logGroup}}
+			service := &ComplianceService{
+				logsClient:     mockLogsClient,
+				kmsClient:      &MockKMSClient{KeyState: kmstypes.KeyStateEnabled},
+				ruleClassifier: logguardiantypes.NewRuleClassifier(),
+				config: ServiceConfig{
+					DefaultKMSKeyAlias:   "alias/test-key",
+					DefaultRetentionDays: 365,
+					Region:               "ca-central-1",
+					MaxKMSRetries:        3,
+					RetryBaseDelay:       time.Millisecond,
+				},
+			}
+
+			result, err := service.RemediateSingleLogGroup(context.Background(), "ca-central-1", "/aws/lambda/test", tt.ruleType)
+
+			require.NoError(t, err)
+			assert.True(t, result.Success)
+			assert.Equal(t, "/aws/lambda/test", result.LogGroupName)
+			assert.Equal(t, tt.expectEncryption, result.EncryptionApplied)
+			assert.Equal(t, tt.expectEncryption, mockLogsClient.AssociateKmsKeyCalled)
+			assert.Equal(t, tt.expectRetention, result.RetentionApplied)
+			assert.Equal(t, tt.expectRetention, mockLogsClient.PutRetentionPolicyCalled)
+			assert.Equal(t, !tt.expectEncryption && !tt.expectRetention, result.NoActionNeeded)
+		})
+	}
+
+	t.Run("missing group returns no result", func(t *testing.T) {
+		service := &ComplianceService{
+			logsClient:     &MockCloudWatchLogsClient{},
+			ruleClassifier: logguardiantypes.NewRuleClassifier(),
+			config:         ServiceConfig{Region: "ca-central-1"},
+		}
+
+		result, err := service.RemediateSingleLogGroup(context.Background(), "ca-central-1", "/aws/lambda/missing", logguardiantypes.RuleTypeRetention)
+
+		assert.Nil(t, result)
+		assert.ErrorIs(t, err, ErrLogGroupNotFound)
+	})
+}
+
+func TestComplianceService_KeyMigration(t *testing.T) {
+	const (
+		oldKeyArn = "arn:aws:kms:ca-central-1:123456789012:key/00000000-0000-0000-0000-000000000001"
+		newKeyArn = "arn:aws:kms:ca-central-1:123456789012:key/12345678-1234-1234-1234-123456789012"
+	)
+
+	newService := func(logger *slog.Logger, policy RekeyPolicy) (*ComplianceService, *MockCloudWatchLogsClient) {
+		mockLogsClient := &MockCloudWatchLogsClient{
+			LogGroups: []types.LogGroup{
+				{LogGroupName: aws.String("/aws/lambda/old-key"), KmsKeyId: aws.String(oldKeyArn)},
+				{LogGroupName: aws.String("/aws/lambda/new-key"), KmsKeyId: aws.String(newKeyArn)},
+			},
+		}
+		return &ComplianceService{
+			logsClient:     mockLogsClient,
+			kmsClient:      &MockKMSClient{},
+			ruleClassifier: logguardiantypes.NewRuleClassifier(),
+			logger:         logger,
+			config: ServiceConfig{
+				DefaultKMSKeyAlias:   "alias/test-key",
+				DefaultRetentionDays: 365,
+				Region:               "ca-central-1",
+				MaxKMSRetries:        3,
+				RetryBaseDelay:       time.Millisecond,
+				RekeyPolicy:          policy,
+				OldKeyArns:           []string{oldKeyArn},
+			},
+		}, mockLogsClient
+	}
+
+	t.Run("group on an old key is migrated", func(t *testing.T) {
+		logger, logs := testutil.CaptureLogs(t)
+		service, mockLogsClient := newService(logger, RekeyPolicyAlways)
+
+		result, err := service.RemediateSingleLogGroup(context.Background(), "ca-central-1", "/aws/lambda/old-key", logguardiantypes.RuleTypeEncryption)
+
+		require.NoError(t, err)
+		assert.True(t, result.EncryptionApplied)
+		assert.True(t, mockLogsClient.AssociateKmsKeyCalled)
+		assert.True(t, logs.HasAuditAction(AuditActionKeyMigration))
+		assert.False(t, logs.HasAuditAction(AuditActionRekeyDecision))
+	})
+
+	t.Run("group on the new key is left alone", func(t *testing.T) {
+		logger, logs := testutil.CaptureLogs(t)
+		service, mockLogsClient := newService(logger, RekeyPolicyAlways)
+
+		result, err := service.RemediateSingleLogGroup(context.Background(), "ca-central-1", "/aws/lambda/new-key", logguardiantypes.RuleTypeEncryption)
+
+		require.NoError(t, err)
+		assert.True(t, result.NoActionNeeded)
+		assert.False(t, mockLogsClient.AssociateKmsKeyCalled)
+		assert.False(t, logs.HasAuditAction(AuditActionKeyMigration))
+	})
+
+	t.Run("migration overrides a re-key policy that keeps existing keys", func(t *testing.T) {
+		logger, logs := testutil.CaptureLogs(t)
+		service, mockLogsClient := newService(logger, RekeyPolicyNeverRekey)
+
+		result, err := service.RemediateLogGroup(context.Background(), logguardiantypes.ComplianceResult{
+			LogGroupName:      "/aws/lambda/old-key",
+			Region:            "ca-central-1",
+			MissingEncryption: true,
+			CurrentKmsKeyId:   oldKeyArn,
+		})
+
+		require.NoError(t, err)
+		assert.True(t, result.EncryptionApplied)
+		assert.True(t, mockLogsClient.AssociateKmsKeyCalled)
+		assert.True(t, logs.HasAuditAction(AuditActionKeyMigration))
+		assert.False(t, logs.HasAuditAction(AuditActionRekeyDecision))
+	})
+}
+
+func TestComplianceService_EvaluateCompliance_CreationLag(t *testing.T) {
+	created := types.LogGroup{LogGroupName: aws.String("/aws/lambda/new"), RetentionInDays: aws.Int32(30)}
+
+	t.Run("retry resolves a group not yet visible", func(t *testing.T) {
+		mockLogsClient := &MockCloudWatchLogsClient{
+			DescribeLogGroupsResults: [][]types.LogGroup{{}, {}, {created}},
+		}
+		service := &ComplianceService{
+			logsClient: mockLogsClient,
+			config:     ServiceConfig{Region: "ca-central-1", LogGroupLookupRetries: 3, LogGroupLookupDelay: time.Millisecond},
+		}
+
+		result, err := service.EvaluateCompliance(context.Background(), "/aws/lambda/new", "ca-central-1")
+		require.NoError(t, err)
+		assert.Equal(t, 3, mockLogsClient.DescribeLogGroupsCalls)
+		assert.True(t, result.MissingEncryption)
+		assert.False(t, result.MissingRetention)
+	})
+
+	t.Run("still missing after retries is not found", func(t *testing.T) {
+		mockLogsClient := &MockCloudWatchLogsClient{}
+		service := &ComplianceService{
+			logsClient: mockLogsClient,
+			config:     ServiceConfig{Region: "ca-central-1", LogGroupLookupRetries: 2, LogGroupLookupDelay: time.Millisecond},
+		}
+
+		_, err := service.EvaluateCompliance(context.Background(), "/aws/lambda/new", "ca-central-1")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrLogGroupNotFound)
+		assert.Equal(t, 3, mockLogsClient.DescribeLogGroupsCalls)
+	})
+
+	t.Run("API errors are not retried", func(t *testing.T) {
+		mockLogsClient := &MockCloudWatchLogsClient{DescribeLogGroupsError: errors.New("AccessDeniedException")}
+		service := &ComplianceService{
+			logsClient: mockLogsClient,
+			config:     ServiceConfig{Region: "ca-central-1", LogGroupLookupRetries: 3, LogGroupLookupDelay: time.Millisecond},
+		}
+
+		_, err := service.EvaluateCompliance(context.Background(), "/aws/lambda/new", "ca-central-1")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrLogGroupNotFound)
+		assert.Equal(t, 1, mockLogsClient.DescribeLogGroupsCalls)
+	})
+}
+
+func TestComplianceService_RemediateLogGroup_CreationLag(t *testing.T) {
+	// A Config event for a just-created group reads its live state before the retention guard
+	// decides, and that lookup tolerates the same creation lag as EvaluateCompliance
+	mockLogsClient := &MockCloudWatchLogsClient{
+		DescribeLogGroupsResults: [][]types.LogGroup{{}, {{LogGroupName: aws.String("/aws/lambda/new")}}},
+	}
+	service := &ComplianceService{
+		logsClient: mockLogsClient,
+		config: ServiceConfig{
+			Region:                "ca-central-1",
+			DefaultRetentionDays:  30,
+			LogGroupLookupRetries: 3,
+			LogGroupLookupDelay:   time.Millisecond,
+		},
+	}
+
+	result, err := service.RemediateLogGroup(context.Background(), logguardiantypes.ComplianceResult{
+		LogGroupName:     "/aws/lambda/new",
+		Region:           "ca-central-1",
+		MissingRetention: true,
+	})
+
+	require.NoError(t, err)
+	assert.True(t, result.RetentionApplied)
+	assert.Equal(t, 2, mockLogsClient.DescribeLogGroupsCalls)
+}
+
+func TestComplianceService_ReportEvaluation(t *testing.T) {
+	configItem := logguardiantypes.ConfigurationItem{
+		ResourceId:                   "/aws/lambda/test",
+		ResourceType:                 "AWS::Logs::LogGroup",
+		ConfigurationItemCaptureTime: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name             string
+		reportEnabled    bool
+		dryRun           bool
+		result           logguardiantypes.RemediationResult
+		putError         error
+		expectCall       bool
+		expectCompliance configtypes.ComplianceType
+		expectError      bool
+		expectAudit      string
+	}{
+		{
+			name:   "disabled by default",
+			result: logguardiantypes.RemediationResult{Success: true},
+		},
+		{
+			name:          "dry run does not report",
+			reportEnabled: true,
+			dryRun:        true,
+			result:        logguardiantypes.RemediationResult{Success: true},
+		},
+		{
+			name:             "successful remediation reported compliant",
+			reportEnabled:    true,
+			result:           logguardiantypes.RemediationResult{Success: true, EncryptionApplied: true},
+			expectCall:       true,
+			expectCompliance: configtypes.ComplianceTypeCompliant,
+			expectAudit:      AuditActionEvaluationReported,
+		},
+		{
+			name:             "failed remediation reported non-compliant",
+			reportEnabled:    true,
+			result:           logguardiantypes.RemediationResult{Error: errors.New("access denied")},
+			expectCall:       true,
+			expectCompliance: configtypes.ComplianceTypeNonCompliant,
+			expectAudit:      AuditActionEvaluationReported,
+		},
+		{
+			name:             "expired token logged without error",
+			reportEnabled:    true,
+			result:           logguardiantypes.RemediationResult{Success: true},
+			putError:         &configtypes.InvalidResultTokenException{Message: aws.String("token expired")},
+			expectCall:       true,
+			expectCompliance: configtypes.ComplianceTypeCompliant,
+			expectAudit:      AuditActionEvaluationTokenExpired,
+		},
+		{
+			name:             "other errors returned",
+			reportEnabled:    true,
+			result:           logguardiantypes.RemediationResult{Success: true},
+			putError:         errors.New("service unavailable"),
+			expectCall:       true,
+			expectCompliance: configtypes.ComplianceTypeCompliant,
+			expectError:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, logs := testutil.CaptureLogs(t)
+			configClient := &MockConfigServiceClient{PutEvaluationsError: tt.putError}
+			service := &ComplianceService{
+				configClient: configClient,
+				logger:       logger,
+				config: ServiceConfig{
+					ReportEvaluations: tt.reportEnabled,
+					DryRun:            tt.dryRun,
+				},
+			}
+
+			err := service.ReportEvaluation(context.Background(), "token-123", configItem, &tt.result)
+
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			if !tt.expectCall {
+				assert.Nil(t, configClient.PutEvaluationsInput)
+				return
+			}
+
+			require.NotNil(t, configClient.PutEvaluationsInput)
+			assert.Equal(t, "token-123", aws.ToString(configClient.PutEvaluationsInput.ResultToken))
+			require.Len(t, configClient.PutEvaluationsInput.Evaluations, 1)
+			evaluation := configClient.PutEvaluationsInput.Evaluations[0]
+			assert.Equal(t, tt.expectCompliance, evaluation.ComplianceType)
+			assert.Equal(t, "/aws/lambda/test", aws.ToString(evaluation.ComplianceResourceId))
+			assert.Equal(t, configItem.ConfigurationItemCaptureTime, aws.ToTime(evaluation.OrderingTimestamp))
+			if tt.expectAudit != "" {
+				assert.True(t, logs.HasAuditAction(tt.expectAudit))
+			}
+		})
+	}
+}
+
+func TestComplianceService_ReportEvaluation_Throttling(t *testing.T) {
+	throttled := errors.New("ThrottlingException: Rate exceeded")
+	configItem := func(resourceId string) logguardiantypes.ConfigurationItem {
+		return logguardiantypes.ConfigurationItem{ResourceId: resourceId, ResourceType: "AWS::Logs::LogGroup"}
+	}
+	newService := func(t *testing.T, configClient *MockConfigServiceClient, strict bool) (*ComplianceService, *testutil.LogCapture) {
+		logger, logs := testutil.CaptureLogs(t)
+		return &ComplianceService{
+			configClient: configClient,
+			logger:       logger,
+			config: ServiceConfig{
+				ReportEvaluations: true,
+				EvaluationRetries: 3,
+				StrictEvaluations: strict,
+			},
+		}, logs
+	}
+
+	t.Run("retries until throttling clears", func(t *testing.T) {
+		configClient := &MockConfigServiceClient{PutEvaluationsErrors: []error{throttled, throttled, nil}}
+		service, logs := newService(t, configClient, false)
+
+		err := service.ReportEvaluation(context.Background(), "token-123", configItem("/aws/lambda/a"), &logguardiantypes.RemediationResult{Success: true})
+
+		require.NoError(t, err)
+		assert.Len(t, configClient.PutEvaluationsInputs, 3)
+		assert.True(t, logs.HasAuditAction(AuditActionEvaluationReported))
+		assert.Empty(t, service.unsentEvaluations.entries)
+	})
+
+	t.Run("persistent throttling buffers and flushes after the next success", func(t *testing.T) {
+		configClient := &MockConfigServiceClient{PutEvaluationsError: throttled}
+		service, logs := newService(t, configClient, false)
+
+		err := service.ReportEvaluation(context.Background(), "token-123", configItem("/aws/lambda/a"), &logguardiantypes.RemediationResult{Success: true})
+
+		require.NoError(t, err, "throttling is non-fatal by default")
+		assert.Len(t, configClient.PutEvaluationsInputs, 3)
+		assert.True(t, logs.HasAuditAction(AuditActionEvaluationBuffered))
+		require.Len(t, service.unsentEvaluations.entries, 1)
+
+		configClient.PutEvaluationsError = nil
+		configClient.PutEvaluationsInputs = nil
+		err = service.ReportEvaluation(context.Background(), "token-456", configItem("/aws/lambda/b"), &logguardiantypes.RemediationResult{Success: true})
+
+		require.NoError(t, err)
+		require.Len(t, configClient.PutEvaluationsInputs, 2)
+		assert.Equal(t, "token-123", aws.ToString(configClient.PutEvaluationsInputs[1].ResultToken))
+		assert.Equal(t, "/aws/lambda/a", aws.ToString(configClient.PutEvaluationsInputs[1].Evaluations[0].ComplianceResourceId))
+		assert.Empty(t, service.unsentEvaluations.entries)
+	})
+
+	t.Run("strict mode returns persistent throttling", func(t *testing.T) {
+		configClient := &MockConfigServiceClient{PutEvaluationsError: throttled}
+		service, _ := newService(t, configClient, true)
+
+		err := service.ReportEvaluation(context.Background(), "token-123", configItem("/aws/lambda/a"), &logguardiantypes.RemediationResult{Success: true})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "ThrottlingException")
+		assert.Len(t, service.unsentEvaluations.entries, 1)
+	})
+}
+
+func TestComplianceService_RemediateLogGroup_RekeyPolicy(t *testing.T) {
+	const existingKeyArn = "arn:aws:kms:ca-central-1:123456789012:key/99999999-9999-9999-9999-999999999999"
+
+	tests := []struct {
+		name             string
+		policy           RekeyPolicy
+		currentKeyId     string
+		liveKeyId        string
+		expectAssociate  bool
+		expectError      bool
+		expectNoAction   bool
+		expectAuditEntry bool
+	}{
+		{
+			name:             "always re-keys a group with a different key",
+			policy:           RekeyPolicyAlways,
+			currentKeyId:     existingKeyArn,
+			expectAssociate:  true,
+			expectAuditEntry: true,
+		},
+		{
+			name:             "only-if-unencrypted keeps a different key",
+			policy:           RekeyPolicyOnlyIfUnencrypted,
+			currentKeyId:     existingKeyArn,
+			expectNoAction:   true,
+			expectAuditEntry: true,
+		},
+		{
+			name:             "never-rekey refuses a different key",
+			policy:           RekeyPolicyNeverRekey,
+			currentKeyId:     existingKeyArn,
+			expectError:      true,
+			expectAuditEntry: true,
+		},
+		{
+			name:             "existing key read from live state",
+			policy:           RekeyPolicyOnlyIfUnencrypted,
+			liveKeyId:        existingKeyArn,
+			expectNoAction:   true,
+			expectAuditEntry: true,
+		},
+		{
+			name:            "unencrypted group is encrypted under any policy",
+			policy:          RekeyPolicyNeverRekey,
+			expectAssociate: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, logs := testutil.CaptureLogs(t)
+			mockLogsClient := &MockCloudWatchLogsClient{
+				LogGroups: []types.LogGroup{
+					{LogGroupName: aws.String("/aws/lambda/test"), KmsKeyId: aws.String(tt.liveKeyId)},
+				},
+			}
+
+			service := &ComplianceService{
+				logsClient:     mockLogsClient,
+				kmsClient:      &MockKMSClient{},
+				ruleClassifier: logguardiantypes.NewRuleClassifier(),
+				logger:         logger,
+				config: ServiceConfig{
+					DefaultKMSKeyAlias: "alias/test-key",
+					Region:             "ca-central-1",
+					MaxKMSRetries:      3,
+					RetryBaseDelay:     100 * time.Millisecond,
+					RekeyPolicy:        tt.policy,
+				},
+			}
+
+			result, err := service.RemediateLogGroup(context.Background(), logguardiantypes.ComplianceResult{
+				LogGroupName:      "/aws/lambda/test",
+				Region:            "ca-central-1",
+				MissingEncryption: true,
+				CurrentKmsKeyId:   tt.currentKeyId,
+			})
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.False(t, result.Success)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectAssociate, result.EncryptionApplied)
+				assert.Equal(t, tt.expectNoAction, result.NoActionNeeded)
+			}
+			assert.Equal(t, tt.expectAssociate, mockLogsClient.AssociateKmsKeyCalled)
+			assert.Equal(t, tt.expectAuditEntry, logs.HasAuditAction(AuditActionRekeyDecision))
+		})
+	}
+}
+
+func TestParseRekeyPolicy(t *testing.T) {
+	assert.Equal(t, RekeyPolicyAlways, parseRekeyPolicy("always"))
+	assert.Equal(t, RekeyPolicyOnlyIfUnencrypted, parseRekeyPolicy("only-if-unencrypted"))
+	assert.Equal(t, RekeyPolicyNeverRekey, parseRekeyPolicy(" Never-Rekey "))
+	assert.Equal(t, RekeyPolicyAlways, parseRekeyPolicy("sometimes"))
+}
+
+func TestIsRateLimitError_AdditionalRetryableCodes(t *testing.T) {
+	custom := &smithy.GenericAPIError{Code: "SlowDown", Message: "please reduce your request rate"}
+
+	t.Setenv("ADDITIONAL_RETRYABLE_CODES", "")
+	assert.False(t, isRateLimitError(custom))
+
+	t.Setenv("ADDITIONAL_RETRYABLE_CODES", " EC2ThrottledException , SlowDown,")
+	assert.True(t, isRateLimitError(custom))
+	assert.True(t, isRateLimitError(fmt.Errorf("failed to describe log group: %w", custom)))
+	assert.True(t, isRateLimitError(&smithy.GenericAPIError{Code: "ThrottlingException", Message: "Rate exceeded"}))
+	assert.False(t, isRateLimitError(&smithy.GenericAPIError{Code: "ValidationException", Message: "bad input"}))
+}
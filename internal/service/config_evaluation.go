@@ -2,14 +2,18 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"os"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/configservice"
 	"github.com/aws/aws-sdk-go-v2/service/configservice/types"
+	"github.com/aws/smithy-go"
 	logguardiantypes "github.com/zsoftly/logguardian/internal/types"
 )
 
@@ -19,16 +23,9 @@ type ConfigEvaluationService struct {
 	config       ServiceConfig
 }
 
-// NewConfigEvaluationService creates a new Config evaluation service
-func NewConfigEvaluationService(cfg aws.Config) *ConfigEvaluationService {
-	// Load configuration from environment variables
-	config := ServiceConfig{
-		DefaultKMSKeyAlias:   getEnvOrDefault("KMS_KEY_ALIAS", "alias/cloudwatch-logs-compliance"),
-		DefaultRetentionDays: getEnvAsInt32OrDefault("DEFAULT_RETENTION_DAYS", 365),
-		DryRun:               getEnvAsBoolOrDefault("DRY_RUN", false),
-		BatchLimit:           getEnvAsInt32OrDefault("BATCH_LIMIT", 100),
-	}
-
+// NewConfigEvaluationService creates a new Config evaluation service. It shares the compliance
+// service's configuration, so BatchLimit sets the page size of Config queries.
+func NewConfigEvaluationService(cfg aws.Config, config ServiceConfig) *ConfigEvaluationService {
 	return &ConfigEvaluationService{
 		configClient: configservice.NewFromConfig(cfg),
 		config:       config,
@@ -41,18 +38,47 @@ func (s *ConfigEvaluationService) GetNonCompliantResources(ctx context.Context,
 		"config_rule", configRuleName,
 		"region", region)
 
-	var nonCompliantResources []logguardiantypes.NonCompliantResource
+	nonCompliantResources, err := s.getResourcesByComplianceType(ctx, configRuleName, region, types.ComplianceTypeNonCompliant)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Retrieved non-compliant resources",
+		"config_rule", configRuleName,
+		"region", region,
+		"count", len(nonCompliantResources))
+
+	return nonCompliantResources, nil
+}
+
+// GetCompliantResources retrieves the log groups Config last evaluated as compliant with the rule
+func (s *ConfigEvaluationService) GetCompliantResources(ctx context.Context, configRuleName string, region string) ([]logguardiantypes.NonCompliantResource, error) {
+	compliantResources, err := s.getResourcesByComplianceType(ctx, configRuleName, region, types.ComplianceTypeCompliant)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Retrieved compliant resources",
+		"config_rule", configRuleName,
+		"region", region,
+		"count", len(compliantResources))
+
+	return compliantResources, nil
+}
+
+// getResourcesByComplianceType pages through the rule's evaluation results of one compliance
+// type, keeping only log groups
+func (s *ConfigEvaluationService) getResourcesByComplianceType(ctx context.Context, configRuleName string, region string, complianceType types.ComplianceType) ([]logguardiantypes.NonCompliantResource, error) {
+	var resources []logguardiantypes.NonCompliantResource
 	var nextToken *string
 
 	// Paginate through all results to handle large numbers of resources
 	for {
 		input := &configservice.GetComplianceDetailsByConfigRuleInput{
-			ConfigRuleName: aws.String(configRuleName),
-			ComplianceTypes: []types.ComplianceType{
-				types.ComplianceTypeNonCompliant,
-			},
-			NextToken: nextToken,
-			Limit:     s.config.BatchLimit, // Configurable batch limit
+			ConfigRuleName:  aws.String(configRuleName),
+			ComplianceTypes: []types.ComplianceType{complianceType},
+			NextToken:       nextToken,
+			Limit:           s.config.BatchLimit, // BATCH_LIMIT, within Config's page size range
 		}
 
 		// Add retry logic with exponential backoff for rate limits
@@ -68,7 +94,7 @@ func (s *ConfigEvaluationService) GetNonCompliantResources(ctx context.Context,
 		for _, evalResult := range output.EvaluationResults {
 			// Filter for CloudWatch Log Groups only
 			if evalResult.EvaluationResultIdentifier.EvaluationResultQualifier.ResourceType != nil &&
-				*evalResult.EvaluationResultIdentifier.EvaluationResultQualifier.ResourceType == "AWS::Logs::LogGroup" {
+				*evalResult.EvaluationResultIdentifier.EvaluationResultQualifier.ResourceType == LogGroupResourceType {
 
 				resource := logguardiantypes.NonCompliantResource{
 					ResourceId:     aws.ToString(evalResult.EvaluationResultIdentifier.EvaluationResultQualifier.ResourceId),
@@ -80,7 +106,7 @@ func (s *ConfigEvaluationService) GetNonCompliantResources(ctx context.Context,
 					LastEvaluated:  aws.ToTime(evalResult.ResultRecordedTime),
 				}
 
-				nonCompliantResources = append(nonCompliantResources, resource)
+				resources = append(resources, resource)
 			}
 		}
 
@@ -94,12 +120,7 @@ func (s *ConfigEvaluationService) GetNonCompliantResources(ctx context.Context,
 		time.Sleep(100 * time.Millisecond)
 	}
 
-	slog.Info("Retrieved non-compliant resources",
-		"config_rule", configRuleName,
-		"region", region,
-		"count", len(nonCompliantResources))
-
-	return nonCompliantResources, nil
+	return resources, nil
 }
 
 // ValidateResourceExistence checks if resources still exist before processing
@@ -142,6 +163,29 @@ func (s *ConfigEvaluationService) FilterResourcesByComplianceType(resources []lo
 	return filtered
 }
 
+// FilterByAnnotationKeyword keeps only resources whose annotation contains any of the keywords,
+// ignoring case, such as "retention" to target one kind of finding from a broad rule. The counts
+// are logged at debug level, since AnnotationKeywordFilter calls it for each resource.
+func (s *ConfigEvaluationService) FilterByAnnotationKeyword(resources []logguardiantypes.NonCompliantResource, keywords []string) []logguardiantypes.NonCompliantResource {
+	if len(keywords) == 0 {
+		return resources
+	}
+
+	var filtered []logguardiantypes.NonCompliantResource
+	for _, resource := range resources {
+		if annotationMatchesKeywords(resource.Annotation, keywords) {
+			filtered = append(filtered, resource)
+		}
+	}
+
+	slog.Debug("Filtered resources by annotation keyword",
+		"original_count", len(resources),
+		"filtered_count", len(filtered),
+		"keywords", keywords)
+
+	return filtered
+}
+
 // getComplianceDetailsWithRetry implements retry logic with exponential backoff
 func (s *ConfigEvaluationService) getComplianceDetailsWithRetry(ctx context.Context, input *configservice.GetComplianceDetailsByConfigRuleInput, maxRetries int) (*configservice.GetComplianceDetailsByConfigRuleOutput, error) {
 	var lastErr error
@@ -173,13 +217,35 @@ func (s *ConfigEvaluationService) getComplianceDetailsWithRetry(ctx context.Cont
 	return nil, lastErr
 }
 
-// isRateLimitError checks if an error is a rate limit error
+// isRateLimitError checks if an error is a rate limit error, including the non-standard throttling
+// codes listed in ADDITIONAL_RETRYABLE_CODES
 func isRateLimitError(err error) bool {
 	if err == nil {
 		return false
 	}
 
-	errStr := err.Error()
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && slices.Contains(AdditionalRetryableCodes(), apiErr.ErrorCode()) {
+		return true
+	}
+	return isRateLimitMessage(err.Error())
+}
+
+// AdditionalRetryableCodes returns the comma-separated error codes in ADDITIONAL_RETRYABLE_CODES,
+// which extend the built-in throttling codes. It is read on each call, so the set can change
+// without a rebuild.
+func AdditionalRetryableCodes() []string {
+	var codes []string
+	for _, code := range strings.Split(os.Getenv("ADDITIONAL_RETRYABLE_CODES"), ",") {
+		if code = strings.TrimSpace(code); code != "" {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// isRateLimitMessage reports whether an error message describes AWS API throttling
+func isRateLimitMessage(errStr string) bool {
 	return strings.Contains(errStr, "Throttling") ||
 		strings.Contains(errStr, "TooManyRequests") ||
 		strings.Contains(errStr, "RequestLimitExceeded") ||
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cloudwatchlogstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/zsoftly/logguardian/internal/types"
+)
+
+// driftEntry records the configuration of a log group the batch changed, before and after
+// remediation. The before state is the live log group read before the first attempt. The after
+// state is projected from the changes applied, or read back from the live log group when
+// VERIFY_AFTER is enabled; a failed read-back keeps the projection. Dry runs and groups that
+// needed nothing return nil.
+func (s *ComplianceService) driftEntry(ctx context.Context, compliance types.ComplianceResult, result *types.RemediationResult, batchCtx *BatchRemediationContext) *types.DriftEntry {
+	if batchCtx.dryRun || (!result.EncryptionApplied && !result.RetentionApplied) {
+		return nil
+	}
+
+	entry := &types.DriftEntry{
+		LogGroupName: compliance.LogGroupName,
+		Before: types.LogGroupConfiguration{
+			LogGroupName:         compliance.LogGroupName,
+			RetentionInDays:      compliance.CurrentRetention,
+			KmsKeyId:             compliance.CurrentKmsKeyId,
+			DataProtectionStatus: compliance.DataProtectionStatus,
+		},
+	}
+
+	entry.After = entry.Before
+	if result.EncryptionApplied && batchCtx.kmsCache.keyInfo != nil {
+		entry.After.KmsKeyId = batchCtx.kmsCache.keyInfo.Arn
+	}
+	if result.RetentionApplied {
+		entry.After.RetentionInDays = aws.Int32(batchCtx.retentionDays)
+	}
+
+	if !s.config.VerifyAfter {
+		return entry
+	}
+
+	logGroup, err := s.describeLogGroup(ctx, compliance.LogGroupName)
+	if err != nil || logGroup == nil {
+		s.getLogger().Warn("Could not read back remediated log group, drift report uses the projected state",
+			"log_group", compliance.LogGroupName,
+			"found", logGroup != nil,
+			"error", err)
+		return entry
+	}
+	entry.After = logGroupConfiguration(logGroup)
+	entry.Verified = true
+	return entry
+}
+
+// logGroupConfiguration converts a described log group to the Config configuration shape
+func logGroupConfiguration(logGroup *cloudwatchlogstypes.LogGroup) types.LogGroupConfiguration {
+	return types.LogGroupConfiguration{
+		LogGroupName:         aws.ToString(logGroup.LogGroupName),
+		RetentionInDays:      logGroup.RetentionInDays,
+		KmsKeyId:             aws.ToString(logGroup.KmsKeyId),
+		CreationTime:         aws.ToInt64(logGroup.CreationTime),
+		MetricFilterCount:    aws.ToInt32(logGroup.MetricFilterCount),
+		DataProtectionStatus: string(logGroup.DataProtectionStatus),
+		LogGroupClass:        string(logGroup.LogGroupClass),
+	}
+}
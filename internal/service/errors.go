@@ -0,0 +1,76 @@
+package service
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+)
+
+// AuditError is a remediation failure carrying the audit context it occurred in, so callers can
+// report failures by stage and reason without parsing error messages
+type AuditError struct {
+	Stage    string // One of the FailureStage constants
+	Reason   string // One of the FailureReason constants; empty when the failure is not classified
+	Resource string // Log group or KMS key the failure concerns
+	Err      error
+}
+
+// auditError wraps err with the stage, reason and resource it occurred in
+func auditError(stage, reason, resource string, err error) error {
+	return &AuditError{
+		Stage:    stage,
+		Reason:   reason,
+		Resource: resource,
+		Err:      err,
+	}
+}
+
+func (e *AuditError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("%s failed for %s: %v", e.Stage, e.Resource, e.Err)
+	}
+	return fmt.Sprintf("%s failed for %s (%s): %v", e.Stage, e.Resource, e.Reason, e.Err)
+}
+
+func (e *AuditError) Unwrap() error {
+	return e.Err
+}
+
+// KMSPreflightError reports the regions whose KMS key failed comprehensive validation during
+// PreflightAllRegions
+type KMSPreflightError struct {
+	Failures map[string]string // Joined validation errors, keyed by failing region
+	Regions  int               // Regions validated, including those that passed
+}
+
+func (e *KMSPreflightError) Error() string {
+	regions := slices.Sorted(maps.Keys(e.Failures))
+	failures := make([]string, 0, len(regions))
+	for _, region := range regions {
+		failures = append(failures, fmt.Sprintf("%s: %s", region, e.Failures[region]))
+	}
+	return fmt.Sprintf("%d of %d regions failed KMS preflight: %s", len(failures), e.Regions, strings.Join(failures, "; "))
+}
+
+// KMSKeyStateError reports a KMS key that exists but is in a state that cannot encrypt log groups
+type KMSKeyStateError struct {
+	State kmstypes.KeyState
+}
+
+func (e *KMSKeyStateError) Error() string {
+	switch e.State {
+	case kmstypes.KeyStateDisabled:
+		return "key is disabled"
+	case kmstypes.KeyStatePendingDeletion:
+		return "key is pending deletion"
+	case kmstypes.KeyStatePendingImport:
+		return "key is pending import"
+	case kmstypes.KeyStateUnavailable:
+		return "key is unavailable"
+	default:
+		return fmt.Sprintf("KMS key is in unknown state: %s", string(e.State))
+	}
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditError(t *testing.T) {
+	cause := errors.New("boom")
+	err := fmt.Errorf("failed to apply encryption: %w",
+		auditError(FailureStageKeyAssociation, FailureReasonGeneralError, "/aws/lambda/test", cause))
+
+	var auditErr *AuditError
+	require.True(t, errors.As(err, &auditErr))
+	assert.Equal(t, FailureStageKeyAssociation, auditErr.Stage)
+	assert.Equal(t, FailureReasonGeneralError, auditErr.Reason)
+	assert.Equal(t, "/aws/lambda/test", auditErr.Resource)
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, "failed to apply encryption: key_association failed for /aws/lambda/test (general_error): boom", err.Error())
+
+	unclassified := auditError(FailureStageRetentionPolicy, "", "/aws/lambda/test", cause)
+	assert.Equal(t, "retention_policy failed for /aws/lambda/test: boom", unclassified.Error())
+}
+
+func TestAuditError_RemediationPaths(t *testing.T) {
+	tests := []struct {
+		name           string
+		run            func(s *ComplianceService) error
+		expectStage    string
+		expectReason   string
+		expectResource string
+	}{
+		{
+			name: "retention policy failure",
+			run: func(s *ComplianceService) error {
+				s.logsClient = &MockCloudWatchLogsClient{PutRetentionPolicyError: errors.New("ServiceUnavailable")}
+				return s.applyRetentionPolicy(context.Background(), "/aws/lambda/retention", 30)
+			},
+			expectStage:    FailureStageRetentionPolicy,
+			expectResource: "/aws/lambda/retention",
+		},
+		{
+			name: "key not found during encryption",
+			run: func(s *ComplianceService) error {
+				s.kmsClient = &MockKMSClient{DescribeKeyError: &kmstypes.NotFoundException{Message: aws.String("missing")}}
+				s.logsClient = &MockCloudWatchLogsClient{}
+				_, err := s.applyEncryption(context.Background(), "/aws/lambda/encryption", "alias/missing", "")
+				return err
+			},
+			expectStage:    FailureStageKeyValidation,
+			expectReason:   FailureReasonAliasNotFound,
+			expectResource: "/aws/lambda/encryption",
+		},
+		{
+			name: "key association failure",
+			run: func(s *ComplianceService) error {
+				s.kmsClient = &MockKMSClient{}
+				s.logsClient = &MockCloudWatchLogsClient{AssociateKmsKeyError: errors.New("InvalidParameterException")}
+				_, err := s.applyEncryption(context.Background(), "/aws/lambda/encryption", "alias/test-key", "")
+				return err
+			},
+			expectStage:    FailureStageKeyAssociation,
+			expectResource: "/aws/lambda/encryption",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &ComplianceService{
+				config: ServiceConfig{
+					Region:         "ca-central-1",
+					MaxKMSRetries:  1,
+					RetryBaseDelay: MinRetryBaseDelay,
+				},
+			}
+
+			err := tt.run(service)
+
+			var auditErr *AuditError
+			require.True(t, errors.As(err, &auditErr), "expected AuditError, got %v", err)
+			assert.Equal(t, tt.expectStage, auditErr.Stage)
+			assert.Equal(t, tt.expectReason, auditErr.Reason)
+			assert.Equal(t, tt.expectResource, auditErr.Resource)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/configservice"
+	configtypes "github.com/aws/aws-sdk-go-v2/service/configservice/types"
+)
+
+// MaxUnsentEvaluations bounds the evaluations buffered while PutEvaluations is throttled; the
+// oldest is dropped when the buffer is full, and every buffered evaluation is already logged
+const MaxUnsentEvaluations = 100
+
+// unsentEvaluation is an evaluation Config throttled, kept with the result token it belongs to
+type unsentEvaluation struct {
+	resultToken string
+	evaluation  configtypes.Evaluation
+}
+
+// evaluationBuffer holds throttled evaluations until the next successful report. It is safe for
+// concurrent use and its zero value is empty.
+type evaluationBuffer struct {
+	mu      sync.Mutex
+	entries []unsentEvaluation
+}
+
+// putEvaluationWithRetry submits one evaluation, retrying throttling errors with the shared
+// exponential backoff up to EVALUATION_RETRIES attempts
+func (s *ComplianceService) putEvaluationWithRetry(ctx context.Context, resultToken string, evaluation configtypes.Evaluation) (*configservice.PutEvaluationsOutput, error) {
+	attempts := max(int(s.config.EvaluationRetries), 1)
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			delay := s.retryBackoffDelay(attempt)
+			s.getLogger().Info("Retrying throttled PutEvaluations",
+				"resource_id", aws.ToString(evaluation.ComplianceResourceId),
+				"attempt", attempt+1,
+				"delay", delay)
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+
+		var output *configservice.PutEvaluationsOutput
+		output, err = s.configClient.PutEvaluations(ctx, &configservice.PutEvaluationsInput{
+			ResultToken: aws.String(resultToken),
+			Evaluations: []configtypes.Evaluation{evaluation},
+		})
+		if err == nil || !isRateLimitError(err) {
+			return output, err
+		}
+	}
+	return nil, err
+}
+
+// bufferUnsentEvaluation keeps a throttled evaluation for the next successful report and logs
+// everything needed to submit it by hand
+func (s *ComplianceService) bufferUnsentEvaluation(resultToken string, evaluation configtypes.Evaluation, err error) {
+	s.unsentEvaluations.mu.Lock()
+	defer s.unsentEvaluations.mu.Unlock()
+
+	if len(s.unsentEvaluations.entries) >= MaxUnsentEvaluations {
+		dropped := s.unsentEvaluations.entries[0]
+		s.unsentEvaluations.entries = s.unsentEvaluations.entries[1:]
+		s.getLogger().Warn("Unsent evaluation buffer full, dropping oldest evaluation",
+			"resource_id", aws.ToString(dropped.evaluation.ComplianceResourceId),
+			"max_unsent_evaluations", MaxUnsentEvaluations)
+	}
+	s.unsentEvaluations.entries = append(s.unsentEvaluations.entries, unsentEvaluation{
+		resultToken: resultToken,
+		evaluation:  evaluation,
+	})
+
+	s.getLogger().Warn("PutEvaluations throttled, evaluation buffered for later submission",
+		"resource_id", aws.ToString(evaluation.ComplianceResourceId),
+		"resource_type", aws.ToString(evaluation.ComplianceResourceType),
+		"compliance_type", string(evaluation.ComplianceType),
+		"ordering_timestamp", aws.ToTime(evaluation.OrderingTimestamp).UTC().Format(time.RFC3339),
+		"annotation", aws.ToString(evaluation.Annotation),
+		"result_token", resultToken,
+		"unsent_evaluations", len(s.unsentEvaluations.entries),
+		"error", err,
+		"audit_action", AuditActionEvaluationBuffered)
+}
+
+// flushUnsentEvaluations resubmits buffered evaluations once each. Evaluations still throttled go
+// back into the buffer; any other failure, including an expired token, is logged and dropped.
+func (s *ComplianceService) flushUnsentEvaluations(ctx context.Context) {
+	s.unsentEvaluations.mu.Lock()
+	pending := s.unsentEvaluations.entries
+	s.unsentEvaluations.entries = nil
+	s.unsentEvaluations.mu.Unlock()
+
+	for _, unsent := range pending {
+		resourceId := aws.ToString(unsent.evaluation.ComplianceResourceId)
+		output, err := s.configClient.PutEvaluations(ctx, &configservice.PutEvaluationsInput{
+			ResultToken: aws.String(unsent.resultToken),
+			Evaluations: []configtypes.Evaluation{unsent.evaluation},
+		})
+		switch {
+		case isRateLimitError(err):
+			s.bufferUnsentEvaluation(unsent.resultToken, unsent.evaluation, err)
+		case err != nil:
+			s.getLogger().Warn("Failed to report buffered evaluation, dropping it",
+				"resource_id", resourceId,
+				"error", err)
+		case len(output.FailedEvaluations) > 0:
+			s.getLogger().Warn("Config rejected buffered evaluation", "resource_id", resourceId)
+		default:
+			s.getLogger().Info("Reported buffered evaluation to Config",
+				"resource_id", resourceId,
+				"compliance_type", string(unsent.evaluation.ComplianceType),
+				"audit_action", AuditActionEvaluationReported)
+		}
+	}
+}
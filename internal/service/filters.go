@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/zsoftly/logguardian/internal/types"
+)
+
+// LogGroupResourceType is the AWS Config resource type for CloudWatch log groups
+const LogGroupResourceType = "AWS::Logs::LogGroup"
+
+// SkipReason explains why a filter dropped a resource
+type SkipReason string
+
+const (
+	SkipReasonUnsupportedResourceType SkipReason = "unsupported resource type"
+	SkipReasonDuplicate               SkipReason = "duplicate log group"
+	SkipReasonAnnotationKeyword       SkipReason = "annotation matches no keyword"
+	// SkipReasonRuleNotApplicable marks a batch resource the Config rule does not evaluate
+	SkipReasonRuleNotApplicable SkipReason = "config rule not applicable"
+	// SkipReasonOtherAccount marks a resource owned by an account other than the one remediated
+	SkipReasonOtherAccount SkipReason = "log group in another account"
+)
+
+// ResourceFilter decides whether a validated non-compliant resource should be remediated
+type ResourceFilter interface {
+	Keep(ctx context.Context, resource types.NonCompliantResource) (bool, SkipReason)
+}
+
+// ResourceFilterFunc adapts a function to the ResourceFilter interface
+type ResourceFilterFunc func(ctx context.Context, resource types.NonCompliantResource) (bool, SkipReason)
+
+// Keep calls f
+func (f ResourceFilterFunc) Keep(ctx context.Context, resource types.NonCompliantResource) (bool, SkipReason) {
+	return f(ctx, resource)
+}
+
+// SkippedResource records a resource dropped by a filter chain and why
+type SkippedResource struct {
+	Resource types.NonCompliantResource
+	Reason   SkipReason
+}
+
+// FilterChain applies filters in order; a resource is dropped by the first filter that rejects it,
+// and later filters never see it. A chain is itself a ResourceFilter, so chains compose.
+type FilterChain []ResourceFilter
+
+// NewFilterChain returns a chain applying filters in the given order
+func NewFilterChain(filters ...ResourceFilter) FilterChain {
+	return FilterChain(filters)
+}
+
+// NewDefaultFilterChain returns the chain applied before batching, with any additional filters
+// applied after the resource type check and before deduplication. Filters may be stateful, so a
+// new chain is built for each resource set.
+func NewDefaultFilterChain(filters ...ResourceFilter) FilterChain {
+	chain := NewFilterChain(ResourceTypeFilter(LogGroupResourceType))
+	chain = append(chain, filters...)
+	return append(chain, NewDuplicateFilter())
+}
+
+// Keep reports whether every filter keeps the resource, stopping at the first that drops it
+func (c FilterChain) Keep(ctx context.Context, resource types.NonCompliantResource) (bool, SkipReason) {
+	for _, filter := range c {
+		if keep, reason := filter.Keep(ctx, resource); !keep {
+			return false, reason
+		}
+	}
+	return true, ""
+}
+
+// Apply filters resources once, preserving order, and returns the kept and skipped resources
+func (c FilterChain) Apply(ctx context.Context, resources []types.NonCompliantResource) ([]types.NonCompliantResource, []SkippedResource) {
+	kept := make([]types.NonCompliantResource, 0, len(resources))
+	var skipped []SkippedResource
+
+	for _, resource := range resources {
+		if keep, reason := c.Keep(ctx, resource); !keep {
+			skipped = append(skipped, SkippedResource{Resource: resource, Reason: reason})
+			continue
+		}
+		kept = append(kept, resource)
+	}
+
+	return kept, skipped
+}
+
+// ResourceTypeFilter keeps only resources of the given AWS Config resource type
+func ResourceTypeFilter(resourceType string) ResourceFilter {
+	return ResourceFilterFunc(func(ctx context.Context, resource types.NonCompliantResource) (bool, SkipReason) {
+		if resource.ResourceType != resourceType {
+			return false, SkipReasonUnsupportedResourceType
+		}
+		return true, ""
+	})
+}
+
+// NewDuplicateFilter returns a filter that keeps the first occurrence of each log group name
+func NewDuplicateFilter() ResourceFilter {
+	seen := make(map[string]bool)
+	return ResourceFilterFunc(func(ctx context.Context, resource types.NonCompliantResource) (bool, SkipReason) {
+		if seen[resource.ResourceName] {
+			return false, SkipReasonDuplicate
+		}
+		seen[resource.ResourceName] = true
+		return true, ""
+	})
+}
+
+// AnnotationKeywordFilter keeps only resources whose Config annotation contains one of the
+// keywords, ignoring case, as ConfigEvaluationService.FilterByAnnotationKeyword does. With no
+// keywords every resource is kept.
+func AnnotationKeywordFilter(keywords []string) ResourceFilter {
+	evaluation := &ConfigEvaluationService{}
+	return ResourceFilterFunc(func(ctx context.Context, resource types.NonCompliantResource) (bool, SkipReason) {
+		if len(evaluation.FilterByAnnotationKeyword([]types.NonCompliantResource{resource}, keywords)) == 0 {
+			return false, SkipReasonAnnotationKeyword
+		}
+		return true, ""
+	})
+}
+
+// annotationMatchesKeywords reports whether annotation contains any keyword, ignoring case; an
+// empty keyword list matches every annotation
+func annotationMatchesKeywords(annotation string, keywords []string) bool {
+	if len(keywords) == 0 {
+		return true
+	}
+	annotation = strings.ToLower(annotation)
+	for _, keyword := range keywords {
+		if strings.Contains(annotation, strings.ToLower(keyword)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zsoftly/logguardian/internal/types"
+)
+
+func logGroupResource(name string) types.NonCompliantResource {
+	return types.NonCompliantResource{
+		ResourceName: name,
+		ResourceType: LogGroupResourceType,
+		Region:       "ca-central-1",
+	}
+}
+
+func TestFilterChain_ShortCircuitsOnFirstDrop(t *testing.T) {
+	var calls []string
+	dropAll := ResourceFilterFunc(func(ctx context.Context, resource types.NonCompliantResource) (bool, SkipReason) {
+		calls = append(calls, "first:"+resource.ResourceName)
+		return false, "dropped by first"
+	})
+	second := ResourceFilterFunc(func(ctx context.Context, resource types.NonCompliantResource) (bool, SkipReason) {
+		calls = append(calls, "second:"+resource.ResourceName)
+		return true, ""
+	})
+
+	keep, reason := NewFilterChain(dropAll, second).Keep(context.Background(), logGroupResource("/aws/lambda/a"))
+
+	assert.False(t, keep)
+	assert.Equal(t, SkipReason("dropped by first"), reason)
+	assert.Equal(t, []string{"first:/aws/lambda/a"}, calls)
+}
+
+func TestFilterChain_AppliesFiltersInOrder(t *testing.T) {
+	var calls []string
+	record := func(name string) ResourceFilter {
+		return ResourceFilterFunc(func(ctx context.Context, resource types.NonCompliantResource) (bool, SkipReason) {
+			calls = append(calls, name)
+			return true, ""
+		})
+	}
+
+	keep, reason := NewFilterChain(record("a"), NewFilterChain(record("b"), record("c"))).Keep(context.Background(), logGroupResource("/aws/lambda/a"))
+
+	assert.True(t, keep)
+	assert.Empty(t, reason)
+	assert.Equal(t, []string{"a", "b", "c"}, calls)
+}
+
+func TestFilterChain_Apply(t *testing.T) {
+	unsupported := logGroupResource("bucket")
+	unsupported.ResourceType = "AWS::S3::Bucket"
+	resources := []types.NonCompliantResource{
+		logGroupResource("/aws/lambda/a"),
+		unsupported,
+		logGroupResource("/aws/lambda/b"),
+		logGroupResource("/aws/lambda/a"),
+	}
+
+	kept, skipped := NewDefaultFilterChain().Apply(context.Background(), resources)
+
+	assert.Equal(t, []types.NonCompliantResource{resources[0], resources[2]}, kept)
+	require.Len(t, skipped, 2)
+	assert.Equal(t, SkippedResource{Resource: unsupported, Reason: SkipReasonUnsupportedResourceType}, skipped[0])
+	assert.Equal(t, SkippedResource{Resource: resources[3], Reason: SkipReasonDuplicate}, skipped[1])
+}
+
+func TestNewDefaultFilterChain_AdditionalFilters(t *testing.T) {
+	unmatched := logGroupResource("/aws/lambda/a")
+	matched := logGroupResource("/aws/lambda/a")
+	matched.Annotation = "No retention policy"
+	unsupported := logGroupResource("bucket")
+	unsupported.ResourceType = "AWS::S3::Bucket"
+
+	// Additional filters run after the type check and before deduplication, so a resource they
+	// drop does not hide a later occurrence of the same log group
+	kept, skipped := NewDefaultFilterChain(AnnotationKeywordFilter([]string{"retention"})).Apply(context.Background(),
+		[]types.NonCompliantResource{unsupported, unmatched, matched})
+
+	assert.Equal(t, []types.NonCompliantResource{matched}, kept)
+	require.Len(t, skipped, 2)
+	assert.Equal(t, SkipReasonUnsupportedResourceType, skipped[0].Reason)
+	assert.Equal(t, SkipReasonAnnotationKeyword, skipped[1].Reason)
+}
+
+func TestFilterChain_EmptyChainKeepsEverything(t *testing.T) {
+	resources := []types.NonCompliantResource{logGroupResource("/aws/lambda/a")}
+
+	kept, skipped := NewFilterChain().Apply(context.Background(), resources)
+
+	assert.Equal(t, resources, kept)
+	assert.Empty(t, skipped)
+}
+
+func TestAnnotationKeywordFilter(t *testing.T) {
+	retention := logGroupResource("/aws/lambda/retention")
+	retention.Annotation = "Log group has no Retention policy"
+	encryption := logGroupResource("/aws/lambda/encryption")
+	encryption.Annotation = "Log group is not encrypted with KMS"
+	unannotated := logGroupResource("/aws/lambda/unannotated")
+	resources := []types.NonCompliantResource{retention, encryption, unannotated}
+
+	tests := []struct {
+		name     string
+		keywords []string
+		expected []types.NonCompliantResource
+	}{
+		{name: "matching keyword ignores case", keywords: []string{"RETENTION"}, expected: []types.NonCompliantResource{retention}},
+		{name: "any keyword matches", keywords: []string{"retention", "kms"}, expected: []types.NonCompliantResource{retention, encryption}},
+		{name: "no matching keyword", keywords: []string{"deleted"}, expected: nil},
+		{name: "empty keywords pass everything through", keywords: nil, expected: resources},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ConfigEvaluationService{}
+			assert.Equal(t, tt.expected, s.FilterByAnnotationKeyword(resources, tt.keywords))
+
+			kept, skipped := NewFilterChain(AnnotationKeywordFilter(tt.keywords)).Apply(context.Background(), resources)
+			assert.ElementsMatch(t, tt.expected, kept)
+			assert.Len(t, skipped, len(resources)-len(tt.expected))
+			for _, skip := range skipped {
+				assert.Equal(t, SkipReasonAnnotationKeyword, skip.Reason)
+			}
+		})
+	}
+}
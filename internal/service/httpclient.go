@@ -0,0 +1,47 @@
+package service
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+)
+
+// HTTPTimeoutFromEnvironment returns the AWS_HTTP_TIMEOUT_MS bound on connecting to AWS endpoints
+// and waiting for response headers, or zero to keep the SDK defaults
+func HTTPTimeoutFromEnvironment() time.Duration {
+	return time.Duration(getEnvAsInt32OrDefault("AWS_HTTP_TIMEOUT_MS", 0)) * time.Millisecond
+}
+
+// ApplyHTTPTimeout returns a copy of cfg whose HTTP client gives up dialing an endpoint, or waiting
+// for its response headers, after timeout, so a blocked endpoint fails fast instead of stalling
+// the run. The SDK's buildable client is extended, keeping settings such as a custom CA bundle or
+// proxy; a new client is built only when cfg has none, and a client of another type is left
+// as it is. A timeout of zero or less returns cfg unchanged.
+func ApplyHTTPTimeout(cfg aws.Config, timeout time.Duration) aws.Config {
+	if timeout <= 0 {
+		return cfg
+	}
+
+	var client *awshttp.BuildableClient
+	switch existing := cfg.HTTPClient.(type) {
+	case nil:
+		client = awshttp.NewBuildableClient()
+	case *awshttp.BuildableClient:
+		client = existing
+	default:
+		return cfg
+	}
+
+	cfg = cfg.Copy()
+	cfg.HTTPClient = client.
+		WithDialerOptions(func(d *net.Dialer) {
+			d.Timeout = timeout
+		}).
+		WithTransportOptions(func(tr *http.Transport) {
+			tr.ResponseHeaderTimeout = timeout
+		})
+	return cfg
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyHTTPTimeout(t *testing.T) {
+	t.Run("zero keeps the SDK defaults", func(t *testing.T) {
+		cfg := ApplyHTTPTimeout(aws.Config{}, 0)
+		assert.Nil(t, cfg.HTTPClient)
+	})
+
+	t.Run("timeout bounds dial and response headers", func(t *testing.T) {
+		base := aws.Config{}
+		cfg := ApplyHTTPTimeout(base, 2*time.Second)
+
+		client, ok := cfg.HTTPClient.(*awshttp.BuildableClient)
+		require.True(t, ok)
+		assert.Equal(t, 2*time.Second, client.GetDialer().Timeout)
+		assert.Equal(t, 2*time.Second, client.GetTransport().ResponseHeaderTimeout)
+		assert.Nil(t, base.HTTPClient, "base config must not be modified")
+	})
+
+	t.Run("existing client keeps its transport settings", func(t *testing.T) {
+		proxy := func(*http.Request) (*url.URL, error) { return url.Parse("http://proxy.internal:3128") }
+		rootCAs := x509.NewCertPool()
+		existing := awshttp.NewBuildableClient().WithTransportOptions(func(tr *http.Transport) {
+			tr.Proxy = proxy
+			tr.TLSClientConfig = &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12}
+		})
+
+		cfg := ApplyHTTPTimeout(aws.Config{HTTPClient: existing}, 2*time.Second)
+
+		client, ok := cfg.HTTPClient.(*awshttp.BuildableClient)
+		require.True(t, ok)
+		transport := client.GetTransport()
+		assert.Equal(t, 2*time.Second, transport.ResponseHeaderTimeout)
+		assert.Equal(t, 2*time.Second, client.GetDialer().Timeout)
+		require.NotNil(t, transport.Proxy)
+		proxyURL, err := transport.Proxy(&http.Request{})
+		require.NoError(t, err)
+		assert.Equal(t, "proxy.internal:3128", proxyURL.Host)
+		require.NotNil(t, transport.TLSClientConfig)
+		assert.Same(t, rootCAs, transport.TLSClientConfig.RootCAs)
+		assert.Zero(t, existing.GetTransport().ResponseHeaderTimeout, "existing client must not be modified")
+	})
+
+	t.Run("client of another type is kept", func(t *testing.T) {
+		custom := &http.Client{}
+		cfg := ApplyHTTPTimeout(aws.Config{HTTPClient: custom}, 2*time.Second)
+		assert.Same(t, custom, cfg.HTTPClient)
+	})
+}
+
+func TestNewComplianceService_HTTPTimeout(t *testing.T) {
+	t.Setenv("AWS_HTTP_TIMEOUT_MS", "1500")
+
+	service := NewComplianceService(aws.Config{Region: "ca-central-1"})
+	assert.Equal(t, 1500*time.Millisecond, service.config.HTTPTimeout)
+
+	logsClient, ok := service.logsClient.(*cloudwatchlogs.Client)
+	require.True(t, ok)
+	client, ok := logsClient.Options().HTTPClient.(*awshttp.BuildableClient)
+	require.True(t, ok)
+	assert.Equal(t, 1500*time.Millisecond, client.GetDialer().Timeout)
+	assert.Equal(t, 1500*time.Millisecond, client.GetTransport().ResponseHeaderTimeout)
+}
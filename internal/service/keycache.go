@@ -0,0 +1,104 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// sharedKMSKeyCache is the process-wide KMS key cache, shared by every service so warm Lambda
+// invocations and regions reuse validated keys
+var sharedKMSKeyCache = newKMSKeyCache()
+
+// kmsKeyCacheKey identifies a key by the alias it was requested with, the region it was described
+// in and the account being remediated, since an alias resolves per account and the cached info
+// records whether the key is cross-account
+type kmsKeyCacheKey struct {
+	alias     string
+	region    string
+	accountId string
+}
+
+type kmsKeyCacheEntry struct {
+	info      KMSKeyInfo
+	expiresAt time.Time
+}
+
+// kmsKeyCache is a read-through cache of validated KMS key info. It is safe for concurrent use.
+type kmsKeyCache struct {
+	mu      sync.Mutex
+	entries map[kmsKeyCacheKey]kmsKeyCacheEntry
+}
+
+func newKMSKeyCache() *kmsKeyCache {
+	return &kmsKeyCache{entries: make(map[kmsKeyCacheKey]kmsKeyCacheEntry)}
+}
+
+// get returns a copy of the cached key info if present and not expired at now
+func (c *kmsKeyCache) get(alias, region, accountId string, now time.Time) (*KMSKeyInfo, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := kmsKeyCacheKey{alias, region, accountId}
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !now.Before(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	info := entry.info
+	return &info, true
+}
+
+// put caches a copy of info until now plus ttl
+func (c *kmsKeyCache) put(alias, region, accountId string, info *KMSKeyInfo, now time.Time, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[kmsKeyCacheKey{alias, region, accountId}] = kmsKeyCacheEntry{info: *info, expiresAt: now.Add(ttl)}
+}
+
+// invalidate drops the cached key info so the next validation describes the key again
+func (c *kmsKeyCache) invalidate(alias, region, accountId string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, kmsKeyCacheKey{alias, region, accountId})
+}
+
+// cachedKMSKeyInfo returns the cached key info for keyAlias in the current region and account when
+// the cache is enabled
+func (s *ComplianceService) cachedKMSKeyInfo(keyAlias string) (*KMSKeyInfo, bool) {
+	if s.keyCache == nil || s.config.KMSKeyCacheTTL <= 0 {
+		return nil, false
+	}
+	return s.keyCache.get(keyAlias, s.getCurrentRegion(), s.config.AccountId, s.now())
+}
+
+// cacheKMSKeyInfo stores validated key info for KMS_KEY_CACHE_TTL_SECONDS when the cache is enabled
+func (s *ComplianceService) cacheKMSKeyInfo(keyAlias string, info *KMSKeyInfo) {
+	if s.keyCache == nil || s.config.KMSKeyCacheTTL <= 0 {
+		return
+	}
+	s.keyCache.put(keyAlias, s.getCurrentRegion(), s.config.AccountId, info, s.now(), s.config.KMSKeyCacheTTL)
+}
+
+// invalidateKMSKeyInfo drops cached key info after an association error that points at the key
+// itself, such as a deleted, disabled or no longer permitted key
+func (s *ComplianceService) invalidateKMSKeyInfo(keyAlias string, err error) {
+	if s.keyCache == nil || !isKMSKeyProblemError(err) {
+		return
+	}
+	s.keyCache.invalidate(keyAlias, s.getCurrentRegion(), s.config.AccountId)
+	s.getLogger().Info("Invalidated cached KMS key info after key error",
+		"kms_key_alias", keyAlias,
+		"error", err)
+}
+
+// isKMSKeyProblemError reports whether err indicates the KMS key is missing, unusable or denied
+func isKMSKeyProblemError(err error) bool {
+	return isKMSKeyNotFoundError(err) || isKMSAccessDeniedError(err) || checkAPIErrorCode(err, []string{
+		"DisabledException",
+		"KMSInvalidStateException",
+		"InvalidParameterException",
+	})
+}
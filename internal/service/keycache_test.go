@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	logguardiantypes "github.com/zsoftly/logguardian/internal/types"
+)
+
+func newKeyCacheService(kmsClient *MockKMSClient, logsClient *MockCloudWatchLogsClient, now *time.Time) *ComplianceService {
+	return &ComplianceService{
+		logsClient:     logsClient,
+		kmsClient:      kmsClient,
+		ruleClassifier: logguardiantypes.NewRuleClassifier(),
+		keyCache:       newKMSKeyCache(),
+		clock:          func() time.Time { return *now },
+		config: ServiceConfig{
+			DefaultKMSKeyAlias:   "alias/test-key",
+			DefaultRetentionDays: 365,
+			Region:               "ca-central-1",
+			MaxKMSRetries:        1,
+			KMSKeyCacheTTL:       5 * time.Minute,
+		},
+	}
+}
+
+func TestValidateKMSKeyAccessibility_CacheHitSkipsDescribeKey(t *testing.T) {
+	now := time.Unix(0, 0)
+	kmsClient := &MockKMSClient{}
+	service := newKeyCacheService(kmsClient, &MockCloudWatchLogsClient{}, &now)
+
+	first, err := service.validateKMSKeyAccessibility(context.Background(), "alias/test-key")
+	require.NoError(t, err)
+	require.True(t, kmsClient.DescribeKeyCalled)
+
+	kmsClient.DescribeKeyCalled = false
+	now = now.Add(time.Minute)
+	second, err := service.validateKMSKeyAccessibility(context.Background(), "alias/test-key")
+	require.NoError(t, err)
+	assert.False(t, kmsClient.DescribeKeyCalled, "warm cache should skip DescribeKey")
+	assert.Equal(t, first.Arn, second.Arn)
+
+	// Other regions are cached separately
+	service.config.Region = "ca-west-1"
+	_, err = service.validateKMSKeyAccessibility(context.Background(), "alias/test-key")
+	require.NoError(t, err)
+	assert.True(t, kmsClient.DescribeKeyCalled)
+
+	// So are other accounts, where the same alias names a different key
+	kmsClient.DescribeKeyCalled = false
+	service.config.AccountId = "210987654321"
+	_, err = service.validateKMSKeyAccessibility(context.Background(), "alias/test-key")
+	require.NoError(t, err)
+	assert.True(t, kmsClient.DescribeKeyCalled)
+}
+
+func TestValidateKMSKeyAccessibility_CacheExpiryRefreshes(t *testing.T) {
+	now := time.Unix(0, 0)
+	kmsClient := &MockKMSClient{}
+	service := newKeyCacheService(kmsClient, &MockCloudWatchLogsClient{}, &now)
+
+	_, err := service.validateKMSKeyAccessibility(context.Background(), "alias/test-key")
+	require.NoError(t, err)
+
+	kmsClient.DescribeKeyCalled = false
+	now = now.Add(5 * time.Minute)
+	_, err = service.validateKMSKeyAccessibility(context.Background(), "alias/test-key")
+	require.NoError(t, err)
+	assert.True(t, kmsClient.DescribeKeyCalled, "expired entry should be refreshed")
+}
+
+func TestValidateKMSKeyAccessibility_CacheDisabledByDefault(t *testing.T) {
+	now := time.Unix(0, 0)
+	kmsClient := &MockKMSClient{}
+	service := newKeyCacheService(kmsClient, &MockCloudWatchLogsClient{}, &now)
+	service.config.KMSKeyCacheTTL = 0
+
+	_, err := service.validateKMSKeyAccessibility(context.Background(), "alias/test-key")
+	require.NoError(t, err)
+
+	kmsClient.DescribeKeyCalled = false
+	_, err = service.validateKMSKeyAccessibility(context.Background(), "alias/test-key")
+	require.NoError(t, err)
+	assert.True(t, kmsClient.DescribeKeyCalled)
+}
+
+func TestRemediateLogGroup_KeyErrorInvalidatesCache(t *testing.T) {
+	now := time.Unix(0, 0)
+	kmsClient := &MockKMSClient{}
+	logsClient := &MockCloudWatchLogsClient{AssociateKmsKeyError: &kmstypes.NotFoundException{Message: aws.String("key deleted")}}
+	service := newKeyCacheService(kmsClient, logsClient, &now)
+
+	_, err := service.validateKMSKeyAccessibility(context.Background(), "alias/test-key")
+	require.NoError(t, err)
+
+	_, err = service.RemediateLogGroup(context.Background(), logguardiantypes.ComplianceResult{
+		LogGroupName:      "/aws/lambda/test",
+		Region:            "ca-central-1",
+		MissingEncryption: true,
+	})
+	require.Error(t, err)
+
+	kmsClient.DescribeKeyCalled = false
+	_, err = service.validateKMSKeyAccessibility(context.Background(), "alias/test-key")
+	require.NoError(t, err)
+	assert.True(t, kmsClient.DescribeKeyCalled, "key error should invalidate the cached entry")
+}
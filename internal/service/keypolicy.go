@@ -0,0 +1,347 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/zsoftly/logguardian/internal/types"
+)
+
+// DefaultMaxKeyPolicyBytes is the largest key policy KMS accepts, and the default bound on the
+// policies parsed during KMS validation
+const DefaultMaxKeyPolicyBytes = 32768
+
+// cloudWatchLogsKeyActions are the KMS actions CloudWatch Logs needs to encrypt log data; a
+// statement granting any of them counts as CloudWatch Logs access
+var cloudWatchLogsKeyActions = []string{"kms:Encrypt", "kms:Decrypt", "kms:GenerateDataKey"}
+
+// suggestedKeyActions are the actions AWS documents for a key that encrypts CloudWatch Logs log groups
+var suggestedKeyActions = []string{"kms:Encrypt*", "kms:Decrypt*", "kms:ReEncrypt*", "kms:GenerateDataKey*", "kms:Describe*"}
+
+// suggestedKeyPolicyStatement is the statement suggested for keys lacking CloudWatch Logs access
+type suggestedKeyPolicyStatement struct {
+	Sid       string                       `json:"Sid"`
+	Effect    string                       `json:"Effect"`
+	Principal map[string]string            `json:"Principal"`
+	Action    []string                     `json:"Action"`
+	Resource  string                       `json:"Resource"`
+	Condition map[string]map[string]string `json:"Condition,omitempty"`
+}
+
+// keyPolicyDocument is the subset of a KMS key policy needed to evaluate CloudWatch Logs access
+type keyPolicyDocument struct {
+	Statement policyList[keyPolicyStatement] `json:"Statement"`
+}
+
+// keyPolicyStatement is a single key policy statement
+type keyPolicyStatement struct {
+	Sid       string             `json:"Sid"`
+	Effect    string             `json:"Effect"`
+	Principal json.RawMessage    `json:"Principal"`
+	Action    policyList[string] `json:"Action"`
+}
+
+// policyList decodes policy elements that may be a single value or a list of values
+type policyList[T any] []T
+
+func (l *policyList[T]) UnmarshalJSON(data []byte) error {
+	var single T
+	if err := json.Unmarshal(data, &single); err == nil {
+		*l = policyList[T]{single}
+		return nil
+	}
+	var list []T
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+// checkCloudWatchLogsPolicyAccess reports whether the key policy has an Allow statement granting a
+// CloudWatch Logs service principal an encryption action. Policies larger than the configured
+// maximum or that are not valid JSON are logged and reported as not granting access.
+func (s *ComplianceService) checkCloudWatchLogsPolicyAccess(policy string) bool {
+	if maxBytes := s.maxKeyPolicyBytes(); len(policy) > maxBytes {
+		s.getLogger().Warn("KMS key policy exceeds maximum size, CloudWatch Logs access not evaluated",
+			"policy_bytes", len(policy),
+			"max_policy_bytes", maxBytes,
+			"audit_action", AuditActionPolicyValidationWarning)
+		return false
+	}
+
+	var document keyPolicyDocument
+	if err := json.Unmarshal([]byte(policy), &document); err != nil {
+		s.getLogger().Warn("KMS key policy is not valid JSON, CloudWatch Logs access not evaluated",
+			"error", err,
+			"audit_action", AuditActionPolicyValidationWarning)
+		return false
+	}
+
+	principals := s.cloudWatchLogsPrincipals()
+	for _, statement := range document.Statement {
+		if statement.Effect == "Allow" && statementGrantsPrincipal(statement, principals) && statementGrantsKeyUse(statement) {
+			return true
+		}
+	}
+	return false
+}
+
+// cloudWatchLogsPrincipals are the service principals CloudWatch Logs uses for key access in the
+// current region
+func (s *ComplianceService) cloudWatchLogsPrincipals() []string {
+	return []string{
+		"logs.amazonaws.com",
+		fmt.Sprintf("logs.%s.amazonaws.com", s.getCurrentRegion()),
+	}
+}
+
+// hasCloudWatchLogsGrant reports whether any grant on the key gives a CloudWatch Logs service
+// principal an encryption operation. All grant pages are read, so a matching grant is found
+// wherever it falls and an equivalent grant is never created twice.
+func (s *ComplianceService) hasCloudWatchLogsGrant(ctx context.Context, keyId string) (bool, error) {
+	grants, err := s.listAllGrants(ctx, keyId)
+	if err != nil {
+		return false, err
+	}
+
+	principals := s.cloudWatchLogsPrincipals()
+	for _, grant := range grants {
+		if !slices.ContainsFunc(principals, func(principal string) bool {
+			return strings.EqualFold(aws.ToString(grant.GranteePrincipal), principal)
+		}) {
+			continue
+		}
+		for _, operation := range grant.Operations {
+			if slices.Contains(cloudWatchLogsKeyActions, "kms:"+string(operation)) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// listAllGrants returns every grant on the key, following NextMarker until the listing is no
+// longer truncated
+func (s *ComplianceService) listAllGrants(ctx context.Context, keyId string) ([]kmstypes.GrantListEntry, error) {
+	var grants []kmstypes.GrantListEntry
+	var marker *string
+	for {
+		output, err := s.kmsClient.ListGrants(ctx, &kms.ListGrantsInput{
+			KeyId:  aws.String(keyId),
+			Marker: marker,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list grants for key %s: %w", keyId, err)
+		}
+		grants = append(grants, output.Grants...)
+
+		if !output.Truncated || aws.ToString(output.NextMarker) == "" {
+			return grants, nil
+		}
+		marker = output.NextMarker
+	}
+}
+
+// statementGrantsPrincipal reports whether a statement's Principal is a wildcard or names one of
+// the service principals
+func statementGrantsPrincipal(statement keyPolicyStatement, principals []string) bool {
+	var wildcard string
+	if err := json.Unmarshal(statement.Principal, &wildcard); err == nil {
+		return wildcard == "*"
+	}
+
+	var principal struct {
+		Service policyList[string] `json:"Service"`
+	}
+	if err := json.Unmarshal(statement.Principal, &principal); err != nil {
+		return false
+	}
+	for _, service := range principal.Service {
+		for _, candidate := range principals {
+			if strings.EqualFold(service, candidate) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// statementGrantsKeyUse reports whether a statement's actions, which may use IAM wildcards, cover
+// any action CloudWatch Logs needs
+func statementGrantsKeyUse(statement keyPolicyStatement) bool {
+	for _, action := range statement.Action {
+		pattern := strings.ToLower(action)
+		for _, required := range cloudWatchLogsKeyActions {
+			if matched, err := path.Match(pattern, strings.ToLower(required)); err == nil && matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// suggestedCloudWatchLogsStatement returns the JSON key policy statement granting the region's
+// CloudWatch Logs service principal use of the key, scoped to the account's log groups when the
+// account is known
+func suggestedCloudWatchLogsStatement(region, accountId string) string {
+	statement := suggestedKeyPolicyStatement{
+		Sid:       "AllowCloudWatchLogsEncryption",
+		Effect:    "Allow",
+		Principal: map[string]string{"Service": fmt.Sprintf("logs.%s.amazonaws.com", region)},
+		Action:    suggestedKeyActions,
+		Resource:  "*",
+	}
+	if accountId != "" {
+		statement.Condition = map[string]map[string]string{
+			"ArnLike": {"kms:EncryptionContext:aws:logs:arn": fmt.Sprintf("arn:%s:logs:%s:%s:*", regionPartition(region), region, accountId)},
+		}
+	}
+
+	data, err := json.MarshalIndent(statement, "", "  ")
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// suggestedPolicyDiff returns the statement-level change adding the suggested statement makes to
+// the key policy and logs it for audit. Only the statement summaries are logged, never the full
+// policy. A policy or suggestion that cannot be parsed is logged and yields no diff.
+func (s *ComplianceService) suggestedPolicyDiff(keyAlias, policy, suggestion string) *types.KeyPolicyDiff {
+	diff, err := keyPolicyStatementDiff(policy, suggestion)
+	if err != nil {
+		s.getLogger().Warn("Cannot compute KMS key policy diff for suggested statement",
+			"key_alias", keyAlias,
+			"error", err,
+			"audit_action", AuditActionPolicyValidationWarning)
+		return nil
+	}
+
+	s.getLogger().Info("KMS key policy fix diff",
+		"key_alias", keyAlias,
+		"before", diff.Before,
+		"after", diff.After,
+		"added", diff.Added,
+		"removed", diff.Removed,
+		"audit_action", AuditActionKMSPolicyAutofixDiff)
+	return diff
+}
+
+// keyPolicyStatementDiff compares the policy's statements with the same statements plus the
+// suggested one
+func keyPolicyStatementDiff(policy, suggestion string) (*types.KeyPolicyDiff, error) {
+	var document keyPolicyDocument
+	if err := json.Unmarshal([]byte(policy), &document); err != nil {
+		return nil, fmt.Errorf("key policy is not valid JSON: %w", err)
+	}
+	var added keyPolicyStatement
+	if err := json.Unmarshal([]byte(suggestion), &added); err != nil {
+		return nil, fmt.Errorf("suggested statement is not valid JSON: %w", err)
+	}
+
+	before := make([]types.KeyPolicyStatementSummary, 0, len(document.Statement))
+	for _, statement := range document.Statement {
+		before = append(before, summarizeKeyPolicyStatement(statement))
+	}
+	after := append(slices.Clone(before), summarizeKeyPolicyStatement(added))
+
+	return &types.KeyPolicyDiff{
+		Before:  before,
+		After:   after,
+		Added:   missingStatements(after, before),
+		Removed: missingStatements(before, after),
+	}, nil
+}
+
+// summarizeKeyPolicyStatement reduces a statement to its Sid, Effect, compact Principal and Actions
+func summarizeKeyPolicyStatement(statement keyPolicyStatement) types.KeyPolicyStatementSummary {
+	principal := string(statement.Principal)
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, statement.Principal); err == nil {
+		principal = compact.String()
+	}
+	return types.KeyPolicyStatementSummary{
+		Sid:       statement.Sid,
+		Effect:    statement.Effect,
+		Principal: principal,
+		Actions:   slices.Clone([]string(statement.Action)),
+	}
+}
+
+// missingStatements returns the statements in from that are not in other
+func missingStatements(from, other []types.KeyPolicyStatementSummary) []types.KeyPolicyStatementSummary {
+	var missing []types.KeyPolicyStatementSummary
+	for _, statement := range from {
+		if !slices.ContainsFunc(other, func(candidate types.KeyPolicyStatementSummary) bool {
+			return candidate.Sid == statement.Sid && candidate.Effect == statement.Effect &&
+				candidate.Principal == statement.Principal && slices.Equal(candidate.Actions, statement.Actions)
+		}) {
+			missing = append(missing, statement)
+		}
+	}
+	return missing
+}
+
+// loadKMSPolicyStatement reads the key policy statement operators supply in place of the generated
+// suggestion, returning it indented for the validation report. An empty path returns an empty
+// statement; an unreadable file or a statement that is not a JSON object with an Effect, a
+// Principal and an Action is an error.
+func loadKMSPolicyStatement(statementFile string) (string, error) {
+	if statementFile == "" {
+		return "", nil
+	}
+
+	data, err := os.ReadFile(filepath.Clean(statementFile))
+	if err != nil {
+		return "", fmt.Errorf("failed to read KMS_POLICY_STATEMENT_FILE %s: %w", statementFile, err)
+	}
+	statement, err := parseKMSPolicyStatement(data)
+	if err != nil {
+		return "", fmt.Errorf("invalid KMS_POLICY_STATEMENT_FILE %s: %w", statementFile, err)
+	}
+	return statement, nil
+}
+
+// parseKMSPolicyStatement validates a single key policy statement and returns it indented
+func parseKMSPolicyStatement(data []byte) (string, error) {
+	var statement keyPolicyStatement
+	if err := json.Unmarshal(data, &statement); err != nil {
+		return "", fmt.Errorf("statement is not a valid JSON policy statement: %w", err)
+	}
+
+	switch {
+	case statement.Effect == "":
+		return "", fmt.Errorf("statement is missing Effect")
+	case statement.Effect != "Allow" && statement.Effect != "Deny":
+		return "", fmt.Errorf("statement Effect must be Allow or Deny, got %q", statement.Effect)
+	case len(statement.Principal) == 0 || string(statement.Principal) == "null":
+		return "", fmt.Errorf("statement is missing Principal")
+	case len(statement.Action) == 0:
+		return "", fmt.Errorf("statement is missing Action")
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, data, "", "  "); err != nil {
+		return "", err
+	}
+	return indented.String(), nil
+}
+
+// maxKeyPolicyBytes returns the largest key policy to parse, falling back to DefaultMaxKeyPolicyBytes
+func (s *ComplianceService) maxKeyPolicyBytes() int {
+	if s.config.MaxKeyPolicyBytes <= 0 {
+		return DefaultMaxKeyPolicyBytes
+	}
+	return s.config.MaxKeyPolicyBytes
+}
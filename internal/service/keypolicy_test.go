@@ -0,0 +1,339 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zsoftly/logguardian/internal/testutil"
+	"github.com/zsoftly/logguardian/internal/types"
+)
+
+func TestComplianceService_CheckCloudWatchLogsPolicyAccess(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   string
+		expected bool
+	}{
+		{
+			name:     "allow with service principal list",
+			policy:   `{"Statement":[{"Effect":"Allow","Principal":{"Service":["logs.amazonaws.com"]},"Action":["kms:Encrypt*","kms:Decrypt*"],"Resource":"*"}]}`,
+			expected: true,
+		},
+		{
+			name:     "allow with regional principal and single action",
+			policy:   `{"Statement":{"Effect":"Allow","Principal":{"Service":"logs.ca-central-1.amazonaws.com"},"Action":"kms:GenerateDataKey*","Resource":"*"}}`,
+			expected: true,
+		},
+		{
+			name:     "allow with wildcard action",
+			policy:   `{"Statement":[{"Effect":"Allow","Principal":{"Service":"logs.amazonaws.com"},"Action":"kms:*","Resource":"*"}]}`,
+			expected: true,
+		},
+		{
+			name:     "deny mentioning logs principal",
+			policy:   `{"Statement":[{"Effect":"Deny","Principal":{"Service":"logs.amazonaws.com"},"Action":"kms:*","Resource":"*"},{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::123456789012:root"},"Action":"kms:*","Resource":"*"}]}`,
+			expected: false,
+		},
+		{
+			name:     "logs principal in a condition only",
+			policy:   `{"Statement":[{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::123456789012:root"},"Action":"kms:*","Resource":"*","Condition":{"StringEquals":{"kms:ViaService":"logs.amazonaws.com"}}}]}`,
+			expected: false,
+		},
+		{
+			name:     "allow without encryption actions",
+			policy:   `{"Statement":[{"Effect":"Allow","Principal":{"Service":"logs.amazonaws.com"},"Action":["kms:DescribeKey"],"Resource":"*"}]}`,
+			expected: false,
+		},
+		{
+			name:     "malformed JSON",
+			policy:   `{"Statement":[{"Effect":"Allow","Principal":{"Service":"logs.amazonaws.com"}`,
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &ComplianceService{config: ServiceConfig{Region: "ca-central-1"}}
+			assert.Equal(t, tt.expected, service.checkCloudWatchLogsPolicyAccess(tt.policy))
+		})
+	}
+}
+
+func TestComplianceService_CheckCloudWatchLogsPolicyAccess_Warnings(t *testing.T) {
+	allowed := `{"Statement":[{"Effect":"Allow","Principal":{"Service":"logs.amazonaws.com"},"Action":"kms:*","Resource":"*","Sid":"` +
+		strings.Repeat("x", 200) + `"}]}`
+
+	t.Run("oversized policy is not parsed", func(t *testing.T) {
+		logger, logs := testutil.CaptureLogs(t)
+		service := &ComplianceService{logger: logger, config: ServiceConfig{Region: "ca-central-1", MaxKeyPolicyBytes: 100}}
+
+		assert.False(t, service.checkCloudWatchLogsPolicyAccess(allowed))
+		assert.True(t, logs.HasAuditAction(AuditActionPolicyValidationWarning))
+	})
+
+	t.Run("malformed policy logs a warning", func(t *testing.T) {
+		logger, logs := testutil.CaptureLogs(t)
+		service := &ComplianceService{logger: logger, config: ServiceConfig{Region: "ca-central-1"}}
+
+		assert.False(t, service.checkCloudWatchLogsPolicyAccess(`not json`))
+		assert.True(t, logs.HasAuditAction(AuditActionPolicyValidationWarning))
+	})
+
+	t.Run("default limit parses large valid policies", func(t *testing.T) {
+		service := &ComplianceService{config: ServiceConfig{Region: "ca-central-1"}}
+		assert.True(t, service.checkCloudWatchLogsPolicyAccess(allowed))
+	})
+}
+
+func TestComplianceService_ValidateKMSKeyComprehensively_SuggestedPolicyStatement(t *testing.T) {
+	t.Run("missing access suggests a statement", func(t *testing.T) {
+		kmsClient := &MockKMSClient{
+			KeyPolicy: `{"Statement":[{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::123456789012:root"},"Action":"kms:*","Resource":"*"}]}`,
+		}
+		service := &ComplianceService{
+			kmsClient: kmsClient,
+			config:    ServiceConfig{Region: "ca-central-1", AccountId: "123456789012"},
+		}
+
+		report, err := service.ValidateKMSKeyComprehensively(context.Background(), "alias/test-key")
+		require.NoError(t, err)
+		assert.False(t, report.CloudWatchLogsAccess)
+		assert.True(t, kmsClient.ListGrantsCalled, "Expected grants to be checked when the policy lacks access")
+		require.NotEmpty(t, report.SuggestedPolicyStatement)
+
+		var statement struct {
+			Effect    string
+			Principal struct{ Service string }
+			Action    []string
+			Resource  string
+			Condition map[string]map[string]string
+		}
+		require.NoError(t, json.Unmarshal([]byte(report.SuggestedPolicyStatement), &statement))
+		assert.Equal(t, "Allow", statement.Effect)
+		assert.Equal(t, "logs.ca-central-1.amazonaws.com", statement.Principal.Service)
+		assert.Equal(t, []string{"kms:Encrypt*", "kms:Decrypt*", "kms:ReEncrypt*", "kms:GenerateDataKey*", "kms:Describe*"}, statement.Action)
+		assert.Equal(t, "*", statement.Resource)
+		assert.Equal(t, "arn:aws:logs:ca-central-1:123456789012:*", statement.Condition["ArnLike"]["kms:EncryptionContext:aws:logs:arn"])
+
+		// Adding the suggestion to the policy grants the access that was missing
+		policy := `{"Statement":[` + report.SuggestedPolicyStatement + `]}`
+		assert.True(t, service.checkCloudWatchLogsPolicyAccess(policy))
+	})
+
+	t.Run("existing access suggests nothing", func(t *testing.T) {
+		kmsClient := &MockKMSClient{}
+		service := &ComplianceService{
+			kmsClient: kmsClient,
+			config:    ServiceConfig{Region: "ca-central-1", AccountId: "123456789012"},
+		}
+
+		report, err := service.ValidateKMSKeyComprehensively(context.Background(), "alias/test-key")
+		require.NoError(t, err)
+		assert.True(t, report.CloudWatchLogsAccess)
+		assert.Empty(t, report.SuggestedPolicyStatement)
+		assert.False(t, kmsClient.ListGrantsCalled, "Expected no ListGrants call when the policy grants access")
+	})
+}
+
+func TestComplianceService_ValidateKMSKeyComprehensively_SuggestedPolicyDiff(t *testing.T) {
+	t.Run("diff identifies the added statement", func(t *testing.T) {
+		logger, logs := testutil.CaptureLogs(t)
+		service := &ComplianceService{
+			kmsClient: &MockKMSClient{
+				KeyPolicy: `{"Statement":[{"Sid":"EnableRoot","Effect":"Allow","Principal":{"AWS":"arn:aws:iam::123456789012:root"},"Action":"kms:*","Resource":"*"}]}`,
+			},
+			logger: logger,
+			config: ServiceConfig{Region: "ca-central-1", AccountId: "123456789012"},
+		}
+
+		report, err := service.ValidateKMSKeyComprehensively(context.Background(), "alias/test-key")
+		require.NoError(t, err)
+		require.NotNil(t, report.SuggestedPolicyDiff)
+
+		existing := types.KeyPolicyStatementSummary{
+			Sid:       "EnableRoot",
+			Effect:    "Allow",
+			Principal: `{"AWS":"arn:aws:iam::123456789012:root"}`,
+			Actions:   []string{"kms:*"},
+		}
+		added := types.KeyPolicyStatementSummary{
+			Sid:       "AllowCloudWatchLogsEncryption",
+			Effect:    "Allow",
+			Principal: `{"Service":"logs.ca-central-1.amazonaws.com"}`,
+			Actions:   []string{"kms:Encrypt*", "kms:Decrypt*", "kms:ReEncrypt*", "kms:GenerateDataKey*", "kms:Describe*"},
+		}
+		assert.Equal(t, []types.KeyPolicyStatementSummary{existing}, report.SuggestedPolicyDiff.Before)
+		assert.Equal(t, []types.KeyPolicyStatementSummary{existing, added}, report.SuggestedPolicyDiff.After)
+		assert.Equal(t, []types.KeyPolicyStatementSummary{added}, report.SuggestedPolicyDiff.Added)
+		assert.Empty(t, report.SuggestedPolicyDiff.Removed)
+
+		records := logs.WithAttr("audit_action", AuditActionKMSPolicyAutofixDiff)
+		require.Len(t, records, 1)
+		assert.Equal(t, "alias/test-key", records[0].Attrs["key_alias"])
+		assert.NotContains(t, records[0].Attrs, "policy")
+	})
+
+	t.Run("existing access has no diff", func(t *testing.T) {
+		logger, logs := testutil.CaptureLogs(t)
+		service := &ComplianceService{
+			kmsClient: &MockKMSClient{},
+			logger:    logger,
+			config:    ServiceConfig{Region: "ca-central-1", AccountId: "123456789012"},
+		}
+
+		report, err := service.ValidateKMSKeyComprehensively(context.Background(), "alias/test-key")
+		require.NoError(t, err)
+		assert.Nil(t, report.SuggestedPolicyDiff)
+		assert.False(t, logs.HasAuditAction(AuditActionKMSPolicyAutofixDiff))
+	})
+}
+
+func TestLoadKMSPolicyStatement(t *testing.T) {
+	writeStatement := func(t *testing.T, statement string) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "statement.json")
+		require.NoError(t, os.WriteFile(path, []byte(statement), 0o600))
+		return path
+	}
+
+	t.Run("unset file generates the suggestion", func(t *testing.T) {
+		statement, err := loadKMSPolicyStatement("")
+		require.NoError(t, err)
+		assert.Empty(t, statement)
+	})
+
+	t.Run("custom statement replaces the suggestion", func(t *testing.T) {
+		custom := `{"Sid":"CustomLogs","Effect":"Allow","Principal":{"Service":"logs.amazonaws.com"},"Action":["kms:Encrypt*","kms:Decrypt*","kms:GenerateDataKey*"],"Resource":"*"}`
+		statement, err := loadKMSPolicyStatement(writeStatement(t, custom))
+		require.NoError(t, err)
+		assert.JSONEq(t, custom, statement)
+
+		service := &ComplianceService{
+			kmsClient: &MockKMSClient{
+				KeyPolicy: `{"Statement":[{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::123456789012:root"},"Action":"kms:*","Resource":"*"}]}`,
+			},
+			config:          ServiceConfig{Region: "ca-central-1", AccountId: "123456789012"},
+			policyStatement: statement,
+		}
+
+		report, err := service.ValidateKMSKeyComprehensively(context.Background(), "alias/test-key")
+		require.NoError(t, err)
+		assert.False(t, report.CloudWatchLogsAccess)
+		assert.Equal(t, statement, report.SuggestedPolicyStatement)
+	})
+
+	malformed := []struct {
+		name      string
+		statement string
+		expected  string
+	}{
+		{name: "invalid JSON", statement: `{"Effect":"Allow",`, expected: "not a valid JSON policy statement"},
+		{name: "missing Effect", statement: `{"Principal":{"Service":"logs.amazonaws.com"},"Action":"kms:Encrypt*"}`, expected: "missing Effect"},
+		{name: "unknown Effect", statement: `{"Effect":"Permit","Principal":{"Service":"logs.amazonaws.com"},"Action":"kms:Encrypt*"}`, expected: `got "Permit"`},
+		{name: "missing Principal", statement: `{"Effect":"Allow","Action":"kms:Encrypt*"}`, expected: "missing Principal"},
+		{name: "missing Action", statement: `{"Effect":"Allow","Principal":{"Service":"logs.amazonaws.com"}}`, expected: "missing Action"},
+	}
+	for _, tt := range malformed {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeStatement(t, tt.statement)
+			_, err := loadKMSPolicyStatement(path)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid KMS_POLICY_STATEMENT_FILE "+path)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := loadKMSPolicyStatement(filepath.Join(t.TempDir(), "missing.json"))
+		assert.ErrorContains(t, err, "failed to read KMS_POLICY_STATEMENT_FILE")
+	})
+}
+
+func TestComplianceService_ListAllGrants(t *testing.T) {
+	kmsClient := &MockKMSClient{
+		GrantPages: [][]kmstypes.GrantListEntry{
+			{{GrantId: aws.String("grant-1")}, {GrantId: aws.String("grant-2")}},
+			{{GrantId: aws.String("grant-3")}},
+			{{GrantId: aws.String("grant-4")}},
+		},
+	}
+	service := &ComplianceService{kmsClient: kmsClient}
+
+	grants, err := service.listAllGrants(context.Background(), "key-12345")
+	require.NoError(t, err)
+
+	grantIds := make([]string, 0, len(grants))
+	for _, grant := range grants {
+		grantIds = append(grantIds, aws.ToString(grant.GrantId))
+	}
+	assert.Equal(t, []string{"grant-1", "grant-2", "grant-3", "grant-4"}, grantIds)
+	assert.Equal(t, []string{"", "1", "2"}, kmsClient.ListGrantsMarkers)
+
+	t.Run("error", func(t *testing.T) {
+		service := &ComplianceService{kmsClient: &MockKMSClient{ListGrantsError: errors.New("AccessDeniedException")}}
+		_, err := service.listAllGrants(context.Background(), "key-12345")
+		assert.ErrorContains(t, err, "failed to list grants for key key-12345")
+	})
+}
+
+func TestComplianceService_HasCloudWatchLogsGrant(t *testing.T) {
+	otherGrant := kmstypes.GrantListEntry{
+		GranteePrincipal: aws.String("arn:aws:iam::123456789012:role/app"),
+		Operations:       []kmstypes.GrantOperation{kmstypes.GrantOperationEncrypt},
+	}
+	logsGrant := kmstypes.GrantListEntry{
+		GranteePrincipal: aws.String("logs.ca-central-1.amazonaws.com"),
+		Operations:       []kmstypes.GrantOperation{kmstypes.GrantOperationEncrypt, kmstypes.GrantOperationDecrypt},
+	}
+	describeOnlyGrant := kmstypes.GrantListEntry{
+		GranteePrincipal: aws.String("logs.amazonaws.com"),
+		Operations:       []kmstypes.GrantOperation{kmstypes.GrantOperationDescribeKey},
+	}
+
+	tests := []struct {
+		name     string
+		pages    [][]kmstypes.GrantListEntry
+		expected bool
+	}{
+		{name: "grant on a later page", pages: [][]kmstypes.GrantListEntry{{otherGrant}, {otherGrant}, {logsGrant}}, expected: true},
+		{name: "no logs grant on any page", pages: [][]kmstypes.GrantListEntry{{otherGrant}, {otherGrant}}, expected: false},
+		{name: "logs grant without encryption operations", pages: [][]kmstypes.GrantListEntry{{describeOnlyGrant}}, expected: false},
+		{name: "no grants", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kmsClient := &MockKMSClient{GrantPages: tt.pages}
+			service := &ComplianceService{kmsClient: kmsClient, config: ServiceConfig{Region: "ca-central-1"}}
+
+			hasGrant, err := service.hasCloudWatchLogsGrant(context.Background(), "key-12345")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, hasGrant)
+			assert.Len(t, kmsClient.ListGrantsMarkers, max(len(tt.pages), 1), "every grant page must be read")
+		})
+	}
+
+	t.Run("grant satisfies validation without a policy statement", func(t *testing.T) {
+		service := &ComplianceService{
+			kmsClient: &MockKMSClient{
+				KeyPolicy:  `{"Statement":[{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::123456789012:root"},"Action":"kms:*","Resource":"*"}]}`,
+				GrantPages: [][]kmstypes.GrantListEntry{{otherGrant}, {logsGrant}},
+			},
+			config: ServiceConfig{Region: "ca-central-1", AccountId: "123456789012"},
+		}
+
+		report, err := service.ValidateKMSKeyComprehensively(context.Background(), "alias/test-key")
+		require.NoError(t, err)
+		assert.True(t, report.CloudWatchLogsAccess)
+		assert.Empty(t, report.SuggestedPolicyStatement)
+	})
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+)
+
+// lastKeyRotation returns when the key's material was last rotated, reading every page of its
+// rotation history. A key that has never rotated, or whose type has no rotation history such as
+// an asymmetric or imported key, returns nil without an error.
+func (s *ComplianceService) lastKeyRotation(ctx context.Context, keyId string) (*time.Time, error) {
+	var lastRotated *time.Time
+	var marker *string
+	for {
+		output, err := s.kmsClient.ListKeyRotations(ctx, &kms.ListKeyRotationsInput{
+			KeyId:  aws.String(keyId),
+			Marker: marker,
+		})
+		if err != nil {
+			if checkAPIErrorCode(err, []string{"UnsupportedOperationException"}) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to list key rotations for key %s: %w", keyId, err)
+		}
+
+		for _, rotation := range output.Rotations {
+			if rotation.RotationDate != nil && (lastRotated == nil || rotation.RotationDate.After(*lastRotated)) {
+				lastRotated = rotation.RotationDate
+			}
+		}
+
+		if !output.Truncated || aws.ToString(output.NextMarker) == "" {
+			return lastRotated, nil
+		}
+		marker = output.NextMarker
+	}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComplianceService_ValidateKMSKeyComprehensively_KeyDates(t *testing.T) {
+	createdAt := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	firstRotation := time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)
+	lastRotation := time.Date(2026, 1, 15, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		rotations       []kmstypes.RotationsListEntry
+		rotationsError  error
+		expectRotatedAt *time.Time
+		expectWarning   bool
+	}{
+		{
+			name: "latest rotation reported",
+			rotations: []kmstypes.RotationsListEntry{
+				{RotationDate: aws.Time(lastRotation), RotationType: kmstypes.RotationTypeAutomatic},
+				{RotationDate: aws.Time(firstRotation), RotationType: kmstypes.RotationTypeOnDemand},
+			},
+			expectRotatedAt: &lastRotation,
+		},
+		{
+			name: "never rotated",
+		},
+		{
+			name:           "key type without rotation history",
+			rotationsError: &kmstypes.UnsupportedOperationException{Message: aws.String("asymmetric key")},
+		},
+		{
+			name:           "unreadable history warns",
+			rotationsError: errors.New("AccessDeniedException: not authorized to perform kms:ListKeyRotations"),
+			expectWarning:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &ComplianceService{
+				kmsClient: &MockKMSClient{
+					CreationDate:       aws.Time(createdAt),
+					Rotations:          tt.rotations,
+					ListRotationsError: tt.rotationsError,
+				},
+				config: ServiceConfig{Region: "ca-central-1"},
+			}
+
+			report, err := service.ValidateKMSKeyComprehensively(context.Background(), "alias/test-key")
+
+			require.NoError(t, err)
+			require.NotNil(t, report.KeyCreatedAt)
+			assert.Equal(t, createdAt, *report.KeyCreatedAt)
+			assert.Equal(t, tt.expectRotatedAt, report.LastRotatedAt)
+
+			hasWarning := slices.ContainsFunc(report.ValidationWarnings, func(warning string) bool {
+				return strings.HasPrefix(warning, "Cannot read key rotation history")
+			})
+			assert.Equal(t, tt.expectWarning, hasWarning)
+		})
+	}
+}
+
+func TestComplianceService_ValidateKMSKeyComprehensively_CrossAccountRotations(t *testing.T) {
+	keyArn := "arn:aws:kms:ca-central-1:999999999999:key/12345678-1234-1234-1234-123456789012"
+	kmsClient := &MockKMSClient{KeyArn: keyArn}
+	service := &ComplianceService{
+		kmsClient: kmsClient,
+		config:    ServiceConfig{Region: "ca-central-1", AccountId: "123456789012"},
+	}
+
+	report, err := service.ValidateKMSKeyComprehensively(context.Background(), keyArn)
+
+	require.NoError(t, err)
+	assert.True(t, report.IsCrossAccount)
+	assert.Equal(t, keyArn, kmsClient.ListRotationsKeyId)
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+)
+
+// kmsCallSlots is a semaphore bounding how many KMS API calls are in flight at once
+type kmsCallSlots chan struct{}
+
+// newKMSCallSlots creates a semaphore admitting limit concurrent KMS calls; zero or less disables
+// the bound and returns nil
+func newKMSCallSlots(limit int32) kmsCallSlots {
+	if limit <= 0 {
+		return nil
+	}
+	return make(kmsCallSlots, limit)
+}
+
+// acquire waits for a free slot, returning the context error if ctx is done first
+func (s kmsCallSlots) acquire(ctx context.Context) error {
+	select {
+	case s <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees a slot taken by acquire
+func (s kmsCallSlots) release() {
+	<-s
+}
+
+// limitedKMSClient holds a slot for the duration of every KMS call, so KMS throttling is
+// controlled independently of the batch and region worker pools
+type limitedKMSClient struct {
+	client KMSClientInterface
+	slots  kmsCallSlots
+}
+
+// limitKMSCalls bounds the client's concurrent calls by slots; with nil slots the client is
+// returned unchanged
+func limitKMSCalls(client KMSClientInterface, slots kmsCallSlots) KMSClientInterface {
+	if slots == nil {
+		return client
+	}
+	return &limitedKMSClient{client: client, slots: slots}
+}
+
+func (c *limitedKMSClient) DescribeKey(ctx context.Context, params *kms.DescribeKeyInput, optFns ...func(*kms.Options)) (*kms.DescribeKeyOutput, error) {
+	if err := c.slots.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.slots.release()
+	return c.client.DescribeKey(ctx, params, optFns...)
+}
+
+func (c *limitedKMSClient) GetKeyPolicy(ctx context.Context, params *kms.GetKeyPolicyInput, optFns ...func(*kms.Options)) (*kms.GetKeyPolicyOutput, error) {
+	if err := c.slots.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.slots.release()
+	return c.client.GetKeyPolicy(ctx, params, optFns...)
+}
+
+func (c *limitedKMSClient) ListGrants(ctx context.Context, params *kms.ListGrantsInput, optFns ...func(*kms.Options)) (*kms.ListGrantsOutput, error) {
+	if err := c.slots.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.slots.release()
+	return c.client.ListGrants(ctx, params, optFns...)
+}
+
+func (c *limitedKMSClient) ListKeyRotations(ctx context.Context, params *kms.ListKeyRotationsInput, optFns ...func(*kms.Options)) (*kms.ListKeyRotationsOutput, error) {
+	if err := c.slots.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer c.slots.release()
+	return c.client.ListKeyRotations(ctx, params, optFns...)
+}
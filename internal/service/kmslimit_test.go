@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitKMSCalls_BoundsConcurrentCalls(t *testing.T) {
+	tracking := &concurrencyTrackingKMSClient{delay: 10 * time.Millisecond}
+	client := limitKMSCalls(tracking, newKMSCallSlots(3))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 12; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.DescribeKey(context.Background(), &kms.DescribeKeyInput{KeyId: aws.String("alias/test-key")})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(3), tracking.peak.Load())
+}
+
+func TestLimitKMSCalls_Disabled(t *testing.T) {
+	client := &MockKMSClient{}
+	assert.Nil(t, newKMSCallSlots(0))
+	assert.Same(t, client, limitKMSCalls(client, newKMSCallSlots(0)))
+}
+
+func TestLimitKMSCalls_CancelledWhileWaiting(t *testing.T) {
+	slots := newKMSCallSlots(1)
+	require.NoError(t, slots.acquire(context.Background()))
+	defer slots.release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	tracking := &concurrencyTrackingKMSClient{}
+	_, err := limitKMSCalls(tracking, slots).GetKeyPolicy(ctx, &kms.GetKeyPolicyInput{KeyId: aws.String("key-12345")})
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(0), tracking.peak.Load(), "The call must not reach KMS without a slot")
+}
+
+// regionTrackingKMSClient gives each region its own mock while DescribeKey calls from every region
+// are counted by a shared tracker
+type regionTrackingKMSClient struct {
+	MockKMSClient
+	tracker *concurrencyTrackingKMSClient
+}
+
+func (m *regionTrackingKMSClient) DescribeKey(ctx context.Context, params *kms.DescribeKeyInput, optFns ...func(*kms.Options)) (*kms.DescribeKeyOutput, error) {
+	return m.tracker.DescribeKey(ctx, params, optFns...)
+}
+
+func TestValidateKMSKeysAcrossRegions_SharedKMSCallLimit(t *testing.T) {
+	t.Setenv("MAX_CONCURRENT_KMS_CALLS", "2")
+
+	tracking := &concurrencyTrackingKMSClient{delay: 10 * time.Millisecond}
+	mrs := NewMultiRegionComplianceService(aws.Config{})
+	for i := 0; i < 6; i++ {
+		region := fmt.Sprintf("region-%d", i)
+		mrs.services[region] = &ComplianceService{
+			kmsClient: limitKMSCalls(&regionTrackingKMSClient{tracker: tracking}, mrs.kmsSlots),
+			config:    ServiceConfig{DefaultKMSKeyAlias: "alias/test-key", Region: region},
+		}
+	}
+
+	reports, err := mrs.ValidateKMSKeysAcrossRegions(context.Background(), WithMaxRegionWorkers(6))
+	require.NoError(t, err)
+	assert.Len(t, reports, 6)
+	assert.Equal(t, int32(2), tracking.peak.Load(), "Regions must share the KMS call limit")
+}
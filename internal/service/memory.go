@@ -17,66 +17,84 @@ type MemoryOptimizedComplianceService struct {
 
 // ClientPool manages reusable AWS clients to reduce memory allocations
 type ClientPool struct {
-	logsClients map[string]*cloudwatchlogs.Client
-	kmsClients  map[string]*kms.Client
+	logsClients map[string]*logsClientEntry
+	kmsClients  map[string]*kmsClientEntry
 	mu          sync.RWMutex
 }
 
+// logsClientEntry guards construction of a single region's CloudWatch Logs client
+type logsClientEntry struct {
+	once   sync.Once
+	client *cloudwatchlogs.Client
+}
+
+// kmsClientEntry guards construction of a single region's KMS client
+type kmsClientEntry struct {
+	once   sync.Once
+	client *kms.Client
+}
+
 // NewMemoryOptimizedComplianceService creates a memory-optimized service
 func NewMemoryOptimizedComplianceService(baseService *ComplianceService) *MemoryOptimizedComplianceService {
 	return &MemoryOptimizedComplianceService{
 		ComplianceService: baseService,
-		pool: &ClientPool{
-			logsClients: make(map[string]*cloudwatchlogs.Client),
-			kmsClients:  make(map[string]*kms.Client),
-		},
+		pool:              NewClientPool(),
+	}
+}
+
+// NewClientPool creates an empty client pool
+func NewClientPool() *ClientPool {
+	return &ClientPool{
+		logsClients: make(map[string]*logsClientEntry),
+		kmsClients:  make(map[string]*kmsClientEntry),
 	}
 }
 
-// GetLogsClient returns a cached CloudWatch Logs client for the region
+// GetLogsClient returns a cached CloudWatch Logs client for the region.
+// The map lock only guards entry lookup; createFunc runs outside it so first access
+// for different regions proceeds concurrently, while sync.Once keeps one client per region.
 func (cp *ClientPool) GetLogsClient(region string, createFunc func() *cloudwatchlogs.Client) *cloudwatchlogs.Client {
 	cp.mu.RLock()
-	if client, exists := cp.logsClients[region]; exists {
-		cp.mu.RUnlock()
-		return client
-	}
+	entry, exists := cp.logsClients[region]
 	cp.mu.RUnlock()
 
-	cp.mu.Lock()
-	defer cp.mu.Unlock()
-
-	// Double-check after acquiring write lock
-	if client, exists := cp.logsClients[region]; exists {
-		return client
+	if !exists {
+		cp.mu.Lock()
+		// Double-check after acquiring write lock
+		if entry, exists = cp.logsClients[region]; !exists {
+			entry = &logsClientEntry{}
+			cp.logsClients[region] = entry
+		}
+		cp.mu.Unlock()
 	}
 
-	// Create new client
-	client := createFunc()
-	cp.logsClients[region] = client
-	return client
+	entry.once.Do(func() {
+		entry.client = createFunc()
+	})
+	return entry.client
 }
 
-// GetKMSClient returns a cached KMS client for the region
+// GetKMSClient returns a cached KMS client for the region.
+// Construction happens outside the map lock, mirroring GetLogsClient.
 func (cp *ClientPool) GetKMSClient(region string, createFunc func() *kms.Client) *kms.Client {
 	cp.mu.RLock()
-	if client, exists := cp.kmsClients[region]; exists {
-		cp.mu.RUnlock()
-		return client
-	}
+	entry, exists := cp.kmsClients[region]
 	cp.mu.RUnlock()
 
-	cp.mu.Lock()
-	defer cp.mu.Unlock()
-
-	// Double-check after acquiring write lock
-	if client, exists := cp.kmsClients[region]; exists {
-		return client
+	if !exists {
+		cp.mu.Lock()
+		// Double-check after acquiring write lock
+		if entry, exists = cp.kmsClients[region]; !exists {
+			entry = &kmsClientEntry{}
+			cp.kmsClients[region] = entry
+		}
+		cp.mu.Unlock()
 	}
 
-	// Create new client
-	client := createFunc()
-	cp.kmsClients[region] = client
-	return client
+	entry.once.Do(func() {
+		entry.client = createFunc()
+	})
+	return entry.client
 }
 
 // Cleanup releases resources and triggers garbage collection
@@ -85,8 +103,8 @@ func (cp *ClientPool) Cleanup() {
 	defer cp.mu.Unlock()
 
 	// Clear client maps to allow GC
-	cp.logsClients = make(map[string]*cloudwatchlogs.Client)
-	cp.kmsClients = make(map[string]*kms.Client)
+	cp.logsClients = make(map[string]*logsClientEntry)
+	cp.kmsClients = make(map[string]*kmsClientEntry)
 
 	// Force garbage collection to free memory
 	runtime.GC()
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientPool_OneClientPerRegion(t *testing.T) {
+	pool := NewClientPool()
+
+	var created atomic.Int32
+	createFunc := func() *cloudwatchlogs.Client {
+		created.Add(1)
+		time.Sleep(10 * time.Millisecond) // Widen the race window
+		return cloudwatchlogs.New(cloudwatchlogs.Options{Region: "ca-central-1"})
+	}
+
+	var wg sync.WaitGroup
+	clients := make([]*cloudwatchlogs.Client, 20)
+	for i := range clients {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			clients[i] = pool.GetLogsClient("ca-central-1", createFunc)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), created.Load(), "Expected exactly one client to be constructed")
+	for _, client := range clients {
+		assert.Same(t, clients[0], client)
+	}
+}
+
+func TestClientPool_DistinctRegionsDoNotSerialize(t *testing.T) {
+	pool := NewClientPool()
+
+	const regions = 8
+	const constructDelay = 50 * time.Millisecond
+
+	createFunc := func() *kms.Client {
+		time.Sleep(constructDelay)
+		return kms.New(kms.Options{})
+	}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < regions; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			pool.GetKMSClient(fmt.Sprintf("region-%d", i), createFunc)
+		}(i)
+	}
+	wg.Wait()
+
+	// Serialized construction would take regions*constructDelay
+	assert.Less(t, time.Since(start), time.Duration(regions/2)*constructDelay,
+		"Expected construction for different regions to run concurrently")
+}
+
+// BenchmarkClientPool_ConcurrentDistinctRegions exercises first access for many regions at once.
+// Run with -race to verify the pool's locking.
+func BenchmarkClientPool_ConcurrentDistinctRegions(b *testing.B) {
+	const regions = 32
+
+	createFunc := func() *cloudwatchlogs.Client {
+		return cloudwatchlogs.New(cloudwatchlogs.Options{})
+	}
+
+	for i := 0; i < b.N; i++ {
+		pool := NewClientPool()
+		var wg sync.WaitGroup
+		for r := 0; r < regions; r++ {
+			wg.Add(1)
+			go func(r int) {
+				defer wg.Done()
+				pool.GetLogsClient(fmt.Sprintf("region-%d", r), createFunc)
+			}(r)
+		}
+		wg.Wait()
+	}
+}
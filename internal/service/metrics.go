@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"log/slog"
+	"slices"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -12,7 +13,7 @@ import (
 
 // MetricsService handles CloudWatch metrics publishing
 type MetricsService struct {
-	cloudwatchClient *cloudwatch.Client
+	cloudwatchClient CloudWatchClientInterface
 	environment      string
 	region           string
 	namespace        string
@@ -28,11 +29,24 @@ func NewMetricsService(cfg aws.Config) *MetricsService {
 	}
 }
 
+// maxMetricValues is the most values CloudWatch accepts in a single metric datum
+const maxMetricValues = 150
+
 // MetricsData holds metrics for batch publishing
 type MetricsData struct {
 	LogGroupsProcessed  int
 	LogGroupsRemediated int
 	RemediationErrors   int
+	// RemediationDurations holds the duration of each remediated resource, published as a
+	// RemediationDuration distribution per region and action
+	RemediationDurations []RemediationDuration
+}
+
+// RemediationDuration is how long remediating a single resource took
+type RemediationDuration struct {
+	Region   string
+	Action   string
+	Duration time.Duration
 }
 
 // PublishBatchMetrics publishes all metrics from a batch operation
@@ -89,6 +103,8 @@ func (m *MetricsService) PublishBatchMetrics(ctx context.Context, metrics Metric
 		})
 	}
 
+	metricData = append(metricData, m.remediationDurationData(metrics.RemediationDurations, timestamp)...)
+
 	// Publish metrics if we have any
 	if len(metricData) > 0 {
 		input := &cloudwatch.PutMetricDataInput{
@@ -112,12 +128,55 @@ func (m *MetricsService) PublishBatchMetrics(ctx context.Context, metrics Metric
 			"metrics_published", len(metricData),
 			"processed", metrics.LogGroupsProcessed,
 			"remediated", metrics.LogGroupsRemediated,
-			"errors", metrics.RemediationErrors)
+			"errors", metrics.RemediationErrors,
+			"durations", len(metrics.RemediationDurations))
 	}
 
 	return nil
 }
 
+// remediationDurationData groups the durations by region and action into RemediationDuration
+// datums of millisecond values, so CloudWatch can report their distribution
+func (m *MetricsService) remediationDurationData(durations []RemediationDuration, timestamp time.Time) []types.MetricDatum {
+	type dimensions struct{ region, action string }
+	var order []dimensions
+	values := make(map[dimensions][]float64)
+	for _, d := range durations {
+		key := dimensions{region: d.Region, action: d.Action}
+		if _, ok := values[key]; !ok {
+			order = append(order, key)
+		}
+		values[key] = append(values[key], float64(d.Duration.Microseconds())/1000)
+	}
+
+	var metricData []types.MetricDatum
+	for _, key := range order {
+		for chunk := range slices.Chunk(values[key], maxMetricValues) {
+			metricData = append(metricData, types.MetricDatum{
+				MetricName: aws.String("RemediationDuration"),
+				Values:     chunk,
+				Unit:       types.StandardUnitMilliseconds,
+				Timestamp:  &timestamp,
+				Dimensions: []types.Dimension{
+					{
+						Name:  aws.String("Environment"),
+						Value: aws.String(m.environment),
+					},
+					{
+						Name:  aws.String("Region"),
+						Value: aws.String(key.region),
+					},
+					{
+						Name:  aws.String("Action"),
+						Value: aws.String(key.action),
+					},
+				},
+			})
+		}
+	}
+	return metricData
+}
+
 // PublishSingleMetric publishes a single metric
 func (m *MetricsService) PublishSingleMetric(ctx context.Context, metricName string, value float64, unit types.StandardUnit) error {
 	timestamp := time.Now()
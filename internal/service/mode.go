@@ -0,0 +1,52 @@
+package service
+
+import "strconv"
+
+// ExecutionMode is whether a run applies remediation or only previews it
+type ExecutionMode string
+
+const (
+	ExecutionModeApply  ExecutionMode = "apply"
+	ExecutionModeDryRun ExecutionMode = "dry-run"
+)
+
+// ExecutionModeSource names the setting that decided the execution mode
+type ExecutionModeSource string
+
+const (
+	ExecutionModeSourceFlag    ExecutionModeSource = "flag"    // The --dry-run flag
+	ExecutionModeSourceEnv     ExecutionModeSource = "env"     // The DRY_RUN environment variable
+	ExecutionModeSourceDefault ExecutionModeSource = "default" // Neither was set
+)
+
+// ExecutionModeResolution is the resolved execution mode and the setting it came from
+type ExecutionModeResolution struct {
+	Mode   ExecutionMode
+	Source ExecutionModeSource
+}
+
+// DryRun reports whether the resolved mode only previews changes
+func (r ExecutionModeResolution) DryRun() bool {
+	return r.Mode == ExecutionModeDryRun
+}
+
+// ResolveExecutionMode decides the execution mode the same way for every entrypoint. An
+// explicitly set --dry-run flag wins; flagDryRun is nil when the flag was not given, as it always
+// is for the Lambda. Otherwise DRY_RUN decides when it holds a boolean, and the default is apply.
+func ResolveExecutionMode(flagDryRun *bool, envDryRun string) ExecutionModeResolution {
+	if flagDryRun != nil {
+		return newExecutionModeResolution(*flagDryRun, ExecutionModeSourceFlag)
+	}
+	if dryRun, err := strconv.ParseBool(envDryRun); err == nil {
+		return newExecutionModeResolution(dryRun, ExecutionModeSourceEnv)
+	}
+	return newExecutionModeResolution(false, ExecutionModeSourceDefault)
+}
+
+func newExecutionModeResolution(dryRun bool, source ExecutionModeSource) ExecutionModeResolution {
+	mode := ExecutionModeApply
+	if dryRun {
+		mode = ExecutionModeDryRun
+	}
+	return ExecutionModeResolution{Mode: mode, Source: source}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveExecutionMode(t *testing.T) {
+	enabled, disabled := true, false
+
+	tests := []struct {
+		name       string
+		flagDryRun *bool
+		envDryRun  string
+		expected   ExecutionModeResolution
+	}{
+		{
+			name:       "flag wins over environment",
+			flagDryRun: &disabled,
+			envDryRun:  "true",
+			expected:   ExecutionModeResolution{Mode: ExecutionModeApply, Source: ExecutionModeSourceFlag},
+		},
+		{
+			name:       "flag enables dry run",
+			flagDryRun: &enabled,
+			expected:   ExecutionModeResolution{Mode: ExecutionModeDryRun, Source: ExecutionModeSourceFlag},
+		},
+		{
+			name:      "environment wins without flag",
+			envDryRun: "TRUE",
+			expected:  ExecutionModeResolution{Mode: ExecutionModeDryRun, Source: ExecutionModeSourceEnv},
+		},
+		{
+			name:      "environment disables dry run",
+			envDryRun: "false",
+			expected:  ExecutionModeResolution{Mode: ExecutionModeApply, Source: ExecutionModeSourceEnv},
+		},
+		{
+			name:     "default",
+			expected: ExecutionModeResolution{Mode: ExecutionModeApply, Source: ExecutionModeSourceDefault},
+		},
+		{
+			name:      "unparsable environment falls back to default",
+			envDryRun: "yes please",
+			expected:  ExecutionModeResolution{Mode: ExecutionModeApply, Source: ExecutionModeSourceDefault},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolution := ResolveExecutionMode(tt.flagDryRun, tt.envDryRun)
+			assert.Equal(t, tt.expected, resolution)
+			assert.Equal(t, tt.expected.Mode == ExecutionModeDryRun, resolution.DryRun())
+		})
+	}
+}
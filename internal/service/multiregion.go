@@ -2,27 +2,68 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/configservice"
 	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
 	"github.com/zsoftly/logguardian/internal/types"
 )
 
+const (
+	// stsMaxAttempts bounds AssumeRole attempts per region; the standard retryer backs off on throttling
+	stsMaxAttempts = 8
+	// stsMaxBackoff caps the delay between AssumeRole attempts
+	stsMaxBackoff = 20 * time.Second
+	// maxSTSThrottleRetries bounds how often a region's validation is retried after STS throttling
+	maxSTSThrottleRetries = 3
+)
+
 // MultiRegionComplianceService handles compliance across multiple AWS regions
 type MultiRegionComplianceService struct {
 	baseConfig     aws.Config
+	assumeRoleARN  string                        // role assumed in each region, if any
+	autoAddRegions bool                          // add unknown regions on demand instead of failing
+	regionRouting  map[string]string             // declared region -> region remediated in
 	serviceConfigs map[string]ServiceConfig      // region -> config
 	services       map[string]*ComplianceService // region -> service
+	serviceOpts    []ComplianceServiceOption     // applied to every region's service
+	kmsSlots       kmsCallSlots                  // KMS calls in flight across all regions; nil when unbounded
 	mu             sync.RWMutex
 }
 
+// MultiRegionOption configures a MultiRegionComplianceService
+type MultiRegionOption func(*MultiRegionComplianceService)
+
+// WithRegionAssumeRole makes every region's clients assume roleARN through STS
+func WithRegionAssumeRole(roleARN string) MultiRegionOption {
+	return func(mrs *MultiRegionComplianceService) {
+		mrs.assumeRoleARN = roleARN
+	}
+}
+
+// WithRegionServiceOptions applies opts to the compliance service created for every region
+func WithRegionServiceOptions(opts ...ComplianceServiceOption) MultiRegionOption {
+	return func(mrs *MultiRegionComplianceService) {
+		mrs.serviceOpts = append(mrs.serviceOpts, opts...)
+	}
+}
+
+// RegionTask is work run against a single region's compliance service
+type RegionTask func(ctx context.Context, region string, service ComplianceServiceInterface) error
+
 // validationResult represents the result of KMS key validation for a specific region
 type validationResult struct {
 	region string
@@ -35,13 +76,65 @@ type regionJob struct {
 	service *ComplianceService
 }
 
+// WithAutoAddRegions makes RemediateLogGroup add an unconfigured region with the default
+// per-region configuration instead of failing
+func WithAutoAddRegions(enabled bool) MultiRegionOption {
+	return func(mrs *MultiRegionComplianceService) {
+		mrs.autoAddRegions = enabled
+	}
+}
+
+// WithRegionRouting makes RemediateLogGroup remediate log groups declared in a source region
+// through the service of the mapped target region, for resources such as replicas whose Config
+// event names a region other than the one to operate in. Unmapped regions use their own service.
+func WithRegionRouting(routes map[string]string) MultiRegionOption {
+	return func(mrs *MultiRegionComplianceService) {
+		mrs.regionRouting = routes
+	}
+}
+
+// RegionValidationOptions controls how ValidateKMSKeysAcrossRegions fans out
+type RegionValidationOptions struct {
+	// MaxWorkers bounds the number of regions validated concurrently
+	MaxWorkers int
+	// STSThrottleBackoff is the initial delay before retrying a region whose role assumption was
+	// throttled; it doubles on each retry. Only used when a region assume role is configured.
+	STSThrottleBackoff time.Duration
+}
+
+// WithMaxRegionWorkers overrides the REGION_CONCURRENCY and MAX_REGION_WORKERS environment settings
+func WithMaxRegionWorkers(maxWorkers int) func(*RegionValidationOptions) {
+	return func(o *RegionValidationOptions) {
+		o.MaxWorkers = maxWorkers
+	}
+}
+
+// regionWorkersFromEnvironment returns how many regions may be processed at once: REGION_CONCURRENCY
+// when set, else MAX_REGION_WORKERS (default 10). A value of 1 processes regions strictly in sequence.
+func regionWorkersFromEnvironment() int {
+	return getEnvAsIntOrDefault("REGION_CONCURRENCY", getEnvAsIntOrDefault("MAX_REGION_WORKERS", 10))
+}
+
+// WithSTSThrottleBackoff overrides the initial backoff after STS throttles a region's role assumption
+func WithSTSThrottleBackoff(backoff time.Duration) func(*RegionValidationOptions) {
+	return func(o *RegionValidationOptions) {
+		o.STSThrottleBackoff = backoff
+	}
+}
+
 // NewMultiRegionComplianceService creates a new multi-region compliance service
-func NewMultiRegionComplianceService(baseConfig aws.Config) *MultiRegionComplianceService {
-	return &MultiRegionComplianceService{
+func NewMultiRegionComplianceService(baseConfig aws.Config, opts ...MultiRegionOption) *MultiRegionComplianceService {
+	mrs := &MultiRegionComplianceService{
 		baseConfig:     baseConfig,
 		serviceConfigs: make(map[string]ServiceConfig),
 		services:       make(map[string]*ComplianceService),
+		// Every region shares one KMS call semaphore, so fanning out across regions stays within it
+		kmsSlots: newKMSCallSlots(getEnvAsInt32OrDefault("MAX_CONCURRENT_KMS_CALLS", 0)),
+	}
+	for _, opt := range opts {
+		opt(mrs)
 	}
+	return mrs
 }
 
 // AddRegion adds support for a specific region with custom configuration
@@ -49,41 +142,75 @@ func (mrs *MultiRegionComplianceService) AddRegion(region string, serviceConfig
 	mrs.mu.Lock()
 	defer mrs.mu.Unlock()
 
+	return mrs.addRegionLocked(region, serviceConfig)
+}
+
+// addRegionLocked creates the region's clients and service; the caller must hold mu for writing
+func (mrs *MultiRegionComplianceService) addRegionLocked(region string, serviceConfig ServiceConfig) error {
+	policyStatement, err := loadKMSPolicyStatement(serviceConfig.KMSPolicyStatementFile)
+	if err != nil {
+		return err
+	}
+
 	// Create region-specific AWS config
-	regionConfig := mrs.baseConfig.Copy()
+	regionConfig := ApplyHTTPTimeout(ApplyUserAgent(mrs.baseConfig), serviceConfig.HTTPTimeout)
 	regionConfig.Region = region
 
-	// Create CloudWatch Logs and KMS clients for this region
-	logsClient := cloudwatchlogs.NewFromConfig(regionConfig)
-	kmsClient := kms.NewFromConfig(regionConfig)
+	// Assume the configured role in this region; STS throttling is retried with backoff so that
+	// constructing many regions at once does not fail outright
+	if mrs.assumeRoleARN != "" {
+		stsClient := sts.NewFromConfig(regionConfig, func(o *sts.Options) {
+			o.Retryer = retry.NewStandard(func(so *retry.StandardOptions) {
+				so.MaxAttempts = stsMaxAttempts
+				so.MaxBackoff = stsMaxBackoff
+			})
+		})
+		regionConfig.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(stsClient, mrs.assumeRoleARN))
+	}
 
-	// Create compliance service for this region
+	// Create compliance service for this region with its own clients
 	service := &ComplianceService{
-		logsClient:     logsClient,
-		kmsClient:      kmsClient,
-		ruleClassifier: types.NewRuleClassifier(), // Initialize rule classifier
-		config:         serviceConfig,
+		logsClient:      cloudwatchlogs.NewFromConfig(regionConfig),
+		kmsClient:       limitKMSCalls(kms.NewFromConfig(regionConfig), mrs.kmsSlots),
+		configClient:    configservice.NewFromConfig(regionConfig),
+		ruleClassifier:  types.NewRuleClassifier(),
+		metricsService:  NewMetricsService(regionConfig),
+		config:          serviceConfig,
+		logger:          slog.Default(),
+		keyCache:        sharedKMSKeyCache,
+		accountLimiter:  newAccountRateLimiter(serviceConfig.AccountRateLimit),
+		policyStatement: policyStatement,
 	}
+	for _, opt := range mrs.serviceOpts {
+		opt(service)
+	}
+	service.normalizeConfig()
+	service.configEvalService = NewConfigEvaluationService(regionConfig, service.config)
 
-	mrs.serviceConfigs[region] = serviceConfig
+	mrs.serviceConfigs[region] = service.config
 	mrs.services[region] = service
 
 	slog.Info("Added region support",
 		"region", region,
 		"kms_key_alias", serviceConfig.DefaultKMSKeyAlias,
-		"retention_days", serviceConfig.DefaultRetentionDays)
-
+		"retention_days", serviceConfig.DefaultRetentionDays,
+		"assume_role", mrs.assumeRoleARN)
 	return nil
 }
 
 // RemediateLogGroup applies remediation to a log group in the appropriate region
 func (mrs *MultiRegionComplianceService) RemediateLogGroup(ctx context.Context, compliance types.ComplianceResult) (*types.RemediationResult, error) {
-	mrs.mu.RLock()
-	service, exists := mrs.services[compliance.Region]
-	mrs.mu.RUnlock()
+	if target, ok := mrs.regionRouting[compliance.Region]; ok && target != compliance.Region {
+		slog.Info("Routing remediation to mapped region",
+			"log_group", compliance.LogGroupName,
+			"declared_region", compliance.Region,
+			"region", target)
+		compliance.Region = target
+	}
 
-	if !exists {
-		return nil, fmt.Errorf("no service configured for region %s", compliance.Region)
+	service, err := mrs.serviceForRegion(compliance.Region)
+	if err != nil {
+		return nil, err
 	}
 
 	slog.Info("Using region-specific service",
@@ -93,6 +220,119 @@ func (mrs *MultiRegionComplianceService) RemediateLogGroup(ctx context.Context,
 	return service.RemediateLogGroup(ctx, compliance)
 }
 
+// RemediateAcrossRegions runs task for every configured region concurrently, on a worker pool
+// bounded by REGION_CONCURRENCY, MAX_REGION_WORKERS or WithMaxRegionWorkers, and returns each region's error (nil on
+// success). Regions not yet started when ctx is cancelled report the context error.
+func (mrs *MultiRegionComplianceService) RemediateAcrossRegions(ctx context.Context, task RegionTask, opts ...func(*RegionValidationOptions)) map[string]error {
+	// Snapshot the regions so tasks may look up services without holding the read lock
+	jobs := mrs.regionJobs()
+
+	options := RegionValidationOptions{
+		MaxWorkers: regionWorkersFromEnvironment(),
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	numWorkers := min(max(options.MaxWorkers, 1), len(jobs))
+
+	slog.Info("Starting multi-region remediation",
+		"regions", len(jobs),
+		"workers", numWorkers,
+		"audit_action", "multi_region_remediation_start")
+
+	results := make(map[string]error, len(jobs))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	jobChan := make(chan regionJob, len(jobs))
+	for _, job := range jobs {
+		jobChan <- job
+	}
+	close(jobChan)
+
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobChan {
+				err := ctx.Err()
+				if err == nil {
+					err = task(ctx, job.region, job.service)
+				}
+				if err != nil {
+					slog.Error("Multi-region remediation failed in region",
+						"region", job.region,
+						"error", err,
+						"audit_action", "region_remediation_error")
+				}
+
+				mu.Lock()
+				results[job.region] = err
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	failedRegions := 0
+	for _, err := range results {
+		if err != nil {
+			failedRegions++
+		}
+	}
+	slog.Info("Multi-region remediation summary",
+		"total_regions", len(results),
+		"failed_regions", failedRegions,
+		"audit_action", "multi_region_remediation_complete")
+
+	return results
+}
+
+// regionJobs snapshots the configured regions under a brief read lock, so long-running work
+// against them does not block AddRegion. Each job keeps its region's service, so the work stays
+// consistent even if the services map changes while it runs.
+func (mrs *MultiRegionComplianceService) regionJobs() []regionJob {
+	mrs.mu.RLock()
+	defer mrs.mu.RUnlock()
+
+	jobs := make([]regionJob, 0, len(mrs.services))
+	for region, service := range mrs.services {
+		jobs = append(jobs, regionJob{region, service})
+	}
+	return jobs
+}
+
+// serviceForRegion returns the region's service, adding the region with the default configuration
+// when auto-add is enabled
+func (mrs *MultiRegionComplianceService) serviceForRegion(region string) (*ComplianceService, error) {
+	mrs.mu.RLock()
+	service, exists := mrs.services[region]
+	mrs.mu.RUnlock()
+
+	if exists {
+		return service, nil
+	}
+	if !mrs.autoAddRegions || region == "" {
+		return nil, fmt.Errorf("no service configured for region %s", region)
+	}
+
+	mrs.mu.Lock()
+	defer mrs.mu.Unlock()
+
+	// Another caller may have added the region while we waited for the lock
+	if service, exists := mrs.services[region]; exists {
+		return service, nil
+	}
+
+	slog.Warn("Adding unconfigured region with default configuration",
+		"region", region,
+		"audit_action", "region_auto_added")
+	if err := mrs.addRegionLocked(region, defaultRegionServiceConfig(region)); err != nil {
+		return nil, err
+	}
+	return mrs.services[region], nil
+}
+
 // GetSupportedRegions returns the list of configured regions
 func (mrs *MultiRegionComplianceService) GetSupportedRegions() []string {
 	mrs.mu.RLock()
@@ -108,15 +348,7 @@ func (mrs *MultiRegionComplianceService) GetSupportedRegions() []string {
 // LoadRegionsFromConfig loads multiple regions from environment configuration
 func (mrs *MultiRegionComplianceService) LoadRegionsFromConfig(ctx context.Context, regions []string) error {
 	for _, region := range regions {
-		// Create region-specific configuration
-		// You could customize this per region by reading region-specific env vars
-		serviceConfig := ServiceConfig{
-			DefaultKMSKeyAlias:   getEnvOrDefault(fmt.Sprintf("KMS_KEY_ALIAS_%s", region), getEnvOrDefault("KMS_KEY_ALIAS", "alias/cloudwatch-logs-compliance")),
-			DefaultRetentionDays: getEnvAsInt32OrDefault(fmt.Sprintf("DEFAULT_RETENTION_DAYS_%s", region), getEnvAsInt32OrDefault("DEFAULT_RETENTION_DAYS", 365)),
-			DryRun:               getEnvAsBoolOrDefault("DRY_RUN", false),
-		}
-
-		if err := mrs.AddRegion(region, serviceConfig); err != nil {
+		if err := mrs.AddRegion(region, defaultRegionServiceConfig(region)); err != nil {
 			return fmt.Errorf("failed to add region %s: %w", region, err)
 		}
 	}
@@ -125,81 +357,188 @@ func (mrs *MultiRegionComplianceService) LoadRegionsFromConfig(ctx context.Conte
 	return nil
 }
 
-// ValidateRegionAccess validates that we can access required services in each region
-func (mrs *MultiRegionComplianceService) ValidateRegionAccess(ctx context.Context) error {
-	mrs.mu.RLock()
-	defer mrs.mu.RUnlock()
+// defaultRegionServiceConfig builds a region's configuration from the environment, preferring
+// region-suffixed variables such as KMS_KEY_ALIAS_ca-west-1 over the global ones
+func defaultRegionServiceConfig(region string) ServiceConfig {
+	config := serviceConfigFromEnvironment(region)
+	config.DefaultKMSKeyAlias = normalizeKMSKeyAlias(getEnvOrDefault(fmt.Sprintf("KMS_KEY_ALIAS_%s", region), config.DefaultKMSKeyAlias))
+	config.DefaultRetentionDays = getEnvAsInt32OrDefault(fmt.Sprintf("DEFAULT_RETENTION_DAYS_%s", region), config.DefaultRetentionDays)
+	return config
+}
 
-	for region, service := range mrs.services {
-		slog.Info("Validating region access", "region", region)
+// ValidateRegionAccess validates that we can access required services in each region and returns
+// the KMS key state of every region, sorted by region. A missing or unusable key does not fail
+// validation since it may be created or enabled later; CloudWatch Logs access failures do.
+func (mrs *MultiRegionComplianceService) ValidateRegionAccess(ctx context.Context) ([]types.RegionKeySummary, error) {
+	jobs := mrs.regionJobs()
 
-		// Test CloudWatch Logs access by listing log groups (limit 1)
-		logsInput := &cloudwatchlogs.DescribeLogGroupsInput{
-			Limit: aws.Int32(1),
-		}
-		_, err := service.logsClient.DescribeLogGroups(ctx, logsInput)
+	summaries := make([]types.RegionKeySummary, 0, len(jobs))
+	for _, job := range jobs {
+		summary, err := validateRegionAccess(ctx, job.region, job.service)
 		if err != nil {
-			slog.Error("Failed to access CloudWatch Logs in region",
-				"region", region,
-				"error", err,
-				"audit_action", "region_validation_failed",
-				"service", "cloudwatch_logs")
-			return fmt.Errorf("failed to access CloudWatch Logs in region %s: %w", region, err)
+			return nil, err
 		}
+		summaries = append(summaries, summary)
+	}
+
+	slices.SortFunc(summaries, func(a, b types.RegionKeySummary) int {
+		return strings.Compare(a.Region, b.Region)
+	})
+	return summaries, nil
+}
+
+// PreflightRegions runs the ValidateRegionAccess checks in every region without stopping at the
+// first failure, returning one row per region sorted by region. A region fails only when a
+// critical check such as CloudWatch Logs access fails.
+func (mrs *MultiRegionComplianceService) PreflightRegions(ctx context.Context) []types.RegionPreflightResult {
+	jobs := mrs.regionJobs()
 
-		// Test KMS access by validating the key alias
-		keyInfo, err := service.validateKMSKeyAccessibility(ctx, service.config.DefaultKMSKeyAlias)
+	results := make([]types.RegionPreflightResult, 0, len(jobs))
+	for _, job := range jobs {
+		result := types.RegionPreflightResult{Region: job.region}
+		summary, err := validateRegionAccess(ctx, job.region, job.service)
 		if err != nil {
-			slog.Warn("KMS key validation failed during region validation",
-				"region", region,
-				"key_alias", service.config.DefaultKMSKeyAlias,
-				"error", err,
-				"audit_action", "region_validation_warning",
-				"service", "kms",
-				"note", "KMS key may not exist yet - this is acceptable if keys will be created later")
-			// Don't fail validation if KMS key doesn't exist - it might be created later
+			result.Error = err.Error()
 		} else {
-			// If key exists, perform comprehensive validation
-			slog.Info("KMS key validation successful during region access check",
+			result.Passed = true
+			result.Key = &summary
+		}
+		results = append(results, result)
+	}
+
+	slices.SortFunc(results, func(a, b types.RegionPreflightResult) int {
+		return strings.Compare(a.Region, b.Region)
+	})
+	return results
+}
+
+// PreflightAllRegions runs comprehensive KMS validation in every region and returns a
+// *KMSPreflightError naming each region with a hard validation error, sorted by region. Regions
+// with only warnings pass.
+func (mrs *MultiRegionComplianceService) PreflightAllRegions(ctx context.Context) error {
+	reports, err := mrs.ValidateKMSKeysAcrossRegions(ctx)
+	if err != nil {
+		return err
+	}
+
+	failures := make(map[string]string)
+	for region, report := range reports {
+		if len(report.ValidationErrors) > 0 {
+			failures[region] = strings.Join(report.ValidationErrors, ", ")
+		}
+	}
+	if len(failures) > 0 {
+		return &KMSPreflightError{Failures: failures, Regions: len(reports)}
+	}
+	return nil
+}
+
+// validateRegionAccess checks CloudWatch Logs and KMS access in a single region, returning the
+// region's KMS key state. Only a CloudWatch Logs access failure is returned as an error.
+func validateRegionAccess(ctx context.Context, region string, service *ComplianceService) (types.RegionKeySummary, error) {
+	slog.Info("Validating region access", "region", region)
+
+	// Test CloudWatch Logs access by listing log groups (limit 1)
+	logsInput := &cloudwatchlogs.DescribeLogGroupsInput{
+		Limit: aws.Int32(1),
+	}
+	_, err := service.logsClient.DescribeLogGroups(ctx, logsInput)
+	if err != nil {
+		slog.Error("Failed to access CloudWatch Logs in region",
+			"region", region,
+			"error", err,
+			"audit_action", "region_validation_failed",
+			"service", "cloudwatch_logs")
+		return types.RegionKeySummary{}, fmt.Errorf("failed to access CloudWatch Logs in region %s: %w", region, err)
+	}
+
+	// Test KMS access by validating the key alias
+	keyInfo, err := service.validateKMSKeyAccessibility(ctx, service.config.DefaultKMSKeyAlias)
+	summary := regionKeySummary(region, service.config.DefaultKMSKeyAlias, keyInfo, err)
+	slog.Info("Region KMS key state",
+		"region", region,
+		"key_alias", summary.KeyAlias,
+		"key_status", summary.Status,
+		"key_state", summary.KeyState,
+		"audit_action", "region_kms_key_state")
+
+	if err != nil {
+		slog.Warn("KMS key validation failed during region validation",
+			"region", region,
+			"key_alias", service.config.DefaultKMSKeyAlias,
+			"error", err,
+			"audit_action", "region_validation_warning",
+			"service", "kms",
+			"note", "KMS key may not exist yet - this is acceptable if keys will be created later")
+		// Don't fail validation if KMS key doesn't exist - it might be created later
+	} else {
+		// If key exists, perform comprehensive validation
+		slog.Info("KMS key validation successful during region access check",
+			"region", region,
+			"key_alias", service.config.DefaultKMSKeyAlias,
+			"kms_key_id", keyInfo.KeyId,
+			"kms_key_arn", keyInfo.Arn,
+			"key_state", keyInfo.KeyState,
+			"key_region", keyInfo.Region,
+			"audit_action", "region_kms_validation_success")
+
+		// Also validate the key policy for CloudWatch Logs
+		if err := service.validateKMSKeyPolicyForCloudWatchLogs(ctx, keyInfo.KeyId); err != nil {
+			slog.Warn("KMS key policy validation failed during region validation",
 				"region", region,
-				"key_alias", service.config.DefaultKMSKeyAlias,
 				"kms_key_id", keyInfo.KeyId,
-				"kms_key_arn", keyInfo.Arn,
-				"key_state", keyInfo.KeyState,
-				"key_region", keyInfo.Region,
-				"audit_action", "region_kms_validation_success")
-
-			// Also validate the key policy for CloudWatch Logs
-			if err := service.validateKMSKeyPolicyForCloudWatchLogs(ctx, keyInfo.KeyId); err != nil {
-				slog.Warn("KMS key policy validation failed during region validation",
-					"region", region,
-					"kms_key_id", keyInfo.KeyId,
-					"error", err,
-					"audit_action", "region_policy_validation_warning",
-					"note", "Key policy may need adjustment for CloudWatch Logs access")
-			}
+				"error", err,
+				"audit_action", "region_policy_validation_warning",
+				"note", "Key policy may need adjustment for CloudWatch Logs access")
 		}
+	}
 
-		slog.Info("Region validation passed",
-			"region", region,
-			"audit_action", "region_validation_success")
+	slog.Info("Region validation passed",
+		"region", region,
+		"audit_action", "region_validation_success")
+	return summary, nil
+}
+
+// regionKeySummary classifies the outcome of validating a region's KMS key, separating a key that
+// does not exist from one that exists but cannot be used
+func regionKeySummary(region, keyAlias string, keyInfo *KMSKeyInfo, err error) types.RegionKeySummary {
+	summary := types.RegionKeySummary{
+		Region:   region,
+		KeyAlias: keyAlias,
+	}
+	if err == nil {
+		summary.Status = types.RegionKeyStatusUsable
+		summary.KeyState = keyInfo.KeyState
+		return summary
 	}
 
-	return nil
+	summary.Error = err.Error()
+	var auditErr *AuditError
+	var stateErr *KMSKeyStateError
+	switch {
+	case errors.As(err, &stateErr):
+		summary.Status = types.RegionKeyStatusUnusable
+		summary.KeyState = string(stateErr.State)
+	case errors.As(err, &auditErr) && (auditErr.Reason == FailureReasonKeyNotFound || auditErr.Reason == FailureReasonAliasNotFound):
+		summary.Status = types.RegionKeyStatusMissing
+	default:
+		summary.Status = types.RegionKeyStatusUnusable
+	}
+	return summary
 }
 
 // ValidateKMSKeysAcrossRegions validates KMS keys in all configured regions
 // This provides comprehensive cross-region KMS key validation with concurrent processing
-func (mrs *MultiRegionComplianceService) ValidateKMSKeysAcrossRegions(ctx context.Context) (map[string]*types.KMSValidationReport, error) {
-	mrs.mu.RLock()
-	defer mrs.mu.RUnlock()
+func (mrs *MultiRegionComplianceService) ValidateKMSKeysAcrossRegions(ctx context.Context, opts ...func(*RegionValidationOptions)) (map[string]*types.KMSValidationReport, error) {
+	// Validate a snapshot of the regions so regions can be added during a long validation
+	jobs := mrs.regionJobs()
 
 	reports := make(map[string]*types.KMSValidationReport)
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 
 	slog.Info("Starting cross-region KMS key validation",
-		"regions", len(mrs.services),
+		"regions", len(jobs),
 		"audit_action", "multi_region_kms_validation_start")
 
 	// Use worker pool pattern to control concurrency and prevent resource exhaustion
@@ -207,16 +546,33 @@ func (mrs *MultiRegionComplianceService) ValidateKMSKeysAcrossRegions(ctx contex
 	// - AWS APIs typically allow 10-20 requests per second per service
 	// - Each region validation involves multiple API calls (KMS DescribeKey, GetKeyPolicy, etc.)
 	// - This balances performance with avoiding throttling across multiple AWS services
-	maxWorkers := getEnvAsIntOrDefault("MAX_REGION_WORKERS", 10)
+	options := RegionValidationOptions{
+		MaxWorkers:         regionWorkersFromEnvironment(),
+		STSThrottleBackoff: time.Second,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	maxWorkers := options.MaxWorkers
+	if maxWorkers < 1 {
+		maxWorkers = 1
+	}
 
 	// Create channels for work distribution
-	jobChan := make(chan regionJob, len(mrs.services))
-	resultChan := make(chan validationResult, len(mrs.services))
+	jobChan := make(chan regionJob, len(jobs))
+	resultChan := make(chan validationResult, len(jobs))
 
 	// Start worker goroutines with controlled concurrency
 	numWorkers := maxWorkers
-	if numWorkers > len(mrs.services) {
-		numWorkers = len(mrs.services)
+	if numWorkers > len(jobs) {
+		numWorkers = len(jobs)
+	}
+
+	// Each region assumes a role when one is configured, so the effective worker count shrinks
+	// whenever STS throttles; without role assumption every worker runs freely as before
+	var limiter *adaptiveWorkerLimit
+	if mrs.assumeRoleARN != "" {
+		limiter = newAdaptiveWorkerLimit(numWorkers)
 	}
 
 	for i := 0; i < numWorkers; i++ {
@@ -228,7 +584,7 @@ func (mrs *MultiRegionComplianceService) ValidateKMSKeysAcrossRegions(ctx contex
 					"region", job.region,
 					"key_alias", job.service.config.DefaultKMSKeyAlias)
 
-				report, err := job.service.ValidateKMSKeyComprehensively(ctx, job.service.config.DefaultKMSKeyAlias)
+				report, err := mrs.validateRegion(ctx, job, limiter, options.STSThrottleBackoff)
 				if err != nil {
 					slog.Error("Failed to validate KMS key in region",
 						"region", job.region,
@@ -265,8 +621,8 @@ func (mrs *MultiRegionComplianceService) ValidateKMSKeysAcrossRegions(ctx contex
 	}
 
 	// Send all jobs to workers
-	for region, service := range mrs.services {
-		jobChan <- regionJob{region, service}
+	for _, job := range jobs {
+		jobChan <- job
 	}
 	close(jobChan)
 
@@ -311,6 +667,123 @@ func (mrs *MultiRegionComplianceService) ValidateKMSKeysAcrossRegions(ctx contex
 	return reports, nil
 }
 
+// validateRegion validates a region's KMS key. With a limiter, a validation that STS throttled is
+// retried with exponential backoff after reducing the number of regions validated concurrently, and
+// each validation STS did not throttle lets the number grow back.
+func (mrs *MultiRegionComplianceService) validateRegion(ctx context.Context, job regionJob, limiter *adaptiveWorkerLimit, backoff time.Duration) (*types.KMSValidationReport, error) {
+	keyAlias := job.service.config.DefaultKMSKeyAlias
+	if limiter == nil {
+		return job.service.ValidateKMSKeyComprehensively(ctx, keyAlias)
+	}
+
+	for attempt := 0; ; attempt++ {
+		limiter.acquire()
+		report, keyErr := job.service.kmsValidationReport(ctx, keyAlias)
+		if !isSTSThrottlingError(keyErr) {
+			limiter.grow()
+			limiter.release()
+			return report, nil
+		}
+		if attempt >= maxSTSThrottleRetries {
+			limiter.release()
+			return report, nil
+		}
+
+		workers := limiter.throttle()
+		limiter.release()
+
+		delay := backoff << attempt
+		slog.Warn("STS throttled role assumption, reducing region workers",
+			"region", job.region,
+			"workers", workers,
+			"attempt", attempt+1,
+			"delay", delay,
+			"audit_action", "region_sts_throttled")
+
+		if !sleepWithContext(ctx, delay) {
+			return report, nil
+		}
+	}
+}
+
+// isSTSThrottlingError reports whether err is an STS operation, such as the AssumeRole behind a
+// client's credentials, that failed with a throttling error code
+func isSTSThrottlingError(err error) bool {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if opErr, ok := err.(*smithy.OperationError); ok && opErr.Service() == sts.ServiceID {
+			return isThrottlingCode(opErr.Err)
+		}
+	}
+	return false
+}
+
+// isThrottlingCode reports whether err carries one of the SDK's throttling error codes or a code
+// listed in ADDITIONAL_RETRYABLE_CODES
+func isThrottlingCode(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	if _, ok := retry.DefaultThrottleErrorCodes[apiErr.ErrorCode()]; ok {
+		return true
+	}
+	return slices.Contains(AdditionalRetryableCodes(), apiErr.ErrorCode())
+}
+
+// adaptiveWorkerLimit bounds how many workers may run at once. Throttling halves the bound, never
+// below one, and each successful call raises it by one, never above the starting bound.
+type adaptiveWorkerLimit struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	limit    int
+	maxLimit int
+	active   int
+}
+
+func newAdaptiveWorkerLimit(limit int) *adaptiveWorkerLimit {
+	l := &adaptiveWorkerLimit{limit: max(limit, 1), maxLimit: max(limit, 1)}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+// acquire blocks until fewer than limit workers are active
+func (l *adaptiveWorkerLimit) acquire() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for l.active >= l.limit {
+		l.cond.Wait()
+	}
+	l.active++
+}
+
+// release frees a worker slot
+func (l *adaptiveWorkerLimit) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.active--
+	l.cond.Broadcast()
+}
+
+// throttle halves the limit and returns the new value
+func (l *adaptiveWorkerLimit) throttle() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limit = max(l.limit/2, 1)
+	return l.limit
+}
+
+// grow raises the limit by one after a successful call, up to the starting limit, and returns the
+// new value
+func (l *adaptiveWorkerLimit) grow() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.limit < l.maxLimit {
+		l.limit++
+		l.cond.Broadcast()
+	}
+	return l.limit
+}
+
 // NewMultiRegionFromEnvironment creates a multi-region service from environment variables
 func NewMultiRegionFromEnvironment(ctx context.Context) (*MultiRegionComplianceService, error) {
 	// Load base AWS configuration
@@ -319,8 +792,14 @@ func NewMultiRegionFromEnvironment(ctx context.Context) (*MultiRegionComplianceS
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
-	// Create multi-region service
-	mrs := NewMultiRegionComplianceService(cfg)
+	// Create multi-region service, assuming a role in each region when configured
+	var opts []MultiRegionOption
+	if roleARN := getEnvOrDefault("REGION_ASSUME_ROLE_ARN", ""); roleARN != "" {
+		opts = append(opts, WithRegionAssumeRole(roleARN))
+	}
+	opts = append(opts, WithAutoAddRegions(getEnvAsBoolOrDefault("AUTO_ADD_REGIONS", false)))
+	opts = append(opts, WithRegionRouting(parseRegionRouting(getEnvOrDefault("REGION_ROUTING", ""))))
+	mrs := NewMultiRegionComplianceService(cfg, opts...)
 
 	// Load regions from environment (comma-separated list)
 	regionsEnv := getEnvOrDefault("SUPPORTED_REGIONS", "ca-central-1,ca-west-1")
@@ -338,6 +817,22 @@ func NewMultiRegionFromEnvironment(ctx context.Context) (*MultiRegionComplianceS
 	return mrs, nil
 }
 
+// parseRegionRouting parses comma-separated source:target region pairs such as
+// "us-east-1:ca-central-1"; malformed pairs are logged and ignored
+func parseRegionRouting(s string) map[string]string {
+	routes := make(map[string]string)
+	for _, pair := range parseCommaDelimitedString(s) {
+		source, target, ok := strings.Cut(pair, ":")
+		source, target = strings.TrimSpace(source), strings.TrimSpace(target)
+		if !ok || source == "" || target == "" {
+			slog.Warn("Ignoring malformed REGION_ROUTING entry, expected source:target", "entry", pair)
+			continue
+		}
+		routes[source] = target
+	}
+	return routes
+}
+
 // parseCommaDelimitedString splits a comma-delimited string into a slice
 func parseCommaDelimitedString(s string) []string {
 	if s == "" {
@@ -0,0 +1,637 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cloudwatchlogstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zsoftly/logguardian/internal/testutil"
+	"github.com/zsoftly/logguardian/internal/types"
+)
+
+// concurrencyTrackingKMSClient records the peak number of simultaneous DescribeKey calls
+type concurrencyTrackingKMSClient struct {
+	MockKMSClient
+	inFlight atomic.Int32
+	peak     atomic.Int32
+	delay    time.Duration
+}
+
+func (m *concurrencyTrackingKMSClient) DescribeKey(ctx context.Context, params *kms.DescribeKeyInput, optFns ...func(*kms.Options)) (*kms.DescribeKeyOutput, error) {
+	current := m.inFlight.Add(1)
+	defer m.inFlight.Add(-1)
+
+	for {
+		peak := m.peak.Load()
+		if current <= peak || m.peak.CompareAndSwap(peak, current) {
+			break
+		}
+	}
+	time.Sleep(m.delay)
+
+	return &kms.DescribeKeyOutput{
+		KeyMetadata: &kmstypes.KeyMetadata{
+			KeyId:    aws.String("key-12345"),
+			Arn:      aws.String("arn:aws:kms:ca-central-1:123456789012:key/key-12345"),
+			KeyState: kmstypes.KeyStateEnabled,
+		},
+	}, nil
+}
+
+func TestValidateKMSKeysAcrossRegions_MaxWorkersOption(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxWorkers   int
+		expectedPeak int32
+	}{
+		{name: "single worker serializes regions", maxWorkers: 1, expectedPeak: 1},
+		{name: "two workers", maxWorkers: 2, expectedPeak: 2},
+		{name: "non-positive value falls back to one worker", maxWorkers: 0, expectedPeak: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Environment setting must be overridden by the option
+			t.Setenv("MAX_REGION_WORKERS", "10")
+
+			kmsClient := &concurrencyTrackingKMSClient{delay: 20 * time.Millisecond}
+			mrs := NewMultiRegionComplianceService(aws.Config{})
+			for i := 0; i < 6; i++ {
+				region := fmt.Sprintf("region-%d", i)
+				mrs.services[region] = &ComplianceService{
+					kmsClient: kmsClient,
+					config: ServiceConfig{
+						DefaultKMSKeyAlias: "alias/test-key",
+						Region:             region,
+					},
+				}
+			}
+
+			reports, err := mrs.ValidateKMSKeysAcrossRegions(context.Background(), WithMaxRegionWorkers(tt.maxWorkers))
+			require.NoError(t, err)
+
+			assert.Len(t, reports, 6)
+			assert.Equal(t, tt.expectedPeak, kmsClient.peak.Load())
+		})
+	}
+}
+
+// stsThrottleError is how the SDK reports a throttled AssumeRole behind another service's call
+func stsThrottleError() error {
+	return &smithy.OperationError{
+		ServiceID:     "KMS",
+		OperationName: "DescribeKey",
+		Err: fmt.Errorf("failed to refresh cached credentials, %w", &smithy.OperationError{
+			ServiceID:     "STS",
+			OperationName: "AssumeRole",
+			Err:           &smithy.GenericAPIError{Code: "Throttling", Message: "Rate exceeded"},
+		}),
+	}
+}
+
+// stsThrottlingKMSClient fails the first calls as if STS throttled role assumption, then records the
+// peak number of simultaneous successful DescribeKey calls
+type stsThrottlingKMSClient struct {
+	MockKMSClient
+	throttles atomic.Int32
+	inFlight  atomic.Int32
+	peak      atomic.Int32
+	delay     time.Duration
+}
+
+func (m *stsThrottlingKMSClient) DescribeKey(ctx context.Context, params *kms.DescribeKeyInput, optFns ...func(*kms.Options)) (*kms.DescribeKeyOutput, error) {
+	if m.throttles.Add(-1) >= 0 {
+		time.Sleep(m.delay)
+		return nil, stsThrottleError()
+	}
+
+	current := m.inFlight.Add(1)
+	defer m.inFlight.Add(-1)
+	for {
+		peak := m.peak.Load()
+		if current <= peak || m.peak.CompareAndSwap(peak, current) {
+			break
+		}
+	}
+	time.Sleep(m.delay)
+
+	return &kms.DescribeKeyOutput{
+		KeyMetadata: &kmstypes.KeyMetadata{
+			KeyId:    aws.String("key-12345"),
+			Arn:      aws.String("arn:aws:kms:ca-central-1:123456789012:key/key-12345"),
+			KeyState: kmstypes.KeyStateEnabled,
+		},
+	}, nil
+}
+
+// blockingKMSClient signals each DescribeKey call on started and holds it until release is closed
+type blockingKMSClient struct {
+	MockKMSClient
+	started chan struct{}
+	release chan struct{}
+}
+
+func (m *blockingKMSClient) DescribeKey(ctx context.Context, params *kms.DescribeKeyInput, optFns ...func(*kms.Options)) (*kms.DescribeKeyOutput, error) {
+	m.started <- struct{}{}
+	<-m.release
+	return m.MockKMSClient.DescribeKey(ctx, params, optFns...)
+}
+
+func TestAddRegion_NormalizesConfig(t *testing.T) {
+	logger, logs := testutil.CaptureLogs(t)
+	mrs := NewMultiRegionComplianceService(aws.Config{}, WithRegionServiceOptions(WithLogger(logger)))
+
+	require.NoError(t, mrs.AddRegion("ca-west-1", ServiceConfig{
+		DefaultKMSKeyAlias: "alias/test-key",
+		Region:             "ca-west-1",
+		BatchLimit:         500,
+	}))
+
+	service := mrs.services["ca-west-1"]
+	assert.Equal(t, MaxConfigBatchLimit, service.config.BatchLimit)
+	assert.Equal(t, MaxConfigBatchLimit, service.configEvalService.config.BatchLimit)
+	assert.Equal(t, MinRetryBaseDelay, service.config.RetryBaseDelay)
+	assert.Equal(t, service.config, mrs.serviceConfigs["ca-west-1"])
+	require.Len(t, logs.WithAttr("clamped", float64(MaxConfigBatchLimit)), 1)
+}
+
+func TestValidateKMSKeysAcrossRegions_AddRegionDuringValidation(t *testing.T) {
+	kmsClient := &blockingKMSClient{started: make(chan struct{}, 1), release: make(chan struct{})}
+	mrs := NewMultiRegionComplianceService(aws.Config{})
+	mrs.services["ca-central-1"] = &ComplianceService{
+		kmsClient: kmsClient,
+		config:    ServiceConfig{DefaultKMSKeyAlias: "alias/test-key", Region: "ca-central-1"},
+	}
+
+	done := make(chan map[string]*types.KMSValidationReport)
+	go func() {
+		reports, err := mrs.ValidateKMSKeysAcrossRegions(context.Background())
+		assert.NoError(t, err)
+		done <- reports
+	}()
+	<-kmsClient.started
+
+	added := make(chan error)
+	go func() {
+		added <- mrs.AddRegion("ca-west-1", ServiceConfig{DefaultKMSKeyAlias: "alias/test-key", Region: "ca-west-1"})
+	}()
+	select {
+	case err := <-added:
+		require.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		close(kmsClient.release)
+		t.Fatal("AddRegion blocked while validation was in progress")
+	}
+	close(kmsClient.release)
+
+	// The validation covers the regions configured when it started
+	reports := <-done
+	require.Len(t, reports, 1)
+	assert.Contains(t, reports, "ca-central-1")
+	assert.ElementsMatch(t, []string{"ca-central-1", "ca-west-1"}, mrs.GetSupportedRegions())
+}
+
+func TestValidateKMSKeysAcrossRegions_STSThrottlingReducesWorkers(t *testing.T) {
+	tests := []struct {
+		name             string
+		assumeRoleARN    string
+		expectRetried    bool
+		expectedFailures int
+	}{
+		{
+			name:          "assume role backs off and reduces region workers",
+			assumeRoleARN: "arn:aws:iam::123456789012:role/LogGuardianRegion",
+			expectRetried: true,
+		},
+		{
+			name:             "without assume role throttling is reported as before",
+			expectRetried:    false,
+			expectedFailures: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kmsClient := &stsThrottlingKMSClient{delay: 20 * time.Millisecond}
+			kmsClient.throttles.Store(4)
+
+			var opts []MultiRegionOption
+			if tt.assumeRoleARN != "" {
+				opts = append(opts, WithRegionAssumeRole(tt.assumeRoleARN))
+			}
+			mrs := NewMultiRegionComplianceService(aws.Config{}, opts...)
+			for i := 0; i < 6; i++ {
+				region := fmt.Sprintf("region-%d", i)
+				mrs.services[region] = &ComplianceService{
+					kmsClient: kmsClient,
+					config: ServiceConfig{
+						DefaultKMSKeyAlias: "alias/test-key",
+						Region:             region,
+					},
+				}
+			}
+
+			reports, err := mrs.ValidateKMSKeysAcrossRegions(context.Background(),
+				WithMaxRegionWorkers(4), WithSTSThrottleBackoff(20*time.Millisecond))
+			require.NoError(t, err)
+			require.Len(t, reports, 6)
+
+			failures := 0
+			for _, report := range reports {
+				if !report.KeyAccessible {
+					failures++
+				}
+			}
+			assert.Equal(t, tt.expectedFailures, failures)
+
+			if tt.expectRetried {
+				// Throttling halved the limit before any region was retried, and successes grow it
+				// back one worker at a time
+				assert.Less(t, kmsClient.peak.Load(), int32(4))
+			}
+		})
+	}
+}
+
+func TestAdaptiveWorkerLimit_ThrottleHalvesDownToOne(t *testing.T) {
+	limit := newAdaptiveWorkerLimit(10)
+
+	assert.Equal(t, 5, limit.throttle())
+	assert.Equal(t, 2, limit.throttle())
+	assert.Equal(t, 1, limit.throttle())
+	assert.Equal(t, 1, limit.throttle())
+}
+
+func TestAdaptiveWorkerLimit_GrowsBackToStartingLimit(t *testing.T) {
+	limit := newAdaptiveWorkerLimit(4)
+
+	assert.Equal(t, 4, limit.grow(), "Expected the limit never to exceed its starting value")
+	assert.Equal(t, 2, limit.throttle())
+	assert.Equal(t, 3, limit.grow())
+	assert.Equal(t, 4, limit.grow())
+	assert.Equal(t, 4, limit.grow())
+}
+
+func TestIsSTSThrottlingError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "nil", err: nil, expected: false},
+		{name: "throttled AssumeRole behind a KMS call", err: stsThrottleError(), expected: true},
+		{
+			name: "throttled KMS call",
+			err: &smithy.OperationError{ServiceID: "KMS", OperationName: "DescribeKey",
+				Err: &smithy.GenericAPIError{Code: "ThrottlingException"}},
+			expected: false,
+		},
+		{
+			name: "STS access denied",
+			err: &smithy.OperationError{ServiceID: "STS", OperationName: "AssumeRole",
+				Err: &smithy.GenericAPIError{Code: "AccessDenied"}},
+			expected: false,
+		},
+		{name: "message mentioning STS throttling", err: errors.New("STS Throttling: Rate exceeded"), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isSTSThrottlingError(tt.err))
+		})
+	}
+}
+
+func TestMultiRegionComplianceService_RemediateLogGroup_UnknownRegion(t *testing.T) {
+	t.Setenv("DRY_RUN", "true")
+
+	compliance := types.ComplianceResult{
+		LogGroupName: "/aws/lambda/other-region",
+		Region:       "us-east-1",
+	}
+
+	t.Run("fails by default", func(t *testing.T) {
+		mrs := NewMultiRegionComplianceService(aws.Config{})
+
+		result, err := mrs.RemediateLogGroup(context.Background(), compliance)
+
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), "no service configured for region us-east-1")
+		assert.Empty(t, mrs.GetSupportedRegions())
+	})
+
+	t.Run("auto-adds region when enabled", func(t *testing.T) {
+		t.Setenv("KMS_KEY_ALIAS_us-east-1", "alias/us-east-key")
+		mrs := NewMultiRegionComplianceService(aws.Config{}, WithAutoAddRegions(true))
+
+		result, err := mrs.RemediateLogGroup(context.Background(), compliance)
+
+		require.NoError(t, err)
+		assert.True(t, result.Success)
+		assert.Equal(t, []string{"us-east-1"}, mrs.GetSupportedRegions())
+
+		config := mrs.serviceConfigs["us-east-1"]
+		assert.Equal(t, "alias/us-east-key", config.DefaultKMSKeyAlias)
+		assert.True(t, config.DryRun)
+	})
+}
+
+func TestMultiRegionComplianceService_RemediateLogGroup_RegionRouting(t *testing.T) {
+	newRoutingService := func(routes map[string]string) (*MultiRegionComplianceService, map[string]*MockCloudWatchLogsClient) {
+		mrs := NewMultiRegionComplianceService(aws.Config{}, WithRegionRouting(routes))
+		logsClients := map[string]*MockCloudWatchLogsClient{}
+		for _, region := range []string{"ca-central-1", "ca-west-1"} {
+			logsClients[region] = &MockCloudWatchLogsClient{LogGroups: []cloudwatchlogstypes.LogGroup{
+				{LogGroupName: aws.String("/aws/lambda/replica")},
+				{LogGroupName: aws.String("/aws/lambda/west")},
+			}}
+			mrs.services[region] = &ComplianceService{
+				logsClient: logsClients[region],
+				config:     ServiceConfig{Region: region, DefaultRetentionDays: 365},
+			}
+		}
+		return mrs, logsClients
+	}
+
+	t.Run("mapped source region routes to the target service", func(t *testing.T) {
+		mrs, logsClients := newRoutingService(map[string]string{"us-east-1": "ca-central-1"})
+
+		result, err := mrs.RemediateLogGroup(context.Background(), types.ComplianceResult{
+			LogGroupName:     "/aws/lambda/replica",
+			Region:           "us-east-1",
+			MissingRetention: true,
+		})
+
+		require.NoError(t, err)
+		assert.True(t, result.RetentionApplied)
+		assert.Equal(t, "ca-central-1", result.Region)
+		assert.True(t, logsClients["ca-central-1"].PutRetentionPolicyCalled)
+		assert.False(t, logsClients["ca-west-1"].PutRetentionPolicyCalled)
+	})
+
+	t.Run("unmapped region uses its own service", func(t *testing.T) {
+		mrs, logsClients := newRoutingService(map[string]string{"us-east-1": "ca-central-1"})
+
+		result, err := mrs.RemediateLogGroup(context.Background(), types.ComplianceResult{
+			LogGroupName:     "/aws/lambda/west",
+			Region:           "ca-west-1",
+			MissingRetention: true,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "ca-west-1", result.Region)
+		assert.True(t, logsClients["ca-west-1"].PutRetentionPolicyCalled)
+		assert.False(t, logsClients["ca-central-1"].PutRetentionPolicyCalled)
+	})
+}
+
+func TestParseRegionRouting(t *testing.T) {
+	assert.Empty(t, parseRegionRouting(""))
+	assert.Equal(t, map[string]string{"us-east-1": "ca-central-1", "us-west-2": "ca-west-1"},
+		parseRegionRouting(" us-east-1:ca-central-1 , us-west-2 : ca-west-1 "))
+	assert.Equal(t, map[string]string{"us-east-1": "ca-central-1"},
+		parseRegionRouting("us-east-1:ca-central-1,malformed,:ca-west-1,eu-west-1:"))
+}
+
+func TestValidateRegionAccess_RegionKeySummary(t *testing.T) {
+	newRegionService := func(region string, kmsClient *MockKMSClient) *ComplianceService {
+		return &ComplianceService{
+			logsClient: &MockCloudWatchLogsClient{},
+			kmsClient:  kmsClient,
+			config: ServiceConfig{
+				DefaultKMSKeyAlias: "alias/test-key",
+				Region:             region,
+			},
+		}
+	}
+
+	mrs := NewMultiRegionComplianceService(aws.Config{})
+	mrs.services["ca-central-1"] = newRegionService("ca-central-1", &MockKMSClient{})
+	mrs.services["ca-west-1"] = newRegionService("ca-west-1", &MockKMSClient{KeyState: kmstypes.KeyStateDisabled})
+	mrs.services["us-east-1"] = newRegionService("us-east-1", &MockKMSClient{
+		DescribeKeyError: &kmstypes.NotFoundException{Message: aws.String("Alias alias/test-key is not found")},
+	})
+	mrs.services["us-west-2"] = newRegionService("us-west-2", &MockKMSClient{KeyState: kmstypes.KeyStatePendingDeletion})
+
+	summaries, err := mrs.ValidateRegionAccess(context.Background())
+	require.NoError(t, err)
+	require.Len(t, summaries, 4)
+
+	expected := []struct {
+		region   string
+		status   types.RegionKeyStatus
+		keyState string
+	}{
+		{region: "ca-central-1", status: types.RegionKeyStatusUsable, keyState: "Enabled"},
+		{region: "ca-west-1", status: types.RegionKeyStatusUnusable, keyState: "Disabled"},
+		{region: "us-east-1", status: types.RegionKeyStatusMissing},
+		{region: "us-west-2", status: types.RegionKeyStatusUnusable, keyState: "PendingDeletion"},
+	}
+	for i, want := range expected {
+		assert.Equal(t, want.region, summaries[i].Region)
+		assert.Equal(t, "alias/test-key", summaries[i].KeyAlias)
+		assert.Equal(t, want.status, summaries[i].Status, want.region)
+		assert.Equal(t, want.keyState, summaries[i].KeyState, want.region)
+		assert.Equal(t, want.status == types.RegionKeyStatusUsable, summaries[i].Error == "", want.region)
+	}
+}
+
+func TestValidateRegionAccess_LogsAccessFailure(t *testing.T) {
+	mrs := NewMultiRegionComplianceService(aws.Config{})
+	mrs.services["ca-central-1"] = &ComplianceService{
+		logsClient: &MockCloudWatchLogsClient{DescribeLogGroupsError: errors.New("AccessDeniedException")},
+		kmsClient:  &MockKMSClient{},
+		config:     ServiceConfig{DefaultKMSKeyAlias: "alias/test-key", Region: "ca-central-1"},
+	}
+
+	summaries, err := mrs.ValidateRegionAccess(context.Background())
+	require.Error(t, err)
+	assert.Nil(t, summaries)
+	assert.Contains(t, err.Error(), "failed to access CloudWatch Logs in region ca-central-1")
+}
+
+func TestPreflightRegions_FailingRegion(t *testing.T) {
+	mrs := NewMultiRegionComplianceService(aws.Config{})
+	mrs.services["ca-central-1"] = &ComplianceService{
+		logsClient: &MockCloudWatchLogsClient{},
+		kmsClient:  &MockKMSClient{},
+		config:     ServiceConfig{DefaultKMSKeyAlias: "alias/test-key", Region: "ca-central-1"},
+	}
+	mrs.services["ca-west-1"] = &ComplianceService{
+		logsClient: &MockCloudWatchLogsClient{DescribeLogGroupsError: errors.New("AccessDeniedException")},
+		kmsClient:  &MockKMSClient{},
+		config:     ServiceConfig{DefaultKMSKeyAlias: "alias/test-key", Region: "ca-west-1"},
+	}
+	mrs.services["us-east-1"] = &ComplianceService{
+		logsClient: &MockCloudWatchLogsClient{},
+		kmsClient: &MockKMSClient{
+			DescribeKeyError: &kmstypes.NotFoundException{Message: aws.String("Alias alias/test-key is not found")},
+		},
+		config: ServiceConfig{DefaultKMSKeyAlias: "alias/test-key", Region: "us-east-1"},
+	}
+
+	results := mrs.PreflightRegions(context.Background())
+	require.Len(t, results, 3)
+
+	assert.Equal(t, "ca-central-1", results[0].Region)
+	assert.True(t, results[0].Passed)
+	require.NotNil(t, results[0].Key)
+	assert.Equal(t, types.RegionKeyStatusUsable, results[0].Key.Status)
+
+	assert.Equal(t, "ca-west-1", results[1].Region)
+	assert.False(t, results[1].Passed)
+	assert.Nil(t, results[1].Key)
+	assert.Contains(t, results[1].Error, "failed to access CloudWatch Logs in region ca-west-1")
+
+	// A missing key is reported but is not a critical failure
+	assert.Equal(t, "us-east-1", results[2].Region)
+	assert.True(t, results[2].Passed)
+	require.NotNil(t, results[2].Key)
+	assert.Equal(t, types.RegionKeyStatusMissing, results[2].Key.Status)
+}
+
+func TestPreflightAllRegions_FailingRegion(t *testing.T) {
+	mrs := NewMultiRegionComplianceService(aws.Config{})
+	for _, region := range []string{"ca-central-1", "ca-west-1"} {
+		mrs.services[region] = &ComplianceService{
+			logsClient: &MockCloudWatchLogsClient{},
+			kmsClient:  &MockKMSClient{},
+			config:     ServiceConfig{DefaultKMSKeyAlias: "alias/test-key", Region: region},
+		}
+	}
+	require.NoError(t, mrs.PreflightAllRegions(context.Background()))
+
+	mrs.services["us-east-1"] = &ComplianceService{
+		logsClient: &MockCloudWatchLogsClient{},
+		kmsClient: &MockKMSClient{
+			DescribeKeyError: &kmstypes.NotFoundException{Message: aws.String("Alias alias/test-key is not found")},
+		},
+		config: ServiceConfig{DefaultKMSKeyAlias: "alias/test-key", Region: "us-east-1"},
+	}
+
+	err := mrs.PreflightAllRegions(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 of 3 regions failed KMS preflight")
+	assert.Contains(t, err.Error(), "us-east-1: ")
+	assert.NotContains(t, err.Error(), "ca-central-1")
+	assert.NotContains(t, err.Error(), "ca-west-1")
+
+	var preflightErr *KMSPreflightError
+	require.ErrorAs(t, err, &preflightErr)
+	assert.Len(t, preflightErr.Failures, 1)
+	assert.NotEmpty(t, preflightErr.Failures["us-east-1"])
+}
+
+func TestDefaultRegionServiceConfig_NormalizesKMSKeyAlias(t *testing.T) {
+	t.Setenv("KMS_KEY_ALIAS", "global-key")
+	t.Setenv("KMS_KEY_ALIAS_ca-west-1", "west-key")
+
+	assert.Equal(t, "alias/global-key", defaultRegionServiceConfig("ca-central-1").DefaultKMSKeyAlias)
+	assert.Equal(t, "alias/west-key", defaultRegionServiceConfig("ca-west-1").DefaultKMSKeyAlias)
+}
+
+func TestMultiRegionComplianceService_RemediateAcrossRegions(t *testing.T) {
+	t.Setenv("KMS_KEY_ALIAS", "alias/global-key")
+	t.Setenv("KMS_KEY_ALIAS_ca-west-1", "alias/west-key")
+	t.Setenv("MAX_KMS_RETRIES", "5")
+
+	mrs := NewMultiRegionComplianceService(aws.Config{})
+	regions := []string{"ca-central-1", "ca-west-1", "us-east-1", "us-east-2", "us-west-2"}
+	require.NoError(t, mrs.LoadRegionsFromConfig(context.Background(), regions))
+
+	var inFlight, peak atomic.Int32
+	var mu sync.Mutex
+	aliases := map[string]string{}
+
+	results := mrs.RemediateAcrossRegions(context.Background(), func(ctx context.Context, region string, svc ComplianceServiceInterface) error {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if current <= p || peak.CompareAndSwap(p, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		regionService := svc.(*ComplianceService)
+		assert.Equal(t, int32(5), regionService.config.MaxKMSRetries, "region services load the full environment configuration")
+		mu.Lock()
+		aliases[region] = regionService.config.DefaultKMSKeyAlias
+		mu.Unlock()
+
+		if region == "us-east-2" {
+			return errors.New("config rule not found")
+		}
+		return nil
+	}, WithMaxRegionWorkers(2))
+
+	assert.Len(t, results, len(regions))
+	for _, region := range regions {
+		if region == "us-east-2" {
+			assert.Error(t, results[region])
+		} else {
+			assert.NoError(t, results[region], region)
+		}
+	}
+	assert.LessOrEqual(t, peak.Load(), int32(2))
+	assert.Equal(t, "alias/west-key", aliases["ca-west-1"])
+	assert.Equal(t, "alias/global-key", aliases["ca-central-1"])
+}
+
+func TestMultiRegionComplianceService_RegionConcurrency(t *testing.T) {
+	t.Setenv("MAX_REGION_WORKERS", "10")
+	t.Setenv("REGION_CONCURRENCY", "1")
+
+	kmsClient := &concurrencyTrackingKMSClient{delay: 10 * time.Millisecond}
+	mrs := NewMultiRegionComplianceService(aws.Config{})
+	for i := 0; i < 4; i++ {
+		region := fmt.Sprintf("region-%d", i)
+		mrs.services[region] = &ComplianceService{
+			kmsClient: kmsClient,
+			config: ServiceConfig{
+				DefaultKMSKeyAlias: "alias/test-key",
+				Region:             region,
+			},
+		}
+	}
+
+	t.Run("remediation runs one region at a time", func(t *testing.T) {
+		var inFlight, peak atomic.Int32
+		results := mrs.RemediateAcrossRegions(context.Background(), func(ctx context.Context, region string, svc ComplianceServiceInterface) error {
+			current := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				p := peak.Load()
+				if current <= p || peak.CompareAndSwap(p, current) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		})
+
+		assert.Len(t, results, 4)
+		assert.Equal(t, int32(1), peak.Load())
+	})
+
+	t.Run("validation runs one region at a time", func(t *testing.T) {
+		reports, err := mrs.ValidateKMSKeysAcrossRegions(context.Background())
+		require.NoError(t, err)
+
+		assert.Len(t, reports, 4)
+		assert.Equal(t, int32(1), kmsClient.peak.Load())
+	})
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/zsoftly/logguardian/internal/types"
+)
+
+// RuleResources holds the non-compliant resources one Config rule reported
+type RuleResources struct {
+	ConfigRuleName string
+	Resources      []types.NonCompliantResource
+}
+
+// MergeRuleResources merges the resources reported by several Config rules into one list in the
+// order they were first reported, listing a log group flagged by more than one rule once. Each
+// merged resource's Requirements holds the requirement of every rule that flagged it, and its
+// annotation joins theirs. A rule that checks neither encryption nor retention is an error, since
+// its resources would have no requirement to remediate.
+func MergeRuleResources(classifier *types.RuleClassifier, rules []RuleResources) ([]types.NonCompliantResource, error) {
+	merged := []types.NonCompliantResource{}
+	index := make(map[string]int)
+
+	for _, rule := range rules {
+		ruleType := classifier.ClassifyRule(rule.ConfigRuleName)
+		if ruleType == types.RuleTypeUnknown {
+			return nil, fmt.Errorf("config rule %s checks neither encryption nor retention", rule.ConfigRuleName)
+		}
+
+		for _, resource := range rule.Resources {
+			i, seen := index[resource.ResourceName]
+			if !seen {
+				resource.Requirements = []types.RuleType{ruleType}
+				index[resource.ResourceName] = len(merged)
+				merged = append(merged, resource)
+				continue
+			}
+
+			existing := &merged[i]
+			if !slices.Contains(existing.Requirements, ruleType) {
+				existing.Requirements = append(existing.Requirements, ruleType)
+			}
+			if resource.Annotation != "" && !strings.Contains(existing.Annotation, resource.Annotation) {
+				existing.Annotation = strings.TrimPrefix(existing.Annotation+"; "+resource.Annotation, "; ")
+			}
+		}
+	}
+
+	return merged, nil
+}
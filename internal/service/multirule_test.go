@@ -0,0 +1,113 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zsoftly/logguardian/internal/testutil"
+	"github.com/zsoftly/logguardian/internal/types"
+)
+
+func TestMergeRuleResources(t *testing.T) {
+	encryptionOnly := testutil.NewNonCompliantResourceBuilder("/aws/lambda/unencrypted").WithAnnotation("Not encrypted").Build()
+	both := testutil.NewNonCompliantResourceBuilder("/aws/lambda/both").WithAnnotation("Not encrypted").Build()
+	retentionOnly := testutil.NewNonCompliantResourceBuilder("/aws/lambda/no-retention").WithAnnotation("Retention not set").Build()
+	bothRetention := testutil.NewNonCompliantResourceBuilder("/aws/lambda/both").WithAnnotation("Retention not set").Build()
+
+	tests := []struct {
+		name     string
+		rules    []RuleResources
+		expected map[string][]types.RuleType
+		order    []string
+	}{
+		{
+			name: "overlapping resources are merged with both requirements",
+			rules: []RuleResources{
+				{ConfigRuleName: "cw-lg-kms-encryption", Resources: []types.NonCompliantResource{encryptionOnly, both}},
+				{ConfigRuleName: "cw-lg-retention-min", Resources: []types.NonCompliantResource{bothRetention, retentionOnly}},
+			},
+			expected: map[string][]types.RuleType{
+				"/aws/lambda/unencrypted":  {types.RuleTypeEncryption},
+				"/aws/lambda/both":         {types.RuleTypeEncryption, types.RuleTypeRetention},
+				"/aws/lambda/no-retention": {types.RuleTypeRetention},
+			},
+			order: []string{"/aws/lambda/unencrypted", "/aws/lambda/both", "/aws/lambda/no-retention"},
+		},
+		{
+			name: "disjoint resources keep their own requirement",
+			rules: []RuleResources{
+				{ConfigRuleName: "cw-lg-kms-encryption", Resources: []types.NonCompliantResource{encryptionOnly}},
+				{ConfigRuleName: "cw-lg-retention-min", Resources: []types.NonCompliantResource{retentionOnly}},
+			},
+			expected: map[string][]types.RuleType{
+				"/aws/lambda/unencrypted":  {types.RuleTypeEncryption},
+				"/aws/lambda/no-retention": {types.RuleTypeRetention},
+			},
+			order: []string{"/aws/lambda/unencrypted", "/aws/lambda/no-retention"},
+		},
+		{
+			name: "rule reporting a resource twice lists its requirement once",
+			rules: []RuleResources{
+				{ConfigRuleName: "cw-lg-kms-encryption", Resources: []types.NonCompliantResource{encryptionOnly, encryptionOnly}},
+			},
+			expected: map[string][]types.RuleType{"/aws/lambda/unencrypted": {types.RuleTypeEncryption}},
+			order:    []string{"/aws/lambda/unencrypted"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, err := MergeRuleResources(types.NewRuleClassifier(), tt.rules)
+			require.NoError(t, err)
+			require.Len(t, merged, len(tt.order))
+			for i, resource := range merged {
+				assert.Equal(t, tt.order[i], resource.ResourceName)
+				assert.Equal(t, tt.expected[resource.ResourceName], resource.Requirements, resource.ResourceName)
+			}
+		})
+	}
+
+	// The caller's resources are not modified
+	assert.Empty(t, both.Requirements)
+}
+
+func TestMergeRuleResources_JoinsAnnotations(t *testing.T) {
+	merged, err := MergeRuleResources(types.NewRuleClassifier(), []RuleResources{
+		{ConfigRuleName: "cw-lg-kms-encryption", Resources: []types.NonCompliantResource{
+			testutil.NewNonCompliantResourceBuilder("/aws/lambda/both").WithAnnotation("Not encrypted").Build(),
+		}},
+		{ConfigRuleName: "cw-lg-retention-min", Resources: []types.NonCompliantResource{
+			testutil.NewNonCompliantResourceBuilder("/aws/lambda/both").WithAnnotation("Retention not set").Build(),
+		}},
+	})
+	require.NoError(t, err)
+	require.Len(t, merged, 1)
+	assert.Equal(t, "Not encrypted; Retention not set", merged[0].Annotation)
+}
+
+func TestMergeRuleResources_UnknownRule(t *testing.T) {
+	_, err := MergeRuleResources(types.NewRuleClassifier(), []RuleResources{
+		{ConfigRuleName: "cw-lg-kms-encryption"},
+		{ConfigRuleName: "some-unsupported-rule"},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "some-unsupported-rule")
+}
+
+func TestConvertToComplianceResultForRule_MergedRequirements(t *testing.T) {
+	service := &ComplianceService{ruleClassifier: types.NewRuleClassifier()}
+
+	resource := testutil.NewTestNonCompliantResource("/aws/lambda/both")
+	resource.Requirements = []types.RuleType{types.RuleTypeEncryption, types.RuleTypeRetention}
+	compliance := service.convertToComplianceResultForRule("cw-lg-kms-encryption,cw-lg-retention-min", resource)
+	assert.True(t, compliance.MissingEncryption)
+	assert.True(t, compliance.MissingRetention)
+	assert.False(t, compliance.NotApplicable)
+
+	// Merged requirements override the classification of the request's rule name
+	resource.Requirements = []types.RuleType{types.RuleTypeRetention}
+	compliance = service.convertToComplianceResultForRule("cw-lg-kms-encryption,cw-lg-retention-min", resource)
+	assert.False(t, compliance.MissingEncryption)
+	assert.True(t, compliance.MissingRetention)
+}
@@ -0,0 +1,28 @@
+package service
+
+import "strings"
+
+// regionPartitions maps region name prefixes to the ARN partition of regions outside the
+// standard aws partition
+var regionPartitions = []struct {
+	prefix    string
+	partition string
+}{
+	{prefix: "us-gov-", partition: "aws-us-gov"},
+	{prefix: "cn-", partition: "aws-cn"},
+	{prefix: "us-iso-", partition: "aws-iso"},
+	{prefix: "us-isob-", partition: "aws-iso-b"},
+	{prefix: "eu-isoe-", partition: "aws-iso-e"},
+	{prefix: "us-isof-", partition: "aws-iso-f"},
+}
+
+// regionPartition returns the ARN partition of region, such as aws-us-gov for GovCloud and aws-cn
+// for China; every other region is in the standard aws partition
+func regionPartition(region string) string {
+	for _, p := range regionPartitions {
+		if strings.HasPrefix(region, p.prefix) {
+			return p.partition
+		}
+	}
+	return "aws"
+}
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegionPartition(t *testing.T) {
+	tests := map[string]string{
+		"ca-central-1":   "aws",
+		"us-east-1":      "aws",
+		"us-gov-west-1":  "aws-us-gov",
+		"cn-north-1":     "aws-cn",
+		"us-iso-east-1":  "aws-iso",
+		"us-isob-east-1": "aws-iso-b",
+		"":               "aws",
+	}
+
+	for region, expected := range tests {
+		assert.Equal(t, expected, regionPartition(region), region)
+	}
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// accountRateLimiter spaces out remediations per account, so each account's KMS and CloudWatch
+// Logs quotas are throttled independently. An account's limiter is created on first use.
+type accountRateLimiter struct {
+	interval time.Duration
+
+	mu       sync.Mutex
+	accounts map[string]*rateLimiter
+}
+
+// rateLimiter admits one call per interval, handing out slots in the order they are reserved
+type rateLimiter struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// newAccountRateLimiter creates a limiter admitting perSecond remediations per account each
+// second; zero or less disables limiting and returns nil
+func newAccountRateLimiter(perSecond int32) *accountRateLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &accountRateLimiter{
+		interval: time.Second / time.Duration(perSecond),
+		accounts: make(map[string]*rateLimiter),
+	}
+}
+
+// limiter returns the account's limiter, creating it on first use
+func (l *accountRateLimiter) limiter(accountId string) *rateLimiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limiter, ok := l.accounts[accountId]
+	if !ok {
+		limiter = &rateLimiter{interval: l.interval}
+		l.accounts[accountId] = limiter
+	}
+	return limiter
+}
+
+// reserve claims the next free slot at or after now and returns how long to wait for it
+func (r *rateLimiter) reserve(now time.Time) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	slot := r.next
+	if slot.Before(now) {
+		slot = now
+	}
+	r.next = slot.Add(r.interval)
+	return slot.Sub(now)
+}
+
+// waitForAccount blocks until the account may make its next remediation, returning false if ctx
+// is cancelled first. Without ACCOUNT_RATE_LIMIT it returns immediately; resources without an
+// account share the remediation account's limiter.
+func (s *ComplianceService) waitForAccount(ctx context.Context, accountId string) bool {
+	if s.accountLimiter == nil {
+		return ctx.Err() == nil
+	}
+	if accountId == "" {
+		accountId = s.config.AccountId
+	}
+	return sleepWithContext(ctx, s.accountLimiter.limiter(accountId).reserve(s.now()))
+}
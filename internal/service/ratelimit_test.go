@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zsoftly/logguardian/internal/testutil"
+)
+
+func TestAccountRateLimiter_ThrottlesAccountsIndependently(t *testing.T) {
+	limiter := newAccountRateLimiter(10)
+	require.NotNil(t, limiter)
+	now := time.Unix(0, 0)
+
+	// Concurrent first use creates exactly one limiter per account
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(accountId string) {
+			defer wg.Done()
+			limiter.limiter(accountId)
+		}([]string{"111111111111", "222222222222"}[i%2])
+	}
+	wg.Wait()
+	assert.Len(t, limiter.accounts, 2)
+
+	first := limiter.limiter("111111111111")
+	second := limiter.limiter("222222222222")
+	assert.Same(t, first, limiter.limiter("111111111111"))
+
+	assert.Equal(t, time.Duration(0), first.reserve(now))
+	assert.Equal(t, 100*time.Millisecond, first.reserve(now))
+	assert.Equal(t, 200*time.Millisecond, first.reserve(now))
+
+	// The second account's slots are unaffected by the first account's reservations
+	assert.Equal(t, time.Duration(0), second.reserve(now))
+	assert.Equal(t, 100*time.Millisecond, second.reserve(now))
+
+	// Once the interval passes the account is admitted immediately again
+	assert.Equal(t, time.Duration(0), second.reserve(now.Add(time.Second)))
+}
+
+func TestNewAccountRateLimiter_Disabled(t *testing.T) {
+	assert.Nil(t, newAccountRateLimiter(0))
+	assert.Nil(t, newAccountRateLimiter(-1))
+
+	service := &ComplianceService{}
+	assert.True(t, service.waitForAccount(context.Background(), "111111111111"))
+}
+
+func TestProcessNonCompliantResourcesOptimized_AccountRateLimit(t *testing.T) {
+	service := newTimedEncryptionService()
+	service.config.AccountId = "111111111111"
+	service.accountLimiter = newAccountRateLimiter(10)
+
+	// Three resources in each of two accounts, one per batch so every resource runs concurrently;
+	// the fourth omits its account and is limited as the remediation account
+	request := testutil.NewTestBatchComplianceRequest(6,
+		testutil.WithRuleName("cloudwatch-log-group-encrypted"),
+		testutil.WithBatchSize(1))
+	for i := range request.NonCompliantResults {
+		request.NonCompliantResults[i].AccountId = []string{"111111111111", "222222222222"}[i%2]
+	}
+	request.NonCompliantResults[4].AccountId = ""
+
+	start := time.Now()
+	result, err := service.ProcessNonCompliantResourcesOptimized(context.Background(), request)
+	elapsed := time.Since(start)
+
+	require.NoError(t, err)
+	assert.Equal(t, 6, result.SuccessCount)
+	assert.Len(t, service.accountLimiter.accounts, 2)
+
+	// Each account waits two intervals for its third resource; a shared limiter would need five
+	assert.GreaterOrEqual(t, elapsed, 200*time.Millisecond)
+	assert.Less(t, elapsed, 500*time.Millisecond)
+}
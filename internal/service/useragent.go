@@ -0,0 +1,29 @@
+package service
+
+import (
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// UserAgentProduct identifies LogGuardian API calls in the AWS User-Agent header
+const UserAgentProduct = "logguardian"
+
+// Version returns the running LogGuardian version from APP_VERSION, or "unknown" when unset
+func Version() string {
+	if version := os.Getenv("APP_VERSION"); version != "" {
+		return version
+	}
+	return "unknown"
+}
+
+// ApplyUserAgent returns a copy of cfg whose clients tag every API call with logguardian/<version>
+func ApplyUserAgent(cfg aws.Config) aws.Config {
+	cfg = cfg.Copy()
+	apiOptions := make([]func(*middleware.Stack) error, 0, len(cfg.APIOptions)+1)
+	apiOptions = append(apiOptions, cfg.APIOptions...)
+	cfg.APIOptions = append(apiOptions, awsmiddleware.AddUserAgentKeyValue(UserAgentProduct, Version()))
+	return cfg
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// userAgentCapturingClient records the User-Agent of the first request and fails it without network access
+type userAgentCapturingClient struct {
+	userAgent string
+}
+
+func (c *userAgentCapturingClient) Do(req *http.Request) (*http.Response, error) {
+	c.userAgent = req.Header.Get("User-Agent")
+	return nil, errors.New("request captured")
+}
+
+func TestApplyUserAgent(t *testing.T) {
+	t.Setenv("APP_VERSION", "1.2.3")
+
+	base := aws.Config{}
+	cfg := ApplyUserAgent(base)
+
+	assert.Len(t, cfg.APIOptions, 1)
+	assert.Empty(t, base.APIOptions, "base config must not be modified")
+}
+
+func TestNewComplianceService_SetsUserAgent(t *testing.T) {
+	t.Setenv("APP_VERSION", "1.2.3")
+
+	httpClient := &userAgentCapturingClient{}
+	service := NewComplianceService(aws.Config{
+		Region:      "ca-central-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+		HTTPClient:  httpClient,
+		Retryer:     func() aws.Retryer { return aws.NopRetryer{} },
+	})
+
+	_, err := service.logsClient.DescribeLogGroups(context.Background(), &cloudwatchlogs.DescribeLogGroupsInput{})
+	require.Error(t, err)
+
+	assert.Contains(t, httpClient.userAgent, "logguardian/1.2.3")
+}
+
+func TestVersion(t *testing.T) {
+	t.Setenv("APP_VERSION", "")
+	assert.Equal(t, "unknown", Version())
+
+	t.Setenv("APP_VERSION", "v2.0.0")
+	assert.Equal(t, "v2.0.0", Version())
+}
@@ -0,0 +1,133 @@
+package testutil
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zsoftly/logguardian/internal/types"
+)
+
+// Defaults used by the request fixtures
+const (
+	DefaultTestConfigRuleName = "cloudwatch-log-group-retention"
+	DefaultTestRegion         = "ca-central-1"
+	DefaultTestBatchSize      = 10
+)
+
+// BatchRequestOption customizes a request built by NewTestBatchComplianceRequest
+type BatchRequestOption func(*types.BatchComplianceRequest)
+
+// WithRuleName sets the Config rule name; resources are unaffected
+func WithRuleName(configRuleName string) BatchRequestOption {
+	return func(r *types.BatchComplianceRequest) {
+		r.ConfigRuleName = configRuleName
+	}
+}
+
+// WithRegion sets the request region and the region of every resource
+func WithRegion(region string) BatchRequestOption {
+	return func(r *types.BatchComplianceRequest) {
+		r.Region = region
+		for i := range r.NonCompliantResults {
+			r.NonCompliantResults[i].Region = region
+		}
+	}
+}
+
+// WithBatchSize sets the number of resources processed per batch
+func WithBatchSize(batchSize int) BatchRequestOption {
+	return func(r *types.BatchComplianceRequest) {
+		r.BatchSize = batchSize
+	}
+}
+
+// NewTestNonCompliantResource returns a non-compliant log group resource in the default test region
+func NewTestNonCompliantResource(logGroupName string) types.NonCompliantResource {
+	return types.NonCompliantResource{
+		ResourceId:     logGroupName,
+		ResourceType:   "AWS::Logs::LogGroup",
+		ResourceName:   logGroupName,
+		Region:         DefaultTestRegion,
+		AccountId:      "123456789012",
+		ComplianceType: "NON_COMPLIANT",
+	}
+}
+
+// NewNonCompliantResources returns n distinct non-compliant log groups in the default test region,
+// named /aws/lambda/test-0 through /aws/lambda/test-(n-1)
+func NewNonCompliantResources(n int) []types.NonCompliantResource {
+	resources := make([]types.NonCompliantResource, n)
+	for i := range resources {
+		resources[i] = NewTestNonCompliantResource(fmt.Sprintf("/aws/lambda/test-%d", i))
+	}
+	return resources
+}
+
+// NonCompliantResourceBuilder builds a NonCompliantResource starting from the
+// NewTestNonCompliantResource defaults
+type NonCompliantResourceBuilder struct {
+	resource types.NonCompliantResource
+}
+
+// NewNonCompliantResourceBuilder starts a builder for the log group named logGroupName
+func NewNonCompliantResourceBuilder(logGroupName string) *NonCompliantResourceBuilder {
+	return &NonCompliantResourceBuilder{resource: NewTestNonCompliantResource(logGroupName)}
+}
+
+// WithRegion sets the resource region
+func (b *NonCompliantResourceBuilder) WithRegion(region string) *NonCompliantResourceBuilder {
+	b.resource.Region = region
+	return b
+}
+
+// WithAccountId sets the account owning the resource
+func (b *NonCompliantResourceBuilder) WithAccountId(accountId string) *NonCompliantResourceBuilder {
+	b.resource.AccountId = accountId
+	return b
+}
+
+// WithResourceType sets the Config resource type, e.g. to build an unsupported resource
+func (b *NonCompliantResourceBuilder) WithResourceType(resourceType string) *NonCompliantResourceBuilder {
+	b.resource.ResourceType = resourceType
+	return b
+}
+
+// WithComplianceType sets the Config compliance type
+func (b *NonCompliantResourceBuilder) WithComplianceType(complianceType string) *NonCompliantResourceBuilder {
+	b.resource.ComplianceType = complianceType
+	return b
+}
+
+// WithAnnotation sets the Config evaluation annotation
+func (b *NonCompliantResourceBuilder) WithAnnotation(annotation string) *NonCompliantResourceBuilder {
+	b.resource.Annotation = annotation
+	return b
+}
+
+// WithLastEvaluated sets when Config last evaluated the resource
+func (b *NonCompliantResourceBuilder) WithLastEvaluated(lastEvaluated time.Time) *NonCompliantResourceBuilder {
+	b.resource.LastEvaluated = lastEvaluated
+	return b
+}
+
+// Build returns the resource
+func (b *NonCompliantResourceBuilder) Build() types.NonCompliantResource {
+	return b.resource
+}
+
+// NewTestBatchComplianceRequest returns a retention rule request with the n distinct
+// non-compliant log groups of NewNonCompliantResources
+func NewTestBatchComplianceRequest(n int, opts ...BatchRequestOption) types.BatchComplianceRequest {
+	request := types.BatchComplianceRequest{
+		ConfigRuleName:      DefaultTestConfigRuleName,
+		NonCompliantResults: NewNonCompliantResources(n),
+		Region:              DefaultTestRegion,
+		BatchSize:           DefaultTestBatchSize,
+	}
+
+	for _, opt := range opts {
+		opt(&request)
+	}
+
+	return request
+}
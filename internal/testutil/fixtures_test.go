@@ -0,0 +1,86 @@
+package testutil
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zsoftly/logguardian/internal/types"
+)
+
+func TestNewTestBatchComplianceRequest(t *testing.T) {
+	request := NewTestBatchComplianceRequest(25)
+
+	assert.Equal(t, DefaultTestConfigRuleName, request.ConfigRuleName)
+	assert.Equal(t, DefaultTestRegion, request.Region)
+	assert.Equal(t, DefaultTestBatchSize, request.BatchSize)
+	assert.Len(t, request.NonCompliantResults, 25)
+
+	names := make(map[string]bool)
+	for _, resource := range request.NonCompliantResults {
+		assert.Equal(t, "AWS::Logs::LogGroup", resource.ResourceType)
+		assert.False(t, names[resource.ResourceName], "duplicate resource name %s", resource.ResourceName)
+		names[resource.ResourceName] = true
+	}
+}
+
+func TestNewTestBatchComplianceRequest_Options(t *testing.T) {
+	request := NewTestBatchComplianceRequest(3,
+		WithRuleName("cloudwatch-log-group-encrypted"),
+		WithRegion("ca-west-1"),
+		WithBatchSize(2))
+
+	assert.Equal(t, "cloudwatch-log-group-encrypted", request.ConfigRuleName)
+	assert.Equal(t, "ca-west-1", request.Region)
+	assert.Equal(t, 2, request.BatchSize)
+	for _, resource := range request.NonCompliantResults {
+		assert.Equal(t, "ca-west-1", resource.Region)
+	}
+}
+
+func TestNewTestBatchComplianceRequest_Empty(t *testing.T) {
+	request := NewTestBatchComplianceRequest(0)
+	assert.Empty(t, request.NonCompliantResults)
+}
+
+func TestNewNonCompliantResources(t *testing.T) {
+	resources := NewNonCompliantResources(100)
+	require.Len(t, resources, 100)
+
+	names := make(map[string]bool)
+	for i, resource := range resources {
+		assert.Equal(t, fmt.Sprintf("/aws/lambda/test-%d", i), resource.ResourceName)
+		assert.Equal(t, resource.ResourceName, resource.ResourceId)
+		assert.Equal(t, DefaultTestRegion, resource.Region)
+		assert.False(t, names[resource.ResourceName], "duplicate resource name %s", resource.ResourceName)
+		names[resource.ResourceName] = true
+	}
+}
+
+func TestNonCompliantResourceBuilder(t *testing.T) {
+	evaluated := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	resource := NewNonCompliantResourceBuilder("/aws/lambda/custom").
+		WithRegion("ca-west-1").
+		WithAccountId("210987654321").
+		WithResourceType("AWS::S3::Bucket").
+		WithComplianceType("COMPLIANT").
+		WithAnnotation("Missing retention").
+		WithLastEvaluated(evaluated).
+		Build()
+
+	assert.Equal(t, types.NonCompliantResource{
+		ResourceId:     "/aws/lambda/custom",
+		ResourceType:   "AWS::S3::Bucket",
+		ResourceName:   "/aws/lambda/custom",
+		Region:         "ca-west-1",
+		AccountId:      "210987654321",
+		ComplianceType: "COMPLIANT",
+		Annotation:     "Missing retention",
+		LastEvaluated:  evaluated,
+	}, resource)
+
+	// Without overrides the builder matches NewTestNonCompliantResource
+	assert.Equal(t, NewTestNonCompliantResource("/aws/lambda/plain"), NewNonCompliantResourceBuilder("/aws/lambda/plain").Build())
+}
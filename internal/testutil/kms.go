@@ -0,0 +1,66 @@
+package testutil
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/zsoftly/logguardian/internal/types"
+)
+
+// KMSReportExpectation lists the KMSValidationReport fields a test cares about; nil fields are
+// not checked, and timestamps are never compared
+type KMSReportExpectation struct {
+	KeyExists      *bool
+	KeyAccessible  *bool
+	IsCrossRegion  *bool
+	IsCrossAccount *bool
+	ErrorCount     *int
+	WarningCount   *int
+}
+
+// Bool returns a pointer to v, for building expectations
+func Bool(v bool) *bool {
+	return &v
+}
+
+// Int returns a pointer to v, for building expectations
+func Int(v int) *int {
+	return &v
+}
+
+// AssertKMSReport checks report against the fields set in expected, reporting every mismatch in a
+// single failure. It returns whether the report matched.
+func AssertKMSReport(t testing.TB, report *types.KMSValidationReport, expected KMSReportExpectation) bool {
+	t.Helper()
+
+	if report == nil {
+		t.Errorf("KMS validation report is nil")
+		return false
+	}
+
+	var mismatches []string
+	checkBool := func(field string, want *bool, got bool) {
+		if want != nil && *want != got {
+			mismatches = append(mismatches, fmt.Sprintf("%s: expected %t, got %t", field, *want, got))
+		}
+	}
+	checkCount := func(field string, want *int, got []string) {
+		if want != nil && *want != len(got) {
+			mismatches = append(mismatches, fmt.Sprintf("%s: expected %d, got %d %q", field, *want, len(got), got))
+		}
+	}
+
+	checkBool("KeyExists", expected.KeyExists, report.KeyExists)
+	checkBool("KeyAccessible", expected.KeyAccessible, report.KeyAccessible)
+	checkBool("IsCrossRegion", expected.IsCrossRegion, report.IsCrossRegion)
+	checkBool("IsCrossAccount", expected.IsCrossAccount, report.IsCrossAccount)
+	checkCount("ValidationErrors", expected.ErrorCount, report.ValidationErrors)
+	checkCount("ValidationWarnings", expected.WarningCount, report.ValidationWarnings)
+
+	if len(mismatches) > 0 {
+		t.Errorf("KMS validation report for %s does not match:\n  %s", report.KeyAlias, strings.Join(mismatches, "\n  "))
+		return false
+	}
+	return true
+}
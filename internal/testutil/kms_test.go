@@ -0,0 +1,72 @@
+package testutil
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zsoftly/logguardian/internal/types"
+)
+
+// recordingTB captures failures instead of failing the enclosing test
+type recordingTB struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssertKMSReport(t *testing.T) {
+	report := &types.KMSValidationReport{
+		KeyAlias:            "alias/test-key",
+		KeyExists:           true,
+		KeyAccessible:       true,
+		IsCrossRegion:       true,
+		ValidationWarnings:  []string{"cross-region key"},
+		ValidationTimestamp: time.Now(),
+	}
+
+	assert.True(t, AssertKMSReport(t, report, KMSReportExpectation{
+		KeyExists:     Bool(true),
+		KeyAccessible: Bool(true),
+		IsCrossRegion: Bool(true),
+		ErrorCount:    Int(0),
+		WarningCount:  Int(1),
+	}))
+
+	// Unset fields are ignored
+	assert.True(t, AssertKMSReport(t, report, KMSReportExpectation{}))
+}
+
+func TestAssertKMSReport_Mismatch(t *testing.T) {
+	report := &types.KMSValidationReport{
+		KeyAlias:         "alias/missing",
+		ValidationErrors: []string{"KMS key not found"},
+	}
+
+	recorder := &recordingTB{TB: t}
+	matched := AssertKMSReport(recorder, report, KMSReportExpectation{
+		KeyExists:     Bool(true),
+		IsCrossRegion: Bool(false),
+		ErrorCount:    Int(0),
+	})
+
+	assert.False(t, matched)
+	if assert.Len(t, recorder.failures, 1) {
+		assert.Equal(t, "KMS validation report for alias/missing does not match:\n"+
+			"  KeyExists: expected true, got false\n"+
+			"  ValidationErrors: expected 0, got 1 [\"KMS key not found\"]", recorder.failures[0])
+	}
+}
+
+func TestAssertKMSReport_NilReport(t *testing.T) {
+	recorder := &recordingTB{TB: t}
+
+	assert.False(t, AssertKMSReport(recorder, nil, KMSReportExpectation{}))
+	assert.Equal(t, []string{"KMS validation report is nil"}, recorder.failures)
+}
@@ -0,0 +1,112 @@
+// Package testutil provides shared helpers for LogGuardian tests
+package testutil
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"testing"
+)
+
+// LogRecord is a single structured log line decoded from JSON output
+type LogRecord struct {
+	Level   string
+	Message string
+	Attrs   map[string]any
+}
+
+// LogCapture collects JSON log output written by a captured logger
+type LogCapture struct {
+	t   testing.TB
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// CaptureLogs returns a debug-level JSON logger that writes to an in-memory buffer,
+// together with the capture used to inspect what was logged
+func CaptureLogs(t testing.TB) (*slog.Logger, *LogCapture) {
+	t.Helper()
+
+	capture := &LogCapture{t: t}
+	logger := slog.New(slog.NewJSONHandler(capture, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+	}))
+	return logger, capture
+}
+
+// Write implements io.Writer so the capture can back a slog handler used from many goroutines
+func (c *LogCapture) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.buf.Write(p)
+}
+
+// Records parses all captured log lines, failing the test on malformed output
+func (c *LogCapture) Records() []LogRecord {
+	c.t.Helper()
+
+	c.mu.Lock()
+	data := append([]byte(nil), c.buf.Bytes()...)
+	c.mu.Unlock()
+
+	records, err := ParseLogRecords(data)
+	if err != nil {
+		c.t.Fatalf("failed to parse captured logs: %v", err)
+	}
+	return records
+}
+
+// WithAttr returns the captured records whose attribute key has the given value
+func (c *LogCapture) WithAttr(key string, value any) []LogRecord {
+	c.t.Helper()
+
+	var matched []LogRecord
+	for _, record := range c.Records() {
+		if got, ok := record.Attrs[key]; ok && fmt.Sprint(got) == fmt.Sprint(value) {
+			matched = append(matched, record)
+		}
+	}
+	return matched
+}
+
+// HasAuditAction reports whether any captured record carries the given audit_action
+func (c *LogCapture) HasAuditAction(action string) bool {
+	c.t.Helper()
+	return len(c.WithAttr("audit_action", action)) > 0
+}
+
+// ParseLogRecords decodes newline-delimited slog JSON output into records
+func ParseLogRecords(data []byte) ([]LogRecord, error) {
+	var records []LogRecord
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var attrs map[string]any
+		if err := json.Unmarshal(line, &attrs); err != nil {
+			return nil, fmt.Errorf("invalid log line %q: %w", line, err)
+		}
+
+		record := LogRecord{Attrs: attrs}
+		record.Level, _ = attrs[slog.LevelKey].(string)
+		record.Message, _ = attrs[slog.MessageKey].(string)
+		delete(attrs, slog.LevelKey)
+		delete(attrs, slog.MessageKey)
+		delete(attrs, slog.TimeKey)
+
+		records = append(records, record)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read log output: %w", err)
+	}
+	return records, nil
+}
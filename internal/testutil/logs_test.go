@@ -0,0 +1,72 @@
+package testutil
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaptureLogs(t *testing.T) {
+	logger, capture := CaptureLogs(t)
+
+	logger.Info("Applied encryption", "log_group", "/aws/lambda/a", "audit_action", "encryption_success")
+	logger.Warn("Key in other region", "audit_action", "cross_region_key_usage", "attempt", 2)
+
+	records := capture.Records()
+	require.Len(t, records, 2)
+
+	assert.Equal(t, "INFO", records[0].Level)
+	assert.Equal(t, "Applied encryption", records[0].Message)
+	assert.Equal(t, "/aws/lambda/a", records[0].Attrs["log_group"])
+	assert.NotContains(t, records[0].Attrs, "time")
+
+	assert.Equal(t, "WARN", records[1].Level)
+	assert.Equal(t, float64(2), records[1].Attrs["attempt"])
+
+	assert.True(t, capture.HasAuditAction("encryption_success"))
+	assert.False(t, capture.HasAuditAction("encryption_failed"))
+	assert.Len(t, capture.WithAttr("attempt", 2), 1)
+}
+
+func TestCaptureLogs_ConcurrentWriters(t *testing.T) {
+	logger, capture := CaptureLogs(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			logger.Info("worker", "index", i)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Len(t, capture.Records(), 50)
+}
+
+func TestParseLogRecords(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expectedLen int
+		expectError bool
+	}{
+		{name: "empty output", input: "", expectedLen: 0},
+		{name: "blank lines skipped", input: "{\"level\":\"INFO\",\"msg\":\"a\"}\n\n{\"level\":\"DEBUG\",\"msg\":\"b\"}\n", expectedLen: 2},
+		{name: "malformed line", input: "not json\n", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := ParseLogRecords([]byte(tt.input))
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, records, tt.expectedLen)
+		})
+	}
+}
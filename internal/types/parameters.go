@@ -0,0 +1,185 @@
+package types
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// RetentionParameterKeys lists the Config rule parameter keys that carry a desired retention period,
+// in order of precedence. Custom rules and the AWS managed retention rule use different names.
+var RetentionParameterKeys = []string{
+	"desiredRetentionInDays",
+	"retentionPeriod",
+	"minRetentionDays",
+	"MinRetentionTime",
+	"retentionDays",
+}
+
+// KMSKeyParameterKeys lists the Config rule parameter keys that carry a required KMS key
+var KMSKeyParameterKeys = []string{
+	"KmsKeyId",
+	"kmsKeyId",
+}
+
+// KMSKeyAliasParameterKeys lists the Config rule parameter keys that carry the KMS key alias to encrypt with
+var KMSKeyAliasParameterKeys = []string{
+	"kmsKeyAlias",
+	"KmsKeyAlias",
+}
+
+// Config rule parameter keys that tune batch remediation for a single invocation
+const (
+	BatchSizeParameterKey     = "batchSize"
+	ResourceDelayParameterKey = "resourceDelayMs"
+	GroupDelayParameterKey    = "groupDelayMs"
+)
+
+// Accepted ranges for the batch tuning parameters
+const (
+	MaxBatchSizeParameter = 100
+	MaxDelayMsParameter   = 60000
+)
+
+// ValidRetentionDays lists the retention periods CloudWatch Logs accepts
+var ValidRetentionDays = []int32{
+	1, 3, 5, 7, 14, 30, 60, 90, 120, 150, 180, 365, 400, 545, 731,
+	1096, 1827, 2192, 2557, 2922, 3288, 3653,
+}
+
+// kmsAliasPattern matches customer-managed KMS alias names
+var kmsAliasPattern = regexp.MustCompile(`^alias/[a-zA-Z0-9/_-]{1,250}$`)
+
+// IsValidRetentionDays reports whether CloudWatch Logs accepts days as a retention period
+func IsValidRetentionDays(days int32) bool {
+	return slices.Contains(ValidRetentionDays, days)
+}
+
+// IsValidKMSKeyAlias reports whether alias is a well-formed customer-managed KMS alias.
+// AWS-managed aliases (alias/aws/...) cannot be used for log group encryption.
+func IsValidKMSKeyAlias(alias string) bool {
+	return kmsAliasPattern.MatchString(alias) && !strings.HasPrefix(alias, "alias/aws/")
+}
+
+// RetentionDaysFromParameters returns the retention period requested by the Config rule parameters,
+// taking the first well-known key whose value is a retention period CloudWatch Logs accepts.
+// The second return value is false when no such key is present.
+func RetentionDaysFromParameters(params map[string]string) (int32, bool) {
+	for _, key := range RetentionParameterKeys {
+		value, ok := params[key]
+		if !ok {
+			continue
+		}
+
+		days, err := strconv.ParseInt(strings.TrimSpace(value), 10, 32)
+		if err != nil || !IsValidRetentionDays(int32(days)) {
+			continue
+		}
+		return int32(days), true
+	}
+	return 0, false
+}
+
+// KMSKeyIdFromParameters returns the KMS key required by the Config rule parameters
+func KMSKeyIdFromParameters(params map[string]string) (string, bool) {
+	for _, key := range KMSKeyParameterKeys {
+		if value := strings.TrimSpace(params[key]); value != "" {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// KMSKeyAliasFromParameters returns the KMS key alias requested by the Config rule parameters.
+// The second return value is false when no well-known key is present or its value is not a valid alias.
+func KMSKeyAliasFromParameters(params map[string]string) (string, bool) {
+	for _, key := range KMSKeyAliasParameterKeys {
+		value, ok := params[key]
+		if !ok {
+			continue
+		}
+
+		alias := strings.TrimSpace(value)
+		if !IsValidKMSKeyAlias(alias) {
+			return "", false
+		}
+		return alias, true
+	}
+	return "", false
+}
+
+// BatchSizeFromParameters returns the batch size requested by the Config rule parameters.
+// The second return value is false when the key is absent or the value is outside 1-MaxBatchSizeParameter.
+func BatchSizeFromParameters(params map[string]string) (int, bool) {
+	return intParameterInRange(params, BatchSizeParameterKey, 1, MaxBatchSizeParameter)
+}
+
+// DelayMsFromParameters returns the delay in milliseconds carried by key in the Config rule parameters.
+// The second return value is false when the key is absent or the value is outside 0-MaxDelayMsParameter.
+func DelayMsFromParameters(params map[string]string, key string) (int, bool) {
+	return intParameterInRange(params, key, 0, MaxDelayMsParameter)
+}
+
+func intParameterInRange(params map[string]string, key string, minValue, maxValue int) (int, bool) {
+	value, ok := params[key]
+	if !ok {
+		return 0, false
+	}
+
+	parsed, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || parsed < minValue || parsed > maxValue {
+		return 0, false
+	}
+	return parsed, true
+}
+
+// InvalidRuleParameters returns the well-known parameter keys that are present but carry an invalid
+// value, so callers can report why a rule's override was ignored
+func InvalidRuleParameters(params map[string]string) []string {
+	var invalid []string
+	for _, key := range RetentionParameterKeys {
+		if value, ok := params[key]; ok {
+			days, err := strconv.ParseInt(strings.TrimSpace(value), 10, 32)
+			if err != nil || !IsValidRetentionDays(int32(days)) {
+				invalid = append(invalid, key)
+			}
+		}
+	}
+	for _, key := range KMSKeyAliasParameterKeys {
+		if value, ok := params[key]; ok && !IsValidKMSKeyAlias(strings.TrimSpace(value)) {
+			invalid = append(invalid, key)
+		}
+	}
+	if _, present := params[BatchSizeParameterKey]; present {
+		if _, ok := BatchSizeFromParameters(params); !ok {
+			invalid = append(invalid, BatchSizeParameterKey)
+		}
+	}
+	for _, key := range []string{ResourceDelayParameterKey, GroupDelayParameterKey} {
+		if _, present := params[key]; present {
+			if _, ok := DelayMsFromParameters(params, key); !ok {
+				invalid = append(invalid, key)
+			}
+		}
+	}
+	return invalid
+}
+
+// IsKMSKeyAlias reports whether keyId names a KMS alias, either as alias/name or as an alias ARN
+func IsKMSKeyAlias(keyId string) bool {
+	return strings.HasPrefix(keyId, "alias/") || strings.Contains(keyId, ":alias/")
+}
+
+// KMSKeyMatches reports whether a log group's current KMS key satisfies the required key.
+// The required key may be a full ARN or a bare key ID; log group configurations always carry the ARN.
+// An alias never matches, since resolving it to a key ARN needs KMS; check IsKMSKeyAlias first.
+func KMSKeyMatches(currentKeyId, requiredKeyId string) bool {
+	if currentKeyId == "" || requiredKeyId == "" {
+		return false
+	}
+	if currentKeyId == requiredKeyId {
+		return true
+	}
+	return strings.HasSuffix(currentKeyId, ":key/"+requiredKeyId)
+}
@@ -0,0 +1,156 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestRetentionDaysFromParameters(t *testing.T) {
+	tests := []struct {
+		name         string
+		params       map[string]string
+		expectedDays int32
+		expectedOK   bool
+	}{
+		{name: "nil parameters", params: nil, expectedOK: false},
+		{name: "no well-known key", params: map[string]string{"other": "30"}, expectedOK: false},
+		{name: "desiredRetentionInDays", params: map[string]string{"desiredRetentionInDays": "365"}, expectedDays: 365, expectedOK: true},
+		{name: "AWS managed rule key", params: map[string]string{"MinRetentionTime": " 30 "}, expectedDays: 30, expectedOK: true},
+		{name: "non-numeric value", params: map[string]string{"retentionPeriod": "forever"}, expectedOK: false},
+		{name: "zero value", params: map[string]string{"minRetentionDays": "0"}, expectedOK: false},
+		{name: "retentionDays", params: map[string]string{"retentionDays": "731"}, expectedDays: 731, expectedOK: true},
+		{name: "value CloudWatch does not accept", params: map[string]string{"retentionDays": "100"}, expectedOK: false},
+		{
+			name:         "invalid key falls through to the next supported key",
+			params:       map[string]string{"desiredRetentionInDays": "forever", "retentionDays": "90"},
+			expectedDays: 90,
+			expectedOK:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			days, ok := RetentionDaysFromParameters(tt.params)
+			if ok != tt.expectedOK {
+				t.Fatalf("Expected ok=%v, got %v", tt.expectedOK, ok)
+			}
+			if days != tt.expectedDays {
+				t.Errorf("Expected %d days, got %d", tt.expectedDays, days)
+			}
+		})
+	}
+}
+
+func TestKMSKeyAliasFromParameters(t *testing.T) {
+	tests := []struct {
+		name          string
+		params        map[string]string
+		expectedAlias string
+		expectedOK    bool
+	}{
+		{name: "nil parameters", params: nil, expectedOK: false},
+		{name: "kmsKeyAlias", params: map[string]string{"kmsKeyAlias": " alias/team-logs "}, expectedAlias: "alias/team-logs", expectedOK: true},
+		{name: "missing alias prefix", params: map[string]string{"kmsKeyAlias": "team-logs"}, expectedOK: false},
+		{name: "AWS-managed alias", params: map[string]string{"kmsKeyAlias": "alias/aws/logs"}, expectedOK: false},
+		{name: "invalid characters", params: map[string]string{"kmsKeyAlias": "alias/team logs"}, expectedOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alias, ok := KMSKeyAliasFromParameters(tt.params)
+			if ok != tt.expectedOK {
+				t.Fatalf("Expected ok=%v, got %v", tt.expectedOK, ok)
+			}
+			if alias != tt.expectedAlias {
+				t.Errorf("Expected alias %q, got %q", tt.expectedAlias, alias)
+			}
+		})
+	}
+}
+
+func TestBatchTuningFromParameters(t *testing.T) {
+	params := map[string]string{
+		"batchSize":       " 50 ",
+		"resourceDelayMs": "0",
+		"groupDelayMs":    "60001",
+	}
+
+	if size, ok := BatchSizeFromParameters(params); !ok || size != 50 {
+		t.Errorf("Expected batch size 50, got %d (ok=%v)", size, ok)
+	}
+	if delay, ok := DelayMsFromParameters(params, ResourceDelayParameterKey); !ok || delay != 0 {
+		t.Errorf("Expected resource delay 0, got %d (ok=%v)", delay, ok)
+	}
+	if _, ok := DelayMsFromParameters(params, GroupDelayParameterKey); ok {
+		t.Error("Expected group delay above the maximum to be rejected")
+	}
+	if _, ok := BatchSizeFromParameters(map[string]string{"batchSize": "101"}); ok {
+		t.Error("Expected batch size above the maximum to be rejected")
+	}
+	if _, ok := BatchSizeFromParameters(nil); ok {
+		t.Error("Expected absent batch size to report false")
+	}
+}
+
+func TestInvalidRuleParameters(t *testing.T) {
+	params := map[string]string{
+		"retentionDays":   "forever",
+		"kmsKeyAlias":     "alias/aws/logs",
+		"batchSize":       "0",
+		"retentionPeriod": "30",
+		"other":           "ignored",
+	}
+
+	invalid := InvalidRuleParameters(params)
+	expected := []string{"retentionDays", "kmsKeyAlias", "batchSize"}
+	if len(invalid) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, invalid)
+	}
+	for i := range expected {
+		if invalid[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, invalid)
+		}
+	}
+}
+
+func TestIsKMSKeyAlias(t *testing.T) {
+	tests := []struct {
+		keyId    string
+		expected bool
+	}{
+		{keyId: "alias/team-logs", expected: true},
+		{keyId: "arn:aws:kms:ca-central-1:123456789012:alias/team-logs", expected: true},
+		{keyId: "arn:aws:kms:ca-central-1:123456789012:key/12345678-1234-1234-1234-123456789012", expected: false},
+		{keyId: "12345678-1234-1234-1234-123456789012", expected: false},
+	}
+
+	for _, tt := range tests {
+		if got := IsKMSKeyAlias(tt.keyId); got != tt.expected {
+			t.Errorf("IsKMSKeyAlias(%q) = %v, want %v", tt.keyId, got, tt.expected)
+		}
+	}
+}
+
+func TestKMSKeyMatches(t *testing.T) {
+	arn := "arn:aws:kms:ca-central-1:123456789012:key/12345678-1234-1234-1234-123456789012"
+
+	tests := []struct {
+		name     string
+		current  string
+		required string
+		expected bool
+	}{
+		{name: "identical ARN", current: arn, required: arn, expected: true},
+		{name: "bare key id", current: arn, required: "12345678-1234-1234-1234-123456789012", expected: true},
+		{name: "different key", current: arn, required: "other-key", expected: false},
+		{name: "unencrypted group", current: "", required: arn, expected: false},
+		{name: "alias never matches", current: arn, required: "alias/team-logs", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := KMSKeyMatches(tt.current, tt.required); got != tt.expected {
+				t.Errorf("KMSKeyMatches(%q, %q) = %v, want %v", tt.current, tt.required, got, tt.expected)
+			}
+		})
+	}
+}
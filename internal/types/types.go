@@ -2,6 +2,7 @@ package types
 
 import (
 	"encoding/json"
+	"strings"
 	"time"
 )
 
@@ -53,6 +54,16 @@ type LogGroupConfiguration struct {
 	LogGroupClass        string `json:"logGroupClass"`
 }
 
+// Normalized returns a copy with presence-significant fields cleaned up: some Config snapshots
+// carry whitespace-only or padded KMS key IDs, and a non-positive retention is not a real setting
+func (c LogGroupConfiguration) Normalized() LogGroupConfiguration {
+	c.KmsKeyId = strings.TrimSpace(c.KmsKeyId)
+	if c.RetentionInDays != nil && *c.RetentionInDays <= 0 {
+		c.RetentionInDays = nil
+	}
+	return c
+}
+
 // ComplianceResult represents the result of compliance checking
 type ComplianceResult struct {
 	LogGroupName      string
@@ -62,6 +73,17 @@ type ComplianceResult struct {
 	MissingRetention  bool
 	CurrentRetention  *int32
 	CurrentKmsKeyId   string
+	// DataProtectionStatus is the log group's data protection policy status (e.g. ACTIVATED), if known
+	DataProtectionStatus string
+	// TargetRetentionDays overrides the service default retention when the Config rule supplies one
+	TargetRetentionDays *int32
+	// TargetKMSKeyId overrides the service default KMS key alias when the Config rule supplies one
+	TargetKMSKeyId string
+	// LiveState is true when the Current* fields were read from the live log group rather than a Config snapshot
+	LiveState bool
+	// NotApplicable is true when the Config rule does not evaluate this resource, as opposed to
+	// evaluating it as compliant; such a resource is skipped rather than remediated
+	NotApplicable bool
 }
 
 // RemediationResult represents the result of applying remediation
@@ -70,8 +92,26 @@ type RemediationResult struct {
 	Region            string
 	EncryptionApplied bool
 	RetentionApplied  bool
+	NoActionNeeded    bool   // Already compliant; succeeded without applying anything
+	SkipReason        string // Why a required change was deliberately not applied, if any
 	Success           bool
 	Error             error
+	Duration          time.Duration // Time spent remediating, including rate-limit retries; set by batch remediation
+}
+
+// Status reports the result as a resource status: "failed", "skipped" when a required change was
+// deliberately not applied, "no-action" when the group was already compliant, or "success"
+func (r RemediationResult) Status() string {
+	switch {
+	case !r.Success:
+		return "failed"
+	case r.SkipReason != "":
+		return "skipped"
+	case r.NoActionNeeded:
+		return "no-action"
+	default:
+		return "success"
+	}
 }
 
 // ConfigRuleEvaluationResults represents AWS Config rule evaluation results
@@ -110,6 +150,13 @@ type BatchComplianceRequest struct {
 	NonCompliantResults []NonCompliantResource `json:"nonCompliantResults"`
 	Region              string                 `json:"region"`
 	BatchSize           int                    `json:"batchSize"`
+	// ResourceDelayMs and GroupDelayMs override the service batch delays for this request when set
+	ResourceDelayMs *int `json:"resourceDelayMs,omitempty"`
+	GroupDelayMs    *int `json:"groupDelayMs,omitempty"`
+	// TargetRetentionDays and TargetKMSKeyId override the service default retention and KMS key
+	// alias for this request when the Config rule supplies them
+	TargetRetentionDays *int32 `json:"targetRetentionDays,omitempty"`
+	TargetKMSKeyId      string `json:"targetKmsKeyId,omitempty"`
 }
 
 // NonCompliantResource represents a non-compliant resource from Config
@@ -122,16 +169,75 @@ type NonCompliantResource struct {
 	ComplianceType string    `json:"complianceType"`
 	Annotation     string    `json:"annotation"`
 	LastEvaluated  time.Time `json:"lastEvaluated"`
+	// Requirements lists what the resource is non-compliant for when the resources of several
+	// Config rules were merged; empty leaves it to the batch request's rule
+	Requirements []RuleType `json:"requirements,omitempty"`
 }
 
 // BatchRemediationResult represents the result of batch remediation
 type BatchRemediationResult struct {
 	TotalProcessed     int                 `json:"totalProcessed"`
 	SuccessCount       int                 `json:"successCount"`
+	NoActionCount      int                 `json:"noActionCount"`      // Subset of SuccessCount that needed no remediation
+	SkippedCount       int                 `json:"skippedCount"`       // Subset of SuccessCount left unchanged because every required change was skipped
+	NotApplicableCount int                 `json:"notApplicableCount"` // Resources the rule does not apply to; neither successes nor failures
 	FailureCount       int                 `json:"failureCount"`
 	Results            []RemediationResult `json:"results"`
 	ProcessingDuration time.Duration       `json:"processingDuration"`
 	RateLimitHits      int                 `json:"rateLimitHits"`
+	Cancelled          bool                `json:"cancelled"`          // Context was cancelled before all resources were processed
+	AbortedOnFailure   bool                `json:"abortedOnFailure"`   // FAIL_FAST stopped the batch after the first failure
+	TimedOut           bool                `json:"timedOut"`           // BATCH_TIMEOUT_MS elapsed while resources were still running
+	Deferred           []string            `json:"deferred,omitempty"` // Log groups left unstarted once SOFT_TIME_BUDGET_MS was spent
+	Timing             TimingBreakdown     `json:"timing"`
+	Drift              []DriftEntry        `json:"drift,omitempty"` // Before and after state of each remediated log group
+}
+
+// DriftEntry is audit evidence of one remediated log group's configuration before and after
+// remediation. After is projected from the changes applied unless Verified, in which case it was
+// read back from the live log group.
+type DriftEntry struct {
+	LogGroupName string                `json:"logGroupName"`
+	Before       LogGroupConfiguration `json:"before"`
+	After        LogGroupConfiguration `json:"after"`
+	Verified     bool                  `json:"verified"`
+}
+
+// AuditRecord is self-contained compliance evidence of one remediation: the log group's key and
+// retention before and after, the action taken, who took it and when. After is projected from the
+// changes applied, including any applied before a failure.
+type AuditRecord struct {
+	LogGroupName string     `json:"logGroupName"`
+	Region       string     `json:"region,omitempty"`
+	AccountId    string     `json:"accountId,omitempty"`
+	Action       string     `json:"action"` // e.g. "applied encryption", or "remediation failed"
+	Success      bool       `json:"success"`
+	Error        string     `json:"error,omitempty"`
+	Before       AuditState `json:"before"`
+	After        AuditState `json:"after"`
+	Actor        AuditActor `json:"actor"`
+	Timestamp    time.Time  `json:"timestamp"`
+}
+
+// AuditState is the remediated configuration of a log group; empty values mean unencrypted or
+// never expiring
+type AuditState struct {
+	KmsKeyId        string `json:"kmsKeyId,omitempty"` // Key ARN, as log groups report it
+	RetentionInDays *int32 `json:"retentionInDays,omitempty"`
+}
+
+// AuditActor identifies the run that made a change, as far as it is known
+type AuditActor struct {
+	ExecutionID string `json:"executionId,omitempty"` // Container execution ID or Lambda request ID
+	Role        string `json:"role,omitempty"`        // IAM role the run assumed, when known
+}
+
+// TimingBreakdown splits a batch run's processing time by where it went. Remediation and Sleep are
+// summed across concurrent batches, so with more than one batch they can exceed ProcessingDuration.
+type TimingBreakdown struct {
+	KMSValidation time.Duration `json:"kmsValidation"` // One-time KMS key validation before remediation
+	Remediation   time.Duration `json:"remediation"`   // Remediating resources, including rate-limit retries
+	Sleep         time.Duration `json:"sleep"`         // Resource and group delays plus rate-limit backoff
 }
 
 // LambdaRequest represents the unified request format for the Lambda
@@ -143,6 +249,41 @@ type LambdaRequest struct {
 	BatchSize      int             `json:"batchSize,omitempty"`      // For rule evaluation requests
 }
 
+// RunReport is the Lambda response summarising an invocation, so orchestrators such as
+// Step Functions can branch on the outcome
+type RunReport struct {
+	RequestType      string `json:"requestType"`
+	ConfigRuleName   string `json:"configRuleName,omitempty"`
+	Region           string `json:"region,omitempty"`
+	Total            int    `json:"total"`
+	Success          int    `json:"success"`
+	Failure          int    `json:"failure"`
+	Skipped          int    `json:"skipped"`
+	Deferred         int    `json:"deferred"` // Resources left unstarted once SOFT_TIME_BUDGET_MS was spent
+	Cancelled        bool   `json:"cancelled"`
+	AbortedOnFailure bool   `json:"abortedOnFailure"`
+	TimedOut         bool   `json:"timedOut"`
+	DurationMs       int64  `json:"durationMs"`
+	Status           string `json:"status"`
+
+	// Resources lists the outcome of every log group remediated, so an async invocation's
+	// destination records which groups succeeded and which failed
+	Resources []ResourceOutcome `json:"resources,omitempty"`
+}
+
+// ResourceOutcome is the remediation outcome of a single log group in a RunReport. Status is
+// RemediationResult.Status, matching the container's resource results.
+type ResourceOutcome struct {
+	LogGroupName      string `json:"logGroupName"`
+	Region            string `json:"region,omitempty"`
+	Status            string `json:"status"`
+	EncryptionApplied bool   `json:"encryptionApplied"`
+	RetentionApplied  bool   `json:"retentionApplied"`
+	SkipReason        string `json:"skipReason,omitempty"`
+	Error             string `json:"error,omitempty"`
+	DurationMs        int64  `json:"durationMs,omitempty"`
+}
+
 // KMSEncryptionResult represents the result of KMS encryption operations
 type KMSEncryptionResult struct {
 	LogGroupName      string    `json:"logGroupName"`
@@ -161,19 +302,78 @@ type KMSEncryptionResult struct {
 
 // KMSValidationReport provides comprehensive KMS key validation information
 type KMSValidationReport struct {
-	KeyAlias             string    `json:"keyAlias"`
-	KeyId                string    `json:"keyId"`
-	KeyArn               string    `json:"keyArn"`
-	KeyState             string    `json:"keyState"`
-	KeyRegion            string    `json:"keyRegion"`
-	CurrentRegion        string    `json:"currentRegion"`
-	IsCrossRegion        bool      `json:"isCrossRegion"`
-	KeyExists            bool      `json:"keyExists"`
-	KeyAccessible        bool      `json:"keyAccessible"`
-	PolicyAccessible     bool      `json:"policyAccessible"`
-	CloudWatchLogsAccess bool      `json:"cloudWatchLogsAccess"`
-	ValidationErrors     []string  `json:"validationErrors,omitempty"`
-	ValidationWarnings   []string  `json:"validationWarnings,omitempty"`
-	RecommendedActions   []string  `json:"recommendedActions,omitempty"`
-	ValidationTimestamp  time.Time `json:"validationTimestamp"`
+	KeyAlias             string   `json:"keyAlias"`
+	KeyId                string   `json:"keyId"`
+	KeyArn               string   `json:"keyArn"`
+	KeyState             string   `json:"keyState"`
+	KeyRegion            string   `json:"keyRegion"`
+	KeyAccount           string   `json:"keyAccount"`
+	CurrentRegion        string   `json:"currentRegion"`
+	IsCrossRegion        bool     `json:"isCrossRegion"`
+	IsCrossAccount       bool     `json:"isCrossAccount"`
+	KeyExists            bool     `json:"keyExists"`
+	KeyAccessible        bool     `json:"keyAccessible"`
+	PolicyAccessible     bool     `json:"policyAccessible"`
+	CloudWatchLogsAccess bool     `json:"cloudWatchLogsAccess"`
+	ValidationErrors     []string `json:"validationErrors,omitempty"`
+	ValidationWarnings   []string `json:"validationWarnings,omitempty"`
+	RecommendedActions   []string `json:"recommendedActions,omitempty"`
+	// SuggestedPolicyStatement is a key policy statement granting CloudWatch Logs access, set when
+	// the policy was readable but did not grant it
+	SuggestedPolicyStatement string `json:"suggestedPolicyStatement,omitempty"`
+	// SuggestedPolicyDiff is the change adding SuggestedPolicyStatement makes to the key policy's statements
+	SuggestedPolicyDiff *KeyPolicyDiff `json:"suggestedPolicyDiff,omitempty"`
+	// KeyCreatedAt is when the key was created; LastRotatedAt is its most recent key material
+	// rotation, left unset when the key has never rotated or its rotation history is unavailable
+	KeyCreatedAt        *time.Time `json:"keyCreatedAt,omitempty"`
+	LastRotatedAt       *time.Time `json:"lastRotatedAt,omitempty"`
+	ValidationTimestamp time.Time  `json:"validationTimestamp"`
+}
+
+// KeyPolicyDiff is a structured before and after view of a key policy's statements, with the
+// statements added and removed between them
+type KeyPolicyDiff struct {
+	Before  []KeyPolicyStatementSummary `json:"before"`
+	After   []KeyPolicyStatementSummary `json:"after"`
+	Added   []KeyPolicyStatementSummary `json:"added,omitempty"`
+	Removed []KeyPolicyStatementSummary `json:"removed,omitempty"`
+}
+
+// KeyPolicyStatementSummary is the part of a key policy statement that decides who may use the key
+// for what. Principal is the statement's compact JSON principal.
+type KeyPolicyStatementSummary struct {
+	Sid       string   `json:"sid,omitempty"`
+	Effect    string   `json:"effect"`
+	Principal string   `json:"principal"`
+	Actions   []string `json:"actions"`
+}
+
+// RegionKeyStatus classifies whether a region's compliance KMS key can encrypt log groups
+type RegionKeyStatus string
+
+const (
+	// RegionKeyStatusUsable means the key exists, is enabled and can be described
+	RegionKeyStatusUsable RegionKeyStatus = "usable"
+	// RegionKeyStatusUnusable means the key exists but is disabled, pending deletion or inaccessible
+	RegionKeyStatusUnusable RegionKeyStatus = "unusable"
+	// RegionKeyStatusMissing means no key with the configured alias exists in the region
+	RegionKeyStatusMissing RegionKeyStatus = "missing"
+)
+
+// RegionKeySummary is the preflight KMS key state of a single region
+type RegionKeySummary struct {
+	Region   string          `json:"region"`
+	KeyAlias string          `json:"keyAlias"`
+	Status   RegionKeyStatus `json:"status"`
+	KeyState string          `json:"keyState,omitempty"` // KMS key state such as Enabled or Disabled, when known
+	Error    string          `json:"error,omitempty"`
+}
+
+// RegionPreflightResult is the preflight outcome of a single region. Key is set once the critical
+// checks pass; a missing or unusable key is reported there without failing the region.
+type RegionPreflightResult struct {
+	Region string            `json:"region"`
+	Passed bool              `json:"passed"`
+	Key    *RegionKeySummary `json:"key,omitempty"`
+	Error  string            `json:"error,omitempty"`
 }
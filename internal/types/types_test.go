@@ -0,0 +1,53 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemediationResult_Status(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   RemediationResult
+		expected string
+	}{
+		{
+			name: "successful result",
+			result: RemediationResult{
+				Success: true,
+			},
+			expected: "success",
+		},
+		{
+			name: "failed result",
+			result: RemediationResult{
+				Success: false,
+			},
+			expected: "failed",
+		},
+		{
+			name: "already compliant result",
+			result: RemediationResult{
+				Success:        true,
+				NoActionNeeded: true,
+			},
+			expected: "no-action",
+		},
+		{
+			name: "skipped result",
+			result: RemediationResult{
+				Success:        true,
+				NoActionNeeded: true,
+				SkipReason:     "retention reduction blocked",
+			},
+			expected: "skipped",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.result.Status())
+		})
+	}
+}